@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/audit"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+)
+
+// auditCommand is the safekubectl subcommand that inspects the audit log
+const auditCommand = "audit"
+
+// runAudit handles `safekubectl audit <subcommand>`
+func (r *Runner) runAudit(args []string, cfg *config.Config) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: safekubectl %s verify|query|stats|near-misses|replay [PATH]", auditCommand)
+	}
+	switch args[0] {
+	case "verify":
+		return r.runAuditVerify(args[1:], cfg)
+	case "query":
+		return r.runAuditQuery(args[1:], cfg)
+	case "stats":
+		return r.runAuditStats(args[1:], cfg)
+	case "near-misses":
+		return r.runAuditNearMisses(args[1:], cfg)
+	case "replay":
+		return r.runAuditReplay(args[1:], cfg)
+	}
+	return fmt.Errorf("unknown %s subcommand %q: expected verify, query, stats, near-misses or replay", auditCommand, args[0])
+}
+
+// runAuditVerify walks the audit log (the configured one unless a path is
+// given) and reports every break in its hash chain. A log without chained
+// entries is an error, so a missing chain is never mistaken for an intact one.
+func (r *Runner) runAuditVerify(args []string, cfg *config.Config) error {
+	path := cfg.Audit.Path
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	chained, breaks, err := audit.VerifyChain(path)
+	if err != nil {
+		return err
+	}
+	if chained == 0 {
+		return fmt.Errorf("%s has no hash-chained entries: enable audit.hashChain", path)
+	}
+
+	prompt.DisplayAuditVerifyTo(r.stdout, path, chained, breaks)
+	if len(breaks) > 0 {
+		return fmt.Errorf("audit log hash chain is broken")
+	}
+	return nil
+}
+
+// Usage of the audit subcommands that read the log
+const (
+	auditQueryUsage      = "usage: safekubectl audit query [--cluster NAME] [--namespace NAME] [--status STATUS] [--operation OP] [--since TIME] [--until TIME] [-o table|json] [PATH]"
+	auditStatsUsage      = "usage: safekubectl audit stats [--cluster NAME] [--namespace NAME] [--status STATUS] [--operation OP] [--since TIME] [--until TIME] [-o text|csv] [PATH]"
+	auditNearMissesUsage = "usage: safekubectl audit near-misses [--cluster NAME] [--namespace NAME] [--operation OP] [--since TIME] [--until TIME] [-o text|json] [PATH]"
+	auditReplayUsage     = "usage: safekubectl audit replay ENTRY-ID [--context NAME] [PATH]"
+)
+
+// auditReadFlags are the flags shared by the audit subcommands that read the log
+type auditReadFlags struct {
+	filter audit.Filter
+	path   string
+	output string
+}
+
+// parseAuditReadFlags parses filter flags, -o (one of outputs, the first
+// being the default) and an optional log path, which defaults to the
+// configured one
+func parseAuditReadFlags(args []string, cfg *config.Config, outputs []string, usage string) (auditReadFlags, error) {
+	flags := auditReadFlags{path: cfg.Audit.Path, output: outputs[0]}
+	now := time.Now()
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			flags.path = arg
+			continue
+		}
+		name, value, hasValue := strings.Cut(arg, "=")
+		if !hasValue {
+			if i+1 >= len(args) {
+				return flags, fmt.Errorf("%s needs a value\n%s", name, usage)
+			}
+			i++
+			value = args[i]
+		}
+
+		var err error
+		switch name {
+		case "--cluster":
+			flags.filter.Cluster = value
+		case "-n", "--namespace":
+			flags.filter.Namespace = value
+		case "--status":
+			flags.filter.Status = value
+		case "--operation":
+			flags.filter.Operation = value
+		case "--since":
+			flags.filter.Since, err = audit.ParseSince(value, now)
+		case "--until":
+			flags.filter.Until, err = audit.ParseSince(value, now)
+		case "-o", "--output":
+			if !contains(outputs, value) {
+				err = fmt.Errorf("invalid output %q: expected %s", value, strings.Join(outputs, " or "))
+			}
+			flags.output = value
+		default:
+			err = fmt.Errorf("unknown flag %s\n%s", name, usage)
+		}
+		if err != nil {
+			return flags, err
+		}
+	}
+	return flags, nil
+}
+
+// contains reports whether list holds value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// runAuditQuery prints the audit entries (from the configured log unless a
+// path is given) that match the filter flags, as a table or as JSON
+func (r *Runner) runAuditQuery(args []string, cfg *config.Config) error {
+	flags, err := parseAuditReadFlags(args, cfg, []string{"table", "json"}, auditQueryUsage)
+	if err != nil {
+		return err
+	}
+
+	entries, err := audit.Query(flags.path, flags.filter)
+	if err != nil {
+		return err
+	}
+
+	if flags.output == "json" {
+		if entries == nil {
+			entries = []audit.Entry{}
+		}
+		enc := json.NewEncoder(r.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	prompt.DisplayAuditEntriesTo(r.stdout, entries)
+	return nil
+}
+
+// runAuditStats summarizes the matching audit entries per operation,
+// namespace and cluster, as text or CSV
+func (r *Runner) runAuditStats(args []string, cfg *config.Config) error {
+	flags, err := parseAuditReadFlags(args, cfg, []string{"text", "csv"}, auditStatsUsage)
+	if err != nil {
+		return err
+	}
+
+	entries, err := audit.Query(flags.path, flags.filter)
+	if err != nil {
+		return err
+	}
+
+	stats := audit.Summarize(entries)
+	if flags.output == "csv" {
+		return audit.WriteStatsCSV(r.stdout, stats)
+	}
+	prompt.DisplayAuditStatsTo(r.stdout, stats)
+	return nil
+}
+
+// runAuditNearMisses summarizes the prompted operations that were denied or
+// confirmed per day, user, cluster and rule, and says which prompts prevent
+// incidents and which are noise, as text or JSON
+func (r *Runner) runAuditNearMisses(args []string, cfg *config.Config) error {
+	flags, err := parseAuditReadFlags(args, cfg, []string{"text", "json"}, auditNearMissesUsage)
+	if err != nil {
+		return err
+	}
+
+	entries, err := audit.Query(flags.path, flags.filter)
+	if err != nil {
+		return err
+	}
+
+	summary := audit.SummarizeNearMisses(entries)
+	if flags.output == "json" {
+		enc := json.NewEncoder(r.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summary)
+	}
+	prompt.DisplayNearMissesTo(r.stdout, summary)
+	return nil
+}
+
+// runAuditReplay re-runs an audited apply, create or replace of -f - with the
+// exact manifest bytes archived for it, on the same cluster or, with
+// --context, another one. The replay goes through the usual checks and
+// confirmation, with prompts read from the terminal.
+func (r *Runner) runAuditReplay(args []string, cfg *config.Config) error {
+	var id, context string
+	path := cfg.Audit.Path
+	positionals := 0
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--context" && i+1 < len(args):
+			context = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--context="):
+			context = strings.TrimPrefix(arg, "--context=")
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag %s\n%s", arg, auditReplayUsage)
+		case positionals == 0:
+			id = arg
+			positionals++
+		case positionals == 1:
+			path = arg
+			positionals++
+		default:
+			return fmt.Errorf("unexpected argument %q\n%s", arg, auditReplayUsage)
+		}
+	}
+	if id == "" {
+		return fmt.Errorf("%s", auditReplayUsage)
+	}
+
+	entry, err := audit.Find(path, id)
+	if err != nil {
+		return err
+	}
+	if !writeOperations[entry.Operation] {
+		return fmt.Errorf("audit entry %s is a %s: only apply, create and replace can be replayed", entry.ID, entry.Operation)
+	}
+	if entry.Stdin == nil {
+		return fmt.Errorf("audit entry %s did not read its manifest from stdin: only -f - content is archived", entry.ID)
+	}
+	content, err := audit.ReadStdinArchive(path, entry.Stdin)
+	if err != nil {
+		return err
+	}
+
+	replayArgs := withFlags(withoutFileArgs(entry.Argv()), "-f", manifest.StdinSource)
+	if context != "" {
+		replayArgs = withFlags(withoutContextArgs(replayArgs), "--context", context)
+	}
+	prompt.DisplayReplayTo(r.stdout, entry, replayArgs)
+
+	wrapped := *r
+	wrapped.stdin = bytes.NewReader(content)
+	return wrapped.Run(replayArgs)
+}
+
+// withoutContextArgs strips --context so the command can be re-run against a
+// different cluster
+func withoutContextArgs(args []string) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return append(out, args[i:]...)
+		case arg == "--context":
+			i++
+		case strings.HasPrefix(arg, "--context="):
+		default:
+			out = append(out, arg)
+		}
+	}
+	return out
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/rbac"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/refs"
+)
+
+// authKinds are the kinds whose deletion can break API authentication
+var authKinds = map[string]bool{
+	"ServiceAccount":     true,
+	"Secret":             true,
+	"RoleBinding":        true,
+	"ClusterRoleBinding": true,
+}
+
+// authObject is a named ServiceAccount, Secret or binding a delete removes
+type authObject struct {
+	kind      string
+	name      string
+	namespace string // empty for ClusterRoleBindings
+}
+
+func (o authObject) display() string {
+	return strings.ToLower(o.kind) + "/" + o.name
+}
+
+// commandAuthObjects returns the named auth-related targets of a command
+func commandAuthObjects(cmd *parser.KubectlCommand, namespace string) []authObject {
+	var objects []authObject
+	for _, t := range cmd.Targets {
+		kind := parser.KindFor(t.Resource)
+		if t.Name == "" || !authKinds[kind] {
+			continue
+		}
+		obj := authObject{kind: kind, name: t.Name}
+		if !parser.IsClusterScopedKind(kind) {
+			obj.namespace = namespace
+		}
+		objects = append(objects, obj)
+	}
+	return objects
+}
+
+// manifestAuthObjects returns the auth-related resources in manifests
+func manifestAuthObjects(resources []manifest.Resource) []authObject {
+	var objects []authObject
+	for _, res := range resources {
+		if authKinds[res.Kind] {
+			objects = append(objects, authObject{kind: res.Kind, name: res.Name, namespace: res.Namespace})
+		}
+	}
+	return objects
+}
+
+// authBreakageReasons explains how deleting each object can break
+// authentication for the workloads and clients that depend on it. Objects
+// that cannot be looked up, and Secrets that are not tokens, are skipped.
+func (r *Runner) authBreakageReasons(objects []authObject, kubeContext string) []string {
+	contextArgs := kubectlContextArgs(kubeContext)
+	podsByNamespace := make(map[string][]refs.Pod)
+	var reasons []string
+
+	usedBy := func(namespace, serviceAccount string) int {
+		return refs.Count(r.namespacePods(podsByNamespace, namespace, kubeContext), "ServiceAccount", serviceAccount)
+	}
+
+	for _, obj := range objects {
+		switch obj.kind {
+		case "ServiceAccount":
+			if count := usedBy(obj.namespace, obj.name); count > 0 {
+				reasons = append(reasons, fmt.Sprintf("%s is used by %s: their API authentication breaks and replacement pods cannot start", obj.display(), podCount(count)))
+			} else {
+				reasons = append(reasons, obj.display()+": CI systems and clients using its tokens lose API authentication")
+			}
+
+		case "Secret":
+			out, err := r.queryKubectl(append([]string{"get", "secret", obj.name, "-n", obj.namespace, "-o", "json"}, contextArgs...))
+			if err != nil {
+				continue
+			}
+			if owner, err := rbac.TokenOwner(out); err == nil && owner != "" {
+				reasons = append(reasons, fmt.Sprintf("%s is a token for serviceaccount/%s: CI systems and clients using it lose API authentication", obj.display(), owner))
+			}
+
+		case "RoleBinding", "ClusterRoleBinding":
+			getArgs := []string{"get", strings.ToLower(obj.kind), obj.name, "-o", "json"}
+			if obj.namespace != "" {
+				getArgs = append(getArgs, "-n", obj.namespace)
+			}
+			out, err := r.queryKubectl(append(getArgs, contextArgs...))
+			if err != nil {
+				continue
+			}
+			subjects, err := rbac.ParseSubjects(out)
+			if err != nil || len(subjects) == 0 {
+				continue
+			}
+			var grants []string
+			for _, s := range subjects {
+				grant := s.String()
+				if s.Kind == "ServiceAccount" {
+					if count := usedBy(s.Namespace, s.Name); count > 0 {
+						grant += " (used by " + podCount(count) + ")"
+					}
+				}
+				grants = append(grants, grant)
+			}
+			reasons = append(reasons, fmt.Sprintf("%s grants %s: their API calls may be denied", obj.display(), strings.Join(grants, ", ")))
+		}
+	}
+
+	return reasons
+}
+
+// podCount returns "1 pod" or "N pods"
+func podCount(count int) string {
+	if count == 1 {
+		return "1 pod"
+	}
+	return fmt.Sprintf("%d pods", count)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// backupTarget is an object that must be backed up before it is deleted
+type backupTarget struct {
+	kind      string
+	name      string // empty for type-only targets, e.g. delete pvc --all
+	namespace string // empty for cluster-scoped kinds
+}
+
+func (t backupTarget) display() string {
+	if t.name == "" {
+		return strings.ToLower(t.kind)
+	}
+	return strings.ToLower(t.kind) + "/" + t.name
+}
+
+// commandBackupTargets returns the targets of a delete whose kind requires a backup
+func commandBackupTargets(cmd *parser.KubectlCommand, namespace string, cfg *config.Config) []backupTarget {
+	var targets []backupTarget
+	for _, t := range cmd.Targets {
+		kind := parser.KindFor(t.Resource)
+		if kind == "" {
+			kind = t.Resource
+		}
+		if !cfg.RequiresBackup(kind) {
+			continue
+		}
+		target := backupTarget{kind: kind, name: t.Name}
+		if !parser.IsClusterScopedKind(kind) {
+			target.namespace = namespace
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// manifestBackupTargets returns the manifest resources whose kind requires a backup
+func manifestBackupTargets(resources []manifest.Resource, cfg *config.Config) []backupTarget {
+	var targets []backupTarget
+	for _, res := range resources {
+		if cfg.RequiresBackup(res.Kind) {
+			targets = append(targets, backupTarget{kind: res.Kind, name: res.Name, namespace: res.Namespace})
+		}
+	}
+	return targets
+}
+
+// backupReasons tells the user which targets are backed up before the delete
+func backupReasons(targets []backupTarget) []string {
+	var reasons []string
+	for _, t := range targets {
+		reasons = append(reasons, "backup required: "+t.display()+" is deleted only after the backup hook succeeds")
+	}
+	return reasons
+}
+
+// runBackups runs the backup hook for each target, describing it in
+// SAFEKUBECTL_BACKUP_* environment variables, and stops at the first failure.
+// It returns the backup names the hooks printed, for the audit log.
+func (r *Runner) runBackups(hook []string, targets []backupTarget, cluster string) ([]string, error) {
+	var names []string
+	for _, t := range targets {
+		env := []string{
+			"SAFEKUBECTL_BACKUP_KIND=" + t.kind,
+			"SAFEKUBECTL_BACKUP_NAME=" + t.name,
+			"SAFEKUBECTL_BACKUP_NAMESPACE=" + t.namespace,
+			"SAFEKUBECTL_BACKUP_CLUSTER=" + cluster,
+		}
+		out, err := r.runHook(hook, env, nil)
+		if err != nil {
+			return nil, fmt.Errorf("backup of %s failed, not deleting: %w", t.display(), err)
+		}
+		if name := backupName(out); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// backupName is the last non-empty line a backup hook printed, e.g. the name
+// of the Velero backup it created or found recent enough
+func backupName(out []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
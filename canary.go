@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/audit"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+)
+
+// canaryRolloutTimeout bounds the health check of canary workloads
+const canaryRolloutTimeout = 2 * time.Minute
+
+// workloadKinds have a rollout status to wait on
+var workloadKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+}
+
+// runCanaryApply applies the canary resources first, health-checks them and
+// asks for confirmation, then applies the rest. Each phase is audited.
+func (r *Runner) runCanaryApply(cmd *parser.KubectlCommand, spec string, result *checker.ResourceCheckResult, auditLogger *audit.Logger, args []string) error {
+	canary, rest, err := selectCanary(result.Resources, spec)
+	if err != nil {
+		return err
+	}
+	baseArgs := withoutFileArgs(args)
+
+	// Phase 1: canary
+	prompt.DisplayCanaryPhaseTo(r.stdout, "canary", canary)
+	if err := r.applyPhase(canary, baseArgs, result, auditLogger, args); err != nil {
+		return fmt.Errorf("canary phase failed: %w", err)
+	}
+
+	healthy := true
+	for _, res := range canary {
+		if !workloadKinds[res.Kind] {
+			continue
+		}
+		statusArgs := []string{"rollout", "status", strings.ToLower(res.Kind) + "/" + res.Name, "-n", res.Namespace, "--timeout", canaryRolloutTimeout.String()}
+		_, err := r.queryKubectl(append(statusArgs, kubectlContextArgs(cmd.Context)...))
+		prompt.DisplayCanaryHealthTo(r.stdout, res, err == nil)
+		if err != nil {
+			healthy = false
+		}
+	}
+
+	if len(rest) == 0 {
+		return nil
+	}
+
+	// Phase 2: the rest, only after the operator has seen the canary outcome
+	prompt.DisplayCanaryPauseTo(r.stdout, len(rest), healthy)
+	if !prompt.AskConfirmationFrom(r.stdin, r.stdout) {
+		prompt.DisplayAbortedTo(r.stdout)
+		denied := *result
+		denied.Resources = rest
+		if err := auditLogger.LogResources(&denied, args, false, false); err != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", err)
+		}
+		return nil
+	}
+
+	prompt.DisplayCanaryPhaseTo(r.stdout, "remaining", rest)
+	if err := r.applyPhase(rest, baseArgs, result, auditLogger, args); err != nil {
+		return fmt.Errorf("remaining phase failed: %w", err)
+	}
+	return nil
+}
+
+// applyPhase applies and audits a subset of resources from a temporary manifest
+func (r *Runner) applyPhase(resources []manifest.Resource, baseArgs []string, result *checker.ResourceCheckResult, auditLogger *audit.Logger, args []string) error {
+	path, err := writeSubsetManifest(resources)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	phase := *result
+	phase.Resources = resources
+	execution, err := r.execute(append(append([]string{}, baseArgs...), "-f", path))
+	if logErr := auditLogger.LogResourcesExecuted(&phase, args, true, execution); logErr != nil {
+		fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+	}
+	return err
+}
+
+// selectCanary splits resources into the canary set and the rest. The spec is
+// either a percentage ("25%", rounded up, at least one) or a Kind/name.
+func selectCanary(resources []manifest.Resource, spec string) ([]manifest.Resource, []manifest.Resource, error) {
+	if len(resources) == 0 {
+		return nil, nil, fmt.Errorf("no resources to apply")
+	}
+
+	if pct, ok := strings.CutSuffix(spec, "%"); ok {
+		p, err := strconv.Atoi(pct)
+		if err != nil || p <= 0 || p > 100 {
+			return nil, nil, fmt.Errorf("invalid canary percentage %q", spec)
+		}
+		n := int(math.Ceil(float64(len(resources)) * float64(p) / 100))
+		return resources[:n], resources[n:], nil
+	}
+
+	kind, name, ok := strings.Cut(spec, "/")
+	if !ok || kind == "" || name == "" {
+		return nil, nil, fmt.Errorf("invalid canary %q: expected Kind/name or a percentage", spec)
+	}
+	for i, res := range resources {
+		if strings.EqualFold(res.Kind, kind) && res.Name == name {
+			rest := append(append([]manifest.Resource{}, resources[:i]...), resources[i+1:]...)
+			return []manifest.Resource{res}, rest, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("canary %s not found in manifests", spec)
+}
+
+// writeSubsetManifest writes the resources' documents to a temporary multi-doc YAML file
+func writeSubsetManifest(resources []manifest.Resource) (string, error) {
+	f, err := os.CreateTemp("", "safekubectl-subset-*.yaml")
+	if err != nil {
+		return "", fmt.Errorf("failed to create subset manifest: %w", err)
+	}
+	defer f.Close()
+
+	docs := make([]string, 0, len(resources))
+	for _, res := range resources {
+		docs = append(docs, string(res.Raw))
+	}
+	if _, err := f.WriteString(strings.Join(docs, "\n---\n")); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write subset manifest: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// withoutFileArgs strips -f/--filename and -R/--recursive so the command can be
+// re-run against a different manifest
+func withoutFileArgs(args []string) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-f" || arg == "--filename":
+			i++
+		case strings.HasPrefix(arg, "-f=") || strings.HasPrefix(arg, "--filename="):
+		case arg == "-R" || arg == "--recursive":
+		default:
+			out = append(out, arg)
+		}
+	}
+	return out
+}
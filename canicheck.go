@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// rbacVerbs maps kubectl operations to the RBAC verb they need on their targets
+var rbacVerbs = map[string]string{
+	"delete":   "delete",
+	"apply":    "patch",
+	"patch":    "patch",
+	"edit":     "patch",
+	"label":    "patch",
+	"annotate": "patch",
+	"set":      "patch",
+	"scale":    "patch",
+	"rollout":  "patch",
+	"cordon":   "patch",
+	"uncordon": "patch",
+	"taint":    "patch",
+	"create":   "create",
+	"replace":  "update",
+}
+
+// canIQuery is one `kubectl auth can-i` question
+type canIQuery struct {
+	verb      string
+	resource  string // TYPE or TYPE/NAME
+	namespace string // empty for cluster-scoped resources
+	all       bool   // --all-namespaces
+}
+
+func (q canIQuery) String() string {
+	switch {
+	case q.all:
+		return fmt.Sprintf("%s %s in all namespaces", q.verb, q.resource)
+	case q.namespace != "":
+		return fmt.Sprintf("%s %s in %s", q.verb, q.resource, q.namespace)
+	default:
+		return fmt.Sprintf("%s %s", q.verb, q.resource)
+	}
+}
+
+// commandCanIQueries returns the RBAC questions for a command's targets
+func commandCanIQueries(cmd *parser.KubectlCommand, namespace string) []canIQuery {
+	verb, ok := rbacVerbs[cmd.Operation]
+	if !ok {
+		return nil
+	}
+
+	var queries []canIQuery
+	for _, t := range cmd.Targets {
+		q := canIQuery{verb: verb, resource: t.Resource, all: cmd.AllNamespaces}
+		if t.Name != "" {
+			q.resource += "/" + t.Name
+		}
+		if !cmd.IsNodeScoped() && !parser.IsClusterScopedKind(parser.KindFor(t.Resource)) && !cmd.AllNamespaces {
+			q.namespace = namespace
+		}
+		queries = append(queries, q)
+	}
+	return queries
+}
+
+// manifestCanIQueries returns the RBAC questions for manifest resources
+func manifestCanIQueries(operation string, resources []manifest.Resource) []canIQuery {
+	verb, ok := rbacVerbs[operation]
+	if !ok {
+		return nil
+	}
+
+	var queries []canIQuery
+	for _, res := range resources {
+		resource := strings.ToLower(res.Kind)
+		if res.Name != "" {
+			resource += "/" + res.Name
+		}
+		queries = append(queries, canIQuery{verb: verb, resource: resource, namespace: res.Namespace})
+	}
+	return queries
+}
+
+// canIReasons asks RBAC about each query and reports denials, or that
+// everything is permitted. Broader-than-expected access is flagged too.
+func (r *Runner) canIReasons(queries []canIQuery, kubeContext string) []string {
+	contextArgs := kubectlContextArgs(kubeContext)
+	var reasons []string
+	permitted := 0
+
+	for _, q := range queries {
+		args := []string{"auth", "can-i", q.verb, q.resource}
+		switch {
+		case q.all:
+			args = append(args, "--all-namespaces")
+		case q.namespace != "":
+			args = append(args, "-n", q.namespace)
+		}
+		allowed, known := r.canI(append(args, contextArgs...))
+		switch {
+		case !known:
+			reasons = append(reasons, "RBAC: could not check whether you can "+q.String())
+		case allowed:
+			permitted++
+		default:
+			reasons = append(reasons, "RBAC: you cannot "+q.String()+"; kubectl will reject this operation")
+		}
+	}
+
+	if permitted > 0 && permitted == len(queries) {
+		reasons = append(reasons, fmt.Sprintf("RBAC: permitted (%d of %d checks)", permitted, len(queries)))
+	}
+	if allowed, known := r.canI(append([]string{"auth", "can-i", "*", "*", "--all-namespaces"}, contextArgs...)); known && allowed {
+		reasons = append(reasons, "RBAC: this identity has cluster-admin privileges")
+	}
+	return reasons
+}
+
+// canI runs one `kubectl auth can-i` and reports the answer and whether it was
+// answered at all. kubectl prints "no" and exits non-zero when denied.
+func (r *Runner) canI(args []string) (allowed, known bool) {
+	out, _ := r.queryKubectl(args)
+	answer := strings.TrimSpace(string(out))
+	switch {
+	case strings.HasPrefix(answer, "yes"):
+		return true, true
+	case strings.HasPrefix(answer, "no"):
+		return false, true
+	}
+	return false, false
+}
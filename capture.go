@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"sync"
+)
+
+// outputCapture keeps the first limit bytes written to it. kubectl's stdout
+// and stderr are written concurrently, so writes are serialized.
+type outputCapture struct {
+	mu        sync.Mutex
+	limit     int
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (c *outputCapture) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if room := c.limit - c.buf.Len(); len(p) > room {
+		c.buf.Write(p[:max(room, 0)])
+		c.truncated = true
+	} else {
+		c.buf.Write(p)
+	}
+	return len(p), nil
+}
+
+// reset discards what was captured, before the next command runs
+func (c *outputCapture) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.buf.Reset()
+	c.truncated = false
+}
+
+// result returns a copy of what was captured, and whether it was cut off at
+// the limit
+func (c *outputCapture) result() ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]byte{}, c.buf.Bytes()...), c.truncated
+}
+
+// withOutputCapture returns a copy of the runner that keeps up to limit bytes
+// of what kubectl prints, for execute to record in the audit log
+func (r *Runner) withOutputCapture(limit int) *Runner {
+	if r.filterKubectl == nil {
+		return r
+	}
+	wrapped := *r
+	wrapped.capture = &outputCapture{limit: limit}
+	return wrapped.withFilteredOutput()
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+)
+
+// outsideChangeWindow returns the reason a dangerous command on a protected
+// cluster falls outside the cluster's change window, and that window. It
+// returns "" and nil inside the window, or when no window applies.
+func (r *Runner) outsideChangeWindow(cfg *config.Config, cluster string) (string, *config.ChangeWindow) {
+	if !cfg.IsProtectedCluster(cluster) {
+		return "", nil
+	}
+	window := cfg.ChangeWindowFor(cluster)
+	if window == nil {
+		return "", nil
+	}
+	now := time.Now
+	if r.now != nil {
+		now = r.now
+	}
+	outside, why := window.Outside(now())
+	if !outside {
+		return "", nil
+	}
+	timezone := window.Timezone
+	if timezone == "" {
+		timezone = "local time"
+	}
+	return fmt.Sprintf("OUTSIDE CHANGE WINDOW: %s (%s)", why, timezone), window
+}
+
+// changeWindowConfirmation escalates how a command outside a change window is
+// confirmed, or returns an error if the window blocks it
+func changeWindowConfirmation(window *config.ChangeWindow, cluster, phrase string) (bool, string, error) {
+	switch window.Action {
+	case config.WindowBlock:
+		return true, phrase, fmt.Errorf("%s is outside its change window: make the change inside the window, or have the window changed", cluster)
+	case config.WindowTyped:
+		if phrase == "" {
+			phrase = cluster
+		}
+	}
+	return true, phrase, nil
+}
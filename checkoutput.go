@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+)
+
+// Verdicts printed by --sk-output=json
+const (
+	verdictSafe      = "safe"
+	verdictDangerous = "dangerous"
+	verdictBlocked   = "blocked"
+)
+
+// verdictExitCodes encode the verdict in safekubectl's exit code; 1 remains
+// safekubectl's own failure
+var verdictExitCodes = map[string]int{
+	verdictSafe:      0,
+	verdictDangerous: 2,
+	verdictBlocked:   3,
+}
+
+// verdictExitError makes safekubectl exit with a verdict's code once the
+// verdict has been printed
+type verdictExitError struct {
+	verdict string
+	code    int
+}
+
+func (e *verdictExitError) Error() string {
+	return fmt.Sprintf("command is %s", e.verdict)
+}
+
+// commandVerdict is the --sk-output=json document for a CLI command
+type commandVerdict struct {
+	Verdict  string `json:"verdict"`            // safe | dangerous | blocked
+	Severity string `json:"severity,omitempty"` // LOW | MEDIUM | HIGH | CRITICAL, for dangerous commands
+	Error    string `json:"error,omitempty"`    // why the command is blocked
+	*checker.CheckResult
+}
+
+// resourcesVerdict is the --sk-output=json document for a file-based command
+type resourcesVerdict struct {
+	Verdict  string `json:"verdict"`
+	Severity string `json:"severity,omitempty"`
+	Error    string `json:"error,omitempty"`
+	*checker.ResourceCheckResult
+}
+
+// checkOnlyOutput reports whether --sk-output asks for the verdict instead of
+// running the command
+func checkOnlyOutput(skFlags map[string]string) (bool, error) {
+	switch skFlags["output"] {
+	case "":
+		return false, nil
+	case "json":
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid --sk-output %q: expected json", skFlags["output"])
+}
+
+// printCommandVerdict prints the check of a CLI command as JSON; blocked is
+// the error that would stop it from running, if any
+func (r *Runner) printCommandVerdict(result *checker.CheckResult, blocked error) error {
+	v := commandVerdict{Verdict: verdictFor(result.IsDangerous, blocked), Severity: result.Severity(), CheckResult: result}
+	if blocked != nil {
+		v.Error = blocked.Error()
+	}
+	return r.printVerdict(v, v.Verdict)
+}
+
+// printResourcesVerdict prints the check of a file-based command as JSON
+func (r *Runner) printResourcesVerdict(result *checker.ResourceCheckResult, blocked error) error {
+	v := resourcesVerdict{Verdict: verdictFor(result.IsDangerous, blocked), Severity: result.Severity(), ResourceCheckResult: result}
+	if blocked != nil {
+		v.Error = blocked.Error()
+	}
+	return r.printVerdict(v, v.Verdict)
+}
+
+// verdictFor names the outcome of a check
+func verdictFor(dangerous bool, blocked error) string {
+	switch {
+	case blocked != nil:
+		return verdictBlocked
+	case dangerous:
+		return verdictDangerous
+	}
+	return verdictSafe
+}
+
+// printVerdict writes a verdict document and returns its exit code as an error
+func (r *Runner) printVerdict(v interface{}, verdict string) error {
+	enc := json.NewEncoder(r.stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode verdict: %w", err)
+	}
+	if code := verdictExitCodes[verdict]; code != 0 {
+		return &verdictExitError{verdict: verdict, code: code}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// completionCommand wraps `kubectl completion <shell>` so the generated script
+// also completes safekubectl (and any --sk-alias names)
+const completionCommand = "completion"
+
+// completionRequests are the hidden commands completion scripts call back
+// into; they are read-only and passed straight to kubectl
+var completionRequests = map[string]bool{
+	"__complete":       true,
+	"__completeNoDesc": true,
+}
+
+// completionRegistrations rewrite a script line that registers completion for
+// kubectl into one that registers it for name, per shell
+var completionRegistrations = map[string]func(line, name string) (string, bool){
+	"bash":       registerTrailingName,
+	"zsh":        registerTrailingName,
+	"fish":       registerFish,
+	"powershell": registerPowerShell,
+}
+
+// runCompletion handles `safekubectl completion <shell> [--sk-alias=k,...]`
+func (r *Runner) runCompletion(args []string, skFlags map[string]string) error {
+	if len(args) != 1 || completionRegistrations[args[0]] == nil {
+		return fmt.Errorf("usage: safekubectl %s bash|zsh|fish|powershell [--sk-alias=NAME,...]", completionCommand)
+	}
+	shell := args[0]
+
+	script, err := r.queryKubectl([]string{"completion", shell})
+	if err != nil {
+		return fmt.Errorf("kubectl completion %s failed: %w", shell, err)
+	}
+
+	names := []string{"safekubectl"}
+	for _, alias := range strings.Split(skFlags["alias"], ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			names = append(names, alias)
+		}
+	}
+
+	fmt.Fprint(r.stdout, wrapCompletion(string(script), completionRegistrations[shell], names))
+	return nil
+}
+
+// wrapCompletion copies every kubectl registration line once per name, right
+// after the original so it lands in the same branch of the script
+func wrapCompletion(script string, register func(line, name string) (string, bool), names []string) string {
+	var out strings.Builder
+	for _, line := range strings.SplitAfter(script, "\n") {
+		out.WriteString(line)
+		for _, name := range names {
+			if wrapped, ok := register(strings.TrimSuffix(line, "\n"), name); ok {
+				out.WriteString(wrapped + "\n")
+			}
+		}
+	}
+	return out.String()
+}
+
+// registerTrailingName handles bash `complete ... kubectl` and zsh `compdef _kubectl kubectl`
+func registerTrailingName(line, name string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !(strings.HasPrefix(trimmed, "complete ") || strings.HasPrefix(trimmed, "compdef ")) || !strings.HasSuffix(trimmed, " kubectl") {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimRight(line, " \t"), "kubectl") + name, true
+}
+
+// registerFish handles `complete -c kubectl ...`
+func registerFish(line, name string) (string, bool) {
+	if !strings.HasPrefix(strings.TrimSpace(line), "complete -c kubectl ") {
+		return "", false
+	}
+	return strings.Replace(line, "complete -c kubectl ", "complete -c "+name+" ", 1), true
+}
+
+// registerPowerShell handles `Register-ArgumentCompleter -CommandName 'kubectl' ...`
+func registerPowerShell(line, name string) (string, bool) {
+	if !strings.Contains(line, "Register-ArgumentCompleter") || !strings.Contains(line, "-CommandName 'kubectl'") {
+		return "", false
+	}
+	return strings.Replace(line, "-CommandName 'kubectl'", "-CommandName '"+name+"'", 1), true
+}
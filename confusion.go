@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/kubeconfig"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// flagConfusionReasons flags a namespace named after a kubeconfig context or
+// cluster, and a context the kubeconfig does not define that is named after a
+// namespace of its contexts: both usually mean -n and --context were mixed up
+// and the command lands somewhere unintended.
+func flagConfusionReasons(cmd *parser.KubectlCommand, contexts map[string]kubeconfig.Context) []string {
+	if len(contexts) == 0 {
+		return nil
+	}
+	namespaces := make(map[string]bool)
+	for _, c := range contexts {
+		if c.Namespace != "" {
+			namespaces[strings.ToLower(c.Namespace)] = true
+		}
+	}
+
+	var reasons []string
+	if cmd.Namespace != "" && !namespaces[strings.ToLower(cmd.Namespace)] {
+		if name, kind := contextNamed(cmd.Namespace, contexts); name != "" {
+			reasons = append(reasons, fmt.Sprintf("namespace %q is the name of kubeconfig %s %s: did you mean --context %s?", cmd.Namespace, kind, name, contextFor(name, kind, contexts)))
+		}
+	}
+	if cmd.Context != "" && namespaces[strings.ToLower(cmd.Context)] {
+		if _, ok := contexts[cmd.Context]; !ok {
+			reasons = append(reasons, fmt.Sprintf("context %q is not in the kubeconfig but is a namespace: did you mean -n %s?", cmd.Context, cmd.Context))
+		}
+	}
+	return reasons
+}
+
+// contextNamed returns the context, or else the cluster, named like s
+// regardless of case, and which of the two it is
+func contextNamed(s string, contexts map[string]kubeconfig.Context) (string, string) {
+	for name := range contexts {
+		if strings.EqualFold(name, s) {
+			return name, "context"
+		}
+	}
+	for _, c := range contexts {
+		if c.Cluster != "" && strings.EqualFold(c.Cluster, s) {
+			return c.Cluster, "cluster"
+		}
+	}
+	return "", ""
+}
+
+// contextFor returns the context to suggest for a context or cluster name:
+// the context itself, or the first context using the cluster
+func contextFor(name, kind string, contexts map[string]kubeconfig.Context) string {
+	if kind == "context" {
+		return name
+	}
+	var using []string
+	for contextName, c := range contexts {
+		if c.Cluster == name {
+			using = append(using, contextName)
+		}
+	}
+	sort.Strings(using)
+	return using[0]
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+)
+
+// contextBanner reminds the operator, with contextSwitch.banner, that a
+// command without --context runs against a protected current context
+func (r *Runner) contextBanner(cfg *config.Config, cmd *parser.KubectlCommand, cluster string) {
+	if cfg.ContextSwitch.Banner && cmd.Context == "" && cfg.IsProtectedCluster(cluster) {
+		prompt.DisplayContextBannerTo(r.stderr, cluster)
+	}
+}
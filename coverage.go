@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/coverage"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+)
+
+// coverageCommand is the safekubectl subcommand that reports the commands
+// safekubectl did not fully understand
+const coverageCommand = "coverage"
+
+// runCoverage handles `safekubectl coverage [PATH]`, summarizing the coverage
+// log, the configured one by default
+func (r *Runner) runCoverage(args []string, cfg *config.Config) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: safekubectl %s [PATH]", coverageCommand)
+	}
+	path := cfg.Coverage.Path
+	if len(args) == 1 {
+		path = args[0]
+	}
+	gaps, err := coverage.Summarize(path)
+	if err != nil {
+		return err
+	}
+	prompt.DisplayCoverageTo(r.stdout, path, gaps)
+	return nil
+}
+
+// recordCoverage logs what the parser did not understand about a command,
+// with coverage.enabled. A failure to log is reported and the command goes on.
+func (r *Runner) recordCoverage(cfg config.CoverageConfig, cmd *parser.KubectlCommand) {
+	if !cfg.Enabled {
+		return
+	}
+	gaps := cmd.Gaps()
+	if len(gaps) == 0 {
+		return
+	}
+	now := time.Now
+	if r.now != nil {
+		now = r.now
+	}
+	if err := coverage.Record(cfg.Path, cmd.Operation, gaps, now()); err != nil {
+		fmt.Fprintf(r.stderr, "warning: failed to record coverage gaps: %s\n", err)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"time"
+)
+
+// maxCredentialValidity is how long a client certificate may remain valid
+// before the credentials preflight calls it long-lived
+const maxCredentialValidity = 90 * 24 * time.Hour
+
+// credentialReasons flags the kubeconfig credentials of a context that stay
+// valid until someone revokes them, nudging toward exec or OIDC auth
+func (r *Runner) credentialReasons(kubeconfig, kubeContext, cluster string) []string {
+	if r.getCredentials == nil {
+		return nil
+	}
+	var reasons []string
+	for _, credential := range r.getCredentials(kubeconfig, kubeContext) {
+		reasons = append(reasons, "long-lived credentials for protected cluster "+cluster+": "+credential+"; prefer exec or OIDC auth")
+	}
+	return reasons
+}
+
+// getLongLivedCredentials describes the context's credentials that do not
+// expire on their own
+func getLongLivedCredentials(kubeconfigPath, context string) []string {
+	kc, err := loadKubeconfig(kubeconfigPath)
+	if err != nil {
+		return nil
+	}
+	return kc.LongLivedCredentials(context, time.Now(), maxCredentialValidity)
+}
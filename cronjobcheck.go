@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/cronjob"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// cronJobSuspendReasons explains `kubectl patch` commands that suspend or
+// resume CronJobs and returns the live suspend values they change, so the
+// audit log records how to restore them. CronJobs that cannot be looked up
+// are still explained, without a previous value.
+func (r *Runner) cronJobSuspendReasons(cmd *parser.KubectlCommand, namespace string) ([]string, []string) {
+	patch, patchType, ok := patchFromArgs(cmd.Args)
+	if !ok {
+		return nil, nil
+	}
+	suspend, ok := cronjob.SuspendFromPatch(patch, patchType)
+	if !ok {
+		return nil, nil
+	}
+
+	var reasons, previous []string
+	for _, t := range cmd.Targets {
+		if t.Name == "" || parser.KindFor(t.Resource) != "CronJob" {
+			continue
+		}
+		display := "cronjob/" + t.Name
+
+		out, err := r.queryKubectl(append([]string{"get", "cronjob", t.Name, "-n", namespace, "-o", "json"}, kubectlContextArgs(cmd.Context)...))
+		if err == nil {
+			if current, err := cronjob.ParseSuspend(out); err == nil {
+				if current == suspend {
+					continue // no change
+				}
+				previous = append(previous, fmt.Sprintf("%s.spec.suspend=%t", display, current))
+			}
+		}
+		reasons = append(reasons, cronjob.SuspendReason(display, suspend))
+	}
+	return reasons, previous
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/zufardhiyaulhaq/safekubectl/internal/disruption"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/drain"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// disruptionReasons warns when deleting named pods or draining nodes takes a
+// PodDisruptionBudget below its desired healthy pods or leaves a controller
+// with no ready replicas. Pods and PDBs are listed in the namespace for a
+// delete, and across all namespaces for a drain. Lookups that fail produce
+// no reasons.
+func (r *Runner) disruptionReasons(cmd *parser.KubectlCommand, namespace string) []string {
+	var scope []string
+	var names []string
+	switch cmd.Operation {
+	case "delete":
+		for _, t := range cmd.Targets {
+			if t.Name != "" && parser.KindFor(t.Resource) == "Pod" {
+				names = append(names, t.Name)
+			}
+		}
+		scope = []string{"-n", namespace}
+	case "drain":
+		names = commandNodes(cmd)
+		scope = []string{"-A"}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	contextArgs := kubectlContextArgs(cmd.Context)
+	out, err := r.queryKubectl(append(append([]string{"get", "pods", "-o", "json"}, scope...), contextArgs...))
+	if err != nil {
+		return nil
+	}
+	pods, err := drain.ParsePods(out)
+	if err != nil {
+		return nil
+	}
+	out, err = r.queryKubectl(append(append([]string{"get", "pdb", "-o", "json"}, scope...), contextArgs...))
+	if err != nil {
+		return nil
+	}
+	pdbs, err := drain.ParsePDBs(out)
+	if err != nil {
+		return nil
+	}
+
+	if cmd.Operation == "drain" {
+		return disruption.Reasons(disruption.OnNodes(pods, names), pods, pdbs, true)
+	}
+	return disruption.Reasons(disruption.Named(pods, namespace, names), pods, pdbs, false)
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/audit"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/drain"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+)
+
+// drainPlanCommand is the safekubectl subcommand that drains nodes in a planned order
+const drainPlanCommand = "drain-plan"
+
+// pendingPodPollInterval is how often pending pods are counted while waiting between drains
+const pendingPodPollInterval = 10 * time.Second
+
+// runDrainPlan handles `safekubectl drain-plan <selector> [drain flags...]`.
+// It computes a drain order, confirms it once, then drains node by node.
+// Nodes with a PDB that allows no disruptions are planned last, and the
+// drain stops before one that is still blocked when its turn comes.
+// skFlags carries --sk-ticket for protected clusters.
+func (r *Runner) runDrainPlan(args []string, cfg *config.Config, skFlags map[string]string) error {
+	if len(args) == 0 || args[0] == "" || args[0][0] == '-' {
+		return fmt.Errorf("usage: safekubectl %s <selector> [drain flags...]", drainPlanCommand)
+	}
+	selector := args[0]
+	drainFlags := args[1:]
+
+	// Parse the drain flags as a drain command to pick up --context and --kubeconfig
+	cmd := parser.Parse(append([]string{"drain"}, drainFlags...))
+	if cmd.Kubeconfig != "" {
+		r = r.withKubeconfig(cmd.Kubeconfig)
+	}
+	cluster := cmd.Context
+	if cluster == "" {
+		cluster = r.getCluster(cmd.Kubeconfig)
+	}
+	if len(cfg.ProtectedClusters) > 0 && r.getClusterServer != nil {
+		clusterName, server := r.getClusterServer(cmd.Kubeconfig, cmd.Context)
+		cfg.ResolveProtectedCluster(cluster, clusterName, server)
+	}
+	contextArgs := kubectlContextArgs(cmd.Context)
+
+	plan, err := r.buildDrainPlan(selector, contextArgs)
+	if err != nil {
+		return err
+	}
+	if len(plan.Steps) == 0 {
+		return fmt.Errorf("no nodes match selector %q", selector)
+	}
+
+	auditLogger := audit.New(cfg)
+	var nodes []string
+	for _, step := range plan.Steps {
+		nodes = append(nodes, "node/"+step.Node.Name)
+	}
+	planned := &checker.CheckResult{Operation: "drain", Resources: nodes, Cluster: cluster, IsNodeScoped: true}
+	logDenied := func() {
+		if err := auditLogger.Log(planned, append([]string{drainPlanCommand}, args...), false, false); err != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", err)
+		}
+	}
+
+	// An incident freeze stops the whole plan before it is offered
+	if err := r.freezeBlock(cfg.Freeze, cluster); err != nil {
+		logDenied()
+		return err
+	}
+
+	// Protected nodes are refused with blockProtectedNodes, and called out otherwise
+	for _, step := range plan.Steps {
+		if cfg.IsProtectedNode(step.Node.Name) {
+			planned.ProtectedNodes = append(planned.ProtectedNodes, step.Node.Name)
+			planned.Reasons = append(planned.Reasons, "protected node: "+step.Node.Name)
+		}
+	}
+	if err := protectedNodeBlock(cfg, planned.ProtectedNodes); err != nil {
+		logDenied()
+		return err
+	}
+
+	// Protected clusters may only be drained inside their change windows
+	if reason, window := r.outsideChangeWindow(cfg, cluster); window != nil {
+		planned.Reasons = append(planned.Reasons, reason)
+		var err error
+		_, planned.ConfirmationPhrase, err = changeWindowConfirmation(window, cluster, "")
+		if err != nil {
+			logDenied()
+			return err
+		}
+	}
+
+	prompt.DisplayDrainPlanTo(r.stdout, plan, cluster, drainFlags, planned.Reasons)
+	prompt.DisplayDrainPacingTo(r.stdout, cfg.Drain)
+
+	// Drains of protected clusters are made under a change ticket, checked
+	// before the plan is confirmed
+	ticketID, err := r.changeTicket(cfg.Ticket, cfg.IsProtectedCluster(cluster), skFlags["ticket"])
+	if err != nil {
+		logDenied()
+		return err
+	}
+	planned.Ticket = ticketID
+
+	// A multi-node drain is always confirmed, regardless of mode
+	var confirmed bool
+	if planned.ConfirmationPhrase != "" {
+		confirmed = prompt.AskTypedConfirmationFrom(r.stdin, r.stdout, planned.ConfirmationPhrase)
+	} else {
+		confirmed = prompt.AskConfirmationFrom(r.stdin, r.stdout)
+	}
+	if !confirmed {
+		prompt.DisplayAbortedTo(r.stdout)
+		logDenied()
+		return nil
+	}
+
+	for i, step := range plan.Steps {
+		if len(step.BlockingPDB) > 0 {
+			blocking, err := r.blockingPDBs(selector, step.Node.Name, contextArgs)
+			if err != nil {
+				return fmt.Errorf("stopped before draining %s (%d/%d): %w", step.Node.Name, i+1, len(plan.Steps), err)
+			}
+			if len(blocking) > 0 {
+				return fmt.Errorf("stopped before draining %s (%d/%d): %s allows no disruptions, so its evictions would block", step.Node.Name, i+1, len(plan.Steps), describePDBs(blocking))
+			}
+		}
+
+		prompt.DisplayDrainProgressTo(r.stdout, i+1, len(plan.Steps), step.Node.Name)
+
+		drainArgs := append([]string{"drain", step.Node.Name}, drainFlags...)
+		result := &checker.CheckResult{
+			Operation:    "drain",
+			Resources:    []string{"node/" + step.Node.Name},
+			Cluster:      cluster,
+			IsNodeScoped: true,
+			IsDangerous:  true,
+			Reasons:      planned.Reasons,
+			Ticket:       planned.Ticket,
+		}
+
+		// Policy hooks see, and may veto, every node's drain
+		if err := r.runPreExecHooks(cfg.Hooks.PreExec, commandHookEvent(result, drainArgs)); err != nil {
+			if logErr := auditLogger.Log(result, drainArgs, true, false); logErr != nil {
+				fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+			}
+			return fmt.Errorf("stopped before draining %s (%d/%d): %w", step.Node.Name, i+1, len(plan.Steps), err)
+		}
+		execution, err := r.execute(drainArgs)
+		if logErr := auditLogger.LogExecuted(result, drainArgs, true, execution); logErr != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+		}
+		r.runPostExecHooks(cfg.Hooks.PostExec, commandHookEvent(result, drainArgs), execution)
+		if err != nil {
+			return fmt.Errorf("drain of node %s failed (%d/%d): %w", step.Node.Name, i+1, len(plan.Steps), err)
+		}
+
+		// Pace the operation before touching the next node
+		if i < len(plan.Steps)-1 {
+			if err := r.waitBetweenDrains(cfg.Drain, contextArgs); err != nil {
+				return fmt.Errorf("stopped after draining %s (%d/%d): %w", step.Node.Name, i+1, len(plan.Steps), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// waitBetweenDrains pauses for the configured time, then waits until the
+// cluster has fewer pending pods than the configured maximum
+func (r *Runner) waitBetweenDrains(pacing config.DrainConfig, contextArgs []string) error {
+	if pacing.PauseBetweenNodes > 0 {
+		prompt.DisplayDrainPauseTo(r.stdout, pacing.PauseBetweenNodes)
+		r.sleep(pacing.PauseBetweenNodes)
+	}
+
+	if pacing.MaxPendingPods <= 0 {
+		return nil
+	}
+
+	var waited time.Duration
+	for {
+		pending, err := r.countPendingPods(contextArgs)
+		if err != nil {
+			return err
+		}
+		if pending < pacing.MaxPendingPods {
+			return nil
+		}
+		if waited >= pacing.HealthCheckTimeout {
+			return fmt.Errorf("%d pods still pending after %s (limit %d)", pending, pacing.HealthCheckTimeout, pacing.MaxPendingPods)
+		}
+		prompt.DisplayPendingPodsTo(r.stdout, pending, pacing.MaxPendingPods)
+		r.sleep(pendingPodPollInterval)
+		waited += pendingPodPollInterval
+	}
+}
+
+// countPendingPods returns the number of Pending pods across all namespaces
+func (r *Runner) countPendingPods(contextArgs []string) (int, error) {
+	out, err := r.queryKubectl(append([]string{"get", "pods", "-A", "--field-selector=status.phase=Pending", "-o", "name"}, contextArgs...))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pending pods: %w", err)
+	}
+	return len(strings.Fields(string(out))), nil
+}
+
+// blockingPDBs re-reads the cluster and returns the PDBs that still allow no
+// disruptions for pods on the node
+func (r *Runner) blockingPDBs(selector, node string, contextArgs []string) ([]drain.PDB, error) {
+	plan, err := r.buildDrainPlan(selector, contextArgs)
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range plan.Steps {
+		if step.Node.Name == node {
+			return step.BlockingPDB, nil
+		}
+	}
+	return nil, nil
+}
+
+// describePDBs names PDBs as poddisruptionbudget/NAME in NAMESPACE
+func describePDBs(pdbs []drain.PDB) string {
+	names := make([]string, 0, len(pdbs))
+	for _, pdb := range pdbs {
+		names = append(names, fmt.Sprintf("poddisruptionbudget/%s in %s", pdb.Name, pdb.Namespace))
+	}
+	return strings.Join(names, ", ")
+}
+
+// buildDrainPlan queries nodes, pods and PDBs and computes the drain order
+func (r *Runner) buildDrainPlan(selector string, contextArgs []string) (*drain.Plan, error) {
+	out, err := r.queryKubectl(append([]string{"get", "nodes", "-l", selector, "-o", "json"}, contextArgs...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	nodes, err := drain.ParseNodes(out)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err = r.queryKubectl(append([]string{"get", "pods", "-A", "-o", "json"}, contextArgs...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	pods, err := drain.ParsePods(out)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err = r.queryKubectl(append([]string{"get", "pdb", "-A", "-o", "json"}, contextArgs...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pod disruption budgets: %w", err)
+	}
+	pdbs, err := drain.ParsePDBs(out)
+	if err != nil {
+		return nil, err
+	}
+
+	return drain.BuildPlan(selector, nodes, pods, pdbs), nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/zufardhiyaulhaq/safekubectl/internal/drain"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// commandNodes returns the nodes a drain or cordon names, as NAME or node/NAME
+func commandNodes(cmd *parser.KubectlCommand) []string {
+	return cmd.NodeNames()
+}
+
+// drainEvictions lists the pods on each drained node the way kubectl drain
+// does before evicting them. Nodes whose pods cannot be listed are left out.
+func (r *Runner) drainEvictions(cmd *parser.KubectlCommand) []drain.Evictions {
+	var evictions []drain.Evictions
+	for _, node := range commandNodes(cmd) {
+		out, err := r.queryKubectl(append([]string{"get", "pods", "-A", "--field-selector", "spec.nodeName=" + node, "-o", "json"}, kubectlContextArgs(cmd.Context)...))
+		if err != nil {
+			continue
+		}
+		pods, err := drain.ParsePods(out)
+		if err != nil {
+			continue
+		}
+		evictions = append(evictions, drain.Summarize(node, pods))
+	}
+	return evictions
+}
+
+// drainReasons explains the pods kubectl drain would stop at with the
+// command's flags
+func drainReasons(evictions []drain.Evictions, args []string) []string {
+	force := hasFlag(args, "--force")
+	deleteEmptyDir := hasFlag(args, "--delete-emptydir-data") || hasFlag(args, "--delete-local-data")
+	ignoreDaemonSets := hasFlag(args, "--ignore-daemonsets")
+	var reasons []string
+	for _, e := range evictions {
+		reasons = append(reasons, e.Reasons(force, deleteEmptyDir, ignoreDaemonSets)...)
+	}
+	return reasons
+}
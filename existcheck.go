@@ -0,0 +1,41 @@
+package main
+
+import (
+	"time"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/object"
+)
+
+// existenceReasons looks up each object a delete names and describes it:
+// targets that do not exist are likely typos, and an object's age and owner
+// show what is really being removed. Lookups that fail for other reasons,
+// such as RBAC, produce no reasons.
+func (r *Runner) existenceReasons(targets []backupTarget, kubeContext string) []string {
+	var reasons []string
+	for _, t := range targets {
+		args := []string{"get", t.kind, t.name, "-o", "json"}
+		if t.namespace != "" {
+			args = append(args, "-n", t.namespace)
+		}
+		out, err := r.queryKubectl(append(args, kubectlContextArgs(kubeContext)...))
+		if object.IsNotFound(err) {
+			where := ""
+			if t.namespace != "" {
+				where = " in namespace " + t.namespace
+			}
+			reasons = append(reasons, t.display()+" does not exist"+where+": check the name")
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		summary, err := object.Parse(out)
+		if err != nil {
+			continue
+		}
+		if description := object.Describe(summary, time.Now()); description != "" {
+			reasons = append(reasons, t.display()+": "+description)
+		}
+	}
+	return reasons
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/batch"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+)
+
+// batchCommand is the change a batch tracks: the command without --context,
+// which is what changes from one iteration of a per-context loop to the next
+func batchCommand(args []string) string {
+	return strings.Join(withoutContextArgs(args), " ")
+}
+
+// openBatch loads the --sk-batch batch, or returns nil without one. A batch
+// that cannot be read is reported and ignored; it never blocks the command.
+func (r *Runner) openBatch(id, dir string) *batch.Batch {
+	if id == "" {
+		return nil
+	}
+	b, err := batch.Load(dir, id)
+	if err != nil {
+		fmt.Fprintf(r.stderr, "warning: batch not tracked: %s\n", err)
+		return nil
+	}
+	return b
+}
+
+// batchReasons warns when a command joins a batch that started as a
+// different change
+func batchReasons(b *batch.Batch, args []string) []string {
+	if b == nil || !b.Differs(batchCommand(args)) {
+		return nil
+	}
+	return []string{fmt.Sprintf("batch %s: not the change confirmed on the other clusters (kubectl %s)", b.ID, b.Command)}
+}
+
+// showBatchProgress prints how many clusters of the batch have confirmed the
+// change, out of the contexts in the kubeconfig when they can be counted
+func (r *Runner) showBatchProgress(b *batch.Batch, kubeconfig, cluster string) {
+	if b == nil {
+		return
+	}
+	var confirmed, denied int
+	for name, c := range b.Clusters {
+		switch {
+		case name == cluster:
+		case c:
+			confirmed++
+		default:
+			denied++
+		}
+	}
+	total := 0
+	if r.getContexts != nil {
+		total = len(r.getContexts(kubeconfig))
+	}
+	prompt.DisplayBatchProgressTo(r.stdout, b.ID, confirmed, denied, total)
+}
+
+// recordBatch stores whether the change was confirmed on cluster
+func (r *Runner) recordBatch(b *batch.Batch, dir, cluster string, args []string, confirmed bool) {
+	if b == nil {
+		return
+	}
+	b.Record(cluster, batchCommand(args), confirmed)
+	if err := b.Save(dir); err != nil {
+		fmt.Fprintf(r.stderr, "warning: batch not tracked: %s\n", err)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/freeze"
+)
+
+// freezeBlock returns an error when an incident freeze covering cluster is
+// active. A failed lookup of the freeze file or endpoint blocks the command
+// too, unless freeze.failOpen lets it run with a warning.
+func (r *Runner) freezeBlock(cfg config.FreezeConfig, cluster string) error {
+	if (cfg.File == "" && cfg.URL == "") || !cfg.Covers(cluster) || r.getFreeze == nil {
+		return nil
+	}
+	frozen, reason, err := r.getFreeze(cfg)
+	if err != nil && cfg.FailOpen {
+		fmt.Fprintf(r.stderr, "warning: failed to check for a change freeze: %s\n", err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check for a change freeze on %s, so dangerous commands are blocked: %w", orUnknown(cluster), err)
+	}
+	if !frozen {
+		return nil
+	}
+	if reason == "" {
+		reason = "no reason given"
+	}
+	return fmt.Errorf("changes to %s are frozen (%s): dangerous commands are blocked until the freeze is lifted", orUnknown(cluster), reason)
+}
+
+// lookupFreeze reports whether the configured freeze file or endpoint
+// announces a freeze, and why
+func lookupFreeze(cfg config.FreezeConfig) (bool, string, error) {
+	return freeze.Status(cfg.File, cfg.URL)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/audit"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/gitops"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+)
+
+// gitopsOperations change live objects in ways a GitOps controller reverts
+var gitopsOperations = map[string]bool{
+	"delete":   true,
+	"patch":    true,
+	"edit":     true,
+	"scale":    true,
+	"set":      true,
+	"label":    true,
+	"annotate": true,
+}
+
+// manifestGitOpsOperations are the file-based operations whose targets may
+// be reconciled back by a GitOps controller
+var manifestGitOpsOperations = map[string]bool{
+	"apply":   true,
+	"replace": true,
+	"delete":  true,
+}
+
+// gitopsTarget is a live object whose GitOps manager is looked up
+type gitopsTarget struct {
+	resource  string // as typed on the command line, or the manifest kind
+	name      string
+	namespace string // empty for cluster-scoped objects
+}
+
+// commandGitOpsTargets returns the named targets of a command
+func commandGitOpsTargets(cmd *parser.KubectlCommand, namespace string) []gitopsTarget {
+	var targets []gitopsTarget
+	for _, t := range cmd.Targets {
+		if t.Name == "" {
+			continue
+		}
+		target := gitopsTarget{resource: t.Resource, name: t.Name}
+		if !parser.IsClusterScopedKind(parser.KindFor(t.Resource)) {
+			target.namespace = namespace
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// manifestGitOpsTargets returns the named resources of a manifest
+func manifestGitOpsTargets(resources []manifest.Resource) []gitopsTarget {
+	var targets []gitopsTarget
+	for _, res := range resources {
+		if res.Name != "" {
+			targets = append(targets, gitopsTarget{resource: strings.ToLower(res.Kind), name: res.Name, namespace: res.Namespace})
+		}
+	}
+	return targets
+}
+
+// gitopsManagers looks up each target and returns why the change will be
+// reverted, plus the distinct Flux/Argo CD objects that manage them. With
+// suspend, the reasons point at suspending reconciliation, otherwise at the
+// Git repository. Targets that cannot be looked up are skipped.
+func (r *Runner) gitopsManagers(targets []gitopsTarget, kubeContext, argoNamespace string, suspend bool) ([]string, []gitops.Manager) {
+	var reasons []string
+	var managers []gitops.Manager
+	seen := map[gitops.Manager]bool{}
+
+	for _, t := range targets {
+		args := []string{"get", t.resource, t.name, "-o", "json"}
+		if t.namespace != "" {
+			args = append(args, "-n", t.namespace)
+		}
+		out, err := r.queryKubectl(append(args, kubectlContextArgs(kubeContext)...))
+		if err != nil {
+			continue
+		}
+		manager, ok, err := gitops.ManagerOf(out, argoNamespace)
+		if err != nil || !ok {
+			continue
+		}
+		if suspend {
+			reasons = append(reasons, fmt.Sprintf("gitops: %s/%s is reconciled by %s: the change is reverted unless reconciliation is suspended", t.resource, t.name, manager))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("gitops: %s/%s is reconciled by %s: the change will be reverted; make it in the Git repository instead", t.resource, t.name, manager))
+		}
+		if !seen[manager] {
+			seen[manager] = true
+			managers = append(managers, manager)
+		}
+	}
+	return reasons, managers
+}
+
+// suspendReconciliation offers to suspend each manager before the change is
+// made and returns the ones that were suspended. Suspensions are audited.
+func (r *Runner) suspendReconciliation(managers []gitops.Manager, kubeContext, cluster string, auditLogger *audit.Logger) []gitops.Manager {
+	contextArgs := kubectlContextArgs(kubeContext)
+
+	var suspended []gitops.Manager
+	for _, m := range managers {
+		suspendArgs := append(m.SuspendArgs(), contextArgs...)
+		prompt.DisplaySuspendOfferTo(r.stdout, m.String(), suspendArgs)
+		if !prompt.AskConfirmationFrom(r.stdin, r.stdout) {
+			continue
+		}
+
+		result := &checker.CheckResult{
+			Operation: suspendArgs[0],
+			Resources: []string{strings.ToLower(m.Kind) + "/" + m.Name},
+			Namespace: m.Namespace,
+			Cluster:   cluster,
+		}
+		execution, err := r.execute(suspendArgs)
+		if logErr := auditLogger.LogExecuted(result, suspendArgs, true, execution); logErr != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+		}
+		if err != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to suspend %s: %s\n", m, err)
+			continue
+		}
+		suspended = append(suspended, m)
+	}
+	return suspended
+}
+
+// remindResume tells the user how to resume each suspended manager
+func (r *Runner) remindResume(suspended []gitops.Manager, kubeContext string) {
+	for _, m := range suspended {
+		prompt.DisplayResumeReminderTo(r.stdout, m.String(), append(m.ResumeArgs(), kubectlContextArgs(kubeContext)...))
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/groups"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/oncall"
+)
+
+// groupPolicy returns the group policy that applies to the operator on
+// cluster, if any, the reason to show for it, and the on-call roster decision
+// to audit. A failed group lookup is reported and leaves the usual
+// confirmation in place.
+func (r *Runner) groupPolicy(cfg *config.Config, cluster string) (*config.GroupPolicy, string, string) {
+	if len(cfg.GroupPolicies) == 0 || r.getGroups == nil {
+		return nil, "", ""
+	}
+	memberOf, err := r.getGroups(cfg.Groups)
+	if err != nil {
+		fmt.Fprintf(r.stderr, "warning: failed to look up groups, group policies not applied: %s\n", err)
+		return nil, "", ""
+	}
+	roster := r.onCallRoster(cfg.OnCall, cluster)
+	if roster == rosterOnCall {
+		memberOf = append(memberOf, config.OnCallGroup)
+	}
+	policy, group := cfg.GroupPolicyFor(memberOf, cluster)
+	if policy == nil {
+		return nil, "", roster
+	}
+	if group == "*" {
+		group = "everyone"
+	}
+	return policy, fmt.Sprintf("group policy: %s: %s", group, policy.Mode), roster
+}
+
+// On-call roster decisions recorded in the audit log
+const (
+	rosterOnCall  = "on-call"  // the operator is on call for the cluster
+	rosterOffCall = "off-call" // someone else is
+	rosterUnknown = "unknown"  // the roster could not be fetched
+)
+
+// onCallRoster decides whether the operator is on call for cluster. It
+// returns "" when no schedule is configured for the cluster. A failed lookup
+// is reported, and the operator is not treated as on call.
+func (r *Runner) onCallRoster(cfg config.OnCallConfig, cluster string) string {
+	schedule, ok := cfg.Schedules[cluster]
+	if !ok || r.getOnCall == nil {
+		return ""
+	}
+	emails, err := r.getOnCall(cfg, schedule)
+	if err != nil {
+		fmt.Fprintf(r.stderr, "warning: failed to look up who is on call for %s: %s\n", cluster, err)
+		return rosterUnknown
+	}
+	for _, email := range emails {
+		if strings.EqualFold(email, cfg.Email) {
+			return rosterOnCall
+		}
+	}
+	return rosterOffCall
+}
+
+// groupConfirmation applies a group policy to how a dangerous command is
+// confirmed. Warn-only lifts only the confirmation asked for by mode or a
+// protected namespace or cluster (byMode); protected kinds and nodes, -A,
+// --prune and the like are still confirmed, and typing the namespaces of a
+// namespace deletion is never lifted.
+func groupConfirmation(policy *config.GroupPolicy, cluster string, requiresConfirmation, byMode bool, phrase string) (bool, string) {
+	switch policy.Mode {
+	case config.ModeWarnOnly:
+		return (requiresConfirmation && !byMode) || phrase != "", phrase
+	case config.ModeTyped:
+		if phrase == "" {
+			phrase = cluster
+		}
+		return true, phrase
+	case config.ModeConfirm:
+		return true, phrase
+	}
+	return requiresConfirmation, phrase
+}
+
+// lookupOnCall returns the emails of the users on call for schedule
+func lookupOnCall(cfg config.OnCallConfig, schedule string) ([]string, error) {
+	token := os.Getenv(cfg.TokenEnv)
+	if token == "" {
+		return nil, fmt.Errorf("%s is not set", cfg.TokenEnv)
+	}
+	return oncall.OnCall(cfg.Provider, token, schedule)
+}
+
+// lookupGroups returns the operator's groups from the configured source
+func lookupGroups(cfg config.GroupsConfig) ([]string, error) {
+	if cfg.Source != config.GroupSourceOIDC {
+		return groups.Local()
+	}
+	out, err := exec.Command(cfg.TokenCommand[0], cfg.TokenCommand[1:]...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("groups.tokenCommand failed: %w", err)
+	}
+	return groups.Userinfo(cfg.UserinfoURL, strings.TrimSpace(string(out)), cfg.Claim)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/audit"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+)
+
+// Hook stages, as reported in hookEvent.Stage
+const (
+	hookPreExec  = "preExec"
+	hookPostExec = "postExec"
+)
+
+// hookEvent is the JSON a pre-exec or post-exec hook reads on stdin
+type hookEvent struct {
+	Stage         string   `json:"stage"` // preExec | postExec
+	Operation     string   `json:"operation"`
+	Resources     []string `json:"resources"` // TYPE/NAME, or KIND/NAME@NAMESPACE for file-based commands
+	Namespace     string   `json:"namespace"` // empty for file-based commands
+	Cluster       string   `json:"cluster"`
+	AllNamespaces bool     `json:"allNamespaces"`
+	Identity      string   `json:"identity,omitempty"`
+	Dangerous     bool     `json:"dangerous"`
+	Reasons       []string `json:"reasons"`
+	Args          []string `json:"args"`
+	ExitCode      *int     `json:"exitCode,omitempty"` // postExec only
+	Duration      string   `json:"duration,omitempty"` // postExec only
+}
+
+// commandHookEvent describes a CLI command to hooks
+func commandHookEvent(result *checker.CheckResult, args []string) hookEvent {
+	return hookEvent{
+		Operation:     result.Operation,
+		Resources:     result.Resources,
+		Namespace:     result.Namespace,
+		Cluster:       result.Cluster,
+		AllNamespaces: result.IsAllNamespaces,
+		Identity:      result.Identity,
+		Dangerous:     result.IsDangerous,
+		Reasons:       result.Reasons,
+		Args:          args,
+	}
+}
+
+// resourcesHookEvent describes a file-based command to hooks
+func resourcesHookEvent(result *checker.ResourceCheckResult, args []string) hookEvent {
+	var resources []string
+	for _, res := range result.Resources {
+		resources = append(resources, fmt.Sprintf("%s/%s@%s", res.Kind, res.Name, res.Namespace))
+	}
+	return hookEvent{
+		Operation: result.Operation,
+		Resources: resources,
+		Cluster:   result.Cluster,
+		Identity:  result.Identity,
+		Dangerous: result.IsDangerous,
+		Reasons:   result.Reasons,
+		Args:      args,
+	}
+}
+
+// runPreExecHooks runs each pre-exec hook in turn; the first to exit non-zero
+// blocks the command, with its stderr as the reason
+func (r *Runner) runPreExecHooks(hooks [][]string, event hookEvent) error {
+	event.Stage = hookPreExec
+	for _, hook := range hooks {
+		if err := r.runEventHook(hook, event); err != nil {
+			return fmt.Errorf("blocked by pre-exec hook %s: %w", strings.Join(hook, " "), err)
+		}
+	}
+	return nil
+}
+
+// runPostExecHooks runs each post-exec hook with the command's outcome. The
+// command has already run, so failures are only reported.
+func (r *Runner) runPostExecHooks(hooks [][]string, event hookEvent, execution audit.Execution) {
+	event.Stage = hookPostExec
+	code := execution.ExitCode
+	event.ExitCode = &code
+	event.Duration = execution.Duration.Round(time.Millisecond).String()
+	for _, hook := range hooks {
+		if err := r.runEventHook(hook, event); err != nil {
+			fmt.Fprintf(r.stderr, "warning: post-exec hook %s failed: %s\n", strings.Join(hook, " "), err)
+		}
+	}
+}
+
+// runWithHooks runs the pre-exec hooks, then the command, then the post-exec
+// hooks with its outcome
+func (r *Runner) runWithHooks(hooks config.HooksConfig, event hookEvent, run func() error) error {
+	if err := r.runPreExecHooks(hooks.PreExec, event); err != nil {
+		return err
+	}
+	start := time.Now()
+	err := run()
+	r.runPostExecHooks(hooks.PostExec, event, audit.Execution{ExitCode: exitCode(err), Duration: time.Since(start)})
+	return err
+}
+
+// runEventHook runs one hook with the event as JSON on stdin
+func (r *Runner) runEventHook(hook []string, event hookEvent) error {
+	input, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode hook input: %w", err)
+	}
+	_, err = r.runHook(hook, nil, input)
+	return err
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// connection is how kubectl reaches the API server for a context, as far as
+// its kubeconfig says
+type connection struct {
+	server   string   // API server URL
+	proxyURL string   // the cluster's proxy-url, overriding the environment's
+	as       string   // user the kubeconfig user impersonates
+	asGroups []string // groups it impersonates
+	asUID    string   // UID it impersonates
+}
+
+// getConnection reads the API server, proxy and impersonation settings of a
+// context from the kubeconfig
+func getConnection(kubeconfigPath, context string) connection {
+	kc, err := loadKubeconfig(kubeconfigPath)
+	if err != nil {
+		return connection{}
+	}
+	cluster, server := kc.Server(context)
+	conn := connection{server: server, proxyURL: kc.Clusters[cluster].ProxyURL}
+	if u, ok := kc.User(context); ok {
+		conn.as, conn.asGroups, conn.asUID = u.As, u.AsGroups, u.AsUID
+	}
+	return conn
+}
+
+// interception returns the proxy the command's requests go through and whom
+// it impersonates, since either changes who the API server sees acting.
+// --as, --as-group and --as-uid override the kubeconfig user's settings.
+func (r *Runner) interception(args []string, kubeconfig, kubeContext string) (string, []string) {
+	if r.getConnection == nil {
+		return "", nil
+	}
+	conn := r.getConnection(kubeconfig, kubeContext)
+
+	proxy := conn.proxyURL
+	if proxy == "" {
+		proxy = environmentProxy(conn.server)
+	}
+	if u, err := url.Parse(proxy); err == nil && u.User != nil {
+		proxy = u.Redacted()
+	}
+
+	as, groups, uid := conn.as, conn.asGroups, conn.asUID
+	if values := flagValues(args, "--as"); len(values) > 0 {
+		as = values[len(values)-1]
+	}
+	if values := flagValues(args, "--as-group"); len(values) > 0 {
+		groups = values
+	}
+	if values := flagValues(args, "--as-uid"); len(values) > 0 {
+		uid = values[len(values)-1]
+	}
+	var actingAs []string
+	if as != "" {
+		actingAs = append(actingAs, "user:"+as)
+	}
+	for _, group := range groups {
+		actingAs = append(actingAs, "group:"+group)
+	}
+	if uid != "" {
+		actingAs = append(actingAs, "uid:"+uid)
+	}
+	return proxy, actingAs
+}
+
+// interceptionReasons describes a proxy and impersonation for the warning
+func interceptionReasons(proxy string, actingAs []string) []string {
+	var reasons []string
+	if proxy != "" {
+		reasons = append(reasons, "proxy: API server traffic goes through "+proxy)
+	}
+	if len(actingAs) > 0 {
+		reasons = append(reasons, "impersonation: the command runs as "+strings.Join(actingAs, ", "))
+	}
+	return reasons
+}
+
+// environmentProxy returns the proxy HTTPS_PROXY or HTTP_PROXY set for the
+// API server, honoring NO_PROXY the way kubectl does. Unknown servers are
+// assumed to be https.
+func environmentProxy(server string) string {
+	u, err := url.Parse(server)
+	if err != nil {
+		return ""
+	}
+	proxy := getenvEither("HTTPS_PROXY", "https_proxy")
+	if u.Scheme == "http" {
+		proxy = getenvEither("HTTP_PROXY", "http_proxy")
+	}
+	if proxy == "" || (u.Hostname() != "" && noProxy(u.Hostname(), getenvEither("NO_PROXY", "no_proxy"))) {
+		return ""
+	}
+	return proxy
+}
+
+// noProxy reports whether host matches an entry of a NO_PROXY list: "*", an
+// IP address or CIDR, or a domain matching itself and its subdomains
+func noProxy(host, list string) bool {
+	ip := net.ParseIP(host)
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if h, _, err := net.SplitHostPort(entry); err == nil {
+			entry = h
+		}
+		entry = strings.TrimPrefix(strings.TrimPrefix(entry, "*"), ".")
+		if strings.EqualFold(host, entry) || strings.HasSuffix(strings.ToLower(host), "."+strings.ToLower(entry)) {
+			return true
+		}
+	}
+	return false
+}
+
+// getenvEither returns the first of the environment variables that is set
+func getenvEither(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// flagValues returns every value of a kubectl flag given as "flag value" or
+// "flag=value" ahead of any "--" separator
+func flagValues(args []string, flag string) []string {
+	var values []string
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return values
+		case arg == flag && i+1 < len(args):
+			values = append(values, args[i+1])
+		case strings.HasPrefix(arg, flag+"="):
+			values = append(values, strings.TrimPrefix(arg, flag+"="))
+		}
+	}
+	return values
+}
@@ -4,17 +4,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/user"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
 	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
 )
 
 // Logger handles audit logging
 type Logger struct {
 	config *config.Config
+	stdin  *Stdin // manifest content read from stdin, recorded in file-based entries
 }
 
 // New creates a new audit Logger
@@ -26,27 +31,147 @@ func New(cfg *config.Config) *Logger {
 
 // Entry is a single audit record, rendered as text or JSON.
 type Entry struct {
-	Timestamp string   `json:"timestamp"`
-	Status    string   `json:"status"` // EXECUTED | DENIED
-	Operation string   `json:"operation"`
-	Resources []string `json:"resources"`
-	Namespace string   `json:"namespace"` // empty for file-based commands
-	Cluster   string   `json:"cluster"`
-	Confirmed bool     `json:"confirmed"`
-	Command   string   `json:"command"`
+	ID            string        `json:"id,omitempty"` // set when the log is read; see EntryID
+	Timestamp     string        `json:"timestamp"`
+	Status        string        `json:"status"` // EXECUTED | DENIED | RECREATED | NOT_RECREATED
+	Operation     string        `json:"operation"`
+	Resources     []string      `json:"resources"`
+	Namespace     string        `json:"namespace"` // empty for file-based commands
+	Cluster       string        `json:"cluster"`
+	User          string        `json:"user,omitempty"`        // OS account that ran safekubectl
+	Host          string        `json:"host,omitempty"`        // machine it ran on
+	SSHClient     string        `json:"sshClient,omitempty"`   // client address from SSH_CLIENT, for remote sessions
+	Proxy         string        `json:"proxy,omitempty"`       // proxy kubectl reached the API server through
+	ActingAs      []string      `json:"impersonate,omitempty"` // impersonated user, groups and UID, e.g. "user:alice"
+	Confirmed     bool          `json:"confirmed"`
+	Variant       string        `json:"variant,omitempty"`       // warning experiment variant shown, with warningExperiments
+	Roster        string        `json:"roster,omitempty"`        // on-call roster decision behind a group policy, with onCall
+	Ticket        string        `json:"ticket,omitempty"`        // change ticket the operator gave, with ticket.required
+	Justification string        `json:"justification,omitempty"` // why the operator made the change, with audit.requireReason
+	Previous      []string      `json:"previous,omitempty"`      // values the command changed, for restoring
+	Debug         *parser.Debug `json:"debug,omitempty"`         // node or pod a kubectl debug command attached to, and the image it ran
+	Backups       []string      `json:"backups,omitempty"`       // backups taken before the command ran, as named by the backup hook
+	Impact        *Impact       `json:"impact,omitempty"`        // what a denied operation would have affected, with audit.deniedImpact
+	Stdin         *Stdin        `json:"stdin,omitempty"`         // manifest content the command read from stdin (-f -)
+	ExitCode      *int          `json:"exitCode,omitempty"`      // kubectl exit code; only set once the command has run
+	Duration      string        `json:"duration,omitempty"`      // wall-clock time kubectl took to run
+	Output        *Output       `json:"output,omitempty"`        // what the command printed, with audit.captureOutput
+	PrevHash      string        `json:"prevHash,omitempty"`      // SHA-256 of the previous log line, with audit.hashChain
+	Args          []string      `json:"args,omitempty"`          // kubectl arguments as given, only when command cannot be split back into them
+	Command       string        `json:"command"`
+}
+
+// Execution records how an executed kubectl command finished
+type Execution struct {
+	ExitCode        int
+	Duration        time.Duration
+	Backups         []string // backups taken just before it ran
+	Output          []byte   // stdout and stderr as printed, with audit.captureOutput; nil if not captured
+	OutputTruncated bool     // Output stops at audit.captureOutputLimit
+}
+
+// apply records the execution outcome on an entry
+func (x Execution) apply(e *Entry) {
+	code := x.ExitCode
+	e.ExitCode = &code
+	e.Duration = x.Duration.Round(time.Millisecond).String()
+	e.Backups = x.Backups
 }
 
 // formatText renders an entry as the key=value audit line (no trailing newline).
 // Uses literal quotes around the command so embedded quotes are preserved as-is.
+// variant is only written when a warning experiment variant was shown,
+// roster when the on-call roster was consulted, ticket when a change ticket was given,
+// previous=[...] only when the command changed recorded values,
+// backups=[...] when backups were taken first, stdinSHA256/stdinBytes when
+// manifests were read from stdin, objects/namespaces/severity for
+// the impact of a denied operation, exitCode/duration only once the command
+// has run, outputSHA256/outputBytes when its output was captured, args as a
+// quoted JSON array when an argument contains a space, and prevHash only when the log is hash-chained. user/host/ssh are omitted when unknown, proxy/impersonate when not used.
 func formatText(e Entry) string {
-	return fmt.Sprintf("[%s] %s | operation=%s resources=[%s] namespace=%s cluster=%s confirmed=%t command=\"%s\"",
+	origin := ""
+	if e.User != "" {
+		origin += " user=" + e.User
+	}
+	if e.Host != "" {
+		origin += " host=" + e.Host
+	}
+	if e.SSHClient != "" {
+		origin += " ssh=" + e.SSHClient
+	}
+	if e.Proxy != "" {
+		origin += " proxy=" + e.Proxy
+	}
+	if len(e.ActingAs) > 0 {
+		origin += fmt.Sprintf(" impersonate=[%s]", strings.Join(e.ActingAs, ","))
+	}
+	extra := ""
+	if e.Variant != "" {
+		extra = " variant=" + e.Variant
+	}
+	if e.Roster != "" {
+		extra += " roster=" + e.Roster
+	}
+	if e.Ticket != "" {
+		extra += " ticket=" + e.Ticket
+	}
+	if e.Justification != "" {
+		extra += " justification=" + strconv.Quote(e.Justification)
+	}
+	if len(e.Previous) > 0 {
+		extra += fmt.Sprintf(" previous=[%s]", strings.Join(e.Previous, ","))
+	}
+	if e.Debug != nil {
+		extra += " debugTarget=" + e.Debug.Target
+		if e.Debug.Container != "" {
+			extra += " debugContainer=" + e.Debug.Container
+		}
+		if e.Debug.Image != "" {
+			extra += " debugImage=" + e.Debug.Image
+		}
+		if e.Debug.Profile != "" {
+			extra += " debugProfile=" + e.Debug.Profile
+		}
+		if e.Debug.CopyTo != "" {
+			extra += " debugCopyTo=" + e.Debug.CopyTo
+		}
+	}
+	if len(e.Backups) > 0 {
+		extra += fmt.Sprintf(" backups=[%s]", strings.Join(e.Backups, ","))
+	}
+	if e.Stdin != nil {
+		extra += fmt.Sprintf(" stdinSHA256=%s stdinBytes=%d", e.Stdin.SHA256, e.Stdin.Bytes)
+	}
+	if e.Impact != nil {
+		extra += fmt.Sprintf(" objects=%d namespaces=[%s] severity=%s", e.Impact.Objects, strings.Join(e.Impact.Namespaces, ","), e.Impact.Severity)
+	}
+	if e.ExitCode != nil {
+		extra += fmt.Sprintf(" exitCode=%d duration=%s", *e.ExitCode, e.Duration)
+	}
+	if e.Output != nil {
+		extra += fmt.Sprintf(" outputSHA256=%s outputBytes=%d", e.Output.SHA256, e.Output.Bytes)
+		if e.Output.Truncated {
+			extra += " outputTruncated=true"
+		}
+	}
+	if len(e.Args) > 0 {
+		if args, err := json.Marshal(e.Args); err == nil {
+			extra += " args=" + strconv.Quote(string(args))
+		}
+	}
+	if e.PrevHash != "" {
+		extra += " prevHash=" + e.PrevHash
+	}
+	return fmt.Sprintf("[%s] %s | operation=%s resources=[%s] namespace=%s cluster=%s%s confirmed=%t%s command=\"%s\"",
 		e.Timestamp,
 		e.Status,
 		e.Operation,
 		strings.Join(e.Resources, ","),
 		e.Namespace,
 		e.Cluster,
+		origin,
 		e.Confirmed,
+		extra,
 		e.Command,
 	)
 }
@@ -60,13 +185,65 @@ func formatJSON(e Entry) (string, error) {
 	return string(b), nil
 }
 
+// Lookups for operator, replaced in tests
+var (
+	lookupUser     = currentUsername
+	lookupHostname = os.Hostname
+)
+
+// operator returns who is running safekubectl, on which machine, and the
+// SSH client address if the session is remote. Unknown values are empty.
+func operator() (username, host, sshClient string) {
+	username = lookupUser()
+	host, _ = lookupHostname()
+	// SSH_CLIENT is "<client ip> <client port> <server port>"
+	if fields := strings.Fields(os.Getenv("SSH_CLIENT")); len(fields) > 0 {
+		sshClient = fields[0]
+	}
+	return username, host, sshClient
+}
+
+// currentUsername returns the OS account name, falling back to $USER
+func currentUsername() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
 // writeEntry persists one audit entry if auditing is enabled, choosing the
-// output format from config (only "json" selects JSON; anything else is text).
+// output format from config (only "json" selects JSON; anything else is text)
+// and the sink ("syslog" sends it to the system log; anything else is a file).
 func (l *Logger) writeEntry(e Entry) error {
 	if !l.config.Audit.Enabled {
 		return nil
 	}
 
+	e.User, e.Host, e.SSHClient = operator()
+
+	if l.config.Audit.Sink == "syslog" {
+		line, err := l.format(e)
+		if err != nil {
+			return err
+		}
+		return writeSyslog(l.config.Audit.Facility, e, line)
+	}
+	return l.appendFile(e)
+}
+
+// format renders an entry in the configured format
+func (l *Logger) format(e Entry) (string, error) {
+	if l.config.Audit.Format == "json" {
+		return formatJSON(e)
+	}
+	return formatText(e), nil
+}
+
+// appendFile appends one audit entry to the audit log file. With
+// audit.hashChain, the entry records the hash of the line before it, and the
+// file stays locked from reading that line until the entry is written so two
+// safekubectl processes cannot chain to the same line.
+func (l *Logger) appendFile(e Entry) error {
 	// Ensure directory exists
 	dir := filepath.Dir(l.config.Audit.Path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -74,22 +251,30 @@ func (l *Logger) writeEntry(e Entry) error {
 	}
 
 	// Open file in append mode
-	file, err := os.OpenFile(l.config.Audit.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	file, err := os.OpenFile(l.config.Audit.Path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
 	if err != nil {
 		return fmt.Errorf("failed to open audit log: %w", err)
 	}
 	defer file.Close()
 
-	var line string
-	if l.config.Audit.Format == "json" {
-		line, err = formatJSON(e)
+	if l.config.Audit.HashChain {
+		unlock, err := lockFile(file)
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to lock audit log: %w", err)
+		}
+		defer unlock()
+
+		previous, err := lastLine(file)
+		if err != nil {
+			return fmt.Errorf("failed to read audit log: %w", err)
 		}
-	} else {
-		line = formatText(e)
+		e.PrevHash = chainHash(previous)
 	}
 
+	line, err := l.format(e)
+	if err != nil {
+		return err
+	}
 	if _, err := file.WriteString(line + "\n"); err != nil {
 		return fmt.Errorf("failed to write audit log: %w", err)
 	}
@@ -99,27 +284,109 @@ func (l *Logger) writeEntry(e Entry) error {
 
 // Log writes an audit entry for CLI commands if auditing is enabled
 func (l *Logger) Log(result *checker.CheckResult, args []string, confirmed bool, executed bool) error {
+	entry := commandEntry(result, args, confirmed, executed)
+	if !executed && l.config.Audit.DeniedImpact {
+		protectedNamespace := !result.IsAllNamespaces && !result.IsClusterScoped && l.config.IsProtectedNamespace(result.Namespace)
+		for _, ns := range result.CascadeNamespaces {
+			protectedNamespace = protectedNamespace || l.config.IsProtectedNamespace(ns)
+		}
+		entry.Impact = commandImpact(result, l.config.IsProtectedCluster(result.Cluster), protectedNamespace)
+	}
+	return l.writeEntry(entry)
+}
+
+// LogExecuted writes an audit entry for a CLI command that has run, with
+// its exit code and duration
+func (l *Logger) LogExecuted(result *checker.CheckResult, args []string, confirmed bool, execution Execution) error {
+	entry := commandEntry(result, args, confirmed, true)
+	execution.apply(&entry)
+	return l.writeExecutedEntry(entry, execution)
+}
+
+// writeExecutedEntry writes the entry for a command that has run, after
+// archiving its captured output. The entry is written even if archiving fails.
+func (l *Logger) writeExecutedEntry(entry Entry, execution Execution) error {
+	output, archiveErr := l.archiveOutput(execution)
+	entry.Output = output
+	if err := l.writeEntry(entry); err != nil {
+		return err
+	}
+	return archiveErr
+}
+
+// commandEntry builds the audit entry for a CLI command
+func commandEntry(result *checker.CheckResult, args []string, confirmed bool, executed bool) Entry {
 	status := "DENIED"
 	if executed {
 		status = "EXECUTED"
 	}
 
-	entry := Entry{
-		Timestamp: time.Now().Format(time.RFC3339),
-		Status:    status,
-		Operation: result.Operation,
-		Resources: result.Resources,
-		Namespace: result.Namespace,
-		Cluster:   result.Cluster,
-		Confirmed: confirmed,
-		Command:   strings.Join(args, " "),
+	return Entry{
+		Timestamp:     time.Now().Format(time.RFC3339),
+		Status:        status,
+		Operation:     result.Operation,
+		Resources:     result.Resources,
+		Namespace:     result.Namespace,
+		Cluster:       result.Cluster,
+		Confirmed:     confirmed,
+		Variant:       result.Variant,
+		Roster:        result.Roster,
+		Ticket:        result.Ticket,
+		Justification: result.Justification,
+		Proxy:         result.Proxy,
+		ActingAs:      result.ActingAs,
+		Previous:      result.Previous,
+		Debug:         result.Debug,
+		Args:          exactArgs(args),
+		Command:       strings.Join(args, " "),
 	}
+}
 
-	return l.writeEntry(entry)
+// exactArgs returns args if joining them into the command line loses where
+// one ends, because one is empty or contains a space, and nil otherwise
+func exactArgs(args []string) []string {
+	for _, arg := range args {
+		if arg == "" || strings.ContainsFunc(arg, unicode.IsSpace) {
+			return args
+		}
+	}
+	return nil
+}
+
+// Argv returns the kubectl arguments of the entry: args when it was
+// recorded, otherwise the command line, which then splits back exactly
+func (e Entry) Argv() []string {
+	if len(e.Args) > 0 {
+		return e.Args
+	}
+	return strings.Fields(e.Command)
 }
 
 // LogResources writes an audit entry for file-based commands if auditing is enabled
 func (l *Logger) LogResources(result *checker.ResourceCheckResult, args []string, confirmed bool, executed bool) error {
+	entry := resourcesEntry(result, args, confirmed, executed)
+	entry.Stdin = l.stdin
+	if !executed && l.config.Audit.DeniedImpact {
+		protectedNamespace := false
+		for _, r := range result.Resources {
+			protectedNamespace = protectedNamespace || l.config.IsProtectedNamespace(r.Namespace)
+		}
+		entry.Impact = resourcesImpact(result, l.config.IsProtectedCluster(result.Cluster), protectedNamespace)
+	}
+	return l.writeEntry(entry)
+}
+
+// LogResourcesExecuted writes an audit entry for a file-based command that
+// has run, with its exit code and duration
+func (l *Logger) LogResourcesExecuted(result *checker.ResourceCheckResult, args []string, confirmed bool, execution Execution) error {
+	entry := resourcesEntry(result, args, confirmed, true)
+	entry.Stdin = l.stdin
+	execution.apply(&entry)
+	return l.writeExecutedEntry(entry, execution)
+}
+
+// resourcesEntry builds the audit entry for a file-based command
+func resourcesEntry(result *checker.ResourceCheckResult, args []string, confirmed bool, executed bool) Entry {
 	status := "DENIED"
 	if executed {
 		status = "EXECUTED"
@@ -135,14 +402,41 @@ func (l *Logger) LogResources(result *checker.ResourceCheckResult, args []string
 		resourceList = append(resourceList, fmt.Sprintf("%s/%s@%s", r.Kind, r.Name, ns))
 	}
 
+	return Entry{
+		Timestamp:     time.Now().Format(time.RFC3339),
+		Status:        status,
+		Operation:     result.Operation,
+		Resources:     resourceList,
+		Namespace:     "", // file-based: namespace is per-resource in the strings
+		Cluster:       result.Cluster,
+		Confirmed:     confirmed,
+		Variant:       result.Variant,
+		Roster:        result.Roster,
+		Ticket:        result.Ticket,
+		Justification: result.Justification,
+		Proxy:         result.Proxy,
+		ActingAs:      result.ActingAs,
+		Args:          exactArgs(args),
+		Command:       strings.Join(args, " "),
+	}
+}
+
+// LogRecreation writes a follow-up entry recording whether a deleted target's
+// replacement became ready after the delete executed
+func (l *Logger) LogRecreation(result *checker.CheckResult, target string, args []string, ready bool) error {
+	status := "NOT_RECREATED"
+	if ready {
+		status = "RECREATED"
+	}
+
 	entry := Entry{
 		Timestamp: time.Now().Format(time.RFC3339),
 		Status:    status,
 		Operation: result.Operation,
-		Resources: resourceList,
-		Namespace: "", // file-based: namespace is per-resource in the strings
+		Resources: []string{target},
+		Namespace: result.Namespace,
 		Cluster:   result.Cluster,
-		Confirmed: confirmed,
+		Confirmed: true,
 		Command:   strings.Join(args, " "),
 	}
 
@@ -6,10 +6,12 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
 	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
 	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
 )
 
 func TestNew(t *testing.T) {
@@ -541,6 +543,83 @@ func TestFormatText(t *testing.T) {
 	}
 }
 
+func TestFormatTextPrevious(t *testing.T) {
+	e := Entry{
+		Timestamp: "2026-06-14T10:38:14Z",
+		Status:    "EXECUTED",
+		Operation: "patch",
+		Resources: []string{"cronjob/nightly"},
+		Namespace: "prod",
+		Cluster:   "prod-cluster",
+		Confirmed: true,
+		Previous:  []string{"cronjob/nightly.spec.suspend=false"},
+		Command:   `patch cronjob nightly -p {"spec":{"suspend":true}}`,
+	}
+
+	got := formatText(e)
+	want := `[2026-06-14T10:38:14Z] EXECUTED | operation=patch resources=[cronjob/nightly] namespace=prod cluster=prod-cluster confirmed=true previous=[cronjob/nightly.spec.suspend=false] command="patch cronjob nightly -p {"spec":{"suspend":true}}"`
+
+	if got != want {
+		t.Errorf("formatText():\n got: %s\nwant: %s", got, want)
+	}
+
+	if line, _ := formatJSON(e); !strings.Contains(line, `"previous":["cronjob/nightly.spec.suspend=false"]`) {
+		t.Errorf("formatJSON() missing previous values, got: %s", line)
+	}
+	if line, _ := formatJSON(Entry{}); strings.Contains(line, "previous") {
+		t.Errorf("formatJSON() must omit empty previous values, got: %s", line)
+	}
+}
+
+func TestFormatTextExecution(t *testing.T) {
+	code := 1
+	e := Entry{
+		Timestamp: "2026-06-14T10:38:14Z",
+		Status:    "EXECUTED",
+		Operation: "delete",
+		Resources: []string{"pod/nginx"},
+		Namespace: "default",
+		Cluster:   "prod-cluster",
+		Confirmed: true,
+		ExitCode:  &code,
+		Duration:  "1.5s",
+		Command:   "delete pod nginx",
+	}
+
+	got := formatText(e)
+	want := `[2026-06-14T10:38:14Z] EXECUTED | operation=delete resources=[pod/nginx] namespace=default cluster=prod-cluster confirmed=true exitCode=1 duration=1.5s command="delete pod nginx"`
+
+	if got != want {
+		t.Errorf("formatText():\n got: %s\nwant: %s", got, want)
+	}
+
+	if line, _ := formatJSON(e); !strings.Contains(line, `"exitCode":1,"duration":"1.5s"`) {
+		t.Errorf("formatJSON() missing exit code and duration, got: %s", line)
+	}
+	if line, _ := formatJSON(Entry{}); strings.Contains(line, "exitCode") || strings.Contains(line, "duration") {
+		t.Errorf("formatJSON() must omit exit code and duration before execution, got: %s", line)
+	}
+}
+
+func TestLogExecuted(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	cfg := &config.Config{Audit: config.AuditConfig{Enabled: true, Path: logPath, Format: "json"}}
+	logger := New(cfg)
+
+	result := &checker.CheckResult{Operation: "delete", Resources: []string{"pod/nginx"}, Namespace: "default", Cluster: "test-cluster"}
+	if err := logger.LogExecuted(result, []string{"delete", "pod", "nginx"}, true, Execution{ExitCode: 0, Duration: 1234567 * time.Microsecond}); err != nil {
+		t.Fatalf("LogExecuted() returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(content), `"status":"EXECUTED"`) || !strings.Contains(string(content), `"exitCode":0,"duration":"1.235s"`) {
+		t.Errorf("expected exit code 0 and rounded duration, got: %s", content)
+	}
+}
+
 func TestFormatTextEmptyNamespace(t *testing.T) {
 	e := Entry{
 		Timestamp: "2026-06-14T10:38:14Z",
@@ -810,3 +889,185 @@ func TestLogUnknownFormatFallsBackToText(t *testing.T) {
 		t.Errorf("unknown format did not produce text layout, got: %s", line)
 	}
 }
+
+func TestLogRecreation(t *testing.T) {
+	tests := []struct {
+		name     string
+		ready    bool
+		expected string
+	}{
+		{"replacement ready", true, "RECREATED |"},
+		{"replacement not ready", false, "NOT_RECREATED |"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := filepath.Join(t.TempDir(), "audit.log")
+			cfg := &config.Config{
+				Audit: config.AuditConfig{Enabled: true, Path: logPath},
+			}
+			result := &checker.CheckResult{
+				Operation: "delete",
+				Resources: []string{"pod/web-1", "pod/web-2"},
+				Namespace: "production",
+				Cluster:   "prod-cluster",
+			}
+
+			if err := New(cfg).LogRecreation(result, "pod/web-1", []string{"delete", "pod", "web-1", "web-2"}, tt.ready); err != nil {
+				t.Fatalf("LogRecreation() returned error: %v", err)
+			}
+
+			content, err := os.ReadFile(logPath)
+			if err != nil {
+				t.Fatalf("failed to read log file: %v", err)
+			}
+			logContent := string(content)
+
+			if !strings.Contains(logContent, "] "+tt.expected) {
+				t.Errorf("expected status %q, got:\n%s", tt.expected, logContent)
+			}
+			if !strings.Contains(logContent, "resources=[pod/web-1]") {
+				t.Errorf("expected only the watched target, got:\n%s", logContent)
+			}
+		})
+	}
+}
+
+func TestLogRecordsOperator(t *testing.T) {
+	originalUser, originalHostname := lookupUser, lookupHostname
+	lookupUser = func() string { return "alice" }
+	lookupHostname = func() (string, error) { return "bastion-1", nil }
+	t.Cleanup(func() { lookupUser, lookupHostname = originalUser, originalHostname })
+	t.Setenv("SSH_CLIENT", "10.0.0.5 51234 22")
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"text", "cluster=test-cluster user=alice host=bastion-1 ssh=10.0.0.5 confirmed=true"},
+		{"json", `"cluster":"test-cluster","user":"alice","host":"bastion-1","sshClient":"10.0.0.5"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			logPath := filepath.Join(t.TempDir(), "audit.log")
+			logger := New(&config.Config{Audit: config.AuditConfig{Enabled: true, Path: logPath, Format: tt.format}})
+
+			result := &checker.CheckResult{Operation: "delete", Resources: []string{"pod/nginx"}, Namespace: "default", Cluster: "test-cluster"}
+			if err := logger.Log(result, []string{"delete", "pod", "nginx"}, true, true); err != nil {
+				t.Fatalf("Log() returned error: %v", err)
+			}
+			resources := &checker.ResourceCheckResult{Operation: "apply", Cluster: "test-cluster", Resources: []manifest.Resource{{Kind: "Deployment", Name: "web"}}}
+			if err := logger.LogResources(resources, []string{"apply", "-f", "web.yaml"}, true, true); err != nil {
+				t.Fatalf("LogResources() returned error: %v", err)
+			}
+
+			content, err := os.ReadFile(logPath)
+			if err != nil {
+				t.Fatalf("failed to read log file: %v", err)
+			}
+			if got := strings.Count(string(content), tt.expected); got != 2 {
+				t.Errorf("expected %q in both entries, got:\n%s", tt.expected, content)
+			}
+		})
+	}
+}
+
+func TestFormatTextBackups(t *testing.T) {
+	entry := Entry{Timestamp: "2024-01-15T10:30:00Z", Status: "EXECUTED", Operation: "delete", Resources: []string{"pvc/data"}, Namespace: "db", Cluster: "prod", Confirmed: true, Command: "delete pvc data -n db"}
+	Execution{ExitCode: 0, Duration: time.Second, Backups: []string{"pre-delete-data"}}.apply(&entry)
+
+	line := formatText(entry)
+	if !strings.Contains(line, " backups=[pre-delete-data] exitCode=0") {
+		t.Errorf("expected backups before exit code, got: %s", line)
+	}
+
+	js, err := formatJSON(entry)
+	if err != nil {
+		t.Fatalf("formatJSON: %v", err)
+	}
+	if !strings.Contains(js, `"backups":["pre-delete-data"]`) {
+		t.Errorf("expected backups in JSON, got: %s", js)
+	}
+}
+
+func TestFormatTextRoster(t *testing.T) {
+	entry := Entry{Timestamp: "2024-01-15T10:30:00Z", Status: "EXECUTED", Operation: "delete", Resources: []string{"pod/web-1"}, Namespace: "web", Cluster: "prod", Confirmed: true, Roster: "on-call", Command: "delete pod web-1 -n web"}
+
+	line := formatText(entry)
+	if !strings.Contains(line, " confirmed=true roster=on-call") {
+		t.Errorf("expected the roster after confirmed, got: %s", line)
+	}
+	got, err := ParseLine(line)
+	if err != nil {
+		t.Fatalf("ParseLine: %v", err)
+	}
+	if got.Roster != "on-call" {
+		t.Errorf("roster: got %q, expected %q", got.Roster, "on-call")
+	}
+}
+
+func TestFormatTextTicket(t *testing.T) {
+	entry := Entry{Timestamp: "2024-01-15T10:30:00Z", Status: "EXECUTED", Operation: "delete", Resources: []string{"pod/web-1"}, Namespace: "web", Cluster: "prod", Confirmed: true, Ticket: "CHG0031337", Command: "delete pod web-1 -n web"}
+
+	line := formatText(entry)
+	if !strings.Contains(line, " confirmed=true ticket=CHG0031337") {
+		t.Errorf("expected the ticket after confirmed, got: %s", line)
+	}
+	got, err := ParseLine(line)
+	if err != nil {
+		t.Fatalf("ParseLine: %v", err)
+	}
+	if got.Ticket != "CHG0031337" {
+		t.Errorf("ticket: got %q, expected %q", got.Ticket, "CHG0031337")
+	}
+}
+
+func TestFormatTextJustification(t *testing.T) {
+	entry := Entry{Timestamp: "2024-01-15T10:30:00Z", Status: "EXECUTED", Operation: "delete", Resources: []string{"pod/web-1"}, Namespace: "web", Cluster: "prod", Confirmed: true, Justification: `stuck after the "node" reboot, see command="x"`, Command: "delete pod web-1 -n web"}
+
+	line := formatText(entry)
+	if !strings.Contains(line, ` confirmed=true justification="stuck after the \"node\" reboot, see command=\"x\"" command=`) {
+		t.Errorf("expected the quoted justification after confirmed, got: %s", line)
+	}
+	got, err := ParseLine(line)
+	if err != nil {
+		t.Fatalf("ParseLine: %v", err)
+	}
+	if got.Justification != entry.Justification || got.Command != entry.Command {
+		t.Errorf("got justification %q and command %q", got.Justification, got.Command)
+	}
+}
+
+func TestFormatTextDebug(t *testing.T) {
+	debug := &parser.Debug{Target: "node/worker-1", Image: "busybox", Profile: "sysadmin"}
+	entry := Entry{Timestamp: "2024-01-15T10:30:00Z", Status: "EXECUTED", Operation: "debug", Resources: []string{"node/worker-1"}, Namespace: "default", Cluster: "prod", Confirmed: true, Debug: debug, Command: "debug node/worker-1 -it --image=busybox --profile=sysadmin"}
+
+	line := formatText(entry)
+	if !strings.Contains(line, " confirmed=true debugTarget=node/worker-1 debugImage=busybox debugProfile=sysadmin ") {
+		t.Errorf("expected the debug target, image and profile after confirmed, got: %s", line)
+	}
+	got, err := ParseLine(line)
+	if err != nil {
+		t.Fatalf("ParseLine: %v", err)
+	}
+	if got.Debug == nil || *got.Debug != *debug {
+		t.Errorf("debug: got %+v, expected %+v", got.Debug, debug)
+	}
+}
+
+func TestFormatTextInterception(t *testing.T) {
+	entry := Entry{Timestamp: "2024-01-15T10:30:00Z", Status: "EXECUTED", Operation: "delete", Resources: []string{"pod/web-1"}, Namespace: "web", Cluster: "prod", User: "alice", Proxy: "http://proxy.example.com:3128", ActingAs: []string{"user:admin", "group:system:masters"}, Confirmed: true, Command: "delete pod web-1 -n web"}
+
+	line := formatText(entry)
+	if !strings.Contains(line, " user=alice proxy=http://proxy.example.com:3128 impersonate=[user:admin,group:system:masters] ") {
+		t.Errorf("expected proxy and impersonation after the operator, got: %s", line)
+	}
+	got, err := ParseLine(line)
+	if err != nil {
+		t.Fatalf("ParseLine: %v", err)
+	}
+	if got.Proxy != entry.Proxy || strings.Join(got.ActingAs, ",") != "user:admin,group:system:masters" {
+		t.Errorf("got proxy %q acting as %v", got.Proxy, got.ActingAs)
+	}
+}
@@ -0,0 +1,160 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+)
+
+// writeChainedLog writes n hash-chained entries and returns the log lines
+func writeChainedLog(t *testing.T, format string, n int) (string, []string) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	logger := New(&config.Config{Audit: config.AuditConfig{Enabled: true, Path: logPath, Format: format, HashChain: true}})
+	for i := 0; i < n; i++ {
+		result := &checker.CheckResult{Operation: "delete", Resources: []string{"pod/nginx"}, Namespace: "default", Cluster: "test-cluster"}
+		if err := logger.Log(result, []string{"delete", "pod", "nginx", strings.Repeat("x", i*3000)}, true, true); err != nil {
+			t.Fatalf("Log() returned error: %v", err)
+		}
+	}
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	return logPath, strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
+
+func TestVerifyChainIntact(t *testing.T) {
+	for _, format := range []string{"text", "json"} {
+		t.Run(format, func(t *testing.T) {
+			logPath, lines := writeChainedLog(t, format, 4)
+			if !strings.Contains(lines[0], genesisHash) {
+				t.Errorf("first entry must chain to the genesis hash, got: %s", lines[0])
+			}
+			if !strings.Contains(lines[3], chainHash(lines[2])) {
+				t.Errorf("entry must record the hash of the previous line, got: %s", lines[3])
+			}
+
+			chained, breaks, err := VerifyChain(logPath)
+			if err != nil || chained != 4 || len(breaks) != 0 {
+				t.Errorf("VerifyChain(): got (%d, %v, %v), expected 4 intact entries", chained, breaks, err)
+			}
+		})
+	}
+}
+
+func TestVerifyChainBreaks(t *testing.T) {
+	tests := []struct {
+		name          string
+		tamper        func(lines []string) []string
+		expectedLines []int
+	}{
+		{"modified entry", func(lines []string) []string {
+			lines[1] = strings.Replace(lines[1], "confirmed=true", "confirmed=false", 1)
+			return lines
+		}, []int{3}},
+		{"removed entry", func(lines []string) []string {
+			return append(lines[:1], lines[2:]...)
+		}, []int{2}},
+		{"inserted entry", func(lines []string) []string {
+			return append(lines[:2], append([]string{"[2026-01-01T00:00:00Z] EXECUTED | operation=get command=\"get pods\""}, lines[2:]...)...)
+		}, []int{3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath, lines := writeChainedLog(t, "text", 4)
+			if err := os.WriteFile(logPath, []byte(strings.Join(tt.tamper(lines), "\n")+"\n"), 0644); err != nil {
+				t.Fatalf("failed to rewrite log: %v", err)
+			}
+
+			_, breaks, err := VerifyChain(logPath)
+			if err != nil {
+				t.Fatalf("VerifyChain() returned error: %v", err)
+			}
+			var got []int
+			for _, b := range breaks {
+				got = append(got, b.Line)
+			}
+			if len(got) != len(tt.expectedLines) || (len(got) > 0 && got[0] != tt.expectedLines[0]) {
+				t.Errorf("break lines: got %v, expected %v", got, tt.expectedLines)
+			}
+		})
+	}
+}
+
+func TestVerifyChainStartsAtFirstHashedEntry(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	cfg := &config.Config{Audit: config.AuditConfig{Enabled: true, Path: logPath}}
+	result := &checker.CheckResult{Operation: "delete"}
+	if err := New(cfg).Log(result, []string{"delete", "pod", "a"}, true, true); err != nil {
+		t.Fatalf("Log() returned error: %v", err)
+	}
+	cfg.Audit.HashChain = true
+	if err := New(cfg).Log(result, []string{"delete", "pod", "b"}, true, true); err != nil {
+		t.Fatalf("Log() returned error: %v", err)
+	}
+
+	chained, breaks, err := VerifyChain(logPath)
+	if err != nil || chained != 1 || len(breaks) != 0 {
+		t.Errorf("VerifyChain(): got (%d, %v, %v), expected 1 intact entry", chained, breaks, err)
+	}
+}
+
+func TestEntryPrevHashIgnoresCommand(t *testing.T) {
+	line := `[2026-06-14T10:38:14Z] EXECUTED | operation=annotate confirmed=true command="annotate pod x note=prevHash=abc"`
+	if got := entryPrevHash(line); got != "" {
+		t.Errorf("entryPrevHash(): got %q, expected no hash", got)
+	}
+}
+
+func TestEntryPrevHashIgnoresJustification(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	logger := New(&config.Config{Audit: config.AuditConfig{Enabled: true, Path: logPath, HashChain: true}})
+	result := &checker.CheckResult{Operation: "delete", Resources: []string{"pod/nginx"}, Namespace: "default", Cluster: "test-cluster", Justification: "cleanup prevHash=" + genesisHash}
+	for i := 0; i < 2; i++ {
+		if err := logger.Log(result, []string{"delete", "pod", "nginx"}, true, true); err != nil {
+			t.Fatalf("Log() returned error: %v", err)
+		}
+	}
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if got, expected := entryPrevHash(lines[1]), chainHash(lines[0]); got != expected {
+		t.Errorf("entryPrevHash(): got %q, expected the recorded %q", got, expected)
+	}
+
+	line := `[2026-06-14T10:38:14Z] EXECUTED | operation=delete confirmed=true justification="x prevHash=abc" command="delete pod x"`
+	if got := entryPrevHash(line); got != "" {
+		t.Errorf("entryPrevHash(): got %q, expected no hash", got)
+	}
+}
+
+func TestAppendFileChainsConcurrentWriters(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// A logger per writer opens the log separately, like separate processes
+			logger := New(&config.Config{Audit: config.AuditConfig{Enabled: true, Path: logPath, HashChain: true}})
+			result := &checker.CheckResult{Operation: "delete", Resources: []string{"pod/nginx"}, Namespace: "default", Cluster: "test-cluster"}
+			if err := logger.Log(result, []string{"delete", "pod", "nginx"}, true, true); err != nil {
+				t.Errorf("Log() returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	chained, breaks, err := VerifyChain(logPath)
+	if err != nil || chained != 20 || len(breaks) != 0 {
+		t.Errorf("VerifyChain(): got (%d, %v, %v), expected 20 intact entries", chained, breaks, err)
+	}
+}
@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"sort"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+)
+
+// Impact severities, from most to least severe
+const (
+	SeverityCritical = "critical" // cascading deletes confirmed by typing a name
+	SeverityHigh     = "high"     // protected clusters, protected namespaces or all namespaces
+	SeverityMedium   = "medium"
+)
+
+// Impact is what a denied operation would have affected, so near misses can
+// be studied alongside executed changes
+type Impact struct {
+	Objects    int      `json:"objects"`    // targets of the command, or manifest objects
+	Namespaces []string `json:"namespaces"` // "*" for all namespaces; empty for cluster-scoped targets
+	Severity   string   `json:"severity"`   // critical | high | medium
+	Reasons    []string `json:"reasons"`    // why the operation was flagged; JSON format only
+}
+
+// commandImpact computes the impact of a CLI command
+func commandImpact(result *checker.CheckResult, protectedCluster, protectedNamespace bool) *Impact {
+	impact := &Impact{Objects: len(result.Resources), Severity: SeverityMedium, Reasons: result.Reasons}
+	switch {
+	case len(result.CascadeNamespaces) > 0:
+		impact.Namespaces = result.CascadeNamespaces
+	case result.IsAllNamespaces:
+		impact.Namespaces = []string{"*"}
+	case !result.IsClusterScoped && !result.IsNodeScoped && result.Namespace != "":
+		impact.Namespaces = []string{result.Namespace}
+	}
+
+	switch {
+	case result.ConfirmationPhrase != "":
+		impact.Severity = SeverityCritical
+	case protectedCluster || protectedNamespace || result.IsAllNamespaces:
+		impact.Severity = SeverityHigh
+	}
+	return impact
+}
+
+// resourcesImpact computes the impact of a file-based command
+func resourcesImpact(result *checker.ResourceCheckResult, protectedCluster, protectedNamespace bool) *Impact {
+	impact := &Impact{Objects: len(result.Resources), Severity: SeverityMedium, Reasons: result.Reasons}
+	seen := map[string]bool{}
+	for _, r := range result.Resources {
+		if r.Namespace != "" && !seen[r.Namespace] {
+			seen[r.Namespace] = true
+			impact.Namespaces = append(impact.Namespaces, r.Namespace)
+		}
+	}
+	sort.Strings(impact.Namespaces)
+
+	if protectedCluster || protectedNamespace {
+		impact.Severity = SeverityHigh
+	}
+	return impact
+}
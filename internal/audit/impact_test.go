@@ -0,0 +1,160 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+)
+
+func TestCommandImpact(t *testing.T) {
+	tests := []struct {
+		name               string
+		result             *checker.CheckResult
+		protectedCluster   bool
+		protectedNamespace bool
+		expected           *Impact
+	}{
+		{
+			name:     "namespaced delete",
+			result:   &checker.CheckResult{Resources: []string{"pod/a", "pod/b"}, Namespace: "dev", Reasons: []string{"delete operation"}},
+			expected: &Impact{Objects: 2, Namespaces: []string{"dev"}, Severity: SeverityMedium, Reasons: []string{"delete operation"}},
+		},
+		{
+			name:             "protected cluster",
+			result:           &checker.CheckResult{Resources: []string{"pod/a"}, Namespace: "dev"},
+			protectedCluster: true,
+			expected:         &Impact{Objects: 1, Namespaces: []string{"dev"}, Severity: SeverityHigh},
+		},
+		{
+			name:     "all namespaces",
+			result:   &checker.CheckResult{Resources: []string{"pods"}, IsAllNamespaces: true},
+			expected: &Impact{Objects: 1, Namespaces: []string{"*"}, Severity: SeverityHigh},
+		},
+		{
+			name:     "cascading namespace delete",
+			result:   &checker.CheckResult{Resources: []string{"namespace/a", "namespace/b"}, IsClusterScoped: true, CascadeNamespaces: []string{"a", "b"}, ConfirmationPhrase: "a"},
+			expected: &Impact{Objects: 2, Namespaces: []string{"a", "b"}, Severity: SeverityCritical},
+		},
+		{
+			name:     "cluster-scoped",
+			result:   &checker.CheckResult{Resources: []string{"clusterrole/admin"}, Namespace: "default", IsClusterScoped: true},
+			expected: &Impact{Objects: 1, Severity: SeverityMedium},
+		},
+	}
+
+	for _, tt := range tests {
+		got := commandImpact(tt.result, tt.protectedCluster, tt.protectedNamespace)
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: got %+v, expected %+v", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestResourcesImpact(t *testing.T) {
+	result := &checker.ResourceCheckResult{
+		Resources: []manifest.Resource{
+			{Kind: "Deployment", Name: "web", Namespace: "prod"},
+			{Kind: "Service", Name: "web", Namespace: "prod"},
+			{Kind: "ConfigMap", Name: "cfg", Namespace: "batch"},
+			{Kind: "ClusterRole", Name: "reader"},
+		},
+	}
+
+	got := resourcesImpact(result, false, true)
+	expected := &Impact{Objects: 4, Namespaces: []string{"batch", "prod"}, Severity: SeverityHigh}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %+v, expected %+v", got, expected)
+	}
+}
+
+func TestLogDeniedImpact(t *testing.T) {
+	tests := []struct {
+		name         string
+		deniedImpact bool
+		executed     bool
+		expected     bool
+	}{
+		{"denied with deniedImpact", true, false, true},
+		{"denied without deniedImpact", false, false, false},
+		{"executed with deniedImpact", true, true, false},
+	}
+
+	for _, tt := range tests {
+		logPath := filepath.Join(t.TempDir(), "audit.log")
+		cfg := &config.Config{
+			Audit:             config.AuditConfig{Enabled: true, Path: logPath, Format: "json", DeniedImpact: tt.deniedImpact},
+			ProtectedClusters: []string{"prod"},
+		}
+		result := &checker.CheckResult{Operation: "delete", Resources: []string{"deployment/web"}, Namespace: "shop", Cluster: "prod", Reasons: []string{"delete operation"}}
+		if err := New(cfg).Log(result, []string{"delete", "deployment", "web", "-n", "shop"}, tt.executed, tt.executed); err != nil {
+			t.Fatalf("%s: Log returned error: %v", tt.name, err)
+		}
+
+		content, err := os.ReadFile(logPath)
+		if err != nil {
+			t.Fatalf("%s: failed to read log file: %v", tt.name, err)
+		}
+		var entry Entry
+		if err := json.Unmarshal(content, &entry); err != nil {
+			t.Fatalf("%s: invalid JSON entry: %v", tt.name, err)
+		}
+		if got := entry.Impact != nil; got != tt.expected {
+			t.Errorf("%s: impact recorded: got %v, expected %v", tt.name, got, tt.expected)
+			continue
+		}
+		if tt.expected && (entry.Impact.Severity != SeverityHigh || entry.Impact.Objects != 1 || len(entry.Impact.Reasons) != 1) {
+			t.Errorf("%s: got impact %+v", tt.name, entry.Impact)
+		}
+	}
+}
+
+func TestLogResourcesDeniedImpact(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	cfg := &config.Config{
+		Audit:               config.AuditConfig{Enabled: true, Path: logPath, DeniedImpact: true},
+		ProtectedNamespaces: []string{"kube-system"},
+	}
+	result := &checker.ResourceCheckResult{
+		Operation: "delete",
+		Cluster:   "dev",
+		Resources: []manifest.Resource{{Kind: "Deployment", Name: "coredns", Namespace: "kube-system"}},
+	}
+	if err := New(cfg).LogResources(result, []string{"delete", "-f", "dns.yaml"}, false, false); err != nil {
+		t.Fatalf("LogResources returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(content), " objects=1 namespaces=[kube-system] severity=high ") {
+		t.Errorf("expected impact in text entry, got: %s", content)
+	}
+}
+
+func TestParseLineImpact(t *testing.T) {
+	entry := Entry{
+		Timestamp: "2024-01-15T10:30:00Z",
+		Status:    "DENIED",
+		Operation: "delete",
+		Resources: []string{"namespace/a", "namespace/b"},
+		Cluster:   "prod",
+		Impact:    &Impact{Objects: 2, Namespaces: []string{"a", "b"}, Severity: SeverityCritical},
+		Command:   "delete namespace a b",
+	}
+
+	got, err := ParseLine(formatText(entry))
+	if err != nil {
+		t.Fatalf("ParseLine returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, entry) {
+		t.Errorf("got %+v, expected %+v", got, entry)
+	}
+}
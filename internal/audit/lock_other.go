@@ -0,0 +1,11 @@
+//go:build windows || plan9
+
+package audit
+
+import "os"
+
+// lockFile is a no-op where flock is not available; appends may interleave
+// with another safekubectl writing the same log
+func lockFile(f *os.File) (func(), error) {
+	return func() {}, nil
+}
@@ -0,0 +1,163 @@
+package audit
+
+import (
+	"sort"
+	"strings"
+)
+
+// Verdicts on whether a confirmation prompt earns its friction
+const (
+	VerdictPreventing = "preventing" // the prompt regularly stops operations
+	VerdictNoise      = "noise"      // the prompt is almost always confirmed; worth tuning
+)
+
+// Thresholds behind the verdicts
+const (
+	preventingDenialRate = 0.2
+	noiseDenialRate      = 0.05
+	noiseMinPrompts      = 10
+)
+
+// NearMissCount tallies the prompted operations for one key: those the
+// operator confirmed and those that were denied or blocked
+type NearMissCount struct {
+	Key       string `json:"key"`
+	Confirmed int    `json:"confirmed"`
+	Denied    int    `json:"denied"`
+	Severe    int    `json:"severe"` // denials recorded with high or critical impact
+}
+
+// Prompted is the number of operations that needed a decision
+func (c NearMissCount) Prompted() int {
+	return c.Confirmed + c.Denied
+}
+
+// DenialRate is the share of prompted operations that were denied, from 0 to 1
+func (c NearMissCount) DenialRate() float64 {
+	if c.Prompted() == 0 {
+		return 0
+	}
+	return float64(c.Denied) / float64(c.Prompted())
+}
+
+// Verdict says whether the friction for this key prevents incidents, is
+// noise, or neither yet
+func (c NearMissCount) Verdict() string {
+	switch {
+	case c.Severe > 0 || (c.Denied > 0 && c.DenialRate() >= preventingDenialRate):
+		return VerdictPreventing
+	case c.Prompted() >= noiseMinPrompts && c.DenialRate() < noiseDenialRate:
+		return VerdictNoise
+	}
+	return ""
+}
+
+// NearMisses summarizes prompted operations. Unconfirmed executions never
+// needed a decision and are not counted.
+type NearMisses struct {
+	First     string          `json:"first"`
+	Last      string          `json:"last"`
+	Overall   NearMissCount   `json:"overall"`
+	ByDay     []NearMissCount `json:"byDay"`  // oldest first
+	ByUser    []NearMissCount `json:"byUser"` // most denials first, as are the others
+	ByCluster []NearMissCount `json:"byCluster"`
+	ByRule    []NearMissCount `json:"byRule"`    // OPERATION RESOURCE-TYPE, e.g. "delete deployment"
+	ByVariant []NearMissCount `json:"byVariant"` // "RULE / VARIANT", for entries that showed a warning experiment variant
+}
+
+// SummarizeNearMisses aggregates entries, oldest first, into NearMisses
+func SummarizeNearMisses(entries []Entry) NearMisses {
+	summary := NearMisses{Overall: NearMissCount{Key: "total"}}
+	days := map[string]*NearMissCount{}
+	users := map[string]*NearMissCount{}
+	clusters := map[string]*NearMissCount{}
+	rules := map[string]*NearMissCount{}
+	variants := map[string]*NearMissCount{}
+
+	for _, e := range entries {
+		if e.Status != "DENIED" && !(e.Status == "EXECUTED" && e.Confirmed) {
+			continue
+		}
+		if summary.First == "" {
+			summary.First = e.Timestamp
+		}
+		summary.Last = e.Timestamp
+
+		day, _, _ := strings.Cut(e.Timestamp, "T")
+		for _, c := range []*NearMissCount{
+			&summary.Overall,
+			nearMissFor(days, day),
+			nearMissFor(users, e.User),
+			nearMissFor(clusters, e.Cluster),
+			nearMissFor(rules, entryRule(e)),
+		} {
+			tallyNearMiss(c, e)
+		}
+		if e.Variant != "" {
+			tallyNearMiss(nearMissFor(variants, entryRule(e)+" / "+e.Variant), e)
+		}
+	}
+
+	summary.ByDay = sortedNearMisses(days, func(a, b NearMissCount) bool { return a.Key < b.Key })
+	summary.ByUser = sortedNearMisses(users, mostDenied)
+	summary.ByCluster = sortedNearMisses(clusters, mostDenied)
+	summary.ByRule = sortedNearMisses(rules, mostDenied)
+	summary.ByVariant = sortedNearMisses(variants, func(a, b NearMissCount) bool { return a.Key < b.Key })
+	return summary
+}
+
+// entryRule names the check that prompted for an entry: its operation and
+// the type of its first resource
+func entryRule(e Entry) string {
+	if len(e.Resources) == 0 {
+		return e.Operation
+	}
+	kind, _, _ := strings.Cut(e.Resources[0], "/")
+	return e.Operation + " " + strings.ToLower(kind)
+}
+
+// tallyNearMiss adds one prompted entry to a count
+func tallyNearMiss(c *NearMissCount, e Entry) {
+	if e.Status != "DENIED" {
+		c.Confirmed++
+		return
+	}
+	c.Denied++
+	if e.Impact != nil && (e.Impact.Severity == SeverityHigh || e.Impact.Severity == SeverityCritical) {
+		c.Severe++
+	}
+}
+
+// nearMissFor returns the count for a key, creating it if needed
+func nearMissFor(counts map[string]*NearMissCount, key string) *NearMissCount {
+	if key == "" {
+		key = "-"
+	}
+	c, ok := counts[key]
+	if !ok {
+		c = &NearMissCount{Key: key}
+		counts[key] = c
+	}
+	return c
+}
+
+// mostDenied orders counts by denials, then prompts, then key
+func mostDenied(a, b NearMissCount) bool {
+	if a.Denied != b.Denied {
+		return a.Denied > b.Denied
+	}
+	if a.Prompted() != b.Prompted() {
+		return a.Prompted() > b.Prompted()
+	}
+	return a.Key < b.Key
+}
+
+// sortedNearMisses flattens and orders counts
+func sortedNearMisses(counts map[string]*NearMissCount, less func(a, b NearMissCount) bool) []NearMissCount {
+	sorted := make([]NearMissCount, 0, len(counts))
+	for _, c := range counts {
+		sorted = append(sorted, *c)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+	return sorted
+}
@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNearMissVerdict(t *testing.T) {
+	tests := []struct {
+		name     string
+		count    NearMissCount
+		expected string
+	}{
+		{"frequently denied", NearMissCount{Confirmed: 3, Denied: 1}, VerdictPreventing},
+		{"severe denial", NearMissCount{Confirmed: 50, Denied: 1, Severe: 1}, VerdictPreventing},
+		{"always confirmed", NearMissCount{Confirmed: 12}, VerdictNoise},
+		{"rarely denied", NearMissCount{Confirmed: 40, Denied: 1}, VerdictNoise},
+		{"too few prompts", NearMissCount{Confirmed: 4}, ""},
+		{"in between", NearMissCount{Confirmed: 10, Denied: 1}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.count.Verdict(); got != tt.expected {
+			t.Errorf("%s: got %q, expected %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestSummarizeNearMisses(t *testing.T) {
+	entries := []Entry{
+		{Timestamp: "2024-01-10T09:00:00Z", Status: "EXECUTED", Operation: "delete", Resources: []string{"pod/a"}, Cluster: "prod", User: "alice", Confirmed: true},
+		{Timestamp: "2024-01-10T10:00:00Z", Status: "EXECUTED", Operation: "get", Resources: []string{"pods"}, Cluster: "prod", User: "alice"},
+		{Timestamp: "2024-01-11T09:00:00Z", Status: "DENIED", Operation: "delete", Resources: []string{"namespace/shop"}, Cluster: "prod", User: "bob", Variant: "blunt", Impact: &Impact{Objects: 1, Severity: SeverityCritical}},
+		{Timestamp: "2024-01-11T09:30:00Z", Status: "DENIED", Operation: "delete", Resources: []string{"Deployment/web@shop"}, Cluster: "dev", User: "bob"},
+		{Timestamp: "2024-01-12T09:00:00Z", Status: "RECREATED", Operation: "delete", Resources: []string{"pod/a"}, Cluster: "prod"},
+	}
+
+	summary := SummarizeNearMisses(entries)
+	if summary.First != "2024-01-10T09:00:00Z" || summary.Last != "2024-01-11T09:30:00Z" {
+		t.Errorf("range: got %s to %s", summary.First, summary.Last)
+	}
+	if expected := (NearMissCount{Key: "total", Confirmed: 1, Denied: 2, Severe: 1}); summary.Overall != expected {
+		t.Errorf("overall: got %+v, expected %+v", summary.Overall, expected)
+	}
+
+	expectedDays := []NearMissCount{{Key: "2024-01-10", Confirmed: 1}, {Key: "2024-01-11", Denied: 2, Severe: 1}}
+	if !reflect.DeepEqual(summary.ByDay, expectedDays) {
+		t.Errorf("by day: got %+v, expected %+v", summary.ByDay, expectedDays)
+	}
+	expectedUsers := []NearMissCount{{Key: "bob", Denied: 2, Severe: 1}, {Key: "alice", Confirmed: 1}}
+	if !reflect.DeepEqual(summary.ByUser, expectedUsers) {
+		t.Errorf("by user: got %+v, expected %+v", summary.ByUser, expectedUsers)
+	}
+	expectedRules := []NearMissCount{{Key: "delete deployment", Denied: 1}, {Key: "delete namespace", Denied: 1, Severe: 1}, {Key: "delete pod", Confirmed: 1}}
+	if !reflect.DeepEqual(summary.ByRule, expectedRules) {
+		t.Errorf("by rule: got %+v, expected %+v", summary.ByRule, expectedRules)
+	}
+	expectedVariants := []NearMissCount{{Key: "delete namespace / blunt", Denied: 1, Severe: 1}}
+	if !reflect.DeepEqual(summary.ByVariant, expectedVariants) {
+		t.Errorf("by variant: got %+v, expected %+v", summary.ByVariant, expectedVariants)
+	}
+}
@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Output identifies what an executed command printed, kept next to the audit
+// log with audit.captureOutput so reviewers see its result, not just its intent
+type Output struct {
+	SHA256    string `json:"sha256"`
+	Bytes     int    `json:"bytes"`               // size of what was kept
+	Truncated bool   `json:"truncated,omitempty"` // the command printed more than audit.captureOutputLimit
+}
+
+// archiveOutput keeps the output captured for an execution in
+// OutputArchivePath and returns the record for its entry, or nil if no output
+// was captured
+func (l *Logger) archiveOutput(x Execution) (*Output, error) {
+	if !l.config.Audit.Enabled || !l.config.Audit.CaptureOutput || x.Output == nil {
+		return nil, nil
+	}
+	sum := sha256.Sum256(x.Output)
+	output := &Output{SHA256: hex.EncodeToString(sum[:]), Bytes: len(x.Output), Truncated: x.OutputTruncated}
+
+	path := OutputArchivePath(l.config.Audit.Path, output.SHA256)
+	if _, err := os.Stat(path); err == nil {
+		return output, nil // the same output was archived before
+	}
+	// Output may show object contents, so the archive is private to the user
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return output, fmt.Errorf("failed to create output archive: %w", err)
+	}
+	if err := os.WriteFile(path, x.Output, 0600); err != nil {
+		return output, fmt.Errorf("failed to archive output: %w", err)
+	}
+	return output, nil
+}
+
+// OutputArchivePath is where captured output with the given SHA-256 is kept:
+// in an output directory next to the audit log, named by its hash
+func OutputArchivePath(auditPath, sha256 string) string {
+	return filepath.Join(filepath.Dir(auditPath), "output", sha256+".log")
+}
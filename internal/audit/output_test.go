@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+)
+
+func TestLogExecutedOutput(t *testing.T) {
+	output := []byte("deployment.apps \"web\" deleted\n")
+
+	tests := []struct {
+		name          string
+		capture       bool
+		expectArchive bool
+	}{
+		{"not captured", false, false},
+		{"captured", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := filepath.Join(t.TempDir(), "audit.log")
+			cfg := &config.Config{Audit: config.AuditConfig{Enabled: true, Path: logPath, CaptureOutput: tt.capture}}
+			result := &checker.CheckResult{Operation: "delete", Resources: []string{"deployment/web"}, Namespace: "app", Cluster: "prod"}
+			if err := New(cfg).LogExecuted(result, []string{"delete", "deployment", "web"}, true, Execution{Output: output}); err != nil {
+				t.Fatalf("LogExecuted returned error: %v", err)
+			}
+
+			entries, err := Query(logPath, Filter{})
+			if err != nil || len(entries) != 1 {
+				t.Fatalf("expected one entry, got %v, %v", entries, err)
+			}
+			if recorded := entries[0].Output != nil; recorded != tt.expectArchive {
+				t.Fatalf("output recorded: got %v, expected %v", recorded, tt.expectArchive)
+			}
+			if !tt.expectArchive {
+				return
+			}
+			archived, err := os.ReadFile(OutputArchivePath(logPath, sha256Hex(output)))
+			if err != nil || string(archived) != string(output) {
+				t.Errorf("archived output: got %q, %v", archived, err)
+			}
+		})
+	}
+}
+
+func TestParseLineOutput(t *testing.T) {
+	exitCode := 0
+	entry := Entry{
+		Timestamp: "2024-01-15T10:30:00Z",
+		Status:    "EXECUTED",
+		Operation: "delete",
+		Resources: []string{"deployment/web"},
+		Namespace: "app",
+		Cluster:   "prod",
+		Confirmed: true,
+		ExitCode:  &exitCode,
+		Duration:  "1s",
+		Output:    &Output{SHA256: "ab12", Bytes: 65536, Truncated: true},
+		Command:   "delete deployment web",
+	}
+
+	got, err := ParseLine(formatText(entry))
+	if err != nil {
+		t.Fatalf("ParseLine returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, entry) {
+		t.Errorf("got %+v, expected %+v", got, entry)
+	}
+}
@@ -0,0 +1,292 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// Filter selects audit entries. Empty fields and zero times match everything;
+// string fields are compared case-insensitively.
+type Filter struct {
+	Cluster   string
+	Namespace string // matches the entry namespace or a file-based resource's @namespace
+	Status    string // EXECUTED | DENIED | RECREATED | NOT_RECREATED
+	Operation string
+	Since     time.Time // inclusive
+	Until     time.Time // exclusive
+}
+
+// Matches reports whether an entry passes the filter. Entries whose timestamp
+// cannot be parsed never match a time range.
+func (f Filter) Matches(e Entry) bool {
+	if f.Cluster != "" && !strings.EqualFold(f.Cluster, e.Cluster) {
+		return false
+	}
+	if f.Status != "" && !strings.EqualFold(f.Status, e.Status) {
+		return false
+	}
+	if f.Operation != "" && !strings.EqualFold(f.Operation, e.Operation) {
+		return false
+	}
+	if f.Namespace != "" && !inNamespace(e, f.Namespace) {
+		return false
+	}
+	if f.Since.IsZero() && f.Until.IsZero() {
+		return true
+	}
+	ts, err := time.Parse(time.RFC3339, e.Timestamp)
+	if err != nil {
+		return false
+	}
+	if !f.Since.IsZero() && ts.Before(f.Since) {
+		return false
+	}
+	return f.Until.IsZero() || ts.Before(f.Until)
+}
+
+// inNamespace reports whether an entry is about the namespace. File-based
+// entries have no namespace of their own; it is baked into each resource as
+// KIND/NAME@NAMESPACE.
+func inNamespace(e Entry, namespace string) bool {
+	if strings.EqualFold(e.Namespace, namespace) {
+		return true
+	}
+	for _, r := range e.Resources {
+		if i := strings.LastIndexByte(r, '@'); i >= 0 && strings.EqualFold(r[i+1:], namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// Query reads an audit log in either format and returns the entries that
+// match the filter, oldest first. Lines that are not audit entries are skipped.
+func Query(path string, f Filter) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		e, err := ParseLine(scanner.Text())
+		if err != nil {
+			continue
+		}
+		e.ID = EntryID(scanner.Text())
+		if f.Matches(e) {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// ParseLine parses one audit log line written in the text or JSON format
+func ParseLine(line string) (Entry, error) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "{") {
+		var e Entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return Entry{}, fmt.Errorf("invalid JSON audit entry: %w", err)
+		}
+		return e, nil
+	}
+	return parseText(line)
+}
+
+// parseText parses the key=value format written by formatText:
+//
+//	[TIMESTAMP] STATUS | operation=... resources=[...] ... command="..."
+func parseText(line string) (Entry, error) {
+	var e Entry
+	rest, ok := strings.CutPrefix(line, "[")
+	if !ok {
+		return e, fmt.Errorf("not an audit entry: %q", line)
+	}
+	e.Timestamp, rest, ok = strings.Cut(rest, "] ")
+	if !ok {
+		return e, fmt.Errorf("not an audit entry: %q", line)
+	}
+	e.Status, rest, ok = strings.Cut(rest, " | ")
+	if !ok {
+		return e, fmt.Errorf("not an audit entry: %q", line)
+	}
+
+	// The command is last and may contain anything, including quotes
+	if i := strings.Index(rest, `command="`); i >= 0 {
+		e.Command = strings.TrimSuffix(rest[i+len(`command="`):], `"`)
+		rest = rest[:i]
+	}
+
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		key, value, _ := strings.Cut(rest, "=")
+		value, rest = textValue(value)
+		switch key {
+		case "operation":
+			e.Operation = value
+		case "resources", "resource":
+			e.Resources = textList(value)
+		case "namespace":
+			e.Namespace = value
+		case "cluster":
+			e.Cluster = value
+		case "user":
+			e.User = value
+		case "host":
+			e.Host = value
+		case "ssh":
+			e.SSHClient = value
+		case "proxy":
+			e.Proxy = value
+		case "impersonate":
+			e.ActingAs = textList(value)
+		case "confirmed":
+			e.Confirmed = value == "true"
+		case "variant":
+			e.Variant = value
+		case "roster":
+			e.Roster = value
+		case "ticket":
+			e.Ticket = value
+		case "justification":
+			e.Justification, _ = strconv.Unquote(value)
+		case "previous":
+			e.Previous = textList(value)
+		case "backups":
+			e.Backups = textList(value)
+		case "debugTarget":
+			debug(&e).Target = value
+		case "debugContainer":
+			debug(&e).Container = value
+		case "debugImage":
+			debug(&e).Image = value
+		case "debugProfile":
+			debug(&e).Profile = value
+		case "debugCopyTo":
+			debug(&e).CopyTo = value
+		case "stdinSHA256":
+			stdin(&e).SHA256 = value
+		case "stdinBytes":
+			stdin(&e).Bytes, _ = strconv.Atoi(value)
+		case "objects":
+			impact(&e).Objects, _ = strconv.Atoi(value)
+		case "namespaces":
+			impact(&e).Namespaces = textList(value)
+		case "severity":
+			impact(&e).Severity = value
+		case "exitCode":
+			if code, err := strconv.Atoi(value); err == nil {
+				e.ExitCode = &code
+			}
+		case "duration":
+			e.Duration = value
+		case "outputSHA256":
+			output(&e).SHA256 = value
+		case "outputBytes":
+			output(&e).Bytes, _ = strconv.Atoi(value)
+		case "outputTruncated":
+			output(&e).Truncated = value == "true"
+		case "args":
+			if args, err := strconv.Unquote(value); err == nil {
+				_ = json.Unmarshal([]byte(args), &e.Args)
+			}
+		case "prevHash":
+			e.PrevHash = value
+		}
+	}
+	return e, nil
+}
+
+// stdin returns the entry's stdin record, creating it on first use
+func stdin(e *Entry) *Stdin {
+	if e.Stdin == nil {
+		e.Stdin = &Stdin{}
+	}
+	return e.Stdin
+}
+
+// debug returns the entry's debug record, creating it on first use
+func debug(e *Entry) *parser.Debug {
+	if e.Debug == nil {
+		e.Debug = &parser.Debug{}
+	}
+	return e.Debug
+}
+
+// output returns the entry's output record, creating it on first use
+func output(e *Entry) *Output {
+	if e.Output == nil {
+		e.Output = &Output{}
+	}
+	return e.Output
+}
+
+// impact returns the entry's impact, creating it on first use
+func impact(e *Entry) *Impact {
+	if e.Impact == nil {
+		e.Impact = &Impact{}
+	}
+	return e.Impact
+}
+
+// textValue splits one value off the front of s. A bracketed list runs to its
+// closing bracket, a quoted string to its closing quote; anything else runs
+// to the next space.
+func textValue(s string) (value, rest string) {
+	if strings.HasPrefix(s, `"`) {
+		if quoted, err := strconv.QuotedPrefix(s); err == nil {
+			return quoted, s[len(quoted):]
+		}
+	}
+	end := " "
+	if strings.HasPrefix(s, "[") {
+		end = "] "
+	}
+	value, rest, ok := strings.Cut(s, end)
+	if ok && end == "] " {
+		value += "]"
+	}
+	return value, rest
+}
+
+// textList parses a bracketed, comma-separated list such as [a,b]
+func textList(value string) []string {
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// ParseSince parses a --since/--until value: a duration before now ("36h",
+// or days such as "7d"), an RFC 3339 timestamp, or a date (2006-01-02, UTC)
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: expected a duration such as 7d or 12h, an RFC 3339 timestamp, or a date (2006-01-02)", value)
+}
@@ -0,0 +1,167 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+)
+
+func TestParseLineRoundTrip(t *testing.T) {
+	code := 1
+	entry := Entry{
+		Timestamp: "2024-01-15T10:30:00Z",
+		Status:    "EXECUTED",
+		Operation: "patch",
+		Resources: []string{"cronjob/nightly", "cronjob/hourly"},
+		Namespace: "batch",
+		Cluster:   "prod",
+		User:      "alice",
+		Host:      "bastion",
+		SSHClient: "10.0.0.5",
+		Confirmed: true,
+		Variant:   "blunt",
+		Previous:  []string{"cronjob/nightly.spec.suspend=false"},
+		Backups:   []string{"pre-delete-data"},
+		ExitCode:  &code,
+		Duration:  "1.2s",
+		PrevHash:  genesisHash,
+		Command:   `patch cronjob nightly -p {"spec":{"suspend":true}} --field-manager="me"`,
+	}
+
+	js, err := formatJSON(entry)
+	if err != nil {
+		t.Fatalf("formatJSON: %v", err)
+	}
+	for name, line := range map[string]string{"text": formatText(entry), "json": js} {
+		got, err := ParseLine(line)
+		if err != nil {
+			t.Fatalf("%s: ParseLine returned error: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, entry) {
+			t.Errorf("%s: got %+v, expected %+v", name, got, entry)
+		}
+	}
+}
+
+func TestEntryArgv(t *testing.T) {
+	result := &checker.CheckResult{Operation: "label", Resources: []string{"pod/web"}, Namespace: "app", Cluster: "prod"}
+	tests := []struct {
+		name       string
+		args       []string
+		expectArgs bool
+	}{
+		{"plain arguments", []string{"label", "pod", "web", "tier=frontend"}, false},
+		{"argument with a space", []string{"label", "pod", "web", "--field-manager=ops team"}, true},
+		{"empty argument", []string{"label", "pod", "web", "note="}, false},
+		{"empty value", []string{"label", "pod", "web", "--field-manager", ""}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := commandEntry(result, tt.args, true, true)
+			if (entry.Args != nil) != tt.expectArgs {
+				t.Errorf("args: got %q, expected recorded %v", entry.Args, tt.expectArgs)
+			}
+			js, err := formatJSON(entry)
+			if err != nil {
+				t.Fatalf("formatJSON: %v", err)
+			}
+			for name, line := range map[string]string{"text": formatText(entry), "json": js} {
+				got, err := ParseLine(line)
+				if err != nil {
+					t.Fatalf("%s: ParseLine returned error: %v", name, err)
+				}
+				if !reflect.DeepEqual(got.Argv(), tt.args) {
+					t.Errorf("%s: Argv() got %q, expected %q", name, got.Argv(), tt.args)
+				}
+			}
+		})
+	}
+}
+
+func TestParseLineLegacyText(t *testing.T) {
+	got, err := ParseLine(`[2024-01-15T10:30:00+00:00] DENIED | operation=delete resources=[] namespace= cluster=dev confirmed=false command="delete pods --all"`)
+	if err != nil {
+		t.Fatalf("ParseLine returned error: %v", err)
+	}
+	if got.Status != "DENIED" || got.Cluster != "dev" || got.Namespace != "" || got.Resources != nil || got.Command != "delete pods --all" {
+		t.Errorf("unexpected entry: %+v", got)
+	}
+
+	if _, err := ParseLine("not an audit line"); err == nil {
+		t.Error("expected error for non-audit line")
+	}
+}
+
+func TestQuery(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	lines := []string{
+		`[2024-01-10T09:00:00Z] EXECUTED | operation=delete resources=[pod/a] namespace=web cluster=prod confirmed=true command="delete pod a -n web"`,
+		`[2024-01-14T09:00:00Z] DENIED | operation=delete resources=[pod/b] namespace=web cluster=prod confirmed=false command="delete pod b -n web"`,
+		`{"timestamp":"2024-01-15T09:00:00Z","status":"DENIED","operation":"apply","resources":["Deployment/api@payments"],"namespace":"","cluster":"prod","confirmed":false,"command":"apply -f api.yaml"}`,
+		`garbage`,
+		`[2024-01-15T10:00:00Z] DENIED | operation=delete resources=[pod/c] namespace=web cluster=staging confirmed=false command="delete pod c -n web"`,
+	}
+	if err := os.WriteFile(logPath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{"no filter", Filter{}, []string{"delete pod a -n web", "delete pod b -n web", "apply -f api.yaml", "delete pod c -n web"}},
+		{"cluster and status", Filter{Cluster: "prod", Status: "denied"}, []string{"delete pod b -n web", "apply -f api.yaml"}},
+		{"namespace of file-based resources", Filter{Namespace: "payments"}, []string{"apply -f api.yaml"}},
+		{"since", Filter{Since: time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)}, []string{"delete pod b -n web", "apply -f api.yaml", "delete pod c -n web"}},
+		{"until", Filter{Until: time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)}, []string{"delete pod a -n web"}},
+		{"operation", Filter{Operation: "apply"}, []string{"apply -f api.yaml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := Query(logPath, tt.filter)
+			if err != nil {
+				t.Fatalf("Query returned error: %v", err)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.Command)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value     string
+		expected  time.Time
+		expectErr bool
+	}{
+		{"7d", time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC), false},
+		{"36h", time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC), false},
+		{"2024-01-10T08:00:00Z", time.Date(2024, 1, 10, 8, 0, 0, 0, time.UTC), false},
+		{"2024-01-10", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), false},
+		{"last week", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSince(tt.value, now)
+		if (err != nil) != tt.expectErr {
+			t.Errorf("ParseSince(%q) error: got %v, expected error %v", tt.value, err, tt.expectErr)
+		}
+		if !got.Equal(tt.expected) {
+			t.Errorf("ParseSince(%q): got %v, expected %v", tt.value, got, tt.expected)
+		}
+	}
+}
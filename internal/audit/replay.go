@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// entryIDLength is how many hex digits of a line's SHA-256 make an entry ID
+const entryIDLength = 12
+
+// EntryID identifies the entry on an audit log line by the SHA-256 of the
+// line, so it stays the same in copies of the log and needs no extra field
+func EntryID(line string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(line)))
+	return hex.EncodeToString(sum[:])[:entryIDLength]
+}
+
+// Find returns the entry in the audit log whose ID starts with id
+func Find(path, id string) (Entry, error) {
+	if id == "" {
+		return Entry{}, fmt.Errorf("entry ID is empty")
+	}
+	entries, err := Query(path, Filter{})
+	if err != nil {
+		return Entry{}, err
+	}
+
+	var found []Entry
+	for _, e := range entries {
+		if strings.HasPrefix(e.ID, strings.ToLower(id)) {
+			found = append(found, e)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return Entry{}, fmt.Errorf("no audit entry %s in %s", id, path)
+	case 1:
+		return found[0], nil
+	}
+	return Entry{}, fmt.Errorf("audit entry ID %s is ambiguous: it matches %d entries", id, len(found))
+}
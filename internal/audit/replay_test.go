@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	lines := []string{
+		`[2024-01-10T09:00:00Z] EXECUTED | operation=apply resources=[ConfigMap/app@web] namespace= cluster=prod confirmed=true command="apply -f -"`,
+		`[2024-01-10T09:05:00Z] DENIED | operation=delete resources=[pod/a] namespace=web cluster=prod confirmed=false command="delete pod a"`,
+	}
+	path := filepath.Join(t.TempDir(), "audit.log")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	id := EntryID(lines[1])
+	entry, err := Find(path, strings.ToUpper(id[:6]))
+	if err != nil {
+		t.Fatalf("Find returned error: %v", err)
+	}
+	if entry.ID != id || entry.Command != "delete pod a" {
+		t.Errorf("got %s %q, expected %s", entry.ID, entry.Command, id)
+	}
+
+	if _, err := Find(path, "zz"); err == nil || !strings.Contains(err.Error(), "no audit entry zz") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if _, err := Find(path, ""); err == nil {
+		t.Error("expected an error for an empty ID")
+	}
+}
+
+func TestReadStdinArchive(t *testing.T) {
+	content := []byte("kind: ConfigMap\n")
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+	stdin := &Stdin{SHA256: sha256Hex(content), Bytes: len(content)}
+
+	if _, err := ReadStdinArchive(auditPath, stdin); err == nil || !strings.Contains(err.Error(), "enable audit.archiveStdin") {
+		t.Errorf("expected a not archived error, got %v", err)
+	}
+
+	archive := StdinArchivePath(auditPath, stdin.SHA256)
+	if err := os.MkdirAll(filepath.Dir(archive), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(archive, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadStdinArchive(auditPath, stdin)
+	if err != nil || string(got) != string(content) {
+		t.Errorf("got %q, %v", got, err)
+	}
+
+	if err := os.WriteFile(archive, []byte("kind: Secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadStdinArchive(auditPath, stdin); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("expected a mismatch error, got %v", err)
+	}
+}
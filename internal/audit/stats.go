@@ -0,0 +1,160 @@
+package audit
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Count tallies the executed and denied operations for one key
+type Count struct {
+	Key      string
+	Executed int
+	Denied   int
+}
+
+// Total is the number of operations counted
+func (c Count) Total() int {
+	return c.Executed + c.Denied
+}
+
+// DenialRate is the share of operations that were denied, from 0 to 1
+func (c Count) DenialRate() float64 {
+	if c.Total() == 0 {
+		return 0
+	}
+	return float64(c.Denied) / float64(c.Total())
+}
+
+// Stats summarizes audit entries. Only EXECUTED and DENIED entries are
+// operations; RECREATED and NOT_RECREATED follow-ups are not counted.
+type Stats struct {
+	First       string // timestamp of the oldest operation counted
+	Last        string // timestamp of the newest operation counted
+	Overall     Count
+	ByOperation []Count // busiest first
+	ByNamespace []Count // "-" for entries without a namespace, e.g. cluster-scoped ones
+	ByCluster   []Count
+}
+
+// Summarize aggregates entries, oldest first, into Stats
+func Summarize(entries []Entry) Stats {
+	stats := Stats{Overall: Count{Key: "total"}}
+	operations := map[string]*Count{}
+	namespaces := map[string]*Count{}
+	clusters := map[string]*Count{}
+
+	for _, e := range entries {
+		if e.Status != "EXECUTED" && e.Status != "DENIED" {
+			continue
+		}
+		if stats.First == "" {
+			stats.First = e.Timestamp
+		}
+		stats.Last = e.Timestamp
+
+		tally(&stats.Overall, e)
+		tally(countFor(operations, e.Operation), e)
+		tally(countFor(clusters, e.Cluster), e)
+		for _, ns := range entryNamespaces(e) {
+			tally(countFor(namespaces, ns), e)
+		}
+	}
+
+	stats.ByOperation = sortedCounts(operations)
+	stats.ByNamespace = sortedCounts(namespaces)
+	stats.ByCluster = sortedCounts(clusters)
+	return stats
+}
+
+// tally adds one entry to a count
+func tally(c *Count, e Entry) {
+	if e.Status == "DENIED" {
+		c.Denied++
+	} else {
+		c.Executed++
+	}
+}
+
+// countFor returns the count for a key, creating it if needed
+func countFor(counts map[string]*Count, key string) *Count {
+	if key == "" {
+		key = "-"
+	}
+	c, ok := counts[key]
+	if !ok {
+		c = &Count{Key: key}
+		counts[key] = c
+	}
+	return c
+}
+
+// entryNamespaces returns the namespaces an entry is about: its own, or for
+// file-based entries each distinct KIND/NAME@NAMESPACE namespace
+func entryNamespaces(e Entry) []string {
+	if e.Namespace != "" {
+		return []string{e.Namespace}
+	}
+	var namespaces []string
+	seen := map[string]bool{}
+	for _, r := range e.Resources {
+		if i := strings.LastIndexByte(r, '@'); i >= 0 && !seen[r[i+1:]] {
+			seen[r[i+1:]] = true
+			namespaces = append(namespaces, r[i+1:])
+		}
+	}
+	if len(namespaces) == 0 {
+		return []string{""}
+	}
+	return namespaces
+}
+
+// sortedCounts orders counts busiest first, then by key
+func sortedCounts(counts map[string]*Count) []Count {
+	sorted := make([]Count, 0, len(counts))
+	for _, c := range counts {
+		sorted = append(sorted, *c)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Total() != sorted[j].Total() {
+			return sorted[i].Total() > sorted[j].Total()
+		}
+		return sorted[i].Key < sorted[j].Key
+	})
+	return sorted
+}
+
+// WriteStatsCSV writes stats as CSV with one row per group and key:
+// group,key,total,executed,denied,denialRate
+func WriteStatsCSV(w io.Writer, stats Stats) error {
+	out := csv.NewWriter(w)
+	rows := [][]string{{"group", "key", "total", "executed", "denied", "denialRate"}}
+	groups := []struct {
+		name   string
+		counts []Count
+	}{
+		{"overall", []Count{stats.Overall}},
+		{"operation", stats.ByOperation},
+		{"namespace", stats.ByNamespace},
+		{"cluster", stats.ByCluster},
+	}
+	for _, g := range groups {
+		for _, c := range g.counts {
+			rows = append(rows, []string{
+				g.name,
+				c.Key,
+				strconv.Itoa(c.Total()),
+				strconv.Itoa(c.Executed),
+				strconv.Itoa(c.Denied),
+				strconv.FormatFloat(c.DenialRate(), 'f', 3, 64),
+			})
+		}
+	}
+	if err := out.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
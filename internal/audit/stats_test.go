@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	entries := []Entry{
+		{Timestamp: "2024-01-01T09:00:00Z", Status: "EXECUTED", Operation: "delete", Namespace: "web", Cluster: "prod"},
+		{Timestamp: "2024-01-02T09:00:00Z", Status: "RECREATED", Operation: "delete", Namespace: "web", Cluster: "prod"},
+		{Timestamp: "2024-01-03T09:00:00Z", Status: "DENIED", Operation: "delete", Namespace: "web", Cluster: "prod"},
+		{Timestamp: "2024-01-04T09:00:00Z", Status: "DENIED", Operation: "apply", Resources: []string{"Deployment/api@payments", "Service/api@payments", "ConfigMap/x@web"}, Cluster: "staging"},
+		{Timestamp: "2024-01-05T09:00:00Z", Status: "EXECUTED", Operation: "cordon", Resources: []string{"node/a"}, Cluster: "prod"},
+	}
+
+	stats := Summarize(entries)
+
+	if stats.First != "2024-01-01T09:00:00Z" || stats.Last != "2024-01-05T09:00:00Z" {
+		t.Errorf("range: got %s to %s", stats.First, stats.Last)
+	}
+	if stats.Overall != (Count{Key: "total", Executed: 2, Denied: 2}) {
+		t.Errorf("overall: got %+v", stats.Overall)
+	}
+	if rate := stats.Overall.DenialRate(); rate != 0.5 {
+		t.Errorf("denial rate: got %v, expected 0.5", rate)
+	}
+
+	expectedOperations := []Count{{Key: "delete", Executed: 1, Denied: 1}, {Key: "apply", Denied: 1}, {Key: "cordon", Executed: 1}}
+	if !reflect.DeepEqual(stats.ByOperation, expectedOperations) {
+		t.Errorf("by operation: got %+v, expected %+v", stats.ByOperation, expectedOperations)
+	}
+	expectedNamespaces := []Count{{Key: "web", Executed: 1, Denied: 2}, {Key: "-", Executed: 1}, {Key: "payments", Denied: 1}}
+	if !reflect.DeepEqual(stats.ByNamespace, expectedNamespaces) {
+		t.Errorf("by namespace: got %+v, expected %+v", stats.ByNamespace, expectedNamespaces)
+	}
+	expectedClusters := []Count{{Key: "prod", Executed: 2, Denied: 1}, {Key: "staging", Denied: 1}}
+	if !reflect.DeepEqual(stats.ByCluster, expectedClusters) {
+		t.Errorf("by cluster: got %+v, expected %+v", stats.ByCluster, expectedClusters)
+	}
+}
+
+func TestWriteStatsCSV(t *testing.T) {
+	stats := Summarize([]Entry{
+		{Timestamp: "2024-01-01T09:00:00Z", Status: "EXECUTED", Operation: "delete", Namespace: "web", Cluster: "prod"},
+		{Timestamp: "2024-01-02T09:00:00Z", Status: "DENIED", Operation: "delete", Namespace: "web", Cluster: "prod"},
+		{Timestamp: "2024-01-03T09:00:00Z", Status: "DENIED", Operation: "delete", Namespace: "web", Cluster: "prod"},
+	})
+
+	var buf bytes.Buffer
+	if err := WriteStatsCSV(&buf, stats); err != nil {
+		t.Fatalf("WriteStatsCSV returned error: %v", err)
+	}
+	expected := "group,key,total,executed,denied,denialRate\n" +
+		"overall,total,3,1,2,0.667\n" +
+		"operation,delete,3,1,2,0.667\n" +
+		"namespace,web,3,1,2,0.667\n" +
+		"cluster,prod,3,1,2,0.667\n"
+	if buf.String() != expected {
+		t.Errorf("got:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Stdin identifies the manifest content a command read from stdin, which
+// otherwise leaves no trace of what was applied
+type Stdin struct {
+	SHA256 string `json:"sha256"`
+	Bytes  int    `json:"bytes"`
+}
+
+// RecordStdin records the SHA-256 and size of manifest content read from
+// stdin in the entries the logger writes for file-based commands. With
+// audit.archiveStdin, the content is also kept in StdinArchivePath.
+func (l *Logger) RecordStdin(content []byte) error {
+	sum := sha256.Sum256(content)
+	l.stdin = &Stdin{SHA256: hex.EncodeToString(sum[:]), Bytes: len(content)}
+
+	if !l.config.Audit.Enabled || !l.config.Audit.ArchiveStdin {
+		return nil
+	}
+	path := StdinArchivePath(l.config.Audit.Path, l.stdin.SHA256)
+	if _, err := os.Stat(path); err == nil {
+		return nil // the same content was archived before
+	}
+	// Manifests may carry Secrets, so the archive is private to the user
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create stdin archive: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		return fmt.Errorf("failed to archive stdin: %w", err)
+	}
+	return nil
+}
+
+// StdinArchivePath is where archived stdin content with the given SHA-256 is
+// kept: in a stdin directory next to the audit log, named by its hash
+func StdinArchivePath(auditPath, sha256 string) string {
+	return filepath.Join(filepath.Dir(auditPath), "stdin", sha256+".yaml")
+}
+
+// ReadStdinArchive returns the archived stdin content an entry recorded,
+// checked against its SHA-256 and size
+func ReadStdinArchive(auditPath string, s *Stdin) ([]byte, error) {
+	content, err := os.ReadFile(StdinArchivePath(auditPath, s.SHA256))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("stdin content %s was not archived: enable audit.archiveStdin", s.SHA256)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stdin archive: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	if hex.EncodeToString(sum[:]) != s.SHA256 || len(content) != s.Bytes {
+		return nil, fmt.Errorf("archived stdin content %s does not match its recorded SHA-256 and size", s.SHA256)
+	}
+	return content, nil
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+)
+
+func TestRecordStdin(t *testing.T) {
+	content := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n")
+
+	tests := []struct {
+		name          string
+		archive       bool
+		expectArchive bool
+	}{
+		{"recorded", false, false},
+		{"archived", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := filepath.Join(t.TempDir(), "audit.log")
+			cfg := &config.Config{Audit: config.AuditConfig{Enabled: true, Path: logPath, ArchiveStdin: tt.archive}}
+			logger := New(cfg)
+			if err := logger.RecordStdin(content); err != nil {
+				t.Fatalf("RecordStdin returned error: %v", err)
+			}
+			result := &checker.ResourceCheckResult{
+				Operation: "apply",
+				Cluster:   "dev",
+				Resources: []manifest.Resource{{Kind: "ConfigMap", Name: "app", Namespace: "default"}},
+			}
+			if err := logger.LogResourcesExecuted(result, []string{"apply", "-f", "-"}, false, Execution{}); err != nil {
+				t.Fatalf("LogResourcesExecuted returned error: %v", err)
+			}
+
+			log, err := os.ReadFile(logPath)
+			if err != nil {
+				t.Fatalf("failed to read log file: %v", err)
+			}
+			expected := fmt.Sprintf(" stdinSHA256=%s stdinBytes=%d ", sha256Hex(content), len(content))
+			if !strings.Contains(string(log), expected) {
+				t.Errorf("expected %q in entry, got: %s", expected, log)
+			}
+
+			archived, err := os.ReadFile(StdinArchivePath(logPath, sha256Hex(content)))
+			if archived := err == nil; archived != tt.expectArchive {
+				t.Fatalf("archived: got %v, expected %v", archived, tt.expectArchive)
+			}
+			if tt.expectArchive && string(archived) != string(content) {
+				t.Errorf("archived content: got %q", archived)
+			}
+		})
+	}
+}
+
+func TestParseLineStdin(t *testing.T) {
+	exitCode := 0
+	entry := Entry{
+		Timestamp: "2024-01-15T10:30:00Z",
+		Status:    "EXECUTED",
+		Operation: "apply",
+		Resources: []string{"ConfigMap/app@default"},
+		Cluster:   "dev",
+		Stdin:     &Stdin{SHA256: "ab12", Bytes: 48},
+		ExitCode:  &exitCode,
+		Duration:  "1s",
+		Command:   "apply -f -",
+	}
+
+	got, err := ParseLine(formatText(entry))
+	if err != nil {
+		t.Fatalf("ParseLine returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, entry) {
+		t.Errorf("got %+v, expected %+v", got, entry)
+	}
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
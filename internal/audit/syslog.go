@@ -0,0 +1,45 @@
+package audit
+
+// syslogTag identifies safekubectl entries in the system log
+const syslogTag = "safekubectl"
+
+// Syslog severities used for audit entries
+const (
+	severityNotice  = "notice"
+	severityWarning = "warning"
+	severityErr     = "err"
+)
+
+// syslogWriter sends audit lines to the system log
+type syslogWriter interface {
+	Write(severity, line string) error
+	Close() error
+}
+
+// dialSyslog connects to the local system log with a facility name from
+// config.SyslogFacilities; replaced in tests
+var dialSyslog = openSyslog
+
+// syslogSeverity picks the severity of an entry: failed commands and
+// workloads that did not come back are errors, denials are warnings and
+// everything else is a notice
+func syslogSeverity(e Entry) string {
+	switch {
+	case e.Status == "NOT_RECREATED" || (e.ExitCode != nil && *e.ExitCode != 0):
+		return severityErr
+	case e.Status == "DENIED":
+		return severityWarning
+	default:
+		return severityNotice
+	}
+}
+
+// writeSyslog sends one rendered audit line to the system log
+func writeSyslog(facility string, e Entry, line string) error {
+	w, err := dialSyslog(facility)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	return w.Write(syslogSeverity(e), line)
+}
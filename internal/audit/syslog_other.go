@@ -0,0 +1,9 @@
+//go:build windows || plan9
+
+package audit
+
+import "errors"
+
+func openSyslog(facility string) (syslogWriter, error) {
+	return nil, errors.New("audit.sink syslog is not supported on this platform")
+}
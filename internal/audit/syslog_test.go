@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+)
+
+type fakeSyslog struct {
+	facility string
+	lines    []string
+	closed   bool
+}
+
+func (f *fakeSyslog) Write(severity, line string) error {
+	f.lines = append(f.lines, severity+" "+line)
+	return nil
+}
+
+func (f *fakeSyslog) Close() error {
+	f.closed = true
+	return nil
+}
+
+func useFakeSyslog(t *testing.T) *fakeSyslog {
+	fake := &fakeSyslog{}
+	original := dialSyslog
+	dialSyslog = func(facility string) (syslogWriter, error) {
+		fake.facility = facility
+		return fake, nil
+	}
+	t.Cleanup(func() { dialSyslog = original })
+	return fake
+}
+
+func TestLogSyslogSink(t *testing.T) {
+	fake := useFakeSyslog(t)
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	logger := New(&config.Config{Audit: config.AuditConfig{Enabled: true, Sink: "syslog", Facility: "local3", Path: logPath}})
+	result := &checker.CheckResult{Operation: "delete", Resources: []string{"pod/nginx"}, Namespace: "default", Cluster: "test-cluster"}
+
+	if err := logger.Log(result, []string{"delete", "pod", "nginx"}, false, false); err != nil {
+		t.Fatalf("Log() returned error: %v", err)
+	}
+	if err := logger.LogExecuted(result, []string{"delete", "pod", "nginx"}, true, Execution{ExitCode: 0}); err != nil {
+		t.Fatalf("LogExecuted() returned error: %v", err)
+	}
+	if err := logger.LogExecuted(result, []string{"delete", "pod", "nginx"}, true, Execution{ExitCode: 1}); err != nil {
+		t.Fatalf("LogExecuted() returned error: %v", err)
+	}
+
+	if fake.facility != "local3" || !fake.closed {
+		t.Errorf("facility: got %q (closed %v), expected local3", fake.facility, fake.closed)
+	}
+	expectedPrefixes := []string{"warning [", "notice [", "err ["}
+	if len(fake.lines) != len(expectedPrefixes) {
+		t.Fatalf("got %d syslog lines, expected %d: %v", len(fake.lines), len(expectedPrefixes), fake.lines)
+	}
+	for i, prefix := range expectedPrefixes {
+		if !strings.HasPrefix(fake.lines[i], prefix) || !strings.Contains(fake.lines[i], "operation=delete") {
+			t.Errorf("line %d: got %q, expected prefix %q", i, fake.lines[i], prefix)
+		}
+	}
+	if _, err := os.Stat(logPath); !os.IsNotExist(err) {
+		t.Error("syslog sink must not write the audit file")
+	}
+}
+
+func TestLogSyslogUnavailable(t *testing.T) {
+	original := dialSyslog
+	dialSyslog = func(facility string) (syslogWriter, error) { return nil, errors.New("no syslog daemon") }
+	t.Cleanup(func() { dialSyslog = original })
+
+	logger := New(&config.Config{Audit: config.AuditConfig{Enabled: true, Sink: "syslog"}})
+	result := &checker.CheckResult{Operation: "delete"}
+	if err := logger.Log(result, []string{"delete", "pod", "nginx"}, true, true); err == nil {
+		t.Error("expected error when syslog is unavailable")
+	}
+}
@@ -0,0 +1,59 @@
+//go:build !windows && !plan9
+
+package audit
+
+import (
+	"fmt"
+	"log/syslog"
+)
+
+var syslogFacilities = map[string]syslog.Priority{
+	"kern":     syslog.LOG_KERN,
+	"user":     syslog.LOG_USER,
+	"mail":     syslog.LOG_MAIL,
+	"daemon":   syslog.LOG_DAEMON,
+	"auth":     syslog.LOG_AUTH,
+	"syslog":   syslog.LOG_SYSLOG,
+	"lpr":      syslog.LOG_LPR,
+	"news":     syslog.LOG_NEWS,
+	"uucp":     syslog.LOG_UUCP,
+	"cron":     syslog.LOG_CRON,
+	"authpriv": syslog.LOG_AUTHPRIV,
+	"ftp":      syslog.LOG_FTP,
+	"local0":   syslog.LOG_LOCAL0,
+	"local1":   syslog.LOG_LOCAL1,
+	"local2":   syslog.LOG_LOCAL2,
+	"local3":   syslog.LOG_LOCAL3,
+	"local4":   syslog.LOG_LOCAL4,
+	"local5":   syslog.LOG_LOCAL5,
+	"local6":   syslog.LOG_LOCAL6,
+	"local7":   syslog.LOG_LOCAL7,
+}
+
+// unixSyslog writes to the local syslog daemon, which journald also serves
+type unixSyslog struct {
+	*syslog.Writer
+}
+
+func (w unixSyslog) Write(severity, line string) error {
+	switch severity {
+	case severityErr:
+		return w.Err(line)
+	case severityWarning:
+		return w.Warning(line)
+	default:
+		return w.Notice(line)
+	}
+}
+
+func openSyslog(facility string) (syslogWriter, error) {
+	priority, ok := syslogFacilities[facility]
+	if !ok {
+		priority = syslog.LOG_AUTH
+	}
+	w, err := syslog.New(priority|syslog.LOG_NOTICE, syslogTag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	return unixSyslog{w}, nil
+}
@@ -0,0 +1,148 @@
+// Package backup snapshots objects before they are deleted, so a mistaken
+// delete can be undone with kubectl create.
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// serverFields are metadata fields the API server sets, which must not be set
+// on an object that is created again
+var serverFields = []string{
+	"uid",
+	"resourceVersion",
+	"generation",
+	"creationTimestamp",
+	"deletionTimestamp",
+	"deletionGracePeriodSeconds",
+	"managedFields",
+	"selfLink",
+}
+
+// idFormat names each snapshot directory by the time it was taken
+const idFormat = "20060102T150405Z"
+
+// Backup is one snapshot directory and the object files it holds
+type Backup struct {
+	ID    string   // the directory name, e.g. 20240115T103000Z
+	Dir   string   // full path of the directory
+	Files []string // object file names, e.g. app_deployment_web.yaml
+}
+
+// Dir returns the directory for the snapshots taken at now under root
+func Dir(root string, now time.Time) string {
+	return filepath.Join(root, now.UTC().Format(idFormat))
+}
+
+// Clean strips the status and server-set metadata from an object printed by
+// kubectl get -o yaml, so the snapshot can be created again as is
+func Clean(content []byte) ([]byte, error) {
+	var obj map[string]any
+	if err := yaml.Unmarshal(content, &obj); err != nil {
+		return nil, fmt.Errorf("invalid object: %w", err)
+	}
+	delete(obj, "status")
+	if metadata, ok := obj["metadata"].(map[string]any); ok {
+		for _, field := range serverFields {
+			delete(metadata, field)
+		}
+	}
+	return yaml.Marshal(obj)
+}
+
+// Save writes a cleaned snapshot of an object to dir and returns its path.
+// Objects can hold Secrets, so snapshots are private to the user.
+func Save(dir, kind, name, namespace string, content []byte) (string, error) {
+	cleaned, err := Clean(content)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	file := strings.ToLower(kind) + "_" + name + ".yaml"
+	if namespace != "" {
+		file = namespace + "_" + file
+	}
+	path := filepath.Join(dir, file)
+	if err := os.WriteFile(path, cleaned, 0600); err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+	return path, nil
+}
+
+// RestoreCommand returns the kubectl command that creates the objects in a
+// snapshot directory again
+func RestoreCommand(dir, context string) string {
+	command := "kubectl create -f " + dir
+	if context != "" {
+		command += " --context " + context
+	}
+	return command
+}
+
+// List returns the snapshots under root, newest first. A missing root has no
+// snapshots; directories not named like a snapshot are skipped.
+func List(root string) ([]Backup, error) {
+	dirs, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+	var backups []Backup
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		if _, err := time.Parse(idFormat, d.Name()); err != nil {
+			continue
+		}
+		backup, err := read(root, d.Name())
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, backup)
+	}
+	slices.Reverse(backups)
+	return backups, nil
+}
+
+// Find returns the snapshot with the given ID under root
+func Find(root, id string) (Backup, error) {
+	if _, err := time.Parse(idFormat, id); err != nil {
+		return Backup{}, fmt.Errorf("invalid backup ID %q: expected a timestamp such as 20240115T103000Z", id)
+	}
+	backup, err := read(root, id)
+	if os.IsNotExist(err) {
+		return Backup{}, fmt.Errorf("no backup %s in %s", id, root)
+	}
+	return backup, err
+}
+
+// read lists the object files of one snapshot directory
+func read(root, id string) (Backup, error) {
+	dir := filepath.Join(root, id)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Backup{}, err
+		}
+		return Backup{}, fmt.Errorf("failed to read backup %s: %w", id, err)
+	}
+	backup := Backup{ID: id, Dir: dir}
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".yaml") {
+			backup.Files = append(backup.Files, e.Name())
+		}
+	}
+	return backup, nil
+}
@@ -0,0 +1,110 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const liveConfigMap = `apiVersion: v1
+data:
+  LOG_LEVEL: debug
+kind: ConfigMap
+metadata:
+  creationTimestamp: "2024-01-15T10:30:00Z"
+  labels:
+    app: web
+  managedFields:
+  - manager: kubectl
+  name: web
+  namespace: app
+  resourceVersion: "4711"
+  uid: 1b4e28ba-2fa1-11d2-883f-0016d3cca427
+status: {}
+`
+
+func TestClean(t *testing.T) {
+	cleaned, err := Clean([]byte(liveConfigMap))
+	if err != nil {
+		t.Fatalf("Clean returned error: %v", err)
+	}
+	for _, field := range []string{"creationTimestamp", "managedFields", "resourceVersion", "uid", "status"} {
+		if strings.Contains(string(cleaned), field) {
+			t.Errorf("expected %s to be removed, got:\n%s", field, cleaned)
+		}
+	}
+	for _, kept := range []string{"LOG_LEVEL: debug", "app: web", "name: web", "namespace: app"} {
+		if !strings.Contains(string(cleaned), kept) {
+			t.Errorf("expected %q to be kept, got:\n%s", kept, cleaned)
+		}
+	}
+
+	if _, err := Clean([]byte("not: [valid")); err == nil {
+		t.Error("expected an error for invalid YAML")
+	}
+}
+
+func TestSave(t *testing.T) {
+	dir := Dir(t.TempDir(), time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC))
+	if filepath.Base(dir) != "20240115T103000Z" {
+		t.Errorf("dir: got %s", dir)
+	}
+
+	path, err := Save(dir, "ConfigMap", "web", "app", []byte(liveConfigMap))
+	if err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if filepath.Base(path) != "app_configmap_web.yaml" {
+		t.Errorf("path: got %s", path)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected a private snapshot file, got %v, %v", info, err)
+	}
+
+	if got := RestoreCommand(dir, "prod"); got != "kubectl create -f "+dir+" --context prod" {
+		t.Errorf("restore command: got %s", got)
+	}
+}
+
+func TestListAndFind(t *testing.T) {
+	root := t.TempDir()
+	older := Dir(root, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC))
+	newer := Dir(root, time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC))
+	for _, save := range []struct{ dir, name string }{{older, "web"}, {newer, "web"}, {newer, "api"}} {
+		if _, err := Save(save.dir, "ConfigMap", save.name, "app", []byte(liveConfigMap)); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(root, "notes"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := List(root)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(backups) != 2 || backups[0].ID != "20240116T090000Z" || backups[1].ID != "20240115T103000Z" {
+		t.Fatalf("expected both snapshots, newest first, got %+v", backups)
+	}
+	if strings.Join(backups[0].Files, ",") != "app_configmap_api.yaml,app_configmap_web.yaml" {
+		t.Errorf("files: got %v", backups[0].Files)
+	}
+
+	if missing, err := List(filepath.Join(root, "missing")); err != nil || missing != nil {
+		t.Errorf("expected no snapshots in a missing root, got %v, %v", missing, err)
+	}
+
+	found, err := Find(root, "20240115T103000Z")
+	if err != nil || found.Dir != older || len(found.Files) != 1 {
+		t.Errorf("Find: got %+v, %v", found, err)
+	}
+	if _, err := Find(root, "20240101T000000Z"); err == nil || !strings.Contains(err.Error(), "no backup 20240101T000000Z") {
+		t.Errorf("expected a missing backup error, got %v", err)
+	}
+	if _, err := Find(root, "../etc"); err == nil || !strings.Contains(err.Error(), "invalid backup ID") {
+		t.Errorf("expected an invalid ID error, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+// Package batch tracks one logical change confirmed cluster by cluster, when a
+// loop runs safekubectl once per context with the same --sk-batch ID.
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// validID keeps batch IDs usable as file names
+var validID = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Batch is the progress of one change across clusters
+type Batch struct {
+	ID       string          `json:"id"`
+	Command  string          `json:"command"`  // the change, as run on the first cluster without --context
+	Clusters map[string]bool `json:"clusters"` // whether the change was confirmed, by cluster
+}
+
+// Load reads a batch from dir, or starts an empty one if it does not exist yet
+func Load(dir, id string) (*Batch, error) {
+	if !validID.MatchString(id) {
+		return nil, fmt.Errorf("invalid batch ID %q: use letters, digits, '.', '_' and '-'", id)
+	}
+	b := &Batch{ID: id, Clusters: make(map[string]bool)}
+	data, err := os.ReadFile(path(dir, id))
+	if os.IsNotExist(err) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch %s: %w", id, err)
+	}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("invalid batch %s: %w", id, err)
+	}
+	if b.Clusters == nil {
+		b.Clusters = make(map[string]bool)
+	}
+	return b, nil
+}
+
+// Save writes the batch to dir, replacing the previous state in one step
+func (b *Batch) Save(dir string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode batch %s: %w", b.ID, err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create batch directory: %w", err)
+	}
+	tmp := path(dir, b.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write batch %s: %w", b.ID, err)
+	}
+	if err := os.Rename(tmp, path(dir, b.ID)); err != nil {
+		return fmt.Errorf("failed to write batch %s: %w", b.ID, err)
+	}
+	return nil
+}
+
+// Record stores the decision for a cluster. The first recorded command
+// defines the batch's change.
+func (b *Batch) Record(cluster, command string, confirmed bool) {
+	if b.Command == "" {
+		b.Command = command
+	}
+	b.Clusters[cluster] = confirmed
+}
+
+// Differs reports whether command is not the change the batch started with
+func (b *Batch) Differs(command string) bool {
+	return b.Command != "" && b.Command != command
+}
+
+// Confirmed returns the clusters the change was confirmed on, sorted
+func (b *Batch) Confirmed() []string {
+	return b.decided(true)
+}
+
+// Denied returns the clusters the change was denied on, sorted
+func (b *Batch) Denied() []string {
+	return b.decided(false)
+}
+
+func (b *Batch) decided(confirmed bool) []string {
+	var clusters []string
+	for cluster, c := range b.Clusters {
+		if c == confirmed {
+			clusters = append(clusters, cluster)
+		}
+	}
+	sort.Strings(clusters)
+	return clusters
+}
+
+func path(dir, id string) string {
+	return filepath.Join(dir, id+".json")
+}
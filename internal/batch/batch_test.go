@@ -0,0 +1,43 @@
+package batch
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	dir := t.TempDir()
+
+	b, err := Load(dir, "rotate-certs")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(b.Clusters) != 0 || b.Differs("delete secret tls -n web") {
+		t.Fatalf("expected a new empty batch, got %+v", b)
+	}
+	b.Record("prod-a", "delete secret tls -n web", true)
+	b.Record("prod-b", "delete secret tls -n web", false)
+	if err := b.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	loaded, err := Load(dir, "rotate-certs")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	loaded.Record("prod-c", "delete secret tls -n web", true)
+	if got := loaded.Confirmed(); !reflect.DeepEqual(got, []string{"prod-a", "prod-c"}) {
+		t.Errorf("confirmed: got %v", got)
+	}
+	if got := loaded.Denied(); !reflect.DeepEqual(got, []string{"prod-b"}) {
+		t.Errorf("denied: got %v", got)
+	}
+	if !loaded.Differs("delete secret tls -n api") || loaded.Differs("delete secret tls -n web") {
+		t.Errorf("expected only a different command to differ from %q", loaded.Command)
+	}
+
+	if _, err := Load(dir, "../escape"); err == nil || !strings.Contains(err.Error(), "invalid batch ID") {
+		t.Errorf("expected an invalid ID error, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+// Package bundle packs the files of a support bundle into a gzipped tarball,
+// so a user can attach everything a maintainer needs to one bug report.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"time"
+)
+
+// File is one file in the bundle
+type File struct {
+	Name string
+	Data []byte
+}
+
+// Write writes the files as a gzipped tarball with every file under dir, so
+// extracting it does not scatter them in the current directory
+func Write(w io.Writer, dir string, files []File, modTime time.Time) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, f := range files {
+		header := &tar.Header{
+			Name:    path.Join(dir, f.Name),
+			Mode:    0600,
+			Size:    int64(len(f.Data)),
+			ModTime: modTime,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Name, err)
+		}
+		if _, err := tw.Write(f.Data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Read returns the files of a bundle written by Write, with their full names
+func Read(r io.Reader) ([]File, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var files []File
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Name: header.Name, Data: data})
+	}
+}
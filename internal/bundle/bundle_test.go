@@ -0,0 +1,37 @@
+package bundle
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWriteAndRead(t *testing.T) {
+	files := []File{
+		{Name: "config.yaml", Data: []byte("mode: confirm\n")},
+		{Name: "version.txt", Data: []byte("safekubectl: (devel)\n")},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, "safekubectl-support", files, time.Now()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	expected := []File{
+		{Name: "safekubectl-support/config.yaml", Data: []byte("mode: confirm\n")},
+		{Name: "safekubectl-support/version.txt", Data: []byte("safekubectl: (devel)\n")},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %+v, expected %+v", got, expected)
+	}
+}
+
+func TestReadRejectsNonGzip(t *testing.T) {
+	if _, err := Read(bytes.NewReader([]byte("not a bundle"))); err == nil {
+		t.Error("expected error for a file that is not a gzipped tarball")
+	}
+}
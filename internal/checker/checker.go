@@ -1,23 +1,84 @@
 package checker
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
 	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
 	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
 	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/podsecurity"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/priority"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/quota"
 )
 
 // CheckResult contains the result of a danger check
 type CheckResult struct {
-	IsDangerous          bool
-	RequiresConfirmation bool
-	IsNodeScoped         bool
-	IsAllNamespaces      bool
-	IsDryRun             bool
-	Operation            string
-	Resources            []string // display string per target, e.g. ["secret/a", "secret/b"]
-	Namespace            string
-	Cluster              string
-	Reasons              []string
+	IsDangerous          bool          `json:"dangerous"`
+	RequiresConfirmation bool          `json:"requiresConfirmation"`
+	ModeConfirmation     bool          `json:"-"` // confirmation is required only by mode or a protected namespace or cluster, which a warn-only group policy lifts
+	IsNodeScoped         bool          `json:"nodeScoped"`
+	IsClusterScoped      bool          `json:"clusterScoped"` // every target is a cluster-scoped resource (no namespace)
+	IsAllNamespaces      bool          `json:"allNamespaces"`
+	IsDryRun             bool          `json:"dryRun"`
+	Operation            string        `json:"operation"`
+	Resources            []string      `json:"resources"` // display string per target, e.g. ["secret/a", "secret/b"]
+	Namespace            string        `json:"namespace"`
+	Cluster              string        `json:"cluster"`
+	Identity             string        `json:"identity,omitempty"`    // user or service account performing the operation, if known
+	Proxy                string        `json:"proxy,omitempty"`       // proxy the request to the API server goes through, if any
+	ActingAs             []string      `json:"impersonate,omitempty"` // impersonated user, groups and UID, e.g. "user:alice"
+	Reasons              []string      `json:"reasons"`
+	CascadeNamespaces    []string      `json:"cascadeNamespaces,omitempty"`  // namespaces whose deletion removes everything inside them
+	ConfirmationPhrase   string        `json:"confirmationPhrase,omitempty"` // non-empty when the user must type this text to confirm
+	Previous             []string      `json:"previous,omitempty"`           // values the command changes, for restoring, e.g. "cronjob/x.spec.suspend=false"
+	Variant              string        `json:"variant,omitempty"`            // warning experiment variant shown, if any
+	Roster               string        `json:"roster,omitempty"`             // on-call roster decision, with onCall
+	Ticket               string        `json:"ticket,omitempty"`             // change ticket the operator gave, with ticket.required
+	Justification        string        `json:"justification,omitempty"`      // why the operator made the change, with audit.requireReason
+	ProtectedNodes       []string      `json:"protectedNodes,omitempty"`     // nodes the command changes that match protectedNodes
+	Debug                *parser.Debug `json:"debug,omitempty"`              // what kubectl debug attaches to and runs
+}
+
+// hasWarnings reports whether any check found something to warn about, which
+// makes a command dangerous even when its operation is not
+func hasWarnings(reasons ...[]string) bool {
+	for _, r := range reasons {
+		if len(r) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireConfirmation requires confirmation whatever the mode, so that a
+// warn-only group policy does not lift it
+func (r *CheckResult) RequireConfirmation() {
+	r.RequiresConfirmation = true
+	r.ModeConfirmation = false
+}
+
+// readOnlyOperations never modify cluster state, even on protected kinds
+var readOnlyOperations = map[string]bool{
+	"get":           true,
+	"describe":      true,
+	"explain":       true,
+	"logs":          true,
+	"top":           true,
+	"diff":          true,
+	"wait":          true,
+	"events":        true,
+	"auth":          true,
+	"api-resources": true,
+	"api-versions":  true,
+	"cluster-info":  true,
+	"version":       true,
+}
+
+// IsReadOnlyOperation reports whether an operation never modifies cluster state
+func IsReadOnlyOperation(operation string) bool {
+	return readOnlyOperations[operation]
 }
 
 // Checker checks if kubectl commands are dangerous
@@ -36,6 +97,7 @@ func New(cfg *config.Config) *Checker {
 func (c *Checker) Check(cmd *parser.KubectlCommand, cluster string) *CheckResult {
 	namespace := cmd.GetNamespaceDisplay()
 	isNodeScoped := cmd.IsNodeScoped()
+	isClusterScoped := cmd.IsClusterScoped()
 
 	result := &CheckResult{
 		Operation:       cmd.Operation,
@@ -43,6 +105,7 @@ func (c *Checker) Check(cmd *parser.KubectlCommand, cluster string) *CheckResult
 		Namespace:       namespace,
 		Cluster:         cluster,
 		IsNodeScoped:    isNodeScoped,
+		IsClusterScoped: isClusterScoped,
 		IsAllNamespaces: cmd.AllNamespaces,
 		IsDryRun:        cmd.DryRun,
 		Reasons:         []string{},
@@ -53,8 +116,91 @@ func (c *Checker) Check(cmd *parser.KubectlCommand, cluster string) *CheckResult
 		return result
 	}
 
+	// Namespace deletion cascades to every resource inside it, so it is
+	// guarded even when delete is not in the dangerous operations list
+	isNamespaceDeletion := cmd.Operation == "delete" && targetsNamespaces(cmd.Targets)
+
+	// Changes to protected kinds are guarded whatever the operation
+	var protectedKinds []string
+	if !readOnlyOperations[cmd.Operation] {
+		protectedKinds = c.protectedTargetKinds(cmd.Targets)
+	}
+
+	// Weakening Pod Security on a namespace is guarded whatever the operation
+	var podSecurityReasons []string
+	if cmd.Operation == "label" && targetsNamespaces(cmd.Targets) {
+		podSecurityReasons = podsecurity.WeakeningReasons(podsecurity.LabelChanges(cmd.Positionals))
+	}
+
+	// Removing or loosening quotas and limits in protected namespaces is guarded whatever the operation
+	guardrailReasons := c.guardrailReasons(cmd, namespace)
+
+	// Deleting scheduled work in protected namespaces is guarded whatever the operation
+	cronJobReasons := c.cronJobDeletionReasons(cmd, namespace)
+
+	// Changing the objects every namespace relies on to start pods is guarded whatever the operation
+	var namespaceDefaultReasons []string
+	if !readOnlyOperations[cmd.Operation] {
+		namespaceDefaultReasons = namespaceDefaultTargetReasons(cmd, namespace)
+	}
+
+	// Changes to protected nodes are guarded whatever the operation
+	if !readOnlyOperations[cmd.Operation] {
+		result.ProtectedNodes = c.protectedNodes(cmd.NodeNames())
+	}
+
+	// Debugging nodes and pods is guarded whatever the operation
+	result.Debug = cmd.DebugOptions()
+	var debugReasons []string
+	if result.Debug != nil {
+		result.Resources = []string{result.Debug.Target}
+		debugReasons = c.debugReasons(result.Debug, cluster)
+	}
+
+	// Configured -A reads on protected clusters are confirmed like writes
+	allNamespacesReadReasons := c.allNamespacesReadReasons(cmd, cluster)
+
+	// Reads that print Secret contents are flagged whatever the operation
+	var secretReasons []string
+	if c.config.SecretExposure.Enabled {
+		secretReasons = secretExposureReasons(cmd)
+	}
+
+	// Plugins outside kubectlPlugins.allow are flagged as kubectlPlugins.unknown says
+	var pluginReasons []string
+	if action := c.config.KubectlPlugins.Unknown; (action == config.PluginWarn || action == config.PluginConfirm) && cmd.Operation != "" && !parser.IsKubectlCommand(cmd.Operation) && !c.config.KubectlPlugins.IsAllowed(cmd.Operation) {
+		pluginReasons = append(pluginReasons, "kubectl plugin: "+cmd.Operation+" is not in kubectlPlugins.allow")
+	}
+
+	// Switching the current context into a protected cluster is confirmed with contextSwitch.confirm
+	var contextSwitchReasons []string
+	if cmd.SwitchedContext() != "" && c.config.ContextSwitch.Confirm && c.config.IsProtectedCluster(cluster) {
+		contextSwitchReasons = append(contextSwitchReasons, "context switch: commands without --context will run against protected cluster "+cluster)
+	}
+
 	// Only check if operation is dangerous first
-	if !c.config.IsDangerousOperation(cmd.Operation) {
+	if !c.config.IsDangerousOperation(cmd.Operation) && !isNamespaceDeletion && !hasWarnings(protectedKinds, podSecurityReasons, guardrailReasons, cronJobReasons, namespaceDefaultReasons, result.ProtectedNodes, debugReasons) {
+		if len(allNamespacesReadReasons) > 0 {
+			result.IsDangerous = true
+			result.Reasons = append(result.Reasons, allNamespacesReadReasons...)
+			result.Reasons = append(result.Reasons, "protected cluster: "+cluster)
+			result.RequiresConfirmation = true
+		}
+		if len(secretReasons) > 0 {
+			result.IsDangerous = true
+			result.Reasons = append(result.Reasons, secretReasons...)
+			result.RequiresConfirmation = result.RequiresConfirmation || c.config.SecretExposure.Confirm
+		}
+		if len(pluginReasons) > 0 {
+			result.IsDangerous = true
+			result.Reasons = append(result.Reasons, pluginReasons...)
+			result.RequiresConfirmation = result.RequiresConfirmation || c.config.KubectlPlugins.Unknown == config.PluginConfirm
+		}
+		if len(contextSwitchReasons) > 0 {
+			result.IsDangerous = true
+			result.Reasons = append(result.Reasons, contextSwitchReasons...)
+			result.RequiresConfirmation = true
+		}
 		// Safe operations pass through without warning
 		return result
 	}
@@ -62,14 +208,63 @@ func (c *Checker) Check(cmd *parser.KubectlCommand, cluster string) *CheckResult
 	result.IsDangerous = true
 	result.Reasons = append(result.Reasons, "dangerous operation: "+cmd.Operation)
 
+	if isNamespaceDeletion {
+		c.checkNamespaceDeletion(cmd, result)
+		return result
+	}
+
 	// All-namespaces is especially dangerous
 	if cmd.AllNamespaces {
 		result.Reasons = append(result.Reasons, "AFFECTS ALL NAMESPACES (-A/--all-namespaces)")
 		result.RequiresConfirmation = true // Always require confirmation for all-namespaces
 	}
 
+	for _, kind := range protectedKinds {
+		result.Reasons = append(result.Reasons, c.config.ProtectedKindReason(kind))
+		result.RequiresConfirmation = true // Always require confirmation for protected kinds
+	}
+
+	for _, node := range result.ProtectedNodes {
+		result.Reasons = append(result.Reasons, "protected node: "+node)
+		result.RequiresConfirmation = true // Always require confirmation for protected nodes
+	}
+
+	for _, reason := range podSecurityReasons {
+		result.Reasons = append(result.Reasons, "pod security: "+reason)
+		result.RequiresConfirmation = true // Always require confirmation for weakened Pod Security
+	}
+
+	for _, reason := range guardrailReasons {
+		result.Reasons = append(result.Reasons, "governance: "+reason)
+		result.RequiresConfirmation = true // Always require confirmation for weakened guardrails
+	}
+	result.Reasons = append(result.Reasons, cronJobReasons...)
+	if reason := CascadeReason(cmd.Operation, cmd.Cascade); reason != "" {
+		result.Reasons = append(result.Reasons, reason)
+	}
+	result.Reasons = append(result.Reasons, debugReasons...)
+	if result.Debug != nil && c.config.IsProtectedCluster(cluster) && (result.Debug.IsNode() || result.Debug.Profile == "sysadmin") {
+		result.RequiresConfirmation = true // Always require confirmation for host-level access to protected clusters
+	}
+	if len(namespaceDefaultReasons) > 0 {
+		result.Reasons = append(result.Reasons, namespaceDefaultReasons...)
+		result.RequiresConfirmation = true // Always require confirmation for namespace defaults
+	}
+	if len(allNamespacesReadReasons) > 0 {
+		result.Reasons = append(result.Reasons, allNamespacesReadReasons...)
+		result.RequiresConfirmation = true
+	}
+	if len(secretReasons) > 0 {
+		result.Reasons = append(result.Reasons, secretReasons...)
+		result.RequiresConfirmation = result.RequiresConfirmation || c.config.SecretExposure.Confirm
+	}
+	if len(contextSwitchReasons) > 0 {
+		result.Reasons = append(result.Reasons, contextSwitchReasons...)
+		result.RequiresConfirmation = true
+	}
+
 	// Add additional context if in protected namespace/cluster (only if not all-namespaces)
-	if !cmd.AllNamespaces && !isNodeScoped && c.config.IsProtectedNamespace(namespace) {
+	if !cmd.AllNamespaces && !isNodeScoped && !isClusterScoped && c.config.IsProtectedNamespace(namespace) {
 		result.Reasons = append(result.Reasons, "protected namespace: "+namespace)
 	}
 	if c.config.IsProtectedCluster(cluster) {
@@ -78,20 +273,381 @@ func (c *Checker) Check(cmd *parser.KubectlCommand, cluster string) *CheckResult
 
 	// Determine if confirmation is required
 	if !result.RequiresConfirmation {
-		result.RequiresConfirmation = c.config.RequiresConfirmation(namespace, cluster)
+		confirmNamespace := namespace
+		if isClusterScoped {
+			confirmNamespace = "" // no namespace applies to cluster-scoped resources
+		}
+		result.RequiresConfirmation = c.config.RequiresConfirmation(confirmNamespace, cluster)
+		result.ModeConfirmation = result.RequiresConfirmation
 	}
 
 	return result
 }
 
+// checkNamespaceDeletion fills in the result for delete namespace commands.
+// The -n namespace is irrelevant here; the deleted namespaces are checked instead.
+func (c *Checker) checkNamespaceDeletion(cmd *parser.KubectlCommand, result *CheckResult) {
+	result.IsClusterScoped = true // namespaces are cluster-scoped, no -n namespace applies
+
+	for _, t := range cmd.Targets {
+		if parser.KindFor(t.Resource) == "Namespace" && t.Name != "" {
+			result.CascadeNamespaces = append(result.CascadeNamespaces, t.Name)
+		}
+	}
+
+	if len(result.CascadeNamespaces) > 0 {
+		result.Reasons = append(result.Reasons, "namespace deletion removes ALL resources in: "+strings.Join(result.CascadeNamespaces, ", "))
+		result.ConfirmationPhrase = strings.Join(result.CascadeNamespaces, ",")
+	} else {
+		result.Reasons = append(result.Reasons, "namespace deletion removes ALL resources in every matched namespace")
+		result.ConfirmationPhrase = "delete namespaces"
+	}
+
+	if cmd.Cascade == "orphan" {
+		result.Reasons = append(result.Reasons, "cascade=orphan: does not apply to namespace deletion; everything in the namespace is deleted all the same")
+	}
+
+	for _, ns := range result.CascadeNamespaces {
+		if c.config.IsProtectedNamespace(ns) {
+			result.Reasons = append(result.Reasons, "protected namespace: "+ns)
+		}
+	}
+	if c.config.IsProtectedCluster(result.Cluster) {
+		result.Reasons = append(result.Reasons, "protected cluster: "+result.Cluster)
+	}
+
+	// Always typed confirmation, regardless of mode
+	result.RequiresConfirmation = true
+}
+
+// debugReasons describes the access a kubectl debug command gets: a node's
+// host namespaces and filesystem, a privileged container, or an ephemeral
+// container that stays in the pod until it is deleted
+func (c *Checker) debugReasons(d *parser.Debug, cluster string) []string {
+	image := d.Image
+	if image == "" {
+		image = "the default image"
+	}
+	var reasons []string
+	switch {
+	case d.IsNode():
+		reasons = append(reasons, fmt.Sprintf("debug: %s runs %s in a pod sharing the node's host namespaces, with its root filesystem at /host", d.Target, image))
+	case d.CopyTo != "":
+		reasons = append(reasons, fmt.Sprintf("debug: creates pod %s, a copy of %s", d.CopyTo, d.Target))
+	default:
+		container := ""
+		if d.Container != "" {
+			container = ", sharing the processes of container " + d.Container
+		}
+		reasons = append(reasons, fmt.Sprintf("debug: adds an ephemeral container running %s to %s%s; it cannot be removed until the pod is deleted", image, d.Target, container))
+	}
+	switch d.Profile {
+	case "sysadmin":
+		reasons = append(reasons, "debug: --profile=sysadmin runs the debug container privileged")
+	case "netadmin":
+		reasons = append(reasons, "debug: --profile=netadmin grants the debug container NET_ADMIN and NET_RAW")
+	}
+	if (d.IsNode() || d.Profile == "sysadmin") && c.config.IsProtectedCluster(cluster) {
+		reasons = append(reasons, "debug: host-level access to a protected cluster")
+	}
+	return reasons
+}
+
+// CascadeReason describes what a delete's --cascade mode does to the
+// dependents of the deleted objects, or returns "" for other operations and
+// deletes without --cascade
+func CascadeReason(operation, cascade string) string {
+	if operation != "delete" {
+		return ""
+	}
+	switch cascade {
+	case "orphan":
+		return "cascade=orphan: dependents, such as a Deployment's ReplicaSets and Pods, keep running with no owner and are never cleaned up"
+	case "foreground":
+		return "cascade=foreground: dependents are deleted first; the object remains, marked for deletion, until they are gone"
+	case "background":
+		return "cascade=background: the object is deleted at once and the garbage collector deletes its dependents afterwards"
+	case "":
+		return ""
+	}
+	return "cascade=" + cascade + ": not a mode kubectl knows (background, foreground or orphan); the delete fails"
+}
+
+// targetsNamespaces returns true if any target is a namespace
+func targetsNamespaces(targets []parser.Target) bool {
+	for _, t := range targets {
+		if parser.KindFor(t.Resource) == "Namespace" {
+			return true
+		}
+	}
+	return false
+}
+
+// protectedTargetKinds returns the distinct protected kinds among the targets.
+// Unknown resource names (e.g. custom resources) are matched by name as given.
+func (c *Checker) protectedTargetKinds(targets []parser.Target) []string {
+	seen := make(map[string]bool)
+	var kinds []string
+	for _, t := range targets {
+		kind := parser.KindFor(t.Resource)
+		if kind == "" {
+			kind = t.Resource
+		}
+		if kind == "" || seen[kind] || !c.config.IsProtectedKind(kind) {
+			continue
+		}
+		seen[kind] = true
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+// allNamespacesReadReasons describes -A reads of resources listed in
+// allNamespacesReads, on protected clusters only
+func (c *Checker) allNamespacesReadReasons(cmd *parser.KubectlCommand, cluster string) []string {
+	if !cmd.AllNamespaces || !c.config.IsProtectedCluster(cluster) {
+		return nil
+	}
+
+	var reasons []string
+	for _, t := range cmd.Targets {
+		for _, entry := range c.config.AllNamespacesReads {
+			fields := strings.Fields(entry)
+			if len(fields) == 0 || fields[0] != cmd.Operation || (len(fields) > 1 && !parser.SameResource(fields[1], t.Resource)) {
+				continue
+			}
+			reasons = append(reasons, fmt.Sprintf("reads %s across all namespaces (-A) on a protected cluster", t.Resource))
+			break
+		}
+	}
+	return reasons
+}
+
+// secretOutputFormats are the -o formats that print a Secret's data. Template
+// formats only do when the template reads .data, or may from a file.
+var secretOutputFormats = map[string]bool{
+	"yaml": true,
+	"json": true,
+}
+
+// secretTemplateFormats are the -o formats that take a template
+var secretTemplateFormats = map[string]bool{
+	"jsonpath":            true,
+	"jsonpath-as-json":    true,
+	"go-template":         true,
+	"template":            true,
+	"custom-columns":      true,
+	"jsonpath-file":       true,
+	"go-template-file":    true,
+	"templatefile":        true,
+	"custom-columns-file": true,
+}
+
+// secretExposureReasons describes get and describe commands on Secrets whose
+// output shows their contents: get -o yaml/json or a template reading .data,
+// and describe, which lists the data keys and value sizes
+func secretExposureReasons(cmd *parser.KubectlCommand) []string {
+	if cmd.Operation != "get" && cmd.Operation != "describe" {
+		return nil
+	}
+
+	var exposure string // describes the exposure of %s, the secrets
+	switch format, template, _ := strings.Cut(outputFlag(cmd.Args), "="); {
+	case cmd.Operation == "describe":
+		exposure = "describe shows the data keys and value sizes of %s"
+	case secretOutputFormats[format]:
+		exposure = "get -o " + format + " prints the values of %s to the terminal; base64 is not encryption"
+	case secretTemplateFormats[format] && (strings.HasSuffix(format, "file") || strings.Contains(template, ".data")):
+		exposure = "get -o " + format + " may print the values of %s to the terminal"
+	default:
+		return nil
+	}
+
+	var reasons []string
+	for _, t := range cmd.Targets {
+		if parser.KindFor(t.Resource) != "Secret" {
+			continue
+		}
+		what := "secret/" + t.Name
+		switch {
+		case t.Name != "":
+		case cmd.AllNamespaces:
+			what = "every secret in the cluster"
+		default:
+			what = "every secret in the namespace"
+		}
+		reasons = append(reasons, "secret exposure: "+fmt.Sprintf(exposure, what))
+	}
+	return reasons
+}
+
+// outputFlag returns the -o/--output value ahead of any "--" separator
+func outputFlag(args []string) string {
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return ""
+		case (arg == "-o" || arg == "--output") && i+1 < len(args):
+			return args[i+1]
+		case strings.HasPrefix(arg, "--output="):
+			return strings.TrimPrefix(arg, "--output=")
+		case strings.HasPrefix(arg, "-o") && arg != "-o":
+			return strings.TrimPrefix(strings.TrimPrefix(arg, "-o"), "=")
+		}
+	}
+	return ""
+}
+
+// guardrailOperations can remove or loosen a ResourceQuota or LimitRange
+var guardrailOperations = map[string]bool{
+	"delete":  true,
+	"patch":   true,
+	"edit":    true,
+	"replace": true,
+}
+
+// guardrailReasons describes ResourceQuota and LimitRange targets in
+// protected namespaces that the command deletes or may weaken
+func (c *Checker) guardrailReasons(cmd *parser.KubectlCommand, namespace string) []string {
+	if !guardrailOperations[cmd.Operation] || (!cmd.AllNamespaces && !c.config.IsProtectedNamespace(namespace)) {
+		return nil
+	}
+
+	where := "protected namespace " + namespace
+	if cmd.AllNamespaces {
+		where = "all namespaces"
+	}
+
+	var reasons []string
+	for _, t := range cmd.Targets {
+		if !quota.Kinds[parser.KindFor(t.Resource)] {
+			continue
+		}
+		display := t.Resource
+		if t.Name != "" {
+			display += "/" + t.Name
+		}
+		if cmd.Operation == "delete" {
+			reasons = append(reasons, fmt.Sprintf("deletes %s in %s, removing its resource guardrail", display, where))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("changes %s in %s, which may weaken its resource guardrail", display, where))
+		}
+	}
+	return reasons
+}
+
+// protectedNodes returns the nodes that match protectedNodes
+func (c *Checker) protectedNodes(nodes []string) []string {
+	var protected []string
+	for _, node := range nodes {
+		if c.config.IsProtectedNode(node) && !slices.Contains(protected, node) {
+			protected = append(protected, node)
+		}
+	}
+	return protected
+}
+
+// cronJobDeletionReasons describes CronJobs deleted from protected namespaces
+func (c *Checker) cronJobDeletionReasons(cmd *parser.KubectlCommand, namespace string) []string {
+	if cmd.Operation != "delete" || cmd.AllNamespaces || !c.config.IsProtectedNamespace(namespace) {
+		return nil
+	}
+
+	var reasons []string
+	for _, t := range cmd.Targets {
+		if parser.KindFor(t.Resource) != "CronJob" {
+			continue
+		}
+		display := t.Resource
+		if t.Name != "" {
+			display += "/" + t.Name
+		}
+		reasons = append(reasons, cronJobDeletionReason(display, namespace))
+	}
+	return reasons
+}
+
+// cronJobDeletionReason describes the effect of deleting a CronJob
+func cronJobDeletionReason(display, namespace string) string {
+	return fmt.Sprintf("deletes %s in protected namespace %s: its scheduled runs stop and its running jobs are deleted", display, namespace)
+}
+
+// namespaceDefaults are the objects Kubernetes creates in every namespace that
+// pods cannot start without, by kind
+var namespaceDefaults = map[string]string{
+	"ServiceAccount": "default",          // used by every pod that names no service account
+	"ConfigMap":      "kube-root-ca.crt", // projected into every service account token volume
+}
+
+// namespaceDefaultTargetReasons describes changes to a namespace's default
+// ServiceAccount or kube-root-ca.crt ConfigMap named on the command line
+func namespaceDefaultTargetReasons(cmd *parser.KubectlCommand, namespace string) []string {
+	var reasons []string
+	for _, t := range cmd.Targets {
+		kind := parser.KindFor(t.Resource)
+		if t.Name == "" || namespaceDefaults[kind] != t.Name {
+			continue
+		}
+		reasons = append(reasons, namespaceDefaultReason(cmd.Operation, t.Resource+"/"+t.Name, kind, namespace))
+	}
+	return reasons
+}
+
+// namespaceDefaultResourceReasons describes manifests that change a
+// namespace's default ServiceAccount or kube-root-ca.crt ConfigMap
+func namespaceDefaultResourceReasons(operation string, resources []manifest.Resource) []string {
+	var reasons []string
+	for _, r := range resources {
+		if r.Name == "" || namespaceDefaults[r.Kind] != r.Name {
+			continue
+		}
+		ns := r.Namespace
+		if ns == "" {
+			ns = "default"
+		}
+		reasons = append(reasons, namespaceDefaultReason(operation, r.String(), r.Kind, ns))
+	}
+	return reasons
+}
+
+// namespaceDefaultReason describes the effect of changing or deleting a
+// namespace default object
+func namespaceDefaultReason(operation, display, kind, namespace string) string {
+	effect := "pods that name no service account fail to start"
+	if kind == "ConfigMap" {
+		effect = "pods mounting a service account token fail to start"
+	}
+	if operation == "delete" {
+		return fmt.Sprintf("namespace default: deletes %s in %s: until the controller recreates it, %s", display, namespace, effect)
+	}
+	return fmt.Sprintf("namespace default: changes %s in %s, which Kubernetes manages: if it breaks, %s", display, namespace, effect)
+}
+
 // ResourceCheckResult contains check result for file-based commands
 type ResourceCheckResult struct {
-	IsDangerous          bool
-	RequiresConfirmation bool
-	Operation            string
-	Cluster              string
-	Resources            []manifest.Resource
-	Reasons              []string
+	IsDangerous          bool                `json:"dangerous"`
+	RequiresConfirmation bool                `json:"requiresConfirmation"`
+	ModeConfirmation     bool                `json:"-"` // confirmation is required only by mode or a protected namespace or cluster, which a warn-only group policy lifts
+	Operation            string              `json:"operation"`
+	Cluster              string              `json:"cluster"`
+	Identity             string              `json:"identity,omitempty"`    // user or service account performing the operation, if known
+	Proxy                string              `json:"proxy,omitempty"`       // proxy the request to the API server goes through, if any
+	ActingAs             []string            `json:"impersonate,omitempty"` // impersonated user, groups and UID, e.g. "user:alice"
+	Resources            []manifest.Resource `json:"resources"`
+	Reasons              []string            `json:"reasons"`
+	Variant              string              `json:"variant,omitempty"`            // warning experiment variant shown, if any
+	ConfirmationPhrase   string              `json:"confirmationPhrase,omitempty"` // non-empty when the user must type this text to confirm
+	Roster               string              `json:"roster,omitempty"`             // on-call roster decision, with onCall
+	Ticket               string              `json:"ticket,omitempty"`             // change ticket the operator gave, with ticket.required
+	Justification        string              `json:"justification,omitempty"`      // why the operator made the change, with audit.requireReason
+	ProtectedNodes       []string            `json:"protectedNodes,omitempty"`     // Node resources that match protectedNodes
+}
+
+// RequireConfirmation requires confirmation whatever the mode, so that a
+// warn-only group policy does not lift it
+func (r *ResourceCheckResult) RequireConfirmation() {
+	r.RequiresConfirmation = true
+	r.ModeConfirmation = false
 }
 
 // CheckResources analyzes multiple resources from manifest files
@@ -103,17 +659,89 @@ func (c *Checker) CheckResources(operation string, resources []manifest.Resource
 		Reasons:   []string{},
 	}
 
+	// Collect protected kinds among the resources
+	var protectedKinds []string
+	if !readOnlyOperations[operation] {
+		seen := make(map[string]bool)
+		for _, r := range resources {
+			if !seen[r.Kind] && c.config.IsProtectedKind(r.Kind) {
+				seen[r.Kind] = true
+				protectedKinds = append(protectedKinds, r.Kind)
+			}
+		}
+	}
+
+	// Collect Pod Security escalations among the resources
+	var podSecurityReasons []string
+	if !readOnlyOperations[operation] && operation != "delete" {
+		podSecurityReasons = c.podSecurityReasons(resources)
+	}
+
+	// Collect CronJobs deleted from protected namespaces
+	var cronJobReasons []string
+	if operation == "delete" {
+		for _, r := range resources {
+			if r.Kind == "CronJob" && c.config.IsProtectedNamespace(r.Namespace) {
+				cronJobReasons = append(cronJobReasons, cronJobDeletionReason(r.String(), r.Namespace))
+			}
+		}
+	}
+
+	// Collect ordinary workloads given system priority classes
+	var priorityReasons []string
+	if !readOnlyOperations[operation] && operation != "delete" {
+		priorityReasons = priorityClassReasons(resources)
+	}
+
+	// Collect deleted quotas and limits in protected namespaces
+	var guardrailReasons []string
+	if operation == "delete" {
+		guardrailReasons = c.deletedGuardrailReasons(resources)
+	}
+
+	// Collect changes to the objects every namespace needs to start pods
+	var namespaceDefaultReasons []string
+	if !readOnlyOperations[operation] {
+		namespaceDefaultReasons = namespaceDefaultResourceReasons(operation, resources)
+	}
+
+	// Collect protected Node resources
+	if !readOnlyOperations[operation] {
+		var nodes []string
+		for _, r := range resources {
+			if r.Kind == "Node" {
+				nodes = append(nodes, r.Name)
+			}
+		}
+		result.ProtectedNodes = c.protectedNodes(nodes)
+	}
+
 	// Check if operation is dangerous
-	if !c.config.IsDangerousOperation(operation) {
+	if !c.config.IsDangerousOperation(operation) && !hasWarnings(protectedKinds, podSecurityReasons, guardrailReasons, priorityReasons, cronJobReasons, namespaceDefaultReasons, result.ProtectedNodes) {
 		return result
 	}
 
 	result.IsDangerous = true
 	result.Reasons = append(result.Reasons, "dangerous operation: "+operation)
 
+	for _, kind := range protectedKinds {
+		result.Reasons = append(result.Reasons, c.config.ProtectedKindReason(kind))
+	}
+	for _, node := range result.ProtectedNodes {
+		result.Reasons = append(result.Reasons, "protected node: "+node)
+	}
+	result.Reasons = append(result.Reasons, podSecurityReasons...)
+	result.Reasons = append(result.Reasons, guardrailReasons...)
+	result.Reasons = append(result.Reasons, priorityReasons...)
+	result.Reasons = append(result.Reasons, cronJobReasons...)
+	result.Reasons = append(result.Reasons, namespaceDefaultReasons...)
+
 	// Check each resource's namespace
 	protectedNamespaces := make(map[string]bool)
 	for _, r := range resources {
+		if parser.IsClusterScopedKind(r.Kind) {
+			continue
+		}
 		ns := r.Namespace
 		if ns == "" {
 			ns = "default"
@@ -132,14 +760,91 @@ func (c *Checker) CheckResources(operation string, resources []manifest.Resource
 		result.Reasons = append(result.Reasons, "protected cluster: "+cluster)
 	}
 
-	// Determine if confirmation required
-	result.RequiresConfirmation = c.config.Mode == config.ModeConfirm
-	if !result.RequiresConfirmation {
-		// In warn-only mode, still require confirmation for protected resources
-		if len(protectedNamespaces) > 0 || c.config.IsProtectedCluster(cluster) {
-			result.RequiresConfirmation = true
-		}
+	// Determine if confirmation required: always for protected kinds and nodes
+	// and weakened policies, otherwise by mode, and even in warn-only mode for
+	// protected namespaces and clusters
+	if hasWarnings(protectedKinds, podSecurityReasons, guardrailReasons, namespaceDefaultReasons, result.ProtectedNodes) {
+		result.RequiresConfirmation = true
+	} else {
+		result.RequiresConfirmation = c.config.Mode == config.ModeConfirm || len(protectedNamespaces) > 0 || c.config.IsProtectedCluster(cluster)
+		result.ModeConfirmation = result.RequiresConfirmation
 	}
 
 	return result
 }
+
+// ForceReplaceReason explains why replace --force is checked as a delete
+const ForceReplaceReason = "replace --force: deletes the objects and creates them again from the manifests; their UIDs, status and fields not in the manifests are lost, and their pods restart"
+
+// CheckForceReplace checks replace --force as the delete it starts with: it
+// is dangerous whenever deleting the resources would be, or replacing them
+// is, and says so in place of the generic dangerous operation reason
+func (c *Checker) CheckForceReplace(resources []manifest.Resource, cluster string) *ResourceCheckResult {
+	operation := "delete"
+	if !c.config.IsDangerousOperation(operation) {
+		operation = "replace"
+	}
+	result := c.CheckResources(operation, resources, cluster)
+	result.Operation = "replace"
+	for i, reason := range result.Reasons {
+		if reason == "dangerous operation: "+operation {
+			result.Reasons[i] = ForceReplaceReason
+		}
+	}
+	return result
+}
+
+// podSecurityReasons flags Namespace manifests that weaken Pod Security and
+// privileged pod specs (hostPID, hostNetwork, privileged containers) headed
+// for protected namespaces
+func (c *Checker) podSecurityReasons(resources []manifest.Resource) []string {
+	var reasons []string
+	for _, r := range resources {
+		if r.Kind == "Namespace" {
+			weakened, _ := podsecurity.NamespaceReasons(r.Raw)
+			for _, reason := range weakened {
+				reasons = append(reasons, "pod security: "+r.String()+" "+reason)
+			}
+			continue
+		}
+
+		ns := r.Namespace
+		if ns == "" {
+			ns = "default"
+		}
+		if !c.config.IsProtectedNamespace(ns) {
+			continue
+		}
+		settings, _ := podsecurity.PrivilegedSettings(r.Kind, r.Raw)
+		if len(settings) > 0 {
+			reasons = append(reasons, fmt.Sprintf("pod security: %s in protected namespace %s uses %s", r.String(), ns, strings.Join(settings, ", ")))
+		}
+	}
+	return reasons
+}
+
+// deletedGuardrailReasons flags ResourceQuota and LimitRange manifests being
+// deleted from protected namespaces
+func (c *Checker) deletedGuardrailReasons(resources []manifest.Resource) []string {
+	var reasons []string
+	for _, r := range resources {
+		if !quota.Kinds[r.Kind] || !c.config.IsProtectedNamespace(r.Namespace) {
+			continue
+		}
+		reasons = append(reasons, fmt.Sprintf("governance: deletes %s in protected namespace %s, removing its resource guardrail", r.String(), r.Namespace))
+	}
+	return reasons
+}
+
+// priorityClassReasons flags workloads outside kube-system that use a system
+// PriorityClass, whose pods can preempt everything else on their nodes
+func priorityClassReasons(resources []manifest.Resource) []string {
+	var reasons []string
+	for _, r := range resources {
+		class, _ := priority.PodPriorityClass(r.Kind, r.Raw)
+		if priority.IsSystemClassMisuse(class, r.Namespace) {
+			reasons = append(reasons, fmt.Sprintf("priority: %s in namespace %s uses %s, reserved for critical cluster components: its pods can preempt ordinary workloads", r.String(), r.Namespace, class))
+		}
+	}
+	return reasons
+}
@@ -2,6 +2,8 @@ package checker
 
 import (
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
@@ -359,6 +361,35 @@ func TestCheckResourcesProtectedCluster(t *testing.T) {
 	}
 }
 
+func TestCheckForceReplace(t *testing.T) {
+	resources := []manifest.Resource{{Kind: "Deployment", Name: "web", Namespace: "shop", Source: "web.yaml"}}
+	tests := []struct {
+		name      string
+		dangerous []string
+		expected  bool
+	}{
+		{"delete is dangerous", []string{"delete"}, true},
+		{"replace is dangerous", []string{"replace"}, true},
+		{"neither is dangerous", []string{"apply"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chk := New(&config.Config{Mode: config.ModeConfirm, DangerousOperations: tt.dangerous})
+			result := chk.CheckForceReplace(resources, "dev")
+			if result.IsDangerous != tt.expected {
+				t.Fatalf("IsDangerous = %v, expected %v", result.IsDangerous, tt.expected)
+			}
+			if result.Operation != "replace" {
+				t.Errorf("Operation = %q, expected replace", result.Operation)
+			}
+			if tt.expected && (len(result.Reasons) == 0 || result.Reasons[0] != ForceReplaceReason) {
+				t.Errorf("expected the replace --force reason in place of the generic one, got %v", result.Reasons)
+			}
+		})
+	}
+}
+
 func TestCheckResourcesSafeOperation(t *testing.T) {
 	cfg := &config.Config{
 		Mode:                config.ModeConfirm,
@@ -473,3 +504,887 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestCheckNamespaceDeletion(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         *config.Config
+		args           []string
+		expectedPhrase string
+		expectedNS     []string
+	}{
+		{
+			name:           "delete namespace requires typed confirmation",
+			config:         config.DefaultConfig(),
+			args:           []string{"delete", "namespace", "foo"},
+			expectedPhrase: "foo",
+			expectedNS:     []string{"foo"},
+		},
+		{
+			name:           "ns shortname with multiple names",
+			config:         config.DefaultConfig(),
+			args:           []string{"delete", "ns", "foo", "bar"},
+			expectedPhrase: "foo,bar",
+			expectedNS:     []string{"foo", "bar"},
+		},
+		{
+			name:           "slash form",
+			config:         config.DefaultConfig(),
+			args:           []string{"delete", "namespace/foo"},
+			expectedPhrase: "foo",
+			expectedNS:     []string{"foo"},
+		},
+		{
+			name: "guarded even when delete is not dangerous and mode is warn-only",
+			config: &config.Config{
+				Mode:                config.ModeWarnOnly,
+				DangerousOperations: []string{"apply"},
+			},
+			args:           []string{"delete", "ns", "foo"},
+			expectedPhrase: "foo",
+			expectedNS:     []string{"foo"},
+		},
+		{
+			name:           "unnamed namespaces",
+			config:         config.DefaultConfig(),
+			args:           []string{"delete", "namespaces", "-l", "team=a"},
+			expectedPhrase: "delete namespaces",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chk := New(tt.config)
+			result := chk.Check(parser.Parse(tt.args), "dev-cluster")
+
+			if !result.IsDangerous {
+				t.Error("Expected IsDangerous=true for namespace deletion")
+			}
+			if !result.RequiresConfirmation {
+				t.Error("Expected RequiresConfirmation=true for namespace deletion")
+			}
+			if result.ConfirmationPhrase != tt.expectedPhrase {
+				t.Errorf("ConfirmationPhrase: got %q, expected %q", result.ConfirmationPhrase, tt.expectedPhrase)
+			}
+			if !reflect.DeepEqual(result.CascadeNamespaces, tt.expectedNS) {
+				t.Errorf("CascadeNamespaces: got %v, expected %v", result.CascadeNamespaces, tt.expectedNS)
+			}
+		})
+	}
+}
+
+func TestCheckNamespaceDeletionProtected(t *testing.T) {
+	cfg := config.DefaultConfig()
+	chk := New(cfg)
+
+	// -n is irrelevant for namespace deletion; the deleted namespace is what matters
+	result := chk.Check(parser.Parse([]string{"delete", "ns", "kube-system", "-n", "default"}), "dev-cluster")
+
+	found := false
+	for _, r := range result.Reasons {
+		if r == "protected namespace: kube-system" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected protected namespace reason for kube-system, got: %v", result.Reasons)
+	}
+}
+
+func TestCheckDeleteNonNamespaceHasNoPhrase(t *testing.T) {
+	chk := New(config.DefaultConfig())
+	result := chk.Check(parser.Parse([]string{"delete", "pod", "nginx"}), "dev-cluster")
+
+	if result.ConfirmationPhrase != "" {
+		t.Errorf("Expected no confirmation phrase for pod deletion, got %q", result.ConfirmationPhrase)
+	}
+}
+
+func TestCheckProtectedKinds(t *testing.T) {
+	cfg := &config.Config{
+		Mode:                config.ModeWarnOnly,
+		DangerousOperations: []string{"delete"},
+		ProtectedNamespaces: []string{"default"},
+		ProtectedKinds:      []string{"CustomResourceDefinition", "StorageClass"},
+	}
+
+	tests := []struct {
+		name                 string
+		args                 []string
+		expectedDangerous    bool
+		expectedConfirmation bool
+		expectedReasons      []string
+	}{
+		{
+			name:                 "delete crd requires confirmation even in warn-only",
+			args:                 []string{"delete", "crd", "widgets.example.com"},
+			expectedDangerous:    true,
+			expectedConfirmation: true,
+			expectedReasons:      []string{"dangerous operation: delete", "protected kind: CustomResourceDefinition"},
+		},
+		{
+			name:                 "non-dangerous mutating operation on protected kind",
+			args:                 []string{"label", "storageclass", "fast", "tier=gold"},
+			expectedDangerous:    true,
+			expectedConfirmation: true,
+			expectedReasons:      []string{"dangerous operation: label", "protected kind: StorageClass"},
+		},
+		{
+			name:              "read-only operation on protected kind",
+			args:              []string{"get", "crd"},
+			expectedDangerous: false,
+		},
+		{
+			name:                 "cluster-scoped kind skips protected namespace",
+			args:                 []string{"delete", "clusterrole", "admin"},
+			expectedDangerous:    true,
+			expectedConfirmation: false,
+			expectedReasons:      []string{"dangerous operation: delete"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := New(cfg).Check(parser.Parse(tt.args), "dev-cluster")
+
+			if result.IsDangerous != tt.expectedDangerous {
+				t.Errorf("IsDangerous: got %v, expected %v", result.IsDangerous, tt.expectedDangerous)
+			}
+			if result.RequiresConfirmation != tt.expectedConfirmation {
+				t.Errorf("RequiresConfirmation: got %v, expected %v", result.RequiresConfirmation, tt.expectedConfirmation)
+			}
+			if tt.expectedReasons != nil && !reflect.DeepEqual(result.Reasons, tt.expectedReasons) {
+				t.Errorf("Reasons: got %v, expected %v", result.Reasons, tt.expectedReasons)
+			}
+		})
+	}
+}
+
+func TestCheckResourcesProtectedKind(t *testing.T) {
+	cfg := &config.Config{
+		Mode:                config.ModeWarnOnly,
+		DangerousOperations: []string{"delete"},
+		ProtectedNamespaces: []string{"default"},
+		ProtectedKinds:      []string{"ClusterRole"},
+	}
+
+	resources := []manifest.Resource{
+		{Kind: "ClusterRole", Name: "admin"},
+	}
+
+	result := New(cfg).CheckResources("apply", resources, "dev-cluster")
+
+	if !result.IsDangerous {
+		t.Error("Expected IsDangerous=true for apply of protected kind")
+	}
+	if !result.RequiresConfirmation {
+		t.Error("Expected RequiresConfirmation=true for protected kind in warn-only mode")
+	}
+	expected := []string{"dangerous operation: apply", "protected kind: ClusterRole"}
+	if !reflect.DeepEqual(result.Reasons, expected) {
+		t.Errorf("Reasons: got %v, expected %v (cluster-scoped kind must not match protected namespace)", result.Reasons, expected)
+	}
+}
+
+func TestCheckPodSecurityLabel(t *testing.T) {
+	cfg := &config.Config{
+		Mode:                config.ModeWarnOnly,
+		DangerousOperations: []string{"delete"},
+	}
+
+	tests := []struct {
+		name                 string
+		args                 []string
+		expectedDangerous    bool
+		expectedConfirmation bool
+		expectedReasons      []string
+	}{
+		{
+			name:                 "enforce privileged",
+			args:                 []string{"label", "ns", "prod", "pod-security.kubernetes.io/enforce=privileged", "--overwrite"},
+			expectedDangerous:    true,
+			expectedConfirmation: true,
+			expectedReasons: []string{
+				"dangerous operation: label",
+				"pod security: sets pod-security.kubernetes.io/enforce=privileged, disabling Pod Security enforcement",
+			},
+		},
+		{
+			name:                 "remove enforce label",
+			args:                 []string{"label", "namespace", "prod", "pod-security.kubernetes.io/enforce-"},
+			expectedDangerous:    true,
+			expectedConfirmation: true,
+			expectedReasons: []string{
+				"dangerous operation: label",
+				"pod security: removes pod-security.kubernetes.io/enforce, disabling Pod Security enforcement",
+			},
+		},
+		{
+			name:              "enforce restricted",
+			args:              []string{"label", "ns", "prod", "pod-security.kubernetes.io/enforce=restricted"},
+			expectedDangerous: false,
+		},
+		{
+			name:              "same label on a pod",
+			args:              []string{"label", "pod", "web", "pod-security.kubernetes.io/enforce=privileged"},
+			expectedDangerous: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := New(cfg).Check(parser.Parse(tt.args), "dev-cluster")
+			if result.IsDangerous != tt.expectedDangerous {
+				t.Errorf("IsDangerous: got %v, expected %v", result.IsDangerous, tt.expectedDangerous)
+			}
+			if result.RequiresConfirmation != tt.expectedConfirmation {
+				t.Errorf("RequiresConfirmation: got %v, expected %v", result.RequiresConfirmation, tt.expectedConfirmation)
+			}
+			if tt.expectedReasons != nil && !reflect.DeepEqual(result.Reasons, tt.expectedReasons) {
+				t.Errorf("Reasons: got %v, expected %v", result.Reasons, tt.expectedReasons)
+			}
+		})
+	}
+}
+
+func TestCheckResourcesPrivilegedPods(t *testing.T) {
+	cfg := &config.Config{
+		Mode:                config.ModeWarnOnly,
+		DangerousOperations: []string{"delete"},
+		ProtectedNamespaces: []string{"kube-system"},
+	}
+	privileged := []byte("spec:\n  template:\n    spec:\n      hostNetwork: true\n      containers:\n      - name: agent\n        securityContext:\n          privileged: true\n")
+
+	tests := []struct {
+		name              string
+		resource          manifest.Resource
+		expectedDangerous bool
+		expectedReasons   []string
+	}{
+		{
+			name:              "privileged daemonset in protected namespace",
+			resource:          manifest.Resource{Kind: "DaemonSet", Name: "agent", Namespace: "kube-system", Raw: privileged},
+			expectedDangerous: true,
+			expectedReasons: []string{
+				"dangerous operation: create",
+				"pod security: DaemonSet/agent in protected namespace kube-system uses hostNetwork, privileged container agent",
+				"protected namespace: kube-system",
+			},
+		},
+		{
+			name:              "privileged daemonset elsewhere",
+			resource:          manifest.Resource{Kind: "DaemonSet", Name: "agent", Namespace: "sandbox", Raw: privileged},
+			expectedDangerous: false,
+		},
+		{
+			name:              "namespace manifest weakening pod security",
+			resource:          manifest.Resource{Kind: "Namespace", Name: "prod", Raw: []byte("metadata:\n  labels:\n    pod-security.kubernetes.io/enforce: privileged\n")},
+			expectedDangerous: true,
+			expectedReasons: []string{
+				"dangerous operation: create",
+				"pod security: Namespace/prod sets pod-security.kubernetes.io/enforce=privileged, disabling Pod Security enforcement",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := New(cfg).CheckResources("create", []manifest.Resource{tt.resource}, "dev-cluster")
+			if result.IsDangerous != tt.expectedDangerous {
+				t.Errorf("IsDangerous: got %v, expected %v", result.IsDangerous, tt.expectedDangerous)
+			}
+			if tt.expectedDangerous && !result.RequiresConfirmation {
+				t.Error("expected Pod Security escalation to require confirmation in warn-only mode")
+			}
+			if tt.expectedReasons != nil && !reflect.DeepEqual(result.Reasons, tt.expectedReasons) {
+				t.Errorf("Reasons: got %v, expected %v", result.Reasons, tt.expectedReasons)
+			}
+		})
+	}
+}
+
+func TestCheckGuardrails(t *testing.T) {
+	cfg := &config.Config{
+		Mode:                config.ModeWarnOnly,
+		DangerousOperations: []string{"apply"},
+		ProtectedNamespaces: []string{"prod"},
+	}
+
+	tests := []struct {
+		name              string
+		args              []string
+		expectedDangerous bool
+		expectedReasons   []string
+	}{
+		{
+			name:              "delete quota in protected namespace",
+			args:              []string{"delete", "resourcequota", "compute", "-n", "prod"},
+			expectedDangerous: true,
+			expectedReasons: []string{
+				"dangerous operation: delete",
+				"governance: deletes resourcequota/compute in protected namespace prod, removing its resource guardrail",
+				"protected namespace: prod",
+			},
+		},
+		{
+			name:              "patch limit range in protected namespace",
+			args:              []string{"patch", "limits", "defaults", "-n", "prod", "-p", "{}"},
+			expectedDangerous: true,
+			expectedReasons: []string{
+				"dangerous operation: patch",
+				"governance: changes limits/defaults in protected namespace prod, which may weaken its resource guardrail",
+				"protected namespace: prod",
+			},
+		},
+		{
+			name:              "delete quota in all namespaces",
+			args:              []string{"delete", "quota", "--all", "-A"},
+			expectedDangerous: true,
+		},
+		{
+			name:              "delete quota elsewhere",
+			args:              []string{"delete", "quota", "compute", "-n", "sandbox"},
+			expectedDangerous: false,
+		},
+		{
+			name:              "describe quota in protected namespace",
+			args:              []string{"describe", "quota", "compute", "-n", "prod"},
+			expectedDangerous: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := New(cfg).Check(parser.Parse(tt.args), "dev-cluster")
+			if result.IsDangerous != tt.expectedDangerous {
+				t.Errorf("IsDangerous: got %v, expected %v", result.IsDangerous, tt.expectedDangerous)
+			}
+			if tt.expectedDangerous && !result.RequiresConfirmation {
+				t.Error("expected guardrail change to require confirmation in warn-only mode")
+			}
+			if tt.expectedReasons != nil && !reflect.DeepEqual(result.Reasons, tt.expectedReasons) {
+				t.Errorf("Reasons: got %v, expected %v", result.Reasons, tt.expectedReasons)
+			}
+		})
+	}
+}
+
+func TestCheckResourcesDeletedGuardrails(t *testing.T) {
+	cfg := &config.Config{
+		Mode:                config.ModeWarnOnly,
+		DangerousOperations: []string{"apply"},
+		ProtectedNamespaces: []string{"prod"},
+	}
+	resources := []manifest.Resource{
+		{Kind: "LimitRange", Name: "defaults", Namespace: "prod"},
+		{Kind: "ResourceQuota", Name: "compute", Namespace: "sandbox"},
+	}
+
+	result := New(cfg).CheckResources("delete", resources, "dev-cluster")
+	if !result.IsDangerous || !result.RequiresConfirmation {
+		t.Fatalf("expected a dangerous delete requiring confirmation, got %+v", result)
+	}
+	expected := []string{
+		"dangerous operation: delete",
+		"governance: deletes LimitRange/defaults in protected namespace prod, removing its resource guardrail",
+		"protected namespace: prod",
+	}
+	if !reflect.DeepEqual(result.Reasons, expected) {
+		t.Errorf("Reasons: got %v, expected %v", result.Reasons, expected)
+	}
+}
+
+func TestCheckResourcesSystemPriorityClass(t *testing.T) {
+	cfg := &config.Config{
+		Mode:                config.ModeWarnOnly,
+		DangerousOperations: []string{"delete"},
+	}
+	raw := []byte("spec:\n  template:\n    spec:\n      priorityClassName: system-cluster-critical\n")
+
+	tests := []struct {
+		name              string
+		namespace         string
+		expectedDangerous bool
+		expectedReasons   []string
+	}{
+		{
+			name:              "ordinary namespace",
+			namespace:         "shop",
+			expectedDangerous: true,
+			expectedReasons: []string{
+				"dangerous operation: apply",
+				"priority: Deployment/web in namespace shop uses system-cluster-critical, reserved for critical cluster components: its pods can preempt ordinary workloads",
+			},
+		},
+		{
+			name:              "kube-system",
+			namespace:         "kube-system",
+			expectedDangerous: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources := []manifest.Resource{{Kind: "Deployment", Name: "web", Namespace: tt.namespace, Raw: raw}}
+			result := New(cfg).CheckResources("apply", resources, "dev-cluster")
+			if result.IsDangerous != tt.expectedDangerous {
+				t.Errorf("IsDangerous: got %v, expected %v", result.IsDangerous, tt.expectedDangerous)
+			}
+			if tt.expectedReasons != nil && !reflect.DeepEqual(result.Reasons, tt.expectedReasons) {
+				t.Errorf("Reasons: got %v, expected %v", result.Reasons, tt.expectedReasons)
+			}
+		})
+	}
+}
+
+func TestCheckCronJobDeletion(t *testing.T) {
+	cfg := &config.Config{
+		Mode:                config.ModeWarnOnly,
+		DangerousOperations: []string{"apply"},
+		ProtectedNamespaces: []string{"prod"},
+	}
+
+	result := New(cfg).Check(parser.Parse([]string{"delete", "cronjob", "nightly", "-n", "prod"}), "dev-cluster")
+	if !result.IsDangerous || !result.RequiresConfirmation {
+		t.Fatalf("expected a dangerous delete requiring confirmation, got %+v", result)
+	}
+	expected := []string{
+		"dangerous operation: delete",
+		"deletes cronjob/nightly in protected namespace prod: its scheduled runs stop and its running jobs are deleted",
+		"protected namespace: prod",
+	}
+	if !reflect.DeepEqual(result.Reasons, expected) {
+		t.Errorf("Reasons: got %v, expected %v", result.Reasons, expected)
+	}
+
+	result = New(cfg).Check(parser.Parse([]string{"delete", "cronjob", "nightly", "-n", "sandbox"}), "dev-cluster")
+	if result.IsDangerous {
+		t.Errorf("expected CronJob deletion outside protected namespaces to pass, got %v", result.Reasons)
+	}
+
+	resources := []manifest.Resource{{Kind: "CronJob", Name: "nightly", Namespace: "prod"}}
+	resourceResult := New(cfg).CheckResources("delete", resources, "dev-cluster")
+	if !resourceResult.IsDangerous || !strings.Contains(strings.Join(resourceResult.Reasons, "\n"), "deletes CronJob/nightly in protected namespace prod") {
+		t.Errorf("expected manifest CronJob deletion to be flagged, got %v", resourceResult.Reasons)
+	}
+}
+
+func TestCheckSecretExposure(t *testing.T) {
+	tests := []struct {
+		name                 string
+		args                 []string
+		confirm              bool
+		expectedReasons      []string
+		expectedConfirmation bool
+	}{
+		{
+			name:            "get -o yaml",
+			args:            []string{"get", "secret", "db", "-o", "yaml"},
+			expectedReasons: []string{"secret exposure: get -o yaml prints the values of secret/db to the terminal; base64 is not encryption"},
+		},
+		{
+			name:                 "get -o json across namespaces, confirmed",
+			args:                 []string{"get", "secrets", "-A", "-ojson"},
+			confirm:              true,
+			expectedReasons:      []string{"secret exposure: get -o json prints the values of every secret in the cluster to the terminal; base64 is not encryption"},
+			expectedConfirmation: true,
+		},
+		{
+			name:            "jsonpath reading data",
+			args:            []string{"get", "secret/db", "-o", "jsonpath={.data.password}"},
+			expectedReasons: []string{"secret exposure: get -o jsonpath may print the values of secret/db to the terminal"},
+		},
+		{
+			name:            "describe",
+			args:            []string{"describe", "secret", "db", "-n", "web"},
+			expectedReasons: []string{"secret exposure: describe shows the data keys and value sizes of secret/db"},
+		},
+		{
+			name: "jsonpath reading metadata",
+			args: []string{"get", "secret", "db", "-o", "jsonpath={.metadata.name}"},
+		},
+		{
+			name: "table output",
+			args: []string{"get", "secrets"},
+		},
+		{
+			name: "other kinds",
+			args: []string{"get", "configmap", "app", "-o", "yaml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Mode:                config.ModeWarnOnly,
+				DangerousOperations: []string{"delete"},
+				SecretExposure:      config.SecretExposureConfig{Enabled: true, Confirm: tt.confirm},
+			}
+			result := New(cfg).Check(parser.Parse(tt.args), "dev")
+			if result.IsDangerous != (tt.expectedReasons != nil) {
+				t.Errorf("IsDangerous: got %v, expected %v", result.IsDangerous, tt.expectedReasons != nil)
+			}
+			if result.RequiresConfirmation != tt.expectedConfirmation {
+				t.Errorf("RequiresConfirmation: got %v, expected %v", result.RequiresConfirmation, tt.expectedConfirmation)
+			}
+			if tt.expectedReasons != nil && !reflect.DeepEqual(result.Reasons, tt.expectedReasons) {
+				t.Errorf("Reasons: got %v, expected %v", result.Reasons, tt.expectedReasons)
+			}
+		})
+	}
+}
+
+func TestCheckAllNamespacesReads(t *testing.T) {
+	cfg := &config.Config{
+		Mode:                config.ModeWarnOnly,
+		DangerousOperations: []string{"delete"},
+		ProtectedClusters:   []string{"prod"},
+		AllNamespacesReads:  []string{"get secrets", "describe"},
+	}
+
+	tests := []struct {
+		name              string
+		args              []string
+		cluster           string
+		expectedDangerous bool
+		expectedReasons   []string
+	}{
+		{
+			name:              "secret read across namespaces on protected cluster",
+			args:              []string{"get", "secret", "-A", "-o", "yaml"},
+			cluster:           "prod",
+			expectedDangerous: true,
+			expectedReasons: []string{
+				"reads secret across all namespaces (-A) on a protected cluster",
+				"protected cluster: prod",
+			},
+		},
+		{
+			name:              "any describe across namespaces",
+			args:              []string{"describe", "pods", "--all-namespaces"},
+			cluster:           "prod",
+			expectedDangerous: true,
+		},
+		{
+			name:              "unlisted read",
+			args:              []string{"get", "pods", "-A"},
+			cluster:           "prod",
+			expectedDangerous: false,
+		},
+		{
+			name:              "secret read in one namespace",
+			args:              []string{"get", "secrets", "-n", "web"},
+			cluster:           "prod",
+			expectedDangerous: false,
+		},
+		{
+			name:              "dev cluster",
+			args:              []string{"get", "secrets", "-A"},
+			cluster:           "dev",
+			expectedDangerous: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := New(cfg).Check(parser.Parse(tt.args), tt.cluster)
+			if result.IsDangerous != tt.expectedDangerous {
+				t.Errorf("IsDangerous: got %v, expected %v", result.IsDangerous, tt.expectedDangerous)
+			}
+			if tt.expectedDangerous && !result.RequiresConfirmation {
+				t.Error("expected all-namespaces read to require confirmation in warn-only mode")
+			}
+			if tt.expectedReasons != nil && !reflect.DeepEqual(result.Reasons, tt.expectedReasons) {
+				t.Errorf("Reasons: got %v, expected %v", result.Reasons, tt.expectedReasons)
+			}
+		})
+	}
+}
+
+func TestCheckNamespaceDefaults(t *testing.T) {
+	cfg := &config.Config{
+		Mode:                config.ModeWarnOnly,
+		DangerousOperations: []string{"apply"},
+	}
+
+	tests := []struct {
+		name              string
+		args              []string
+		expectedDangerous bool
+		expectedReasons   []string
+	}{
+		{
+			name:              "delete default service account",
+			args:              []string{"delete", "sa", "default", "-n", "web"},
+			expectedDangerous: true,
+			expectedReasons: []string{
+				"dangerous operation: delete",
+				"namespace default: deletes sa/default in web: until the controller recreates it, pods that name no service account fail to start",
+			},
+		},
+		{
+			name:              "patch root CA configmap",
+			args:              []string{"patch", "configmap", "kube-root-ca.crt", "-n", "web", "-p", "{}"},
+			expectedDangerous: true,
+			expectedReasons: []string{
+				"dangerous operation: patch",
+				"namespace default: changes configmap/kube-root-ca.crt in web, which Kubernetes manages: if it breaks, pods mounting a service account token fail to start",
+			},
+		},
+		{
+			name:              "delete other service account",
+			args:              []string{"delete", "sa", "builder", "-n", "web"},
+			expectedDangerous: false,
+		},
+		{
+			name:              "get default service account",
+			args:              []string{"get", "sa", "default", "-n", "web"},
+			expectedDangerous: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := New(cfg).Check(parser.Parse(tt.args), "dev-cluster")
+			if result.IsDangerous != tt.expectedDangerous {
+				t.Fatalf("IsDangerous: got %v, expected %v (%v)", result.IsDangerous, tt.expectedDangerous, result.Reasons)
+			}
+			if tt.expectedDangerous && !result.RequiresConfirmation {
+				t.Error("expected namespace default change to require confirmation in warn-only mode")
+			}
+			if tt.expectedReasons != nil && !reflect.DeepEqual(result.Reasons, tt.expectedReasons) {
+				t.Errorf("Reasons: got %v, expected %v", result.Reasons, tt.expectedReasons)
+			}
+		})
+	}
+
+	resources := []manifest.Resource{{Kind: "ServiceAccount", Name: "default", Namespace: "web"}}
+	resourceResult := New(cfg).CheckResources("replace", resources, "dev-cluster")
+	if !resourceResult.IsDangerous || !resourceResult.RequiresConfirmation || !strings.Contains(strings.Join(resourceResult.Reasons, "\n"), "changes ServiceAccount/default in web") {
+		t.Errorf("expected manifest change of the default service account to be flagged, got %+v", resourceResult)
+	}
+}
+
+func TestCheckProtectedNodes(t *testing.T) {
+	cfg := &config.Config{
+		Mode:                config.ModeWarnOnly,
+		DangerousOperations: []string{"drain"},
+		ProtectedNodes:      []string{"*-master-*"},
+	}
+
+	tests := []struct {
+		name              string
+		args              []string
+		expectedDangerous bool
+		expectedNodes     []string
+	}{
+		{"drain protected node", []string{"drain", "prod-master-0"}, true, []string{"prod-master-0"}},
+		{"cordon protected node", []string{"cordon", "prod-master-0", "worker-1"}, true, []string{"prod-master-0"}},
+		{"delete protected node", []string{"delete", "node", "prod-master-1"}, true, []string{"prod-master-1"}},
+		{"cordon worker", []string{"cordon", "worker-1"}, false, nil},
+		{"describe protected node", []string{"describe", "node", "prod-master-0"}, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := New(cfg).Check(parser.Parse(tt.args), "dev-cluster")
+			if result.IsDangerous != tt.expectedDangerous {
+				t.Fatalf("IsDangerous: got %v, expected %v (%v)", result.IsDangerous, tt.expectedDangerous, result.Reasons)
+			}
+			if !reflect.DeepEqual(result.ProtectedNodes, tt.expectedNodes) {
+				t.Errorf("ProtectedNodes: got %v, expected %v", result.ProtectedNodes, tt.expectedNodes)
+			}
+			if !tt.expectedDangerous {
+				return
+			}
+			if !result.RequiresConfirmation {
+				t.Error("expected a protected node to require confirmation in warn-only mode")
+			}
+			if !slices.Contains(result.Reasons, "protected node: "+tt.expectedNodes[0]) {
+				t.Errorf("expected a protected node reason, got %v", result.Reasons)
+			}
+		})
+	}
+
+	resources := []manifest.Resource{{Kind: "Node", Name: "prod-master-0"}}
+	resourceResult := New(cfg).CheckResources("delete", resources, "dev-cluster")
+	if !resourceResult.IsDangerous || !resourceResult.RequiresConfirmation || !slices.Contains(resourceResult.Reasons, "protected node: prod-master-0") {
+		t.Errorf("expected a protected Node manifest to be flagged, got %+v", resourceResult)
+	}
+}
+
+func TestCheckDebug(t *testing.T) {
+	cfg := &config.Config{
+		Mode:              config.ModeWarnOnly,
+		ProtectedClusters: []string{"prod"},
+	}
+
+	tests := []struct {
+		name            string
+		args            []string
+		cluster         string
+		expectedConfirm bool
+		expectedReason  string
+	}{
+		{"node on a protected cluster", []string{"debug", "node/worker-1", "-it", "--image=busybox"}, "prod", true, "debug: node/worker-1 runs busybox in a pod sharing the node's host namespaces, with its root filesystem at /host"},
+		{"node elsewhere", []string{"debug", "node/worker-1", "-it", "--image=busybox"}, "dev", false, "debug: node/worker-1 runs busybox"},
+		{"sysadmin profile on a protected cluster", []string{"debug", "web-0", "--image=busybox", "--profile=sysadmin"}, "prod", true, "debug: --profile=sysadmin runs the debug container privileged"},
+		{"ephemeral container", []string{"debug", "web-0", "--image=busybox", "--target=web"}, "dev", false, "debug: adds an ephemeral container running busybox to pod/web-0, sharing the processes of container web; it cannot be removed until the pod is deleted"},
+		{"pod copy", []string{"debug", "pod/web-0", "--copy-to=web-debug"}, "dev", false, "debug: creates pod web-debug, a copy of pod/web-0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := New(cfg).Check(parser.Parse(tt.args), tt.cluster)
+			if !result.IsDangerous {
+				t.Fatal("expected kubectl debug to be flagged")
+			}
+			if result.RequiresConfirmation != tt.expectedConfirm {
+				t.Errorf("RequiresConfirmation: got %v, expected %v", result.RequiresConfirmation, tt.expectedConfirm)
+			}
+			if !slices.ContainsFunc(result.Reasons, func(r string) bool { return strings.HasPrefix(r, tt.expectedReason) }) {
+				t.Errorf("expected a reason starting %q, got %v", tt.expectedReason, result.Reasons)
+			}
+			if result.Debug == nil || result.Resources[0] != result.Debug.Target {
+				t.Errorf("expected the debug target as the resource, got %v and %+v", result.Resources, result.Debug)
+			}
+		})
+	}
+}
+
+func TestCheckKubectlPlugins(t *testing.T) {
+	tests := []struct {
+		name              string
+		unknown           string
+		args              []string
+		expectedDangerous bool
+		expectedConfirm   bool
+	}{
+		{"unknown plugin, default policy", "", []string{"node-shell", "worker-1"}, false, false},
+		{"unknown plugin, warn", config.PluginWarn, []string{"node-shell", "worker-1"}, true, false},
+		{"unknown plugin, confirm", config.PluginConfirm, []string{"node-shell", "worker-1"}, true, true},
+		{"allowed plugin", config.PluginConfirm, []string{"neat", "get", "pod", "web"}, false, false},
+		{"allowed plugin by binary name", config.PluginConfirm, []string{"view_secret", "db"}, false, false},
+		{"kubectl command", config.PluginConfirm, []string{"get", "pods"}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Mode:                config.ModeWarnOnly,
+				DangerousOperations: []string{"delete"},
+				KubectlPlugins:      config.KubectlPluginsConfig{Unknown: tt.unknown, Allow: []string{"neat", "view-secret"}},
+			}
+			result := New(cfg).Check(parser.Parse(tt.args), "dev")
+			if result.IsDangerous != tt.expectedDangerous {
+				t.Fatalf("IsDangerous: got %v, expected %v (%v)", result.IsDangerous, tt.expectedDangerous, result.Reasons)
+			}
+			if result.RequiresConfirmation != tt.expectedConfirm {
+				t.Errorf("RequiresConfirmation: got %v, expected %v", result.RequiresConfirmation, tt.expectedConfirm)
+			}
+			if tt.expectedDangerous && !slices.Contains(result.Reasons, "kubectl plugin: node-shell is not in kubectlPlugins.allow") {
+				t.Errorf("expected a plugin reason, got %v", result.Reasons)
+			}
+		})
+	}
+}
+
+func TestCheckContextSwitch(t *testing.T) {
+	tests := []struct {
+		name              string
+		confirm           bool
+		cluster           string
+		expectedDangerous bool
+	}{
+		{"into a protected cluster", true, "prod", true},
+		{"into another cluster", true, "dev", false},
+		{"confirmation off", false, "prod", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Mode:                config.ModeWarnOnly,
+				DangerousOperations: []string{"delete"},
+				ProtectedClusters:   []string{"prod"},
+				ContextSwitch:       config.ContextSwitchConfig{Confirm: tt.confirm},
+			}
+			result := New(cfg).Check(parser.Parse([]string{"config", "use-context", tt.cluster}), tt.cluster)
+			if result.IsDangerous != tt.expectedDangerous || result.RequiresConfirmation != tt.expectedDangerous {
+				t.Fatalf("IsDangerous, RequiresConfirmation: got %v, %v, expected %v (%v)", result.IsDangerous, result.RequiresConfirmation, tt.expectedDangerous, result.Reasons)
+			}
+			if tt.expectedDangerous && !slices.Contains(result.Reasons, "context switch: commands without --context will run against protected cluster prod") {
+				t.Errorf("expected a context switch reason, got %v", result.Reasons)
+			}
+		})
+	}
+}
+
+func TestCheckCascade(t *testing.T) {
+	cfg := &config.Config{
+		Mode:                config.ModeConfirm,
+		DangerousOperations: []string{"delete"},
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"orphan", []string{"delete", "deployment", "web", "--cascade=orphan"}, "cascade=orphan: dependents, such as a Deployment's ReplicaSets and Pods, keep running with no owner and are never cleaned up"},
+		{"foreground", []string{"delete", "deployment", "web", "--cascade=foreground"}, "cascade=foreground: dependents are deleted first; the object remains, marked for deletion, until they are gone"},
+		{"background", []string{"delete", "deployment", "web", "--cascade"}, "cascade=background: the object is deleted at once and the garbage collector deletes its dependents afterwards"},
+		{"unknown mode", []string{"delete", "deployment", "web", "--cascade=later"}, "cascade=later: not a mode kubectl knows (background, foreground or orphan); the delete fails"},
+		{"namespace", []string{"delete", "namespace", "web", "--cascade=orphan"}, "cascade=orphan: does not apply to namespace deletion; everything in the namespace is deleted all the same"},
+		{"no cascade", []string{"delete", "deployment", "web"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := New(cfg).Check(parser.Parse(tt.args), "dev")
+			var cascade []string
+			for _, reason := range result.Reasons {
+				if strings.HasPrefix(reason, "cascade=") {
+					cascade = append(cascade, reason)
+				}
+			}
+			if tt.expected == "" && len(cascade) > 0 {
+				t.Errorf("expected no cascade reason, got %v", cascade)
+			}
+			if tt.expected != "" && !reflect.DeepEqual(cascade, []string{tt.expected}) {
+				t.Errorf("expected %q, got %v", tt.expected, cascade)
+			}
+		})
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *CheckResult
+		expected string
+	}{
+		{"not flagged", &CheckResult{}, ""},
+		{"flagged only", &CheckResult{IsDangerous: true, Reasons: []string{"secret exposure: get secret db -o yaml prints its data"}}, SeverityLow},
+		{"dangerous operation", &CheckResult{IsDangerous: true, Reasons: []string{"dangerous operation: delete"}}, SeverityMedium},
+		{"protected namespace", &CheckResult{IsDangerous: true, Reasons: []string{"dangerous operation: delete", "protected namespace: kube-system"}}, SeverityHigh},
+		{"protected cluster", &CheckResult{IsDangerous: true, Reasons: []string{"dangerous operation: delete", "protected cluster: prod"}}, SeverityHigh},
+		{"all namespaces", &CheckResult{IsDangerous: true, IsAllNamespaces: true, Reasons: []string{"dangerous operation: delete"}}, SeverityHigh},
+		{"all namespaces on a protected cluster", &CheckResult{IsDangerous: true, Reasons: []string{"dangerous operation: delete", "AFFECTS ALL NAMESPACES (-A/--all-namespaces)", "protected cluster: prod"}}, SeverityCritical},
+		{"protected kind on a protected cluster", &CheckResult{IsDangerous: true, Reasons: []string{"dangerous operation: delete", "protected kind: Secret", "protected cluster: prod"}}, SeverityCritical},
+		{"typed confirmation", &CheckResult{IsDangerous: true, ConfirmationPhrase: "shop", Reasons: []string{"dangerous operation: delete"}}, SeverityCritical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.Severity(); got != tt.expected {
+				t.Errorf("Severity() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+
+	resources := &ResourceCheckResult{IsDangerous: true, Reasons: []string{"dangerous operation: apply", "protected namespace: istio-system"}}
+	if got := resources.Severity(); got != SeverityHigh {
+		t.Errorf("ResourceCheckResult.Severity() = %q, expected %q", got, SeverityHigh)
+	}
+}
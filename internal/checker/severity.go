@@ -0,0 +1,55 @@
+package checker
+
+import (
+	"strings"
+)
+
+// Warning severities, from least to most severe
+const (
+	SeverityLow      = "LOW"      // flagged, such as a Secret read or an unknown plugin, but not a dangerous operation
+	SeverityMedium   = "MEDIUM"   // a dangerous operation, or one that needs confirmation
+	SeverityHigh     = "HIGH"     // a protected cluster, namespace, kind or node, or all namespaces
+	SeverityCritical = "CRITICAL" // confirmed by typing a name, or all namespaces or protected objects on a protected cluster
+)
+
+// Severity grades the command's warning from the combination of its reasons,
+// or returns "" if it is not flagged. It reads the reasons as they are when
+// called, so reasons added after the check count.
+func (r *CheckResult) Severity() string {
+	return severity(r.IsDangerous, r.RequiresConfirmation, r.IsAllNamespaces, r.ConfirmationPhrase != "", r.Reasons)
+}
+
+// Severity grades the file-based command's warning like CheckResult.Severity
+func (r *ResourceCheckResult) Severity() string {
+	return severity(r.IsDangerous, r.RequiresConfirmation, false, r.ConfirmationPhrase != "", r.Reasons)
+}
+
+// severity combines what a check found into a severity
+func severity(dangerous, confirm, allNamespaces, typed bool, reasons []string) string {
+	if !dangerous {
+		return ""
+	}
+	var operation, cluster, protected bool
+	for _, reason := range reasons {
+		switch {
+		case strings.HasPrefix(reason, "dangerous operation: "), strings.HasPrefix(reason, "replace --force: "):
+			operation = true
+		case strings.HasPrefix(reason, "protected cluster: "):
+			cluster = true
+		case strings.HasPrefix(reason, "protected namespace: "), strings.HasPrefix(reason, "protected kind: "), strings.HasPrefix(reason, "protected node: "):
+			protected = true
+		case strings.HasPrefix(reason, "AFFECTS ALL NAMESPACES"):
+			allNamespaces = true
+		}
+	}
+
+	switch {
+	case typed, cluster && (allNamespaces || protected):
+		return SeverityCritical
+	case cluster, protected, allNamespaces:
+		return SeverityHigh
+	case operation, confirm:
+		return SeverityMedium
+	}
+	return SeverityLow
+}
@@ -1,10 +1,23 @@
 package config
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/i18n"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/ticket"
 )
 
 // Mode represents the confirmation mode
@@ -13,29 +26,433 @@ type Mode string
 const (
 	ModeConfirm  Mode = "confirm"
 	ModeWarnOnly Mode = "warn-only"
+	ModeTyped    Mode = "typed" // only in groupPolicies: type the cluster name to confirm
 )
 
 // AuditConfig holds audit logging configuration
 type AuditConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	Sink         string `yaml:"sink"` // "file" (default) or "syslog"
+	Path         string `yaml:"path"`
+	Format       string `yaml:"format"`       // "text" (default) or "json"
+	Facility     string `yaml:"facility"`     // syslog facility, e.g. "auth" or "local0"
+	HashChain    bool   `yaml:"hashChain"`    // record the SHA-256 of the previous line in each file entry
+	DeniedImpact bool   `yaml:"deniedImpact"` // record what a denied operation would have affected
+	ArchiveStdin bool   `yaml:"archiveStdin"` // keep manifests applied from stdin (-f -) next to the audit log
+
+	RequireReason bool `yaml:"requireReason"` // ask for a one-line justification after a dangerous command is confirmed
+
+	CaptureOutput      bool `yaml:"captureOutput"`      // keep what executed dangerous commands print next to the audit log
+	CaptureOutputLimit int  `yaml:"captureOutputLimit"` // bytes of output kept per command
+}
+
+// SyslogFacilities are the facility names accepted by audit.facility
+var SyslogFacilities = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news", "uucp", "cron", "authpriv", "ftp",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+}
+
+// DrainConfig holds pacing settings for drain-plan
+type DrainConfig struct {
+	PauseBetweenNodes  time.Duration `yaml:"pauseBetweenNodes"`  // fixed pause after each node
+	MaxPendingPods     int           `yaml:"maxPendingPods"`     // wait until fewer pods are Pending; 0 disables
+	HealthCheckTimeout time.Duration `yaml:"healthCheckTimeout"` // give up waiting for pending pods after this
+}
+
+// WatchRecreationConfig controls waiting for deleted pods/deployments to come back
+type WatchRecreationConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// RolloutGateConfig controls waiting on rollouts after image changes on protected clusters
+type RolloutGateConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// RoutesConfig controls host checks for Ingress, HTTPRoute and Gateway changes
+type RoutesConfig struct {
+	CriticalHosts   []string `yaml:"criticalHosts"`   // hosts whose routes always require confirmation to delete; "*.example.com" wildcards allowed
+	CheckCollisions bool     `yaml:"checkCollisions"` // warn when an apply claims a host another route already serves
+}
+
+// CriticalPods selects pods whose isolation by a NetworkPolicy is a possible lockout
+type CriticalPods struct {
+	Namespace string `yaml:"namespace"` // empty matches every namespace
+	Selector  string `yaml:"selector"`  // e.g. "k8s-app=kube-dns"
+}
+
+// NetworkPolicyConfig controls lockout detection for NetworkPolicies applied to protected namespaces
+type NetworkPolicyConfig struct {
+	CriticalPods []CriticalPods `yaml:"criticalPods"`
+}
+
+// GitOpsConfig controls manual changes to resources reconciled by Flux or Argo CD
+type GitOpsConfig struct {
+	SuspendReconciliation bool   `yaml:"suspendReconciliation"` // offer to suspend reconciliation before changing a managed resource
+	Warn                  bool   `yaml:"warn"`                  // warn that changes to a managed resource are reverted, without offering to suspend
+	ArgoCDNamespace       string `yaml:"argoCDNamespace"`       // where Argo CD Applications live unless their tracking ID says otherwise
+}
+
+// BackupRequiredConfig makes deleting certain kinds on protected clusters
+// depend on a successful backup
+type BackupRequiredConfig struct {
+	Kinds []string `yaml:"kinds"` // e.g. PersistentVolumeClaim, Namespace, CustomResourceDefinition
+	Hook  []string `yaml:"hook"`  // program and arguments run before each delete; a non-zero exit blocks it
+}
+
+// SnapshotConfig saves named objects locally before they are deleted
+type SnapshotConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Dir     string `yaml:"dir"` // each delete's snapshots go in a timestamped directory under it
+}
+
+// HooksConfig lists external programs run around each checked command. Each
+// hook is a program and its arguments; it reads the command as JSON on stdin.
+type HooksConfig struct {
+	PreExec  [][]string `yaml:"preExec"`  // a non-zero exit blocks the command
+	PostExec [][]string `yaml:"postExec"` // run after kubectl, with its exit code
+}
+
+// OPAConfig evaluates a Rego policy against every checked command with the opa CLI
+type OPAConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Policy  string `yaml:"policy"` // .rego file, directory or bundle passed to opa eval --data
+	Query   string `yaml:"query"`  // rule returning {"action": "allow|warn|deny", "messages": [...]}
+	Binary  string `yaml:"binary"` // opa executable
+}
+
+// ControlPlaneLoadConfig flags commands that put a load spike on a shared
+// API server and etcd
+type ControlPlaneLoadConfig struct {
+	Enabled    bool `yaml:"enabled"`
+	MaxObjects int  `yaml:"maxObjects"` // objects one delete --all or apply may touch before it is flagged
+	Confirm    bool `yaml:"confirm"`    // require confirmation for flagged commands instead of only warning
+}
+
+// SecretExposureConfig flags reads that print Secret contents to the
+// terminal, where they end up in scrollback, recordings and CI logs
+type SecretExposureConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Confirm bool `yaml:"confirm"` // require confirmation for flagged reads instead of only warning
+}
+
+// What happens to commands kubectl runs as plugins, for kubectlPlugins.unknown
+const (
+	PluginSafe    = "safe"    // run them without a warning
+	PluginWarn    = "warn"    // warn, then run them
+	PluginConfirm = "confirm" // require confirmation
+)
+
+// KubectlPluginsConfig classifies the commands kubectl does not know, which it
+// runs as plugins (kubectl-NAME executables, e.g. installed with krew).
+// Plugins in dangerousOperations are checked like any dangerous operation.
+type KubectlPluginsConfig struct {
+	Unknown string   `yaml:"unknown"` // safe (default), warn or confirm, for plugins not in allow
+	Allow   []string `yaml:"allow"`   // plugins always run without a warning, e.g. neat, ctx, ns
+}
+
+// IsAllowed reports whether a plugin is in the allowlist. kubectl runs
+// kubectl-foo_bar for foo-bar, so dashes and underscores are the same.
+func (p KubectlPluginsConfig) IsAllowed(plugin string) bool {
+	plugin = strings.ReplaceAll(plugin, "_", "-")
+	for _, name := range p.Allow {
+		if strings.ReplaceAll(name, "_", "-") == plugin {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactConfig filters kubectl's output through a redactor before it reaches
+// the terminal, for shared screens and recorded sessions
+type RedactConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Patterns []string `yaml:"patterns"` // regexes masked on every line; only the first group if a pattern has one
+	Piped    bool     `yaml:"piped"`    // also mask output that is piped or redirected, not just output to a terminal
+}
+
+// LargeOutputConfig controls the guard against dumping every object in the
+// cluster to a terminal
+type LargeOutputConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Pager   string `yaml:"pager"` // pager command, e.g. "less -S"; empty uses $PAGER, then less
+}
+
+// WarningExperiment shows one of several phrasings of the dangerous-operation
+// warning for a rule, so the wording that changes behavior can be measured
+type WarningExperiment struct {
+	Rule     string           `yaml:"rule"` // "operation [resource]", e.g. "delete" or "delete namespace"
+	Variants []WarningVariant `yaml:"variants"`
+}
+
+// WarningVariant is one phrasing in a warning experiment
+type WarningVariant struct {
+	Name     string `yaml:"name"`     // recorded in the audit entry as the variant shown
+	Weight   int    `yaml:"weight"`   // relative share of prompts; 0 counts as 1
+	Headline string `yaml:"headline"` // replaces "DANGEROUS OPERATION DETECTED"; empty keeps it
+	Severity string `yaml:"severity"` // warning (default, yellow) or critical (red)
+}
+
+// warningSeverities are the severities a WarningVariant may use
+var warningSeverities = []string{"", "warning", "critical"}
+
+// PluginsConfig runs rule plugins, executables or WASM modules that add an
+// organization's own checks, found in a directory
+type PluginsConfig struct {
+	Enabled     bool     `yaml:"enabled"`
+	Dir         string   `yaml:"dir"`         // every executable file or .wasm module in it is a plugin
+	WASMRuntime []string `yaml:"wasmRuntime"` // WASI runtime command that runs a .wasm module given as its last argument
+}
+
+// BatchConfig keeps the progress of changes run across clusters with
+// --sk-batch
+type BatchConfig struct {
+	Dir string `yaml:"dir"` // one file per batch ID
+}
+
+// CoverageConfig keeps a local log of commands safekubectl did not fully
+// understand, for `safekubectl coverage`
+type CoverageConfig struct {
 	Enabled bool   `yaml:"enabled"`
-	Path    string `yaml:"path"`
-	Format  string `yaml:"format"` // "text" (default) or "json"
+	Path    string `yaml:"path"` // JSON Lines, one entry per command
+}
+
+// ContextSwitchConfig guards kubectl config use-context into a protected
+// cluster, after which every command without --context runs against it
+type ContextSwitchConfig struct {
+	Confirm bool `yaml:"confirm"` // require confirmation to switch into a protected cluster
+	Banner  bool `yaml:"banner"`  // remind on every command that the current context is a protected cluster
+}
+
+// NotifyConfig alerts the operator when a dangerous command waits for
+// confirmation, so a prompt in a long pipeline is not mistaken for a hang.
+// Each list holds the warning severities that trigger the alert.
+type NotifyConfig struct {
+	Bell     []string        `yaml:"bell"`     // ring the terminal bell, e.g. [HIGH, CRITICAL]
+	Desktop  []string        `yaml:"desktop"`  // send a desktop notification
+	Channels []NotifyChannel `yaml:"channels"` // Slack or webhook channels told about dangerous commands that ran
+}
+
+// notifySeverities are the warning severities notify lists may hold
+var notifySeverities = []string{"LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// Alerts reports whether a prompt of the given severity rings the bell and
+// sends a desktop notification. Severities match case-insensitively.
+func (n NotifyConfig) Alerts(severity string) (bell, desktop bool) {
+	has := func(severities []string) bool {
+		return slices.ContainsFunc(severities, func(s string) bool { return strings.EqualFold(s, severity) })
+	}
+	return severity != "" && has(n.Bell), severity != "" && has(n.Desktop)
+}
+
+// GroupsConfig controls how the operator's groups are looked up for
+// groupPolicies
+type GroupsConfig struct {
+	Source       string   `yaml:"source"`       // "os" (default) or "oidc"
+	UserinfoURL  string   `yaml:"userinfoURL"`  // OIDC userinfo endpoint, with source oidc
+	TokenCommand []string `yaml:"tokenCommand"` // prints the access token sent to userinfoURL
+	Claim        string   `yaml:"claim"`        // userinfo claim listing the groups
+}
+
+// OnCallConfig looks up who is on call for each cluster, so that
+// groupPolicies can match the engineer on call as the "on-call" group
+type OnCallConfig struct {
+	Provider  string            `yaml:"provider"`  // "pagerduty" or "opsgenie"
+	TokenEnv  string            `yaml:"tokenEnv"`  // environment variable holding the API token
+	Email     string            `yaml:"email"`     // the operator's email in the roster
+	Schedules map[string]string `yaml:"schedules"` // cluster to schedule: an ID for PagerDuty, a name for Opsgenie
+}
+
+// FreezeConfig locks down dangerous commands during an incident: while a
+// freeze is active they are refused on the clusters it covers
+type FreezeConfig struct {
+	File     string   `yaml:"file"`     // a freeze is active while this file exists; its content is the reason
+	URL      string   `yaml:"url"`      // answers {"frozen": true, "reason": "..."} while a freeze is active
+	Clusters []string `yaml:"clusters"` // clusters or globs the freeze covers; empty covers every cluster
+	FailOpen bool     `yaml:"failOpen"` // let dangerous commands run while the freeze file or endpoint cannot be read
+}
+
+// Covers reports whether a freeze applies to cluster
+func (f FreezeConfig) Covers(cluster string) bool {
+	if len(f.Clusters) == 0 {
+		return true
+	}
+	for _, pattern := range f.Clusters {
+		if matched, _ := path.Match(pattern, cluster); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// TicketConfig requires a change ticket for dangerous commands on protected
+// clusters, optionally looked up in Jira or ServiceNow, and audits it
+type TicketConfig struct {
+	Required bool     `yaml:"required"`
+	Pattern  string   `yaml:"pattern"`  // regex a ticket ID must match, e.g. "^CHG[0-9]{7}$"
+	Provider string   `yaml:"provider"` // "jira" or "servicenow" to look tickets up; empty only checks the pattern
+	URL      string   `yaml:"url"`      // base URL of the ticketing system
+	UserEnv  string   `yaml:"userEnv"`  // environment variable holding the user for basic auth; empty sends the token as a bearer token
+	TokenEnv string   `yaml:"tokenEnv"` // environment variable holding the API token or password
+	Statuses []string `yaml:"statuses"` // statuses a ticket must be in, e.g. [Implement]; empty accepts any
+	FailOpen bool     `yaml:"failOpen"` // accept tickets as given while the ticketing system cannot be reached
+}
+
+// GroupPolicy sets how dangerous commands are confirmed for members of some
+// groups, optionally only on some clusters
+type GroupPolicy struct {
+	Groups   []string `yaml:"groups"`   // "*" matches everyone
+	Clusters []string `yaml:"clusters"` // empty matches every cluster
+	Mode     Mode     `yaml:"mode"`     // confirm, warn-only or typed
+}
+
+// ReviewConfig replaces the scrolling warning for large file-based operations
+// with an interactive review of the affected resources, grouped by namespace
+type ReviewConfig struct {
+	Enabled      bool `yaml:"enabled"`
+	MinResources int  `yaml:"minResources"` // resources an operation must affect to be reviewed
+	PageSize     int  `yaml:"pageSize"`     // namespaces shown per page
+}
+
+// VerifyCommand is a read-only kubectl command offered after an operation
+// runs, so the change is checked while it is still fresh
+type VerifyCommand struct {
+	Operation string `yaml:"operation"` // "operation [subcommand]", e.g. "delete" or "rollout restart"
+	Resource  string `yaml:"resource"`  // kind or resource name the target must have, e.g. "pod"; empty matches any
+	Command   string `yaml:"command"`   // kubectl args; {name}, {namespace}, {resource} and {selector} are filled in
+}
+
+// allNamespacesReadOperations are the reads an allNamespacesReads entry may name
+var allNamespacesReadOperations = []string{"get", "describe", "top", "events"}
+
+// verifyOperations are the read-only commands a VerifyCommand may run
+var verifyOperations = []string{"get", "describe", "logs", "top", "events", "wait", "rollout status", "rollout history", "auth can-i"}
+
+// Preflight checks run before prompting
+const (
+	PreflightServerDryRun = "server-dry-run" // rehearse apply/create/replace with --dry-run=server
+	PreflightCanI         = "can-i"          // ask RBAC whether the operation would be permitted
+	PreflightCredentials  = "credentials"    // flag static tokens and long-lived client certificates for protected clusters
+)
+
+// Preflights lists the enabled preflight checks. A single string is accepted
+// for compatibility with `preflight: server-dry-run`.
+type Preflights []string
+
+// UnmarshalYAML accepts a string or a list of strings
+func (p *Preflights) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*p = nil
+		if node.Value != "" {
+			*p = Preflights{node.Value}
+		}
+		return nil
+	}
+	var checks []string
+	if err := node.Decode(&checks); err != nil {
+		return err
+	}
+	*p = checks
+	return nil
+}
+
+// Has reports whether a preflight check is enabled
+func (p Preflights) Has(check string) bool {
+	for _, c := range p {
+		if c == check {
+			return true
+		}
+	}
+	return false
 }
 
 // Config holds the safekubectl configuration
 type Config struct {
-	Mode                Mode        `yaml:"mode"`
-	DangerousOperations []string    `yaml:"dangerousOperations"`
-	ProtectedNamespaces []string    `yaml:"protectedNamespaces"`
-	ProtectedClusters   []string    `yaml:"protectedClusters"`
-	Audit               AuditConfig `yaml:"audit"`
+	Mode                     Mode                   `yaml:"mode"`
+	Language                 string                 `yaml:"language"` // language of warnings and confirmation prompts: en, id, ja or es
+	DangerousOperations      []string               `yaml:"dangerousOperations"`
+	ProtectedNamespaces      []string               `yaml:"protectedNamespaces"`
+	ProtectedClusters        []string               `yaml:"protectedClusters"`
+	ProtectedKinds           []string               `yaml:"protectedKinds"`
+	ProtectedNodes           []string               `yaml:"protectedNodes"`      // node names or globs such as "*-master-*" whose cordon, drain, taint or delete always needs confirmation
+	BlockProtectedNodes      bool                   `yaml:"blockProtectedNodes"` // refuse changes to protected nodes instead
+	SafeOperations           []string               `yaml:"safeOperations"`      // passed straight to kubectl, e.g. "top" or "config get-clusters"
+	AllNamespacesReads       []string               `yaml:"allNamespacesReads"`  // "operation [resource]" reads that need confirmation with -A on protected clusters, e.g. "get secrets"
+	Audit                    AuditConfig            `yaml:"audit"`
+	PreviewNamespaceDeletion bool                   `yaml:"previewNamespaceDeletion"` // list namespace contents before deleting it
+	PreviewDrain             bool                   `yaml:"previewDrain"`             // list the pods a drain evicts before confirming it (default true)
+	PreviewPrune             bool                   `yaml:"previewPrune"`             // list the live objects apply --prune deletes before confirming it (default true)
+	DescribeNodes            bool                   `yaml:"describeNodes"`            // show the pool, zone and instance type of drained or cordoned nodes (default true)
+	PreviewApplyOrder        bool                   `yaml:"previewApplyOrder"`        // show the kind order of multi-document applies and flag inverted dependencies (default true)
+	SelectiveApply           bool                   `yaml:"selectiveApply"`           // offer to skip manifest resources in protected namespaces
+	CountConfigReferences    bool                   `yaml:"countConfigReferences"`    // show how many pods use a ConfigMap/Secret being changed
+	CheckMissingReferences   bool                   `yaml:"checkMissingReferences"`   // warn when manifests reference ConfigMaps, Secrets and similar found neither in the apply nor the cluster
+	CheckActiveJobs          bool                   `yaml:"checkActiveJobs"`          // warn when deleting Jobs whose pods are still running
+	CheckDeleteTargets       bool                   `yaml:"checkDeleteTargets"`       // look up named delete targets: missing ones, age, labels and owners
+	CheckDisruption          bool                   `yaml:"checkDisruption"`          // warn when delete pod or drain breaks a PodDisruptionBudget or leaves no ready replicas
+	CheckManifestRisks       bool                   `yaml:"checkManifestRisks"`       // warn about bare Pods, hostPath, privileged containers and similar in applied manifests
+	NamespacePolicy          bool                   `yaml:"namespacePolicy"`          // honor the safekubectl.io/policy annotation of target namespaces (default true)
+	Preflight                Preflights             `yaml:"preflight"`                // "server-dry-run", "can-i" and/or "credentials"
+	Drain                    DrainConfig            `yaml:"drain"`
+	PolicySource             string                 `yaml:"policySource"`   // URL of an organization-wide policy bundle
+	PolicySHA256             string                 `yaml:"policySHA256"`   // pinned bundle checksum; otherwise <policySource>.sha256 is used
+	PolicyCacheTTL           time.Duration          `yaml:"policyCacheTTL"` // how long a fetched bundle is reused
+	Rulesets                 []Ruleset              `yaml:"rulesets"`       // shared rule bundles by name, e.g. github.com/org/safekubectl-rules/istio@v1, pinned by sha256
+	KindMessages             map[string]string      `yaml:"kindMessages"`   // explanation shown with a protected kind, by kind
+	WatchRecreation          WatchRecreationConfig  `yaml:"watchRecreation"`
+	RolloutGate              RolloutGateConfig      `yaml:"rolloutGate"`
+	Routes                   RoutesConfig           `yaml:"routes"`
+	NetworkPolicy            NetworkPolicyConfig    `yaml:"networkPolicy"`
+	GitOps                   GitOpsConfig           `yaml:"gitops"`
+	Verify                   []VerifyCommand        `yaml:"verify"` // follow-up read commands offered after an operation
+	BackupRequired           BackupRequiredConfig   `yaml:"backupRequired"`
+	Snapshot                 SnapshotConfig         `yaml:"snapshot"`
+	Batch                    BatchConfig            `yaml:"batch"`
+	ControlPlaneLoad         ControlPlaneLoadConfig `yaml:"controlPlaneLoad"`
+	LargeOutput              LargeOutputConfig      `yaml:"largeOutput"`
+	SecretExposure           SecretExposureConfig   `yaml:"secretExposure"`
+	KubectlPlugins           KubectlPluginsConfig   `yaml:"kubectlPlugins"`
+	Coverage                 CoverageConfig         `yaml:"coverage"`
+	ContextSwitch            ContextSwitchConfig    `yaml:"contextSwitch"`
+	Notify                   NotifyConfig           `yaml:"notify"`
+	Redact                   RedactConfig           `yaml:"redact"`
+	Review                   ReviewConfig           `yaml:"review"`
+	Hooks                    HooksConfig            `yaml:"hooks"`
+	OPA                      OPAConfig              `yaml:"opa"`
+	Plugins                  PluginsConfig          `yaml:"plugins"`
+	WarningExperiments       []WarningExperiment    `yaml:"warningExperiments"` // A/B tests of warning wording per rule
+	Templates                TemplatesConfig        `yaml:"templates"`
+	Groups                   GroupsConfig           `yaml:"groups"`
+	GroupPolicies            []GroupPolicy          `yaml:"groupPolicies"` // the first policy matching the operator's groups and cluster applies
+	ChangeWindows            []ChangeWindow         `yaml:"changeWindows"` // when dangerous commands may run on protected clusters; the first matching window applies
+	OnCall                   OnCallConfig           `yaml:"onCall"`
+	Freeze                   FreezeConfig           `yaml:"freeze"`
+	Ticket                   TicketConfig           `yaml:"ticket"`
+
+	Sources []string `yaml:"-"` // config files that were loaded, lowest precedence first
+}
+
+// BuiltinSafeOperations are read-only commands that are passed straight to
+// kubectl before the config is loaded or the context is looked up. An entry is
+// an operation, optionally followed by its subcommand.
+var BuiltinSafeOperations = []string{
+	"version",
+	"api-resources",
+	"api-versions",
+	"explain",
+	"config view",
+	"config get-contexts",
+	"config current-context",
 }
 
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
 	homeDir, _ := os.UserHomeDir()
 	return &Config{
-		Mode: ModeConfirm,
+		Mode:     ModeConfirm,
+		Language: i18n.English,
 		DangerousOperations: []string{
 			"delete",
 			"apply",
@@ -52,10 +469,80 @@ func DefaultConfig() *Config {
 			"kube-system",
 		},
 		ProtectedClusters: []string{},
+		ProtectedKinds: []string{
+			"CustomResourceDefinition",
+			"ClusterRole",
+			"ClusterRoleBinding",
+			"StorageClass",
+			"PersistentVolume",
+		},
 		Audit: AuditConfig{
+			Enabled:  false,
+			Sink:     "file",
+			Path:     filepath.Join(homeDir, ".safekubectl", "audit.log"),
+			Format:   "text",
+			Facility: "auth",
+
+			CaptureOutputLimit: 64 * 1024,
+		},
+		Drain: DrainConfig{
+			HealthCheckTimeout: 10 * time.Minute,
+		},
+		NamespacePolicy:   true,
+		PreviewDrain:      true,
+		PreviewPrune:      true,
+		DescribeNodes:     true,
+		PreviewApplyOrder: true,
+		PolicyCacheTTL:    time.Hour,
+		WatchRecreation: WatchRecreationConfig{
+			Enabled: false,
+			Timeout: 2 * time.Minute,
+		},
+		NetworkPolicy: NetworkPolicyConfig{
+			CriticalPods: []CriticalPods{
+				{Namespace: "kube-system", Selector: "k8s-app=kube-dns"},
+			},
+		},
+		RolloutGate: RolloutGateConfig{
+			Enabled: false,
+			Timeout: 5 * time.Minute,
+		},
+		GitOps: GitOpsConfig{
+			ArgoCDNamespace: "argocd",
+		},
+		OPA: OPAConfig{
+			Enabled: false,
+			Query:   "data.safekubectl.decision",
+			Binary:  "opa",
+		},
+		ControlPlaneLoad: ControlPlaneLoadConfig{
+			Enabled:    false,
+			MaxObjects: 500,
+		},
+		Plugins: PluginsConfig{
+			Enabled:     false,
+			Dir:         filepath.Join(homeDir, ".safekubectl", "plugins"),
+			WASMRuntime: []string{"wazero", "run"},
+		},
+		Snapshot: SnapshotConfig{
+			Enabled: false,
+			Dir:     filepath.Join(homeDir, ".safekubectl", "backups"),
+		},
+		Batch: BatchConfig{
+			Dir: filepath.Join(homeDir, ".safekubectl", "batches"),
+		},
+		Coverage: CoverageConfig{
 			Enabled: false,
-			Path:    filepath.Join(homeDir, ".safekubectl", "audit.log"),
-			Format:  "text",
+			Path:    filepath.Join(homeDir, ".safekubectl", "coverage.log"),
+		},
+		Groups: GroupsConfig{
+			Source: GroupSourceOS,
+			Claim:  "groups",
+		},
+		Review: ReviewConfig{
+			Enabled:      false,
+			MinResources: 20,
+			PageSize:     10,
 		},
 	}
 }
@@ -75,25 +562,106 @@ func getConfigPath() string {
 	return filepath.Join(homeDir, ".safekubectl", "config.yaml")
 }
 
-// Load loads the configuration from file or returns defaults
-func Load() (*Config, error) {
-	config := DefaultConfig()
+// systemConfigPath is the machine-wide config, lowest precedence
+var systemConfigPath = "/etc/safekubectl/config.yaml"
+
+// localConfigName is the repo-local config discovered by walking up from the working directory
+const localConfigName = ".safekubectl.yaml"
 
-	configPath := getConfigPath()
-	if configPath == "" {
-		return config, nil
+// localConfigKeys are the only settings a repo-local config may set: lists
+// that add protection. Anything else could turn protection down, or run
+// programs, for whoever runs safekubectl inside a cloned repository.
+var localConfigKeys = []string{"dangerousOperations", "protectedNamespaces", "protectedClusters", "protectedKinds", "protectedNodes"}
+
+// configPaths returns the config files to merge, lowest precedence first,
+// and which of them is the repo-local config, if any.
+// SAFEKUBECTL_CONFIG selects a single file and disables layering.
+func configPaths() ([]string, string) {
+	if envPath := os.Getenv("SAFEKUBECTL_CONFIG"); envPath != "" {
+		return []string{envPath}, ""
+	}
+
+	paths := []string{systemConfigPath}
+	if userPath := getConfigPath(); userPath != "" {
+		paths = append(paths, userPath)
+	}
+	localPath := findLocalConfig()
+	if localPath != "" {
+		paths = append(paths, localPath)
 	}
+	return paths, localPath
+}
 
-	data, err := os.ReadFile(configPath)
+// findLocalConfig walks up from the working directory looking for a repo-local config
+func findLocalConfig() string {
+	dir, err := os.Getwd()
 	if err != nil {
-		if os.IsNotExist(err) {
-			// Config file doesn't exist, use defaults
-			return config, nil
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, localConfigName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Load loads the configuration from file or returns defaults.
+// Config files are layered (system, user, repo-local): later files override
+// scalar settings, and their lists are added to the lists set by earlier files.
+// The first file that sets a list replaces the built-in default for it, even
+// when an earlier file set other settings. The repo-local config may only add
+// to the lists in localConfigKeys.
+func Load() (*Config, error) {
+	return load(true)
+}
+
+// LoadCached loads the configuration like Load, but never fetches the policy
+// bundle or rulesets: their cached copies are used whatever their age, and a
+// missing one is an error. For callers that must not wait on the network.
+func LoadCached() (*Config, error) {
+	return load(false)
+}
+
+// load loads the layered configuration, fetching the policy bundle and
+// rulesets when their cache is stale only if fetch is set
+func load(fetch bool) (*Config, error) {
+	config := DefaultConfig()
+	setLists := make(map[string]bool)
+
+	paths, localPath := configPaths()
+	for _, configPath := range paths {
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Missing layers are skipped
+				continue
+			}
+			return nil, err
 		}
+
+		if configPath == localPath {
+			err = config.mergeLocalLayer(data)
+		} else {
+			err = config.mergeLayer(data, setLists)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", configPath, err)
+		}
+		config.Sources = append(config.Sources, configPath)
+	}
+
+	// Environment variables override every config file
+	if err := config.applyEnv(); err != nil {
 		return nil, err
 	}
 
-	if err := yaml.Unmarshal(data, config); err != nil {
+	if err := config.Validate(); err != nil {
 		return nil, err
 	}
 
@@ -101,10 +669,411 @@ func Load() (*Config, error) {
 	if config.Audit.Path != "" {
 		config.Audit.Path = expandPath(config.Audit.Path)
 	}
+	config.Plugins.Dir = expandPath(config.Plugins.Dir)
+	config.Snapshot.Dir = expandPath(config.Snapshot.Dir)
+	config.Batch.Dir = expandPath(config.Batch.Dir)
+	config.Freeze.File = expandPath(config.Freeze.File)
+	config.Coverage.Path = expandPath(config.Coverage.Path)
+
+	// Merge the organization policy bundle, cached next to the user config file
+	if config.PolicySource != "" {
+		if err := config.applyPolicySource(filepath.Join(filepath.Dir(getConfigPath()), "policy-cache"), fetch); err != nil {
+			return nil, err
+		}
+	}
+
+	// Merge shared rulesets, cached alongside the policy bundle
+	if len(config.Rulesets) > 0 {
+		if err := config.applyRulesets(filepath.Join(filepath.Dir(getConfigPath()), "policy-cache"), fetch); err != nil {
+			return nil, err
+		}
+	}
 
 	return config, nil
 }
 
+// Parse builds a config from the contents of one config file on top of the
+// defaults, without the other layers, environment overrides or policy bundle
+func Parse(data []byte) (*Config, error) {
+	config := DefaultConfig()
+	if err := config.mergeLayer(data, nil); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if config.Audit.Path != "" {
+		config.Audit.Path = expandPath(config.Audit.Path)
+	}
+	config.Plugins.Dir = expandPath(config.Plugins.Dir)
+	config.Snapshot.Dir = expandPath(config.Snapshot.Dir)
+	config.Batch.Dir = expandPath(config.Batch.Dir)
+	config.Freeze.File = expandPath(config.Freeze.File)
+	config.Coverage.Path = expandPath(config.Coverage.Path)
+	return config, nil
+}
+
+// layerLists are the list settings that later config layers add to rather
+// than replace, keyed by their path in the config file
+var layerLists = []struct {
+	key   string
+	merge func(c, previous *Config) // adds the previous layers' list to this one's
+}{
+	{"dangerousOperations", func(c, p *Config) { c.DangerousOperations = mergeUnique(p.DangerousOperations, c.DangerousOperations) }},
+	{"protectedNamespaces", func(c, p *Config) { c.ProtectedNamespaces = mergeUnique(p.ProtectedNamespaces, c.ProtectedNamespaces) }},
+	{"protectedClusters", func(c, p *Config) { c.ProtectedClusters = mergeUnique(p.ProtectedClusters, c.ProtectedClusters) }},
+	{"protectedKinds", func(c, p *Config) { c.ProtectedKinds = mergeUnique(p.ProtectedKinds, c.ProtectedKinds) }},
+	{"protectedNodes", func(c, p *Config) { c.ProtectedNodes = mergeUnique(p.ProtectedNodes, c.ProtectedNodes) }},
+	{"routes.criticalHosts", func(c, p *Config) {
+		c.Routes.CriticalHosts = mergeUnique(p.Routes.CriticalHosts, c.Routes.CriticalHosts)
+	}},
+	{"safeOperations", func(c, p *Config) { c.SafeOperations = mergeUnique(p.SafeOperations, c.SafeOperations) }},
+	{"rulesets", func(c, p *Config) { c.Rulesets = mergeUnique(p.Rulesets, c.Rulesets) }},
+}
+
+// mergeLayer applies one config file on top of the current config. Lists in
+// setLists, the ones an earlier layer already set, are added to instead of
+// replaced, and the lists this file sets are recorded there. A nil setLists
+// replaces every list the file sets.
+func (c *Config) mergeLayer(data []byte, setLists map[string]bool) error {
+	previous := *c
+
+	// Unknown keys are rejected so a typo is not silently ignored
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && err != io.EOF {
+		return err
+	}
+	if setLists == nil {
+		return nil
+	}
+
+	keys, err := layerKeys(data)
+	if err != nil {
+		return err
+	}
+	for _, list := range layerLists {
+		if !keys[list.key] {
+			continue
+		}
+		if setLists[list.key] {
+			list.merge(c, &previous)
+		}
+		setLists[list.key] = true
+	}
+	return nil
+}
+
+// layerKeys returns the top-level keys a config file sets, and the keys one
+// level below them as parent.child
+func layerKeys(data []byte) (map[string]bool, error) {
+	var top map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &top); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]bool)
+	for key, node := range top {
+		keys[key] = true
+		if node.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keys[key+"."+node.Content[i].Value] = true
+		}
+	}
+	return keys, nil
+}
+
+// mergeLocalLayer adds the protections of a repo-local config to the current
+// config, rejecting any setting outside localConfigKeys
+func (c *Config) mergeLocalLayer(data []byte) error {
+	var keys map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+	var rejected []string
+	for key := range keys {
+		if !slices.Contains(localConfigKeys, key) {
+			rejected = append(rejected, key)
+		}
+	}
+	if len(rejected) > 0 {
+		slices.Sort(rejected)
+		return fmt.Errorf("a repo-local config may only add %s; move %s to your user config", strings.Join(localConfigKeys, ", "), strings.Join(rejected, ", "))
+	}
+	// Every list it may set is added to, never replacing the defaults
+	added := make(map[string]bool, len(localConfigKeys))
+	for _, key := range localConfigKeys {
+		added[key] = true
+	}
+	return c.mergeLayer(data, added)
+}
+
+// Validate reports settings that would otherwise silently weaken protection
+func (c *Config) Validate() error {
+	var problems []string
+
+	if c.Mode != ModeConfirm && c.Mode != ModeWarnOnly {
+		problems = append(problems, fmt.Sprintf("invalid mode %q: expected %q or %q", c.Mode, ModeConfirm, ModeWarnOnly))
+	}
+	if !i18n.IsSupported(c.Language) {
+		problems = append(problems, fmt.Sprintf("invalid language %q: expected one of %s", c.Language, strings.Join(i18n.Languages, ", ")))
+	}
+	if len(c.DangerousOperations) == 0 {
+		problems = append(problems, "dangerousOperations is empty: every command would run unchecked; remove the key to use the defaults")
+	}
+	for _, op := range c.SafeOperations {
+		if fields := strings.Fields(op); len(fields) == 1 && c.IsDangerousOperation(fields[0]) {
+			problems = append(problems, fmt.Sprintf("safeOperations entry %q is also a dangerous operation: it would never be checked", op))
+		}
+	}
+	if c.Audit.Format != "" && c.Audit.Format != "text" && c.Audit.Format != "json" {
+		problems = append(problems, fmt.Sprintf("invalid audit.format %q: expected \"text\" or \"json\"", c.Audit.Format))
+	}
+	if c.Audit.Sink != "" && c.Audit.Sink != "file" && c.Audit.Sink != "syslog" {
+		problems = append(problems, fmt.Sprintf("invalid audit.sink %q: expected \"file\" or \"syslog\"", c.Audit.Sink))
+	}
+	if c.Audit.Facility != "" && !isSyslogFacility(c.Audit.Facility) {
+		problems = append(problems, fmt.Sprintf("invalid audit.facility %q: expected one of %s", c.Audit.Facility, strings.Join(SyslogFacilities, ", ")))
+	}
+	if c.Audit.CaptureOutput && c.Audit.CaptureOutputLimit < 1 {
+		problems = append(problems, fmt.Sprintf("invalid audit.captureOutputLimit %d: must be at least 1", c.Audit.CaptureOutputLimit))
+	}
+	for _, check := range c.Preflight {
+		if check != PreflightServerDryRun && check != PreflightCanI && check != PreflightCredentials {
+			problems = append(problems, fmt.Sprintf("invalid preflight %q: expected %q, %q or %q", check, PreflightServerDryRun, PreflightCanI, PreflightCredentials))
+		}
+	}
+	hookStages := []struct {
+		key   string
+		hooks [][]string
+	}{
+		{"preExec", c.Hooks.PreExec},
+		{"postExec", c.Hooks.PostExec},
+	}
+	for _, stage := range hookStages {
+		for i, hook := range stage.hooks {
+			if len(hook) == 0 || hook[0] == "" {
+				problems = append(problems, fmt.Sprintf("hooks.%s[%d]: command is empty", stage.key, i))
+			}
+		}
+	}
+	if c.OPA.Enabled && c.OPA.Policy == "" {
+		problems = append(problems, "opa.enabled is set but opa.policy is empty")
+	}
+	for _, entry := range c.AllNamespacesReads {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 || len(fields) > 2 || !contains(allNamespacesReadOperations, fields[0]) {
+			problems = append(problems, fmt.Sprintf("invalid allNamespacesReads entry %q: expected \"OPERATION [RESOURCE]\" where OPERATION is one of %s", entry, strings.Join(allNamespacesReadOperations, ", ")))
+		}
+	}
+	for i, experiment := range c.WarningExperiments {
+		if fields := strings.Fields(experiment.Rule); len(fields) == 0 || len(fields) > 2 {
+			problems = append(problems, fmt.Sprintf("warningExperiments[%d]: invalid rule %q: expected \"OPERATION [RESOURCE]\"", i, experiment.Rule))
+		}
+		if len(experiment.Variants) == 0 {
+			problems = append(problems, fmt.Sprintf("warningExperiments[%d]: at least one variant is required", i))
+		}
+		names := map[string]bool{}
+		for j, v := range experiment.Variants {
+			switch {
+			case v.Name == "":
+				problems = append(problems, fmt.Sprintf("warningExperiments[%d].variants[%d]: name is required", i, j))
+			case names[v.Name]:
+				problems = append(problems, fmt.Sprintf("warningExperiments[%d].variants[%d]: duplicate name %q", i, j, v.Name))
+			}
+			names[v.Name] = true
+			if v.Weight < 0 {
+				problems = append(problems, fmt.Sprintf("warningExperiments[%d].variants[%d]: invalid weight %d: must not be negative", i, j, v.Weight))
+			}
+			if !contains(warningSeverities, v.Severity) {
+				problems = append(problems, fmt.Sprintf("warningExperiments[%d].variants[%d]: invalid severity %q: expected warning or critical", i, j, v.Severity))
+			}
+		}
+	}
+	for i, ch := range c.Notify.Channels {
+		if ch.Type != ChannelSlack && ch.Type != ChannelWebhook {
+			problems = append(problems, fmt.Sprintf("notify.channels[%d]: invalid type %q: expected %q or %q", i, ch.Type, ChannelSlack, ChannelWebhook))
+		}
+		if ch.URLEnv == "" {
+			problems = append(problems, fmt.Sprintf("notify.channels[%d]: urlEnv is required", i))
+		}
+		for _, s := range []string{ch.MinSeverity, ch.QuietMinSeverity} {
+			if s != "" && severityRank(s) < 0 {
+				problems = append(problems, fmt.Sprintf("notify.channels[%d]: invalid severity %q: expected one of %s", i, s, strings.Join(notifySeverities, ", ")))
+			}
+		}
+		if _, err := time.LoadLocation(ch.Timezone); err != nil {
+			problems = append(problems, fmt.Sprintf("notify.channels[%d]: invalid timezone %q", i, ch.Timezone))
+		}
+		for _, spec := range ch.QuietHours {
+			if _, err := parseTimeRange(spec); err != nil {
+				problems = append(problems, fmt.Sprintf("notify.channels[%d]: %s", i, err))
+			}
+		}
+		for _, pattern := range ch.Clusters {
+			if _, err := path.Match(pattern, ""); err != nil {
+				problems = append(problems, fmt.Sprintf("notify.channels[%d]: invalid cluster pattern %q: %s", i, pattern, err))
+			}
+		}
+	}
+	if _, err := ParseTemplate("warning", c.Templates.Warning); err != nil {
+		problems = append(problems, fmt.Sprintf("invalid templates.warning: %v", err))
+	}
+	if _, err := ParseTemplate("resourceWarning", c.Templates.ResourceWarning); err != nil {
+		problems = append(problems, fmt.Sprintf("invalid templates.resourceWarning: %v", err))
+	}
+	for _, n := range []struct {
+		key        string
+		severities []string
+	}{{"notify.bell", c.Notify.Bell}, {"notify.desktop", c.Notify.Desktop}} {
+		for _, s := range n.severities {
+			if !slices.Contains(notifySeverities, strings.ToUpper(s)) {
+				problems = append(problems, fmt.Sprintf("invalid %s severity %q: expected one of %s", n.key, s, strings.Join(notifySeverities, ", ")))
+			}
+		}
+	}
+	if c.PolicySource != "" && c.PolicySHA256 == "" && !strings.HasPrefix(c.PolicySource, "https://") {
+		problems = append(problems, fmt.Sprintf("invalid policySource %q: expected an https URL, or pin the bundle with policySHA256: a checksum fetched over plain http protects nothing", redactURL(c.PolicySource)))
+	}
+	if len(c.BackupRequired.Kinds) > 0 && len(c.BackupRequired.Hook) == 0 {
+		problems = append(problems, "backupRequired.kinds is set but backupRequired.hook is empty: those deletions would always be blocked")
+	}
+	for i, v := range c.Verify {
+		fields := strings.Fields(v.Command)
+		switch {
+		case v.Operation == "":
+			problems = append(problems, fmt.Sprintf("verify[%d]: operation is required", i))
+		case len(fields) == 0:
+			problems = append(problems, fmt.Sprintf("verify[%d]: command is required", i))
+		case !matchesOperation(verifyOperations, fields[0], subcommandOf(fields)):
+			problems = append(problems, fmt.Sprintf("verify[%d]: command %q is not read-only: expected one of %s", i, v.Command, strings.Join(verifyOperations, ", ")))
+		}
+	}
+	if c.ControlPlaneLoad.Enabled && c.ControlPlaneLoad.MaxObjects < 1 {
+		problems = append(problems, fmt.Sprintf("invalid controlPlaneLoad.maxObjects %d: must be at least 1", c.ControlPlaneLoad.MaxObjects))
+	}
+	if c.Plugins.Enabled && c.Plugins.Dir == "" {
+		problems = append(problems, "plugins.enabled is set but plugins.dir is empty")
+	}
+	for _, ruleset := range c.Rulesets {
+		if err := checkRuleset(ruleset); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	for _, pattern := range c.ProtectedNodes {
+		if _, err := path.Match(pattern, ""); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid protectedNodes entry %q: %s", pattern, err))
+		}
+	}
+	for i, pattern := range c.Redact.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			problems = append(problems, fmt.Sprintf("redact.patterns[%d]: invalid regex %q: %s", i, pattern, err))
+		}
+	}
+	if c.Snapshot.Enabled && c.Snapshot.Dir == "" {
+		problems = append(problems, "snapshot.enabled is set but snapshot.dir is empty")
+	}
+	if c.Groups.Source != GroupSourceOS && c.Groups.Source != GroupSourceOIDC {
+		problems = append(problems, fmt.Sprintf("invalid groups.source %q: expected %q or %q", c.Groups.Source, GroupSourceOS, GroupSourceOIDC))
+	}
+	if c.Groups.Source == GroupSourceOIDC && (c.Groups.UserinfoURL == "" || len(c.Groups.TokenCommand) == 0) {
+		problems = append(problems, "groups.source is oidc but groups.userinfoURL or groups.tokenCommand is empty")
+	}
+	for i, policy := range c.GroupPolicies {
+		if len(policy.Groups) == 0 {
+			problems = append(problems, fmt.Sprintf("groupPolicies[%d]: at least one group is required", i))
+		}
+		if policy.Mode != ModeConfirm && policy.Mode != ModeWarnOnly && policy.Mode != ModeTyped {
+			problems = append(problems, fmt.Sprintf("groupPolicies[%d]: invalid mode %q: expected %q, %q or %q", i, policy.Mode, ModeConfirm, ModeWarnOnly, ModeTyped))
+		}
+	}
+	for i, w := range c.ChangeWindows {
+		if w.Action != "" && w.Action != WindowConfirm && w.Action != WindowTyped && w.Action != WindowBlock {
+			problems = append(problems, fmt.Sprintf("changeWindows[%d]: invalid action %q: expected %q, %q or %q", i, w.Action, WindowConfirm, WindowTyped, WindowBlock))
+		}
+		if _, err := time.LoadLocation(w.Timezone); err != nil {
+			problems = append(problems, fmt.Sprintf("changeWindows[%d]: invalid timezone %q", i, w.Timezone))
+		}
+		if len(w.Allow) == 0 && len(w.Block) == 0 {
+			problems = append(problems, fmt.Sprintf("changeWindows[%d]: allow or block is required", i))
+		}
+		for _, spec := range append(slices.Clone(w.Allow), w.Block...) {
+			if _, err := parseTimeRange(spec); err != nil {
+				problems = append(problems, fmt.Sprintf("changeWindows[%d]: %s", i, err))
+			}
+		}
+		for _, pattern := range w.Clusters {
+			if _, err := path.Match(pattern, ""); err != nil {
+				problems = append(problems, fmt.Sprintf("changeWindows[%d]: invalid cluster pattern %q: %s", i, pattern, err))
+			}
+		}
+	}
+	if c.Freeze.URL != "" && !strings.HasPrefix(c.Freeze.URL, "https://") && !strings.HasPrefix(c.Freeze.URL, "http://") {
+		problems = append(problems, fmt.Sprintf("invalid freeze.url %q: expected an http(s) URL", c.Freeze.URL))
+	}
+	for _, pattern := range c.Freeze.Clusters {
+		if _, err := path.Match(pattern, ""); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid freeze.clusters entry %q: %s", pattern, err))
+		}
+	}
+	if _, err := regexp.Compile(c.Ticket.Pattern); err != nil {
+		problems = append(problems, fmt.Sprintf("ticket.pattern: invalid regex: %s", err))
+	}
+	if c.Ticket.Provider != "" {
+		if c.Ticket.Provider != ticket.Jira && c.Ticket.Provider != ticket.ServiceNow {
+			problems = append(problems, fmt.Sprintf("invalid ticket.provider %q: expected %q or %q", c.Ticket.Provider, ticket.Jira, ticket.ServiceNow))
+		}
+		if c.Ticket.URL == "" || c.Ticket.TokenEnv == "" {
+			problems = append(problems, "ticket.provider is set but ticket.url or ticket.tokenEnv is empty")
+		}
+	}
+	if len(c.OnCall.Schedules) > 0 {
+		if c.OnCall.Provider != OnCallPagerDuty && c.OnCall.Provider != OnCallOpsgenie {
+			problems = append(problems, fmt.Sprintf("invalid onCall.provider %q: expected %q or %q", c.OnCall.Provider, OnCallPagerDuty, OnCallOpsgenie))
+		}
+		if c.OnCall.TokenEnv == "" || c.OnCall.Email == "" {
+			problems = append(problems, "onCall.schedules is set but onCall.tokenEnv or onCall.email is empty")
+		}
+	}
+	if p := c.KubectlPlugins.Unknown; p != "" && p != PluginSafe && p != PluginWarn && p != PluginConfirm {
+		problems = append(problems, fmt.Sprintf("invalid kubectlPlugins.unknown %q: expected %q, %q or %q", p, PluginSafe, PluginWarn, PluginConfirm))
+	}
+	if c.Plugins.Enabled && len(c.Plugins.WASMRuntime) == 0 {
+		problems = append(problems, "plugins.enabled is set but plugins.wasmRuntime is empty")
+	}
+	if c.Review.Enabled && c.Review.MinResources < 1 {
+		problems = append(problems, fmt.Sprintf("invalid review.minResources %d: must be at least 1", c.Review.MinResources))
+	}
+	if c.Review.Enabled && c.Review.PageSize < 1 {
+		problems = append(problems, fmt.Sprintf("invalid review.pageSize %d: must be at least 1", c.Review.PageSize))
+	}
+	if c.Drain.MaxPendingPods < 0 {
+		problems = append(problems, fmt.Sprintf("invalid drain.maxPendingPods %d: must not be negative", c.Drain.MaxPendingPods))
+	}
+
+	durations := []struct {
+		key   string
+		value time.Duration
+	}{
+		{"drain.pauseBetweenNodes", c.Drain.PauseBetweenNodes},
+		{"drain.healthCheckTimeout", c.Drain.HealthCheckTimeout},
+		{"policyCacheTTL", c.PolicyCacheTTL},
+		{"watchRecreation.timeout", c.WatchRecreation.Timeout},
+		{"rolloutGate.timeout", c.RolloutGate.Timeout},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			problems = append(problems, fmt.Sprintf("invalid %s %s: must not be negative", d.key, d.value))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
 // expandPath expands ~ to home directory
 func expandPath(path string) string {
 	if len(path) > 0 && path[0] == '~' {
@@ -127,6 +1096,65 @@ func (c *Config) IsDangerousOperation(operation string) bool {
 	return false
 }
 
+// IsSafeOperation checks if a command is a built-in or configured safe operation
+func (c *Config) IsSafeOperation(operation, subcommand string) bool {
+	return IsBuiltinSafeOperation(operation, subcommand) || matchesOperation(c.SafeOperations, operation, subcommand)
+}
+
+// IsBuiltinSafeOperation checks if a command is a built-in safe operation
+func IsBuiltinSafeOperation(operation, subcommand string) bool {
+	return matchesOperation(BuiltinSafeOperations, operation, subcommand)
+}
+
+// matchesOperation checks a command against "operation [subcommand]" entries.
+// An entry without a subcommand matches every subcommand of the operation.
+func matchesOperation(entries []string, operation, subcommand string) bool {
+	for _, entry := range entries {
+		fields := strings.Fields(entry)
+		switch {
+		case len(fields) == 0 || fields[0] != operation:
+			continue
+		case len(fields) == 1 || fields[1] == subcommand:
+			return true
+		}
+	}
+	return false
+}
+
+// subcommandOf returns the word after the operation in kubectl args, if any
+func subcommandOf(fields []string) string {
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[1]
+}
+
+// VerifyCommandsFor returns the follow-up commands configured for an operation
+func (c *Config) VerifyCommandsFor(operation, subcommand string) []VerifyCommand {
+	var commands []VerifyCommand
+	for _, v := range c.Verify {
+		if matchesOperation([]string{v.Operation}, operation, subcommand) {
+			commands = append(commands, v)
+		}
+	}
+	return commands
+}
+
+// isSyslogFacility checks if name is one of SyslogFacilities
+func isSyslogFacility(name string) bool {
+	return contains(SyslogFacilities, name)
+}
+
+// contains reports whether list holds value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
 // IsProtectedNamespace checks if a namespace is protected
 func (c *Config) IsProtectedNamespace(namespace string) bool {
 	for _, ns := range c.ProtectedNamespaces {
@@ -147,6 +1175,96 @@ func (c *Config) IsProtectedCluster(cluster string) bool {
 	return false
 }
 
+// IsProtectedNode checks if a node name matches a protectedNodes entry, an
+// exact name or a glob such as "*-master-*"
+func (c *Config) IsProtectedNode(node string) bool {
+	for _, pattern := range c.ProtectedNodes {
+		if matched, _ := path.Match(pattern, node); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveProtectedCluster protects a context when a protectedClusters entry
+// names its kubeconfig cluster or matches its API server, so renaming a context
+// does not drop its protection. Server entries are URLs or host patterns such
+// as "*.prod.example.com".
+func (c *Config) ResolveProtectedCluster(context, clusterName, server string) {
+	if context == "" || c.IsProtectedCluster(context) {
+		return
+	}
+	for _, entry := range c.ProtectedClusters {
+		if matchesCluster(entry, clusterName, server) {
+			c.ProtectedClusters = append(c.ProtectedClusters, context)
+			return
+		}
+	}
+}
+
+// matchesCluster checks a protectedClusters entry against a cluster name and server URL
+func matchesCluster(entry, clusterName, server string) bool {
+	if clusterName != "" && entry == clusterName {
+		return true
+	}
+	if server == "" {
+		return false
+	}
+	if entry == server {
+		return true
+	}
+	host := server
+	if u, err := url.Parse(server); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	matched, _ := path.Match(entry, host)
+	return matched
+}
+
+// CriticalSelectors returns the critical pod selectors that apply in a namespace
+func (c *Config) CriticalSelectors(namespace string) []string {
+	var selectors []string
+	for _, p := range c.NetworkPolicy.CriticalPods {
+		if p.Namespace == "" || p.Namespace == namespace {
+			selectors = append(selectors, p.Selector)
+		}
+	}
+	return selectors
+}
+
+// RequiresBackup checks if deleting a kind needs a backup first, given as a
+// kind or any of its resource names
+func (c *Config) RequiresBackup(kind string) bool {
+	for _, k := range c.BackupRequired.Kinds {
+		if parser.SameResource(k, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsProtectedKind checks if a kind is protected, given as a kind or any of
+// its resource names
+func (c *Config) IsProtectedKind(kind string) bool {
+	for _, k := range c.ProtectedKinds {
+		if parser.SameResource(k, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProtectedKindReason returns the warning reason for a protected kind, with
+// its message from kindMessages if there is one
+func (c *Config) ProtectedKindReason(kind string) string {
+	for k, message := range c.KindMessages {
+		if parser.SameResource(k, kind) && message != "" {
+			return "protected kind: " + kind + ": " + message
+		}
+	}
+	return "protected kind: " + kind
+}
+
 // RequiresConfirmation returns true if confirm mode or protected resource
 func (c *Config) RequiresConfirmation(namespace, cluster string) bool {
 	if c.Mode == ModeConfirm {
@@ -3,7 +3,10 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -74,6 +77,68 @@ func TestIsDangerousOperation(t *testing.T) {
 	}
 }
 
+func TestIsSafeOperation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SafeOperations = []string{"top", "config get-clusters"}
+
+	tests := []struct {
+		operation  string
+		subcommand string
+		expected   bool
+	}{
+		{"version", "", true},
+		{"api-resources", "", true},
+		{"config", "view", true},
+		{"config", "current-context", true},
+		{"config", "use-context", false},
+		{"config", "get-clusters", true},
+		{"top", "", true},
+		{"get", "", false},
+		{"delete", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operation+" "+tt.subcommand, func(t *testing.T) {
+			result := cfg.IsSafeOperation(tt.operation, tt.subcommand)
+			if result != tt.expected {
+				t.Errorf("IsSafeOperation(%q, %q) = %v, expected %v", tt.operation, tt.subcommand, result, tt.expected)
+			}
+		})
+	}
+
+	if IsBuiltinSafeOperation("top", "") {
+		t.Error("IsBuiltinSafeOperation(top): configured entries must not be built in")
+	}
+}
+
+func TestResolveProtectedCluster(t *testing.T) {
+	tests := []struct {
+		name        string
+		clusterName string
+		server      string
+		expected    bool
+	}{
+		{"cluster name", "prod-cluster", "https://10.0.0.1:6443", true},
+		{"server host pattern", "eks-1", "https://api.prod.example.com:6443", true},
+		{"server URL", "eks-2", "https://10.1.2.3:6443", true},
+		{"other cluster", "staging", "https://api.staging.example.com", false},
+		{"unknown cluster", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.ProtectedClusters = []string{"prod-cluster", "*.prod.example.com", "https://10.1.2.3:6443"}
+
+			cfg.ResolveProtectedCluster("my-renamed-context", tt.clusterName, tt.server)
+			if got := cfg.IsProtectedCluster("my-renamed-context"); got != tt.expected {
+				t.Errorf("IsProtectedCluster after resolving (%q, %q): got %v, expected %v", tt.clusterName, tt.server, got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestIsProtectedNamespace(t *testing.T) {
 	cfg := &Config{
 		ProtectedNamespaces: []string{"kube-system", "production", "prod"},
@@ -127,6 +192,32 @@ func TestIsProtectedCluster(t *testing.T) {
 	}
 }
 
+func TestIsProtectedNode(t *testing.T) {
+	cfg := &Config{
+		ProtectedNodes: []string{"etcd-1", "*-master-*"},
+	}
+
+	tests := []struct {
+		node     string
+		expected bool
+	}{
+		{"etcd-1", true},
+		{"prod-master-0", true},
+		{"etcd-2", false},
+		{"worker-1", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.node, func(t *testing.T) {
+			result := cfg.IsProtectedNode(tt.node)
+			if result != tt.expected {
+				t.Errorf("IsProtectedNode(%q) = %v, expected %v", tt.node, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestRequiresConfirmation(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -346,3 +437,456 @@ func TestAuditFormatFromYAML(t *testing.T) {
 		t.Errorf("expected audit format %q, got %q", "json", cfg.Audit.Format)
 	}
 }
+
+func TestIsProtectedKind(t *testing.T) {
+	cfg := &Config{
+		ProtectedKinds: []string{"CustomResourceDefinition", "ClusterRole", "deploy", "certificates.cert-manager.io"},
+	}
+
+	tests := []struct {
+		kind     string
+		expected bool
+	}{
+		{"CustomResourceDefinition", true},
+		{"clusterrole", true},
+		{"crds", true},
+		{"Deployment", true},
+		{"deployments.apps", true},
+		{"Certificate", true},
+		{"Role", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			if got := cfg.IsProtectedKind(tt.kind); got != tt.expected {
+				t.Errorf("IsProtectedKind(%q) = %v, expected %v", tt.kind, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProtectedKindsDefault(t *testing.T) {
+	cfg := DefaultConfig()
+	for _, kind := range []string{"CustomResourceDefinition", "ClusterRole", "StorageClass", "PersistentVolume"} {
+		if !cfg.IsProtectedKind(kind) {
+			t.Errorf("expected %s to be protected by default", kind)
+		}
+	}
+}
+
+func TestDrainConfigFromYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	content := "drain:\n  pauseBetweenNodes: 30s\n  maxPendingPods: 5\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	os.Setenv("SAFEKUBECTL_CONFIG", configPath)
+	defer os.Unsetenv("SAFEKUBECTL_CONFIG")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Drain.PauseBetweenNodes != 30*time.Second {
+		t.Errorf("expected pause 30s, got %s", cfg.Drain.PauseBetweenNodes)
+	}
+	if cfg.Drain.MaxPendingPods != 5 {
+		t.Errorf("expected maxPendingPods 5, got %d", cfg.Drain.MaxPendingPods)
+	}
+	if cfg.Drain.HealthCheckTimeout != 10*time.Minute {
+		t.Errorf("expected default health check timeout 10m, got %s", cfg.Drain.HealthCheckTimeout)
+	}
+}
+
+func TestLoadLayeredConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	home := filepath.Join(tmpDir, "home")
+	repo := filepath.Join(tmpDir, "repo")
+	subdir := filepath.Join(repo, "deploy", "prod")
+	for _, dir := range []string{filepath.Join(home, ".safekubectl"), subdir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	systemPath := filepath.Join(tmpDir, "system.yaml")
+	userPath := filepath.Join(home, ".safekubectl", "config.yaml")
+	localPath := filepath.Join(repo, ".safekubectl.yaml")
+	files := map[string]string{
+		systemPath: "mode: confirm\nprotectedNamespaces:\n  - kube-system\nprotectedClusters:\n  - prod\n",
+		userPath:   "mode: warn-only\nprotectedNamespaces:\n  - my-team\n",
+		localPath:  "protectedNamespaces:\n  - payments\n  - kube-system\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	original := systemConfigPath
+	systemConfigPath = systemPath
+	defer func() { systemConfigPath = original }()
+	t.Setenv("SAFEKUBECTL_CONFIG", "")
+	t.Setenv("HOME", home)
+	t.Chdir(subdir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if cfg.Mode != ModeWarnOnly {
+		t.Errorf("mode: got %s, expected %s", cfg.Mode, ModeWarnOnly)
+	}
+	expectedNamespaces := []string{"kube-system", "my-team", "payments"}
+	if !reflect.DeepEqual(cfg.ProtectedNamespaces, expectedNamespaces) {
+		t.Errorf("protectedNamespaces: got %v, expected %v", cfg.ProtectedNamespaces, expectedNamespaces)
+	}
+	if !reflect.DeepEqual(cfg.ProtectedClusters, []string{"prod"}) {
+		t.Errorf("protectedClusters: got %v, expected [prod]", cfg.ProtectedClusters)
+	}
+	if !reflect.DeepEqual(cfg.DangerousOperations, DefaultConfig().DangerousOperations) {
+		t.Errorf("dangerousOperations: got %v, expected defaults", cfg.DangerousOperations)
+	}
+	expectedSources := []string{systemPath, userPath, localPath}
+	if !reflect.DeepEqual(cfg.Sources, expectedSources) {
+		t.Errorf("sources: got %v, expected %v", cfg.Sources, expectedSources)
+	}
+}
+
+func TestLoadListDefaultsReplacedByFirstLayerSettingThem(t *testing.T) {
+	tmpDir := t.TempDir()
+	home := filepath.Join(tmpDir, "home")
+	if err := os.MkdirAll(filepath.Join(home, ".safekubectl"), 0755); err != nil {
+		t.Fatalf("failed to create home: %v", err)
+	}
+
+	// The system layer sets no lists, so the user layer still replaces the defaults
+	systemPath := filepath.Join(tmpDir, "system.yaml")
+	userPath := filepath.Join(home, ".safekubectl", "config.yaml")
+	files := map[string]string{
+		systemPath: "mode: confirm\nprotectedClusters:\n  - prod\n",
+		userPath:   "dangerousOperations:\n  - annotate\nprotectedClusters:\n  - staging\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	original := systemConfigPath
+	systemConfigPath = systemPath
+	defer func() { systemConfigPath = original }()
+	t.Setenv("SAFEKUBECTL_CONFIG", "")
+	t.Setenv("HOME", home)
+	t.Chdir(tmpDir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.DangerousOperations, []string{"annotate"}) {
+		t.Errorf("dangerousOperations: got %v, expected [annotate]", cfg.DangerousOperations)
+	}
+	if !reflect.DeepEqual(cfg.ProtectedClusters, []string{"prod", "staging"}) {
+		t.Errorf("protectedClusters: got %v, expected [prod staging]", cfg.ProtectedClusters)
+	}
+}
+
+func TestLoadLocalConfigOnlyAddsProtection(t *testing.T) {
+	tmpDir := t.TempDir()
+	home := filepath.Join(tmpDir, "home")
+	repo := filepath.Join(tmpDir, "repo")
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatalf("failed to create %s: %v", repo, err)
+	}
+	original := systemConfigPath
+	systemConfigPath = filepath.Join(tmpDir, "missing.yaml")
+	defer func() { systemConfigPath = original }()
+	t.Setenv("SAFEKUBECTL_CONFIG", "")
+	t.Setenv("HOME", home)
+	t.Chdir(repo)
+
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{"warn-only mode", "mode: warn-only\nprotectedClusters:\n  - prod\n", "move mode to your user config"},
+		{"hooks and safe operations", "hooks:\n  preExec:\n    - [\"./pwn.sh\"]\nsafeOperations:\n  - delete\n", "move hooks, safeOperations to your user config"},
+		{"group policy", "groupPolicies:\n  - groups: [\"*\"]\n    mode: warn-only\n", "move groupPolicies"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(filepath.Join(repo, ".safekubectl.yaml"), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write local config: %v", err)
+			}
+			if _, err := Load(); err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Load() error = %v, expected it to contain %q", err, tt.errMsg)
+			}
+		})
+	}
+
+	// Lists are added to the defaults rather than replacing them
+	if err := os.WriteFile(filepath.Join(repo, ".safekubectl.yaml"), []byte("dangerousOperations:\n  - annotate\n"), 0644); err != nil {
+		t.Fatalf("failed to write local config: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !cfg.IsDangerousOperation("annotate") || !cfg.IsDangerousOperation("delete") {
+		t.Errorf("expected annotate added to the default dangerous operations, got %v", cfg.DangerousOperations)
+	}
+}
+
+func TestLoadEnvConfigDisablesLayering(t *testing.T) {
+	tmpDir := t.TempDir()
+	systemPath := filepath.Join(tmpDir, "system.yaml")
+	envPath := filepath.Join(tmpDir, "env.yaml")
+	if err := os.WriteFile(systemPath, []byte("protectedClusters:\n  - prod\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if err := os.WriteFile(envPath, []byte("mode: warn-only\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	original := systemConfigPath
+	systemConfigPath = systemPath
+	defer func() { systemConfigPath = original }()
+	t.Setenv("SAFEKUBECTL_CONFIG", envPath)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(cfg.ProtectedClusters) != 0 {
+		t.Errorf("protectedClusters: got %v, expected none", cfg.ProtectedClusters)
+	}
+	if !reflect.DeepEqual(cfg.Sources, []string{envPath}) {
+		t.Errorf("sources: got %v, expected [%s]", cfg.Sources, envPath)
+	}
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"unknown key", "mode: confirm\nprotectedNamespace:\n  - prod\n", "field protectedNamespace not found"},
+		{"invalid mode", "mode: warnonly\n", `invalid mode "warnonly"`},
+		{"unknown notify severity", "notify:\n  bell: [high, urgent]\n", `invalid notify.bell severity "urgent": expected one of LOW, MEDIUM, HIGH, CRITICAL`},
+		{"notify channel without a url", "notify:\n  channels:\n    - name: ops\n      type: slack\n", "notify.channels[0]: urlEnv is required"},
+		{"notify channel with bad quiet hours", "notify:\n  channels:\n    - type: webhook\n      urlEnv: HOOK\n      quietHours: [\"Someday\"]\n", `notify.channels[0]: invalid range "Someday"`},
+		{"unsupported language", "language: fr\n", `invalid language "fr": expected one of en, id, ja, es`},
+		{"empty dangerous operations", "dangerousOperations: []\n", "dangerousOperations is empty"},
+		{"invalid audit format", "audit:\n  format: xml\n", `invalid audit.format "xml"`},
+		{"invalid audit sink", "audit:\n  sink: splunk\n", `invalid audit.sink "splunk"`},
+		{"invalid audit facility", "audit:\n  sink: syslog\n  facility: local9\n", `invalid audit.facility "local9"`},
+		{"negative timeout", "watchRecreation:\n  timeout: -1m\n", "invalid watchRecreation.timeout"},
+		{"dangerous safe operation", "safeOperations:\n  - delete\n", `safeOperations entry "delete"`},
+		{"mutating verify command", "verify:\n  - operation: delete\n    command: rollout restart deployment/{name}\n", `verify[0]: command "rollout restart deployment/{name}" is not read-only`},
+		{"backup kinds without hook", "backupRequired:\n  kinds:\n    - PersistentVolumeClaim\n", "backupRequired.hook is empty"},
+		{"verify without operation", "verify:\n  - command: get pods\n", "verify[0]: operation is required"},
+		{"control plane load without a threshold", "controlPlaneLoad:\n  enabled: true\n  maxObjects: 0\n", "invalid controlPlaneLoad.maxObjects 0"},
+		{"all-namespaces read that is not a read", "allNamespacesReads:\n  - delete secrets\n", `invalid allNamespacesReads entry "delete secrets"`},
+		{"empty hook", "hooks:\n  preExec:\n    - []\n", "hooks.preExec[0]: command is empty"},
+		{"ruleset without a version", "rulesets:\n  - github.com/org/rules/istio\n", `ruleset "github.com/org/rules/istio" has no version`},
+		{"review without a page size", "review:\n  enabled: true\n  pageSize: 0\n", "invalid review.pageSize 0"},
+		{"opa without a policy", "opa:\n  enabled: true\n", "opa.enabled is set but opa.policy is empty"},
+		{"plugins without a wasm runtime", "plugins:\n  enabled: true\n  wasmRuntime: []\n", "plugins.enabled is set but plugins.wasmRuntime is empty"},
+		{"capture output without a limit", "audit:\n  captureOutput: true\n  captureOutputLimit: 0\n", "invalid audit.captureOutputLimit 0"},
+		{"invalid protected node pattern", "protectedNodes:\n  - \"[master\"\n", `invalid protectedNodes entry "[master"`},
+		{"change window with an unknown day", "changeWindows:\n  - allow: [\"Mon-Fry 09:00-17:00\"]\n", `changeWindows[0]: invalid range "Mon-Fry 09:00-17:00": unknown day "Mon-Fry"`},
+		{"change window with an invalid action", "changeWindows:\n  - block: [Sun]\n    action: escalate\n", `changeWindows[0]: invalid action "escalate"`},
+		{"change window without ranges", "changeWindows:\n  - clusters: [prod]\n", "changeWindows[0]: allow or block is required"},
+		{"freeze endpoint that is not a URL", "freeze:\n  url: freeze.example.com\n", `invalid freeze.url "freeze.example.com"`},
+		{"invalid freeze cluster pattern", "freeze:\n  file: /etc/safekubectl/freeze\n  clusters:\n    - \"[prod\"\n", `invalid freeze.clusters entry "[prod"`},
+		{"invalid kubectl plugin policy", "kubectlPlugins:\n  unknown: block\n", `invalid kubectlPlugins.unknown "block"`},
+		{"invalid warning template", "templates:\n  warning: \"{{ .Operation \"\n", `invalid templates.warning: template: warning:1: unclosed action`},
+		{"unknown template function", "templates:\n  resourceWarning: \"{{ lower .Operation }}\"\n", `invalid templates.resourceWarning: template: resourceWarning:1: function "lower" not defined`},
+		{"invalid ticket pattern", "ticket:\n  required: true\n  pattern: \"CHG[0-9\"\n", "ticket.pattern: invalid regex"},
+		{"ticket provider without a URL", "ticket:\n  provider: jira\n  tokenEnv: JIRA_TOKEN\n", "ticket.provider is set but ticket.url or ticket.tokenEnv is empty"},
+		{"unknown ticket provider", "ticket:\n  provider: remedy\n  url: https://remedy.example.com\n  tokenEnv: TOKEN\n", `invalid ticket.provider "remedy"`},
+		{"invalid redact pattern", "redact:\n  patterns:\n    - \"(\"\n", "redact.patterns[0]: invalid regex"},
+		{"group policy without groups", "groupPolicies:\n  - mode: warn-only\n", "groupPolicies[0]: at least one group is required"},
+		{"group policy with an invalid mode", "groupPolicies:\n  - groups: [sre]\n    mode: relaxed\n", "groupPolicies[0]: invalid mode \"relaxed\""},
+		{"on-call schedules with an invalid provider", "onCall:\n  provider: victorops\n  tokenEnv: TOKEN\n  email: a@example.com\n  schedules:\n    prod: P1\n", "invalid onCall.provider \"victorops\""},
+		{"on-call schedules without an email", "onCall:\n  provider: pagerduty\n  tokenEnv: TOKEN\n  schedules:\n    prod: P1\n", "onCall.tokenEnv or onCall.email is empty"},
+		{"oidc groups without a userinfo URL", "groups:\n  source: oidc\n", "groups.source is oidc but groups.userinfoURL or groups.tokenCommand is empty"},
+		{"warning experiment without variants", "warningExperiments:\n  - rule: delete\n", "warningExperiments[0]: at least one variant is required"},
+		{"warning variant with a duplicate name", "warningExperiments:\n  - rule: delete\n    variants:\n      - name: a\n      - name: a\n", `warningExperiments[0].variants[1]: duplicate name "a"`},
+		{"warning variant with an unknown severity", "warningExperiments:\n  - rule: delete namespace\n    variants:\n      - name: a\n        severity: loud\n", `invalid severity "loud"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+			t.Setenv("SAFEKUBECTL_CONFIG", configPath)
+
+			_, err := Load()
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("error: got %q, expected it to contain %q", err.Error(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestNotifyChannelWants(t *testing.T) {
+	ch := NotifyChannel{
+		MinSeverity:      "MEDIUM",
+		Clusters:         []string{"prod-*", "dev"},
+		Timezone:         "UTC",
+		QuietHours:       []string{"* 20:00-08:00"},
+		QuietMinSeverity: "critical",
+	}
+	day := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+	night := time.Date(2025, 3, 4, 23, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		severity string
+		cluster  string
+		at       time.Time
+		expected bool
+	}{
+		{"above the threshold by day", "HIGH", "dev", day, true},
+		{"below the threshold", "LOW", "dev", day, false},
+		{"another cluster", "CRITICAL", "staging", day, false},
+		{"routine at night", "HIGH", "dev", night, false},
+		{"critical at night", "CRITICAL", "prod-eu", night, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ch.Wants(tt.severity, tt.cluster, tt.at); got != tt.expected {
+				t.Errorf("Wants(%q, %q, %s) = %v, expected %v", tt.severity, tt.cluster, tt.at, got, tt.expected)
+			}
+		})
+	}
+
+	ch.QuietMinSeverity = ""
+	if ch.Wants("CRITICAL", "dev", night) {
+		t.Error("expected nothing to be sent in quiet hours without quietMinSeverity")
+	}
+}
+
+func TestLoadEmptyConfigFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, nil, 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("SAFEKUBECTL_CONFIG", configPath)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Mode != ModeConfirm {
+		t.Errorf("mode: got %s, expected %s", cfg.Mode, ModeConfirm)
+	}
+}
+
+func TestPreflightFromYAML(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected Preflights
+	}{
+		{"single string", "preflight: server-dry-run\n", Preflights{PreflightServerDryRun}},
+		{"list", "preflight:\n  - server-dry-run\n  - can-i\n", Preflights{PreflightServerDryRun, PreflightCanI}},
+		{"credentials", "preflight:\n  - credentials\n", Preflights{PreflightCredentials}},
+		{"empty string", "preflight: \"\"\n", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+			t.Setenv("SAFEKUBECTL_CONFIG", configPath)
+
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load() failed: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.Preflight, tt.expected) {
+				t.Errorf("preflight: got %v, expected %v", cfg.Preflight, tt.expected)
+			}
+		})
+	}
+}
+
+func TestVerifyCommandsFor(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Verify = []VerifyCommand{
+		{Operation: "delete", Resource: "pod", Command: "get pods -n {namespace} -l {selector}"},
+		{Operation: "rollout restart", Command: "rollout status {resource}/{name} -n {namespace}"},
+	}
+
+	tests := []struct {
+		operation  string
+		subcommand string
+		expected   int
+	}{
+		{"delete", "", 1},
+		{"rollout", "restart", 1},
+		{"rollout", "undo", 0},
+		{"apply", "", 0},
+	}
+
+	for _, tt := range tests {
+		if got := cfg.VerifyCommandsFor(tt.operation, tt.subcommand); len(got) != tt.expected {
+			t.Errorf("VerifyCommandsFor(%q, %q): got %d commands, expected %d", tt.operation, tt.subcommand, len(got), tt.expected)
+		}
+	}
+}
+
+func TestFreezeCovers(t *testing.T) {
+	tests := []struct {
+		clusters []string
+		cluster  string
+		expected bool
+	}{
+		{nil, "dev", true},
+		{[]string{"prod-*"}, "prod-us-east-1", true},
+		{[]string{"prod-*"}, "staging", false},
+		{[]string{"staging", "prod"}, "prod", true},
+	}
+
+	for _, tt := range tests {
+		if got := (FreezeConfig{Clusters: tt.clusters}).Covers(tt.cluster); got != tt.expected {
+			t.Errorf("Covers(%q) with clusters %v: got %v, expected %v", tt.cluster, tt.clusters, got, tt.expected)
+		}
+	}
+}
+
+func TestNotifyAlerts(t *testing.T) {
+	n := NotifyConfig{Bell: []string{"high", "CRITICAL"}, Desktop: []string{"CRITICAL"}}
+	tests := []struct {
+		severity string
+		bell     bool
+		desktop  bool
+	}{
+		{"LOW", false, false},
+		{"HIGH", true, false},
+		{"CRITICAL", true, true},
+		{"", false, false},
+	}
+
+	for _, tt := range tests {
+		bell, desktop := n.Alerts(tt.severity)
+		if bell != tt.bell || desktop != tt.desktop {
+			t.Errorf("Alerts(%q) = %v, %v, expected %v, %v", tt.severity, bell, desktop, tt.bell, tt.desktop)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envPrefix is prepended to every environment override name
+const envPrefix = "SAFEKUBECTL_"
+
+// envOverride maps one environment variable onto a config setting
+type envOverride struct {
+	name  string // without envPrefix, e.g. "MODE"
+	apply func(c *Config, value string) error
+}
+
+// envOverrides lists every config key that can be set from the environment.
+// List values, including a program and its arguments, are comma-separated and
+// replace the configured list. Maps and lists of objects have no override.
+var envOverrides = []envOverride{
+	{"MODE", func(c *Config, v string) error {
+		mode := Mode(v)
+		if mode != ModeConfirm && mode != ModeWarnOnly {
+			return fmt.Errorf("invalid mode %q: expected %q or %q", v, ModeConfirm, ModeWarnOnly)
+		}
+		c.Mode = mode
+		return nil
+	}},
+	{"LANGUAGE", envString(func(c *Config) *string { return &c.Language })},
+	{"DANGEROUS_OPERATIONS", envList(func(c *Config) *[]string { return &c.DangerousOperations })},
+	{"PROTECTED_NAMESPACES", envList(func(c *Config) *[]string { return &c.ProtectedNamespaces })},
+	{"PROTECTED_NODES", envList(func(c *Config) *[]string { return &c.ProtectedNodes })},
+	{"BLOCK_PROTECTED_NODES", envBool(func(c *Config) *bool { return &c.BlockProtectedNodes })},
+	{"PROTECTED_CLUSTERS", envList(func(c *Config) *[]string { return &c.ProtectedClusters })},
+	{"PROTECTED_KINDS", envList(func(c *Config) *[]string { return &c.ProtectedKinds })},
+	{"SAFE_OPERATIONS", envList(func(c *Config) *[]string { return &c.SafeOperations })},
+	{"ALL_NAMESPACES_READS", envList(func(c *Config) *[]string { return &c.AllNamespacesReads })},
+	{"RULESETS", envRulesets},
+	{"AUDIT_ENABLED", envBool(func(c *Config) *bool { return &c.Audit.Enabled })},
+	{"AUDIT_PATH", envString(func(c *Config) *string { return &c.Audit.Path })},
+	{"AUDIT_FORMAT", envString(func(c *Config) *string { return &c.Audit.Format })},
+	{"AUDIT_SINK", envString(func(c *Config) *string { return &c.Audit.Sink })},
+	{"AUDIT_FACILITY", envString(func(c *Config) *string { return &c.Audit.Facility })},
+	{"AUDIT_HASH_CHAIN", envBool(func(c *Config) *bool { return &c.Audit.HashChain })},
+	{"AUDIT_DENIED_IMPACT", envBool(func(c *Config) *bool { return &c.Audit.DeniedImpact })},
+	{"AUDIT_ARCHIVE_STDIN", envBool(func(c *Config) *bool { return &c.Audit.ArchiveStdin })},
+	{"AUDIT_CAPTURE_OUTPUT", envBool(func(c *Config) *bool { return &c.Audit.CaptureOutput })},
+	{"AUDIT_CAPTURE_OUTPUT_LIMIT", envInt(func(c *Config) *int { return &c.Audit.CaptureOutputLimit })},
+	{"FREEZE_FILE", envString(func(c *Config) *string { return &c.Freeze.File })},
+	{"FREEZE_URL", envString(func(c *Config) *string { return &c.Freeze.URL })},
+	{"FREEZE_CLUSTERS", envList(func(c *Config) *[]string { return &c.Freeze.Clusters })},
+	{"FREEZE_FAIL_OPEN", envBool(func(c *Config) *bool { return &c.Freeze.FailOpen })},
+	{"AUDIT_REQUIRE_REASON", envBool(func(c *Config) *bool { return &c.Audit.RequireReason })},
+	{"TICKET_REQUIRED", envBool(func(c *Config) *bool { return &c.Ticket.Required })},
+	{"TICKET_FAIL_OPEN", envBool(func(c *Config) *bool { return &c.Ticket.FailOpen })},
+	{"TICKET_PATTERN", envString(func(c *Config) *string { return &c.Ticket.Pattern })},
+	{"TICKET_PROVIDER", envString(func(c *Config) *string { return &c.Ticket.Provider })},
+	{"TICKET_URL", envString(func(c *Config) *string { return &c.Ticket.URL })},
+	{"TICKET_USER_ENV", envString(func(c *Config) *string { return &c.Ticket.UserEnv })},
+	{"TICKET_TOKEN_ENV", envString(func(c *Config) *string { return &c.Ticket.TokenEnv })},
+	{"TICKET_STATUSES", envList(func(c *Config) *[]string { return &c.Ticket.Statuses })},
+	{"ONCALL_EMAIL", envString(func(c *Config) *string { return &c.OnCall.Email })},
+	{"ONCALL_PROVIDER", envString(func(c *Config) *string { return &c.OnCall.Provider })},
+	{"ONCALL_TOKEN_ENV", envString(func(c *Config) *string { return &c.OnCall.TokenEnv })},
+	{"PREVIEW_NAMESPACE_DELETION", envBool(func(c *Config) *bool { return &c.PreviewNamespaceDeletion })},
+	{"PREVIEW_DRAIN", envBool(func(c *Config) *bool { return &c.PreviewDrain })},
+	{"PREVIEW_PRUNE", envBool(func(c *Config) *bool { return &c.PreviewPrune })},
+	{"DESCRIBE_NODES", envBool(func(c *Config) *bool { return &c.DescribeNodes })},
+	{"PREVIEW_APPLY_ORDER", envBool(func(c *Config) *bool { return &c.PreviewApplyOrder })},
+	{"SELECTIVE_APPLY", envBool(func(c *Config) *bool { return &c.SelectiveApply })},
+	{"COUNT_CONFIG_REFERENCES", envBool(func(c *Config) *bool { return &c.CountConfigReferences })},
+	{"CHECK_MISSING_REFERENCES", envBool(func(c *Config) *bool { return &c.CheckMissingReferences })},
+	{"CHECK_ACTIVE_JOBS", envBool(func(c *Config) *bool { return &c.CheckActiveJobs })},
+	{"CHECK_DELETE_TARGETS", envBool(func(c *Config) *bool { return &c.CheckDeleteTargets })},
+	{"CHECK_DISRUPTION", envBool(func(c *Config) *bool { return &c.CheckDisruption })},
+	{"CHECK_MANIFEST_RISKS", envBool(func(c *Config) *bool { return &c.CheckManifestRisks })},
+	{"NAMESPACE_POLICY", envBool(func(c *Config) *bool { return &c.NamespacePolicy })},
+	{"PREFLIGHT", envList(func(c *Config) *[]string { return (*[]string)(&c.Preflight) })},
+	{"DRAIN_PAUSE_BETWEEN_NODES", envDuration(func(c *Config) *time.Duration { return &c.Drain.PauseBetweenNodes })},
+	{"DRAIN_MAX_PENDING_PODS", envInt(func(c *Config) *int { return &c.Drain.MaxPendingPods })},
+	{"DRAIN_HEALTH_CHECK_TIMEOUT", envDuration(func(c *Config) *time.Duration { return &c.Drain.HealthCheckTimeout })},
+	{"POLICY_SOURCE", envString(func(c *Config) *string { return &c.PolicySource })},
+	{"POLICY_SHA256", envString(func(c *Config) *string { return &c.PolicySHA256 })},
+	{"POLICY_CACHE_TTL", envDuration(func(c *Config) *time.Duration { return &c.PolicyCacheTTL })},
+	{"WATCH_RECREATION_ENABLED", envBool(func(c *Config) *bool { return &c.WatchRecreation.Enabled })},
+	{"WATCH_RECREATION_TIMEOUT", envDuration(func(c *Config) *time.Duration { return &c.WatchRecreation.Timeout })},
+	{"ROLLOUT_GATE_ENABLED", envBool(func(c *Config) *bool { return &c.RolloutGate.Enabled })},
+	{"ROLLOUT_GATE_TIMEOUT", envDuration(func(c *Config) *time.Duration { return &c.RolloutGate.Timeout })},
+	{"ROUTES_CRITICAL_HOSTS", envList(func(c *Config) *[]string { return &c.Routes.CriticalHosts })},
+	{"ROUTES_CHECK_COLLISIONS", envBool(func(c *Config) *bool { return &c.Routes.CheckCollisions })},
+	{"GITOPS_SUSPEND_RECONCILIATION", envBool(func(c *Config) *bool { return &c.GitOps.SuspendReconciliation })},
+	{"GITOPS_WARN", envBool(func(c *Config) *bool { return &c.GitOps.Warn })},
+	{"GITOPS_ARGOCD_NAMESPACE", envString(func(c *Config) *string { return &c.GitOps.ArgoCDNamespace })},
+	{"CONTROL_PLANE_LOAD_ENABLED", envBool(func(c *Config) *bool { return &c.ControlPlaneLoad.Enabled })},
+	{"CONTROL_PLANE_LOAD_MAX_OBJECTS", envInt(func(c *Config) *int { return &c.ControlPlaneLoad.MaxObjects })},
+	{"CONTROL_PLANE_LOAD_CONFIRM", envBool(func(c *Config) *bool { return &c.ControlPlaneLoad.Confirm })},
+	{"OPA_ENABLED", envBool(func(c *Config) *bool { return &c.OPA.Enabled })},
+	{"OPA_POLICY", envString(func(c *Config) *string { return &c.OPA.Policy })},
+	{"OPA_QUERY", envString(func(c *Config) *string { return &c.OPA.Query })},
+	{"OPA_BINARY", envString(func(c *Config) *string { return &c.OPA.Binary })},
+	{"PLUGINS_ENABLED", envBool(func(c *Config) *bool { return &c.Plugins.Enabled })},
+	{"PLUGINS_DIR", envString(func(c *Config) *string { return &c.Plugins.Dir })},
+	{"PLUGINS_WASM_RUNTIME", envList(func(c *Config) *[]string { return &c.Plugins.WASMRuntime })},
+	{"LARGE_OUTPUT_ENABLED", envBool(func(c *Config) *bool { return &c.LargeOutput.Enabled })},
+	{"LARGE_OUTPUT_PAGER", envString(func(c *Config) *string { return &c.LargeOutput.Pager })},
+	{"SECRET_EXPOSURE_ENABLED", envBool(func(c *Config) *bool { return &c.SecretExposure.Enabled })},
+	{"SECRET_EXPOSURE_CONFIRM", envBool(func(c *Config) *bool { return &c.SecretExposure.Confirm })},
+	{"COVERAGE_ENABLED", envBool(func(c *Config) *bool { return &c.Coverage.Enabled })},
+	{"COVERAGE_PATH", envString(func(c *Config) *string { return &c.Coverage.Path })},
+	{"CONTEXT_SWITCH_CONFIRM", envBool(func(c *Config) *bool { return &c.ContextSwitch.Confirm })},
+	{"CONTEXT_SWITCH_BANNER", envBool(func(c *Config) *bool { return &c.ContextSwitch.Banner })},
+	{"NOTIFY_BELL", envList(func(c *Config) *[]string { return &c.Notify.Bell })},
+	{"NOTIFY_DESKTOP", envList(func(c *Config) *[]string { return &c.Notify.Desktop })},
+	{"KUBECTL_PLUGINS_UNKNOWN", envString(func(c *Config) *string { return &c.KubectlPlugins.Unknown })},
+	{"KUBECTL_PLUGINS_ALLOW", envList(func(c *Config) *[]string { return &c.KubectlPlugins.Allow })},
+	{"SNAPSHOT_ENABLED", envBool(func(c *Config) *bool { return &c.Snapshot.Enabled })},
+	{"SNAPSHOT_DIR", envString(func(c *Config) *string { return &c.Snapshot.Dir })},
+	{"REDACT_ENABLED", envBool(func(c *Config) *bool { return &c.Redact.Enabled })},
+	{"REDACT_PATTERNS", envList(func(c *Config) *[]string { return &c.Redact.Patterns })},
+	{"REDACT_PIPED", envBool(func(c *Config) *bool { return &c.Redact.Piped })},
+	{"REVIEW_ENABLED", envBool(func(c *Config) *bool { return &c.Review.Enabled })},
+	{"REVIEW_MIN_RESOURCES", envInt(func(c *Config) *int { return &c.Review.MinResources })},
+	{"REVIEW_PAGE_SIZE", envInt(func(c *Config) *int { return &c.Review.PageSize })},
+	{"BATCH_DIR", envString(func(c *Config) *string { return &c.Batch.Dir })},
+	{"BACKUP_REQUIRED_KINDS", envList(func(c *Config) *[]string { return &c.BackupRequired.Kinds })},
+	{"BACKUP_REQUIRED_HOOK", envList(func(c *Config) *[]string { return &c.BackupRequired.Hook })},
+	{"TEMPLATES_WARNING", envString(func(c *Config) *string { return &c.Templates.Warning })},
+	{"TEMPLATES_RESOURCE_WARNING", envString(func(c *Config) *string { return &c.Templates.ResourceWarning })},
+	{"GROUPS_SOURCE", envString(func(c *Config) *string { return &c.Groups.Source })},
+	{"GROUPS_USERINFO_URL", envString(func(c *Config) *string { return &c.Groups.UserinfoURL })},
+	{"GROUPS_TOKEN_COMMAND", envList(func(c *Config) *[]string { return &c.Groups.TokenCommand })},
+	{"GROUPS_CLAIM", envString(func(c *Config) *string { return &c.Groups.Claim })},
+}
+
+// applyEnv overrides config settings from SAFEKUBECTL_* environment variables.
+// Unset variables leave the setting alone; an empty list variable clears the list.
+func (c *Config) applyEnv() error {
+	for _, o := range envOverrides {
+		value, ok := os.LookupEnv(envPrefix + o.name)
+		if !ok {
+			continue
+		}
+		if err := o.apply(c, strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("%s%s: %w", envPrefix, o.name, err)
+		}
+	}
+	return nil
+}
+
+func envString(field func(c *Config) *string) func(c *Config, v string) error {
+	return func(c *Config, v string) error {
+		*field(c) = v
+		return nil
+	}
+}
+
+func envList(field func(c *Config) *[]string) func(c *Config, v string) error {
+	return func(c *Config, v string) error {
+		list := []string{}
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		*field(c) = list
+		return nil
+	}
+}
+
+// envRulesets reads comma-separated rulesets, each a name optionally followed
+// by =<sha256>, e.g. github.com/org/rules/istio@v1=<sha256>
+func envRulesets(c *Config, v string) error {
+	rulesets := []Ruleset{}
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			name, sum, _ := strings.Cut(item, "=")
+			rulesets = append(rulesets, Ruleset{Name: name, SHA256: sum})
+		}
+	}
+	c.Rulesets = rulesets
+	return nil
+}
+
+func envBool(field func(c *Config) *bool) func(c *Config, v string) error {
+	return func(c *Config, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", v)
+		}
+		*field(c) = b
+		return nil
+	}
+}
+
+func envInt(field func(c *Config) *int) func(c *Config, v string) error {
+	return func(c *Config, v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", v)
+		}
+		*field(c) = n
+		return nil
+	}
+}
+
+func envDuration(field func(c *Config) *time.Duration) func(c *Config, v string) error {
+	return func(c *Config, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", v)
+		}
+		*field(c) = d
+		return nil
+	}
+}
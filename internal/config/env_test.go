@@ -0,0 +1,167 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplyEnv(t *testing.T) {
+	t.Setenv("SAFEKUBECTL_MODE", "warn-only")
+	t.Setenv("SAFEKUBECTL_PROTECTED_NAMESPACES", "kube-system, payments,")
+	t.Setenv("SAFEKUBECTL_PROTECTED_CLUSTERS", "")
+	t.Setenv("SAFEKUBECTL_AUDIT_ENABLED", "true")
+	t.Setenv("SAFEKUBECTL_DRAIN_MAX_PENDING_PODS", "3")
+	t.Setenv("SAFEKUBECTL_ROLLOUT_GATE_TIMEOUT", "90s")
+	t.Setenv("SAFEKUBECTL_RULESETS", "github.com/org/rules/istio@v1=abc123, github.com/org/rules/db@"+strings.Repeat("a", 40))
+
+	cfg := DefaultConfig()
+	cfg.ProtectedClusters = []string{"prod"}
+	if err := cfg.applyEnv(); err != nil {
+		t.Fatalf("applyEnv() failed: %v", err)
+	}
+
+	if cfg.Mode != ModeWarnOnly {
+		t.Errorf("mode: got %s, expected %s", cfg.Mode, ModeWarnOnly)
+	}
+	if !reflect.DeepEqual(cfg.ProtectedNamespaces, []string{"kube-system", "payments"}) {
+		t.Errorf("protectedNamespaces: got %v, expected [kube-system payments]", cfg.ProtectedNamespaces)
+	}
+	if len(cfg.ProtectedClusters) != 0 {
+		t.Errorf("protectedClusters: got %v, expected empty", cfg.ProtectedClusters)
+	}
+	if !cfg.Audit.Enabled {
+		t.Error("expected audit to be enabled")
+	}
+	if cfg.Drain.MaxPendingPods != 3 {
+		t.Errorf("maxPendingPods: got %d, expected 3", cfg.Drain.MaxPendingPods)
+	}
+	if cfg.RolloutGate.Timeout != 90*time.Second {
+		t.Errorf("rolloutGate timeout: got %s, expected 1m30s", cfg.RolloutGate.Timeout)
+	}
+	if !reflect.DeepEqual(cfg.DangerousOperations, DefaultConfig().DangerousOperations) {
+		t.Errorf("dangerousOperations: got %v, expected defaults", cfg.DangerousOperations)
+	}
+	expectedRulesets := []Ruleset{{Name: "github.com/org/rules/istio@v1", SHA256: "abc123"}, {Name: "github.com/org/rules/db@" + strings.Repeat("a", 40)}}
+	if !reflect.DeepEqual(cfg.Rulesets, expectedRulesets) {
+		t.Errorf("rulesets: got %v, expected %v", cfg.Rulesets, expectedRulesets)
+	}
+}
+
+func TestApplyEnvInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"SAFEKUBECTL_MODE", "strict"},
+		{"SAFEKUBECTL_AUDIT_ENABLED", "maybe"},
+		{"SAFEKUBECTL_DRAIN_MAX_PENDING_PODS", "many"},
+		{"SAFEKUBECTL_WATCH_RECREATION_TIMEOUT", "soon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+			if err := DefaultConfig().applyEnv(); err == nil {
+				t.Errorf("expected error for %s=%s", tt.name, tt.value)
+			}
+		})
+	}
+}
+
+func TestLoadEnvOverridesFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("mode: confirm\naudit:\n  enabled: true\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("SAFEKUBECTL_CONFIG", configPath)
+	t.Setenv("SAFEKUBECTL_MODE", "warn-only")
+	t.Setenv("SAFEKUBECTL_AUDIT_ENABLED", "false")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Mode != ModeWarnOnly {
+		t.Errorf("mode: got %s, expected %s", cfg.Mode, ModeWarnOnly)
+	}
+	if cfg.Audit.Enabled {
+		t.Error("expected SAFEKUBECTL_AUDIT_ENABLED to disable audit")
+	}
+}
+
+// configLeaves calls visit with the yaml path and value of every setting
+// below v that is not itself a struct
+func configLeaves(v reflect.Value, prefix string, visit func(path string, v reflect.Value)) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		path := prefix + name
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Duration(0)) {
+			configLeaves(v.Field(i), path+".", visit)
+			continue
+		}
+		visit(path, v.Field(i))
+	}
+}
+
+func TestEnvOverridesCoverEveryField(t *testing.T) {
+	// Maps and lists of objects, which a comma-separated value cannot express
+	excluded := map[string]bool{
+		"kindMessages":               true,
+		"networkPolicy.criticalPods": true,
+		"verify":                     true,
+		"notify.channels":            true,
+		"hooks.preExec":              true,
+		"hooks.postExec":             true,
+		"warningExperiments":         true,
+		"groupPolicies":              true,
+		"changeWindows":              true,
+		"onCall.schedules":           true,
+	}
+
+	defaults := make(map[string]any)
+	configLeaves(reflect.ValueOf(*DefaultConfig()), "", func(path string, v reflect.Value) {
+		defaults[path] = v.Interface()
+	})
+
+	// Find the settings each override changes by applying it to the defaults
+	covered := make(map[string]string)
+	for _, o := range envOverrides {
+		for _, value := range []string{"warn-only", "true", "false", "7", "90m"} {
+			cfg := DefaultConfig()
+			if err := o.apply(cfg, value); err != nil {
+				continue
+			}
+			var paths []string
+			configLeaves(reflect.ValueOf(*cfg), "", func(path string, v reflect.Value) {
+				if !reflect.DeepEqual(v.Interface(), defaults[path]) {
+					paths = append(paths, path)
+				}
+			})
+			if len(paths) == 0 {
+				continue
+			}
+			for _, path := range paths {
+				covered[path] = o.name
+			}
+			break
+		}
+	}
+
+	for path := range defaults {
+		_, ok := covered[path]
+		if !ok && !excluded[path] {
+			t.Errorf("%s has no %s* override and is not excluded", path, envPrefix)
+		}
+		if ok && excluded[path] {
+			t.Errorf("%s is excluded but set by %s%s", path, envPrefix, covered[path])
+		}
+	}
+}
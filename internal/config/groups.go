@@ -0,0 +1,35 @@
+package config
+
+import "slices"
+
+// Sources of the operator's groups for groups.source
+const (
+	GroupSourceOS   = "os"   // the local OS groups of the user running safekubectl
+	GroupSourceOIDC = "oidc" // a claim in the OIDC userinfo response
+)
+
+// OnCallGroup is the group the engineer on call for a cluster belongs to
+// while matching groupPolicies, with onCall
+const OnCallGroup = "on-call"
+
+// On-call roster providers for onCall.provider
+const (
+	OnCallPagerDuty = "pagerduty"
+	OnCallOpsgenie  = "opsgenie"
+)
+
+// GroupPolicyFor returns the first group policy that applies to a member of
+// groups on cluster, and the group it matched, or nil if none applies
+func (c *Config) GroupPolicyFor(groups []string, cluster string) (*GroupPolicy, string) {
+	for i, policy := range c.GroupPolicies {
+		if len(policy.Clusters) > 0 && !slices.Contains(policy.Clusters, cluster) {
+			continue
+		}
+		for _, group := range policy.Groups {
+			if group == "*" || slices.Contains(groups, group) {
+				return &c.GroupPolicies[i], group
+			}
+		}
+	}
+	return nil, ""
+}
@@ -0,0 +1,38 @@
+package config
+
+import "testing"
+
+func TestGroupPolicyFor(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.GroupPolicies = []GroupPolicy{
+		{Groups: []string{"sre-oncall"}, Clusters: []string{"prod"}, Mode: ModeWarnOnly},
+		{Groups: []string{"contractors"}, Mode: ModeTyped},
+		{Groups: []string{"*"}, Clusters: []string{"prod"}, Mode: ModeTyped},
+	}
+
+	tests := []struct {
+		name          string
+		groups        []string
+		cluster       string
+		expectedMode  Mode
+		expectedGroup string
+	}{
+		{"first match wins", []string{"sre-oncall", "contractors"}, "prod", ModeWarnOnly, "sre-oncall"},
+		{"cluster not listed", []string{"sre-oncall"}, "dev", "", ""},
+		{"any cluster", []string{"contractors"}, "dev", ModeTyped, "contractors"},
+		{"everyone else", []string{"dev"}, "prod", ModeTyped, "*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, group := cfg.GroupPolicyFor(tt.groups, tt.cluster)
+			var mode Mode
+			if policy != nil {
+				mode = policy.Mode
+			}
+			if mode != tt.expectedMode || group != tt.expectedGroup {
+				t.Errorf("got %q via %q, expected %q via %q", mode, group, tt.expectedMode, tt.expectedGroup)
+			}
+		})
+	}
+}
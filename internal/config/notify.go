@@ -0,0 +1,66 @@
+package config
+
+import (
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Kinds of notification channel, for notify.channels[].type
+const (
+	ChannelSlack   = "slack"   // Slack incoming webhook
+	ChannelWebhook = "webhook" // JSON POST of the event
+)
+
+// NotifyChannel is a Slack or webhook channel told about dangerous commands
+// that ran. A channel only hears about commands at or above its severity
+// threshold, on its clusters, and during its quiet hours only about those at
+// or above quietMinSeverity.
+type NotifyChannel struct {
+	Name             string   `yaml:"name"`
+	Type             string   `yaml:"type"`             // slack or webhook
+	URLEnv           string   `yaml:"urlEnv"`           // environment variable holding the webhook URL
+	MinSeverity      string   `yaml:"minSeverity"`      // LOW, MEDIUM, HIGH or CRITICAL; empty sends every dangerous command
+	Clusters         []string `yaml:"clusters"`         // cluster names or globs; empty matches every cluster
+	Timezone         string   `yaml:"timezone"`         // IANA time zone of quietHours; empty uses local time
+	QuietHours       []string `yaml:"quietHours"`       // e.g. "Mon-Fri 20:00-08:00", "Sat-Sun" or "2025-12-24/2025-12-26"
+	QuietMinSeverity string   `yaml:"quietMinSeverity"` // least severity still sent in quiet hours; empty sends nothing then
+}
+
+// severityRank orders warning severities, -1 for an unknown one
+func severityRank(severity string) int {
+	return slices.IndexFunc(notifySeverities, func(s string) bool { return strings.EqualFold(s, severity) })
+}
+
+// Wants reports whether the channel is told about a command of the given
+// severity on cluster at time t
+func (n NotifyChannel) Wants(severity, cluster string, t time.Time) bool {
+	rank := severityRank(severity)
+	if rank < severityRank(n.MinSeverity) {
+		return false
+	}
+	if len(n.Clusters) > 0 && !slices.ContainsFunc(n.Clusters, func(pattern string) bool {
+		matched, _ := path.Match(pattern, cluster)
+		return matched
+	}) {
+		return false
+	}
+	if !n.Quiet(t) {
+		return true
+	}
+	return n.QuietMinSeverity != "" && rank >= severityRank(n.QuietMinSeverity)
+}
+
+// Quiet reports whether t falls in the channel's quiet hours
+func (n NotifyChannel) Quiet(t time.Time) bool {
+	if loc, err := time.LoadLocation(n.Timezone); err == nil && n.Timezone != "" {
+		t = t.In(loc)
+	}
+	for _, spec := range n.QuietHours {
+		if r, err := parseTimeRange(spec); err == nil && r.contains(t) {
+			return true
+		}
+	}
+	return false
+}
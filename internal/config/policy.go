@@ -0,0 +1,166 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// policyBundle is the subset of config keys a remote policy may set.
+// Bundle entries are added to the local lists, never removed from them.
+type policyBundle struct {
+	DangerousOperations []string `yaml:"dangerousOperations"`
+	ProtectedNamespaces []string `yaml:"protectedNamespaces"`
+	ProtectedClusters   []string `yaml:"protectedClusters"`
+	ProtectedKinds      []string `yaml:"protectedKinds"`
+	ProtectedNodes      []string `yaml:"protectedNodes"`
+}
+
+// applyPolicySource fetches the policy bundle, or only reads its cached copy
+// without fetch, and merges it into the config
+func (c *Config) applyPolicySource(cacheDir string, fetch bool) error {
+	content, err := loadPolicy(c.PolicySource, c.PolicySHA256, c.PolicyCacheTTL, cacheDir, fetch)
+	if err != nil {
+		return err
+	}
+
+	var bundle policyBundle
+	if err := yaml.Unmarshal(content, &bundle); err != nil {
+		return fmt.Errorf("failed to parse policy bundle %s: %w", c.PolicySource, err)
+	}
+
+	c.DangerousOperations = mergeUnique(c.DangerousOperations, bundle.DangerousOperations)
+	c.ProtectedNamespaces = mergeUnique(c.ProtectedNamespaces, bundle.ProtectedNamespaces)
+	c.ProtectedClusters = mergeUnique(c.ProtectedClusters, bundle.ProtectedClusters)
+	c.ProtectedKinds = mergeUnique(c.ProtectedKinds, bundle.ProtectedKinds)
+	c.ProtectedNodes = mergeUnique(c.ProtectedNodes, bundle.ProtectedNodes)
+	return nil
+}
+
+// loadPolicy returns the verified policy content, served from cache while it is
+// fresher than ttl. If the source cannot be reached, the last verified copy is used.
+// A pinned checksum is checked on every read, cached or not. Without one, the
+// checksum comes from the same origin, so the source must be https. Without
+// fetch, only the cache is read, however old it is.
+func loadPolicy(url, pinnedSHA256 string, ttl time.Duration, cacheDir string, fetch bool) ([]byte, error) {
+	if pinnedSHA256 == "" && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("policy bundle %s is not served over https: pin its checksum to fetch it over plain http", url)
+	}
+	cachePath := filepath.Join(cacheDir, fmt.Sprintf("%x.yaml", sha256.Sum256([]byte(url))))
+
+	// A cached copy that no longer matches the pin is refetched
+	readCache := func() ([]byte, bool) {
+		cached, err := os.ReadFile(cachePath)
+		if err != nil || (pinnedSHA256 != "" && verifySHA256(cached, pinnedSHA256) != nil) {
+			return nil, false
+		}
+		return cached, true
+	}
+
+	if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < ttl {
+		if cached, ok := readCache(); ok {
+			return cached, nil
+		}
+	}
+	if !fetch {
+		if cached, ok := readCache(); ok {
+			return cached, nil
+		}
+		return nil, fmt.Errorf("policy bundle %s is not cached", url)
+	}
+
+	content, expected, err := fetchPolicy(url, pinnedSHA256)
+	if err != nil {
+		if cached, ok := readCache(); ok {
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	// A checksum mismatch is never papered over with the cache
+	if err := verifySHA256(content, expected); err != nil {
+		return nil, fmt.Errorf("policy bundle %s %w", url, err)
+	}
+
+	if err := os.MkdirAll(cacheDir, 0755); err == nil {
+		_ = os.WriteFile(cachePath, content, 0644)
+	}
+	return content, nil
+}
+
+// verifySHA256 checks content against an expected hex sha256 checksum
+func verifySHA256(content []byte, expected string) error {
+	sum := sha256.Sum256(content)
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("failed sha256 verification: expected %s, got %s", expected, actual)
+	}
+	return nil
+}
+
+// policyClient fetches policy bundles and rulesets
+var policyClient = &http.Client{Timeout: 10 * time.Second}
+
+// fetchPolicy downloads the bundle and its expected checksum: the pinned value
+// if configured, otherwise the first field of <url>.sha256
+func fetchPolicy(url, pinnedSHA256 string) ([]byte, string, error) {
+	content, err := httpGet(url)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if pinnedSHA256 != "" {
+		return content, pinnedSHA256, nil
+	}
+
+	sumFile, err := httpGet(url + ".sha256")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch policy checksum (set policySHA256 to pin one): %w", err)
+	}
+	fields := strings.Fields(string(sumFile))
+	if len(fields) == 0 {
+		return nil, "", fmt.Errorf("empty policy checksum file %s.sha256", url)
+	}
+	return content, fields[0], nil
+}
+
+// httpGet fetches a URL and returns the body of a 200 response
+func httpGet(url string) ([]byte, error) {
+	resp, err := policyClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: status %d", url, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", url, err)
+	}
+	return body, nil
+}
+
+// mergeUnique appends the extra items not already present in base
+func mergeUnique[T comparable](base, extra []T) []T {
+	seen := make(map[T]bool, len(base))
+	for _, item := range base {
+		seen[item] = true
+	}
+	for _, item := range extra {
+		if !seen[item] {
+			seen[item] = true
+			base = append(base, item)
+		}
+	}
+	return base
+}
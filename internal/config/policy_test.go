@@ -0,0 +1,241 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPolicy = `protectedClusters:
+  - prod-us-east-1
+protectedNamespaces:
+  - payments
+`
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// newPolicyServer serves the policy and its .sha256 file, counting policy fetches
+func newPolicyServer(t *testing.T, policy, checksum string, hits *int) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/policy.yaml":
+			*hits++
+			w.Write([]byte(policy))
+		case "/policy.yaml.sha256":
+			w.Write([]byte(checksum + "  policy.yaml\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	trustServer(t, srv)
+	return srv
+}
+
+// trustServer fetches policies with the test server's client, which trusts its certificate
+func trustServer(t *testing.T, srv *httptest.Server) {
+	t.Helper()
+	original := policyClient
+	policyClient = srv.Client()
+	t.Cleanup(func() { policyClient = original })
+}
+
+// writeConfigWithPolicy writes a config pointing at the policy URL and selects it
+func writeConfigWithPolicy(t *testing.T, extra string) string {
+	t.Helper()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte(extra), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("SAFEKUBECTL_CONFIG", configPath)
+	return dir
+}
+
+func TestLoadPolicySourceMerges(t *testing.T) {
+	hits := 0
+	srv := newPolicyServer(t, testPolicy, sha256Hex(testPolicy), &hits)
+	writeConfigWithPolicy(t, "protectedNamespaces:\n  - kube-system\npolicySource: "+srv.URL+"/policy.yaml\n")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(cfg.ProtectedNamespaces, []string{"kube-system", "payments"}) {
+		t.Errorf("ProtectedNamespaces: got %v", cfg.ProtectedNamespaces)
+	}
+	if !cfg.IsProtectedCluster("prod-us-east-1") {
+		t.Errorf("expected prod-us-east-1 from policy bundle, got %v", cfg.ProtectedClusters)
+	}
+}
+
+func TestLoadPolicySourceCached(t *testing.T) {
+	hits := 0
+	srv := newPolicyServer(t, testPolicy, sha256Hex(testPolicy), &hits)
+	dir := writeConfigWithPolicy(t, "policySource: "+srv.URL+"/policy.yaml\n")
+
+	for i := 0; i < 2; i++ {
+		if _, err := Load(); err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("expected policy fetched once within TTL, got %d fetches", hits)
+	}
+
+	entries, _ := os.ReadDir(filepath.Join(dir, "policy-cache"))
+	if len(entries) != 1 {
+		t.Errorf("expected one cached policy file, got %d", len(entries))
+	}
+}
+
+func TestLoadPolicySourceChecksumMismatch(t *testing.T) {
+	hits := 0
+	srv := newPolicyServer(t, testPolicy, sha256Hex("something else"), &hits)
+	writeConfigWithPolicy(t, "policySource: "+srv.URL+"/policy.yaml\n")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "sha256") {
+		t.Errorf("expected sha256 verification error, got %v", err)
+	}
+}
+
+func TestLoadPolicySourcePinnedChecksum(t *testing.T) {
+	hits := 0
+	// The .sha256 file is wrong, but the pinned checksum takes precedence
+	srv := newPolicyServer(t, testPolicy, "bogus", &hits)
+	writeConfigWithPolicy(t, "policySource: "+srv.URL+"/policy.yaml\npolicySHA256: "+sha256Hex(testPolicy)+"\n")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !cfg.IsProtectedNamespace("payments") {
+		t.Errorf("expected payments from policy bundle, got %v", cfg.ProtectedNamespaces)
+	}
+}
+
+func TestLoadPolicySourceUnreachableUsesStaleCache(t *testing.T) {
+	hits := 0
+	srv := newPolicyServer(t, testPolicy, sha256Hex(testPolicy), &hits)
+	url := srv.URL + "/policy.yaml"
+	dir := writeConfigWithPolicy(t, "policySource: "+url+"\npolicyCacheTTL: 1ns\n")
+
+	if _, err := Load(); err != nil {
+		t.Fatalf("first Load() failed: %v", err)
+	}
+	srv.Close()
+	time.Sleep(time.Millisecond)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected stale cache fallback, got error: %v", err)
+	}
+	if !cfg.IsProtectedNamespace("payments") {
+		t.Errorf("expected cached policy to apply, got %v", cfg.ProtectedNamespaces)
+	}
+
+	// Without a cache, an unreachable source is an error
+	os.RemoveAll(filepath.Join(dir, "policy-cache"))
+	if _, err := Load(); err == nil {
+		t.Error("expected error for unreachable policy source without cache")
+	}
+}
+
+func TestLoadCachedNeverFetches(t *testing.T) {
+	hits := 0
+	srv := newPolicyServer(t, testPolicy, sha256Hex(testPolicy), &hits)
+	writeConfigWithPolicy(t, "policySource: "+srv.URL+"/policy.yaml\npolicyCacheTTL: 1ns\n")
+
+	// Nothing cached yet
+	if _, err := LoadCached(); err == nil || !strings.Contains(err.Error(), "is not cached") {
+		t.Errorf("expected an error without a cached policy, got %v", err)
+	}
+	if _, err := Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+
+	// The stale copy is used rather than fetched again
+	cfg, err := LoadCached()
+	if err != nil {
+		t.Fatalf("LoadCached() failed: %v", err)
+	}
+	if !cfg.IsProtectedNamespace("payments") {
+		t.Errorf("expected the cached policy to apply, got %v", cfg.ProtectedNamespaces)
+	}
+	if hits != 1 {
+		t.Errorf("expected only Load() to fetch the policy, got %d fetches", hits)
+	}
+}
+
+func TestLoadPolicySourceVerifiesPinnedCache(t *testing.T) {
+	hits := 0
+	srv := newPolicyServer(t, testPolicy, "", &hits)
+	dir := writeConfigWithPolicy(t, "policySource: "+srv.URL+"/policy.yaml\npolicySHA256: "+sha256Hex(testPolicy)+"\n")
+	if _, err := Load(); err != nil {
+		t.Fatalf("first Load() failed: %v", err)
+	}
+
+	// A tampered cache is refetched, even within the TTL
+	entries, _ := os.ReadDir(filepath.Join(dir, "policy-cache"))
+	if len(entries) != 1 {
+		t.Fatalf("expected one cached policy file, got %d", len(entries))
+	}
+	cachePath := filepath.Join(dir, "policy-cache", entries[0].Name())
+	os.WriteFile(cachePath, []byte("protectedClusters: []\n"), 0644)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if hits != 2 || !cfg.IsProtectedNamespace("payments") {
+		t.Errorf("expected the tampered cache to be refetched, got %d fetches and %v", hits, cfg.ProtectedNamespaces)
+	}
+
+	// ...and never used as the fallback for an unreachable source
+	os.WriteFile(cachePath, []byte("protectedClusters: []\n"), 0644)
+	srv.Close()
+	if _, err := Load(); err == nil {
+		t.Error("expected an error for an unreachable source with a tampered cache")
+	}
+}
+
+func TestLoadPolicySourceRequiresHTTPS(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testPolicy))
+	}))
+	defer srv.Close()
+
+	writeConfigWithPolicy(t, "policySource: "+srv.URL+"/policy.yaml\n")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "expected an https URL") {
+		t.Errorf("expected an unpinned http source to be rejected, got %v", err)
+	}
+
+	writeConfigWithPolicy(t, "policySource: "+srv.URL+"/policy.yaml\npolicySHA256: "+sha256Hex(testPolicy)+"\n")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected a pinned http source to load, got %v", err)
+	}
+	if !cfg.IsProtectedNamespace("payments") {
+		t.Errorf("expected payments from policy bundle, got %v", cfg.ProtectedNamespaces)
+	}
+}
+
+func TestMergeUnique(t *testing.T) {
+	got := mergeUnique([]string{"a", "b"}, []string{"b", "c", "c"})
+	if !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("mergeUnique() = %v", got)
+	}
+}
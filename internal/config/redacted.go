@@ -0,0 +1,61 @@
+package config
+
+import (
+	"net/url"
+)
+
+// redactedValue replaces settings that may hold credentials or personal data
+const redactedValue = "<redacted>"
+
+// Redacted returns a copy of the config safe to share when reporting a
+// problem: URLs lose their credentials and query, the on-call email is
+// masked, and configured commands keep only their program name, since their
+// arguments may carry tokens.
+func (c *Config) Redacted() *Config {
+	r := *c
+	r.PolicySource = redactURL(c.PolicySource)
+	r.Groups.UserinfoURL = redactURL(c.Groups.UserinfoURL)
+	r.Groups.TokenCommand = redactCommand(c.Groups.TokenCommand)
+	if c.OnCall.Email != "" {
+		r.OnCall.Email = redactedValue
+	}
+	r.BackupRequired.Hook = redactCommand(c.BackupRequired.Hook)
+	r.Hooks.PreExec = redactCommands(c.Hooks.PreExec)
+	r.Hooks.PostExec = redactCommands(c.Hooks.PostExec)
+	return &r
+}
+
+// redactURL masks the user info and query of a URL
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redactedValue
+	}
+	if u.User != nil {
+		u.User = url.User("xxxxx") // as url.URL.Redacted masks passwords
+	}
+	if u.RawQuery != "" {
+		u.RawQuery = redactedValue
+	}
+	return u.String()
+}
+
+// redactCommand keeps a command's program and masks its arguments
+func redactCommand(command []string) []string {
+	if len(command) <= 1 {
+		return command
+	}
+	redacted := []string{command[0]}
+	for range command[1:] {
+		redacted = append(redacted, redactedValue)
+	}
+	return redacted
+}
+
+func redactCommands(commands [][]string) [][]string {
+	var redacted [][]string
+	for _, command := range commands {
+		redacted = append(redacted, redactCommand(command))
+	}
+	return redacted
+}
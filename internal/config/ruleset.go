@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RulesetFile is the file a ruleset repository path must contain, next to
+// its <RulesetFile>.sha256 checksum
+const RulesetFile = "ruleset.yaml"
+
+// Ruleset is a shared ruleset to merge, by name, optionally pinned to the
+// checksum of its ruleset.yaml
+type Ruleset struct {
+	Name   string `yaml:"name"`   // <host>/<org>/<repo>[/<path>]@<ref>
+	SHA256 string `yaml:"sha256"` // pinned ruleset.yaml checksum; otherwise ruleset.yaml.sha256 is used
+}
+
+// UnmarshalYAML accepts a name alone, for compatibility with
+// `rulesets: [github.com/org/rules@<commit>]`, or a name and sha256
+func (r *Ruleset) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*r = Ruleset{Name: node.Value}
+		return nil
+	}
+	type plain Ruleset
+	return node.Decode((*plain)(r))
+}
+
+// rulesetBundle is the subset of config keys a shared ruleset may set. Like a
+// policy bundle, it only adds to the local config. Namespaces and clusters
+// are left out: they belong to an organization, not a community ruleset.
+type rulesetBundle struct {
+	DangerousOperations []string          `yaml:"dangerousOperations"`
+	ProtectedKinds      []string          `yaml:"protectedKinds"`
+	KindMessages        map[string]string `yaml:"kindMessages"`
+}
+
+// rulesetHosts maps the git hosts rulesets can be fetched from to the raw
+// file URL of org, repo, ref and path
+var rulesetHosts = map[string]string{
+	"github.com": "https://raw.githubusercontent.com/%[1]s/%[2]s/%[3]s/%[4]s",
+	"gitlab.com": "https://gitlab.com/%[1]s/%[2]s/-/raw/%[3]s/%[4]s",
+}
+
+// commitRef matches a full git commit SHA, the only ref that cannot be moved
+// to other content
+var commitRef = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// checkRuleset reports a ruleset whose name is invalid, or whose content can
+// change under the same name: a branch or tag without a sha256 pin
+func checkRuleset(r Ruleset) error {
+	if _, err := rulesetURL(r.Name); err != nil {
+		return err
+	}
+	_, ref, _ := strings.Cut(r.Name, "@")
+	if r.SHA256 == "" && !commitRef.MatchString(ref) {
+		return fmt.Errorf("ruleset %q: %s can be moved to other content, and its published checksum moves with it: pin it with sha256 or use a commit SHA", r.Name, ref)
+	}
+	return nil
+}
+
+// rulesetURL resolves a ruleset name, "<host>/<org>/<repo>[/<path>]@<ref>",
+// to the raw URL of its ruleset.yaml at that ref
+func rulesetURL(name string) (string, error) {
+	repoPath, ref, ok := strings.Cut(name, "@")
+	if !ok || ref == "" {
+		return "", fmt.Errorf("ruleset %q has no version: expected <host>/<org>/<repo>[/<path>]@<ref>", name)
+	}
+	parts := strings.Split(strings.Trim(repoPath, "/"), "/")
+	if len(parts) < 3 {
+		return "", fmt.Errorf("ruleset %q: expected <host>/<org>/<repo>[/<path>]@<ref>", name)
+	}
+	format, ok := rulesetHosts[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("ruleset %q: unsupported host %s: expected github.com or gitlab.com", name, parts[0])
+	}
+	return fmt.Sprintf(format, parts[1], parts[2], ref, strings.Join(append(parts[3:], RulesetFile), "/")), nil
+}
+
+// applyRulesets fetches each ruleset, or only reads its cached copy without
+// fetch, verified against its sha256 pin or the .sha256 file published next
+// to it, and merges it into the config
+func (c *Config) applyRulesets(cacheDir string, fetch bool) error {
+	for _, ruleset := range c.Rulesets {
+		url, err := rulesetURL(ruleset.Name)
+		if err != nil {
+			return err
+		}
+		content, err := loadPolicy(url, ruleset.SHA256, c.PolicyCacheTTL, cacheDir, fetch)
+		if err != nil {
+			return fmt.Errorf("ruleset %s: %w", ruleset.Name, err)
+		}
+
+		var bundle rulesetBundle
+		if err := yaml.Unmarshal(content, &bundle); err != nil {
+			return fmt.Errorf("failed to parse ruleset %s: %w", ruleset.Name, err)
+		}
+
+		c.DangerousOperations = mergeUnique(c.DangerousOperations, bundle.DangerousOperations)
+		c.ProtectedKinds = mergeUnique(c.ProtectedKinds, bundle.ProtectedKinds)
+		for kind, message := range bundle.KindMessages {
+			if _, ok := c.KindMessages[kind]; ok {
+				continue // the local config has the last word
+			}
+			if c.KindMessages == nil {
+				c.KindMessages = make(map[string]string)
+			}
+			c.KindMessages[kind] = message
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testRuleset = `protectedKinds:
+  - VirtualService
+  - PeerAuthentication
+kindMessages:
+  PeerAuthentication: changing mTLS mode can break traffic mesh-wide
+  VirtualService: routes live traffic
+`
+
+func TestRulesetURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		err      string
+	}{
+		{"github.com/org/safekubectl-rules/istio@v1", "https://raw.githubusercontent.com/org/safekubectl-rules/v1/istio/ruleset.yaml", ""},
+		{"github.com/org/istio-rules@main", "https://raw.githubusercontent.com/org/istio-rules/main/ruleset.yaml", ""},
+		{"gitlab.com/org/rules/db/postgres@v2.1.0", "https://gitlab.com/org/rules/-/raw/v2.1.0/db/postgres/ruleset.yaml", ""},
+		{"github.com/org/rules/istio", "", "has no version"},
+		{"github.com/org@v1", "", "expected <host>/<org>/<repo>"},
+		{"example.com/org/rules@v1", "", "unsupported host example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rulesetURL(tt.name)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("got %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckRuleset(t *testing.T) {
+	commit := "3f1e0c5b9d7a2e4c6b8a0f1d3e5c7b9a1d2f4e6c"
+	tests := []struct {
+		name    string
+		ruleset Ruleset
+		err     string
+	}{
+		{"pinned tag", Ruleset{Name: "github.com/org/rules/istio@v1", SHA256: sha256Hex(testRuleset)}, ""},
+		{"unpinned commit", Ruleset{Name: "github.com/org/rules/istio@" + commit}, ""},
+		{"unpinned tag", Ruleset{Name: "github.com/org/rules/istio@v1"}, "v1 can be moved to other content"},
+		{"unpinned branch", Ruleset{Name: "gitlab.com/org/rules@main"}, "pin it with sha256 or use a commit SHA"},
+		{"short commit", Ruleset{Name: "github.com/org/rules@3f1e0c5"}, "3f1e0c5 can be moved"},
+		{"invalid name", Ruleset{Name: "github.com/org/rules/istio", SHA256: "abc"}, "has no version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRuleset(tt.ruleset)
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+// serveRulesets serves ruleset files under the test.example host
+func serveRulesets(t *testing.T, files map[string]string) {
+	t.Helper()
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(content))
+	}))
+	t.Cleanup(srv.Close)
+	trustServer(t, srv)
+
+	rulesetHosts["test.example"] = srv.URL + "/%[1]s/%[2]s/%[3]s/%[4]s"
+	t.Cleanup(func() { delete(rulesetHosts, "test.example") })
+}
+
+func TestLoadRulesetsMerge(t *testing.T) {
+	// Pinned, so no ruleset.yaml.sha256 is needed
+	serveRulesets(t, map[string]string{
+		"/org/rules/v1/istio/ruleset.yaml": testRuleset,
+	})
+	writeConfigWithPolicy(t, "kindMessages:\n  VirtualService: owned by team-mesh\nrulesets:\n  - name: test.example/org/rules/istio@v1\n    sha256: "+sha256Hex(testRuleset)+"\n")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if !cfg.IsProtectedKind("PeerAuthentication") || !cfg.IsProtectedKind("VirtualService") || !cfg.IsProtectedKind("ClusterRole") {
+		t.Errorf("ProtectedKinds: got %v", cfg.ProtectedKinds)
+	}
+	expected := map[string]string{
+		"PeerAuthentication": "changing mTLS mode can break traffic mesh-wide",
+		"VirtualService":     "owned by team-mesh",
+	}
+	if !reflect.DeepEqual(cfg.KindMessages, expected) {
+		t.Errorf("KindMessages: got %v, expected %v", cfg.KindMessages, expected)
+	}
+}
+
+func TestLoadRulesetsChecksumMismatch(t *testing.T) {
+	commit := "3f1e0c5b9d7a2e4c6b8a0f1d3e5c7b9a1d2f4e6c"
+	serveRulesets(t, map[string]string{
+		"/org/rules/" + commit + "/ruleset.yaml":        testRuleset,
+		"/org/rules/" + commit + "/ruleset.yaml.sha256": sha256Hex("something else"),
+		"/org/rules/v1/ruleset.yaml":                    testRuleset,
+	})
+
+	for _, rulesets := range []string{
+		"  - test.example/org/rules@" + commit + "\n",
+		"  - name: test.example/org/rules@v1\n    sha256: " + sha256Hex("something else") + "\n",
+	} {
+		writeConfigWithPolicy(t, "rulesets:\n"+rulesets)
+		_, err := Load()
+		if err == nil || !strings.Contains(err.Error(), "failed sha256 verification") {
+			t.Errorf("%s: expected a checksum error, got %v", rulesets, err)
+		}
+	}
+}
+
+func TestProtectedKindReason(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.KindMessages = map[string]string{"VirtualService": "routes live traffic"}
+
+	if got := cfg.ProtectedKindReason("virtualservice"); got != "protected kind: virtualservice: routes live traffic" {
+		t.Errorf("got %q", got)
+	}
+	if got := cfg.ProtectedKindReason("Secret"); got != "protected kind: Secret" {
+		t.Errorf("got %q", got)
+	}
+}
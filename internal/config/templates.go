@@ -0,0 +1,27 @@
+package config
+
+import (
+	"strings"
+	"text/template"
+)
+
+// TemplatesConfig replaces the built-in warnings with Go templates
+// (text/template), e.g. to link to a runbook or an internal policy
+type TemplatesConfig struct {
+	Warning         string `yaml:"warning"`         // for CLI commands; sees the check result's fields and .Command
+	ResourceWarning string `yaml:"resourceWarning"` // for commands with -f; sees the resource check result's fields and .Command
+}
+
+// templateFuncs are the functions warning templates may call besides
+// text/template's own
+var templateFuncs = template.FuncMap{
+	"join":      strings.Join,
+	"hasPrefix": strings.HasPrefix,
+	"upper":     strings.ToUpper,
+}
+
+// ParseTemplate parses a warning template, with the functions warning
+// templates may call
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Parse(text)
+}
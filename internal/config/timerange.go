@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timeRange is a range of whole dates, or of times of day on some weekdays
+type timeRange struct {
+	from, to time.Time     // dates, inclusive, for a date range
+	days     [7]bool       // weekdays, for a weekday range
+	start    time.Duration // time of day the range starts
+	end      time.Duration // time of day it ends, before start when it runs past midnight
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseTimeRange parses "YYYY-MM-DD[/YYYY-MM-DD]" or "DAYS [HH:MM-HH:MM]",
+// where DAYS is "*", a day such as "Mon", a span such as "Mon-Fri" or a
+// comma-separated list of those
+func parseTimeRange(spec string) (timeRange, error) {
+	var r timeRange
+	fields := strings.Fields(spec)
+	if len(fields) == 0 || len(fields) > 2 {
+		return r, fmt.Errorf("invalid range %q: expected \"DAYS [HH:MM-HH:MM]\" or \"YYYY-MM-DD[/YYYY-MM-DD]\"", spec)
+	}
+
+	if len(fields) == 1 && len(fields[0]) >= 10 && fields[0][4] == '-' {
+		first, last, _ := strings.Cut(fields[0], "/")
+		if last == "" {
+			last = first
+		}
+		from, err := time.Parse(time.DateOnly, first)
+		if err != nil {
+			return r, fmt.Errorf("invalid range %q: %w", spec, err)
+		}
+		to, err := time.Parse(time.DateOnly, last)
+		if err != nil {
+			return r, fmt.Errorf("invalid range %q: %w", spec, err)
+		}
+		if to.Before(from) {
+			return r, fmt.Errorf("invalid range %q: ends before it starts", spec)
+		}
+		r.from, r.to = from, to
+		return r, nil
+	}
+
+	for _, part := range strings.Split(fields[0], ",") {
+		if part == "*" {
+			r.days = [7]bool{true, true, true, true, true, true, true}
+			continue
+		}
+		first, last, span := strings.Cut(strings.ToLower(part), "-")
+		from, ok := weekdays[first]
+		to, ok2 := weekdays[last]
+		if !span {
+			to, ok2 = from, ok
+		}
+		if !ok || !ok2 {
+			return r, fmt.Errorf("invalid range %q: unknown day %q", spec, part)
+		}
+		for d := from; ; d = (d + 1) % 7 {
+			r.days[d] = true
+			if d == to {
+				break
+			}
+		}
+	}
+
+	r.end = 24 * time.Hour
+	if len(fields) == 2 {
+		first, last, ok := strings.Cut(fields[1], "-")
+		start, err := parseClock(first)
+		end, err2 := parseClock(last)
+		if !ok || err != nil || err2 != nil || start == end {
+			return r, fmt.Errorf("invalid range %q: expected times as HH:MM-HH:MM", spec)
+		}
+		r.start, r.end = start, end
+	}
+	return r, nil
+}
+
+// parseClock parses a time of day as HH:MM
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// contains reports whether t, in the range's time zone, is inside the range.
+// A range past midnight includes the early hours of the day after each of its days.
+func (r timeRange) contains(t time.Time) bool {
+	if !r.from.IsZero() {
+		date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return !date.Before(r.from) && !date.After(r.to)
+	}
+	clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if r.start < r.end {
+		return r.days[t.Weekday()] && clock >= r.start && clock < r.end
+	}
+	yesterday := (t.Weekday() + 6) % 7
+	return (r.days[t.Weekday()] && clock >= r.start) || (r.days[yesterday] && clock < r.end)
+}
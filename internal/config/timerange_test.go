@@ -0,0 +1,50 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeRangeContains(t *testing.T) {
+	tests := []struct {
+		spec     string
+		at       string
+		expected bool
+	}{
+		{"Mon-Fri 09:00-17:00", "2025-06-03T10:30:00Z", true},
+		{"Mon-Fri 09:00-17:00", "2025-06-03T17:00:00Z", false},
+		{"Mon-Fri 09:00-17:00", "2025-06-07T10:30:00Z", false},
+		{"Sat 22:00-02:00", "2025-06-07T23:00:00Z", true},
+		{"Sat 22:00-02:00", "2025-06-08T01:30:00Z", true},
+		{"Sat 22:00-02:00", "2025-06-08T02:30:00Z", false},
+		{"Sat,Sun", "2025-06-08T12:00:00Z", true},
+		{"* 20:00-08:00", "2025-06-04T07:59:00Z", true},
+		{"2025-12-20/2026-01-05", "2026-01-05T23:59:00Z", true},
+		{"2025-12-20/2026-01-05", "2026-01-06T00:00:00Z", false},
+		{"2025-12-25", "2025-12-25T12:00:00Z", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec+" at "+tt.at, func(t *testing.T) {
+			r, err := parseTimeRange(tt.spec)
+			if err != nil {
+				t.Fatalf("parseTimeRange(%q) error = %v", tt.spec, err)
+			}
+			at, err := time.Parse(time.RFC3339, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.contains(at); got != tt.expected {
+				t.Errorf("contains(%s) = %v, expected %v", tt.at, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseTimeRangeErrors(t *testing.T) {
+	for _, spec := range []string{"", "Mon-Fry", "Mon 9-17", "Mon 09:00-09:00", "2026-01-05/2025-12-20", "2025-13-01", "Mon 09:00-17:00 UTC"} {
+		if _, err := parseTimeRange(spec); err == nil {
+			t.Errorf("parseTimeRange(%q): expected an error", spec)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+// What happens to dangerous commands outside a change window, for
+// changeWindows[].action
+const (
+	WindowConfirm = "confirm" // require confirmation, even in warn-only mode
+	WindowTyped   = "typed"   // require typing the cluster name
+	WindowBlock   = "block"   // refuse the command
+)
+
+// ChangeWindow restricts when dangerous commands may run on protected
+// clusters: only inside an allow range, if any are given, and never inside a
+// block range such as a holiday freeze
+type ChangeWindow struct {
+	Clusters []string `yaml:"clusters"` // protected clusters, or globs on their names; empty matches every protected cluster
+	Timezone string   `yaml:"timezone"` // IANA time zone of the ranges, e.g. Europe/Berlin; empty uses local time
+	Allow    []string `yaml:"allow"`    // e.g. "Mon-Fri 09:00-17:00"
+	Block    []string `yaml:"block"`    // e.g. "Fri 15:00-23:59" or "2025-12-20/2026-01-05"
+	Action   string   `yaml:"action"`   // confirm (default), typed or block
+}
+
+// ChangeWindowFor returns the first change window that applies to cluster,
+// or nil if none does
+func (c *Config) ChangeWindowFor(cluster string) *ChangeWindow {
+	for i, w := range c.ChangeWindows {
+		if len(w.Clusters) == 0 {
+			return &c.ChangeWindows[i]
+		}
+		for _, pattern := range w.Clusters {
+			if matched, _ := path.Match(pattern, cluster); matched {
+				return &c.ChangeWindows[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Outside reports whether t falls outside the window, and the range that
+// rules it out: the block range it is in, or the allow ranges it misses
+func (w *ChangeWindow) Outside(t time.Time) (bool, string) {
+	if loc, err := time.LoadLocation(w.Timezone); err == nil && w.Timezone != "" {
+		t = t.In(loc)
+	}
+	for _, spec := range w.Block {
+		if r, err := parseTimeRange(spec); err == nil && r.contains(t) {
+			return true, "changes blocked " + spec
+		}
+	}
+	if len(w.Allow) == 0 {
+		return false, ""
+	}
+	for _, spec := range w.Allow {
+		if r, err := parseTimeRange(spec); err == nil && r.contains(t) {
+			return false, ""
+		}
+	}
+	return true, "changes allowed " + strings.Join(w.Allow, ", ")
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChangeWindowOutside(t *testing.T) {
+	window := &ChangeWindow{
+		Timezone: "UTC",
+		Allow:    []string{"Mon-Fri 09:00-17:00", "Sat 22:00-02:00"},
+		Block:    []string{"2025-12-20/2026-01-05", "Fri 15:00-17:00"},
+	}
+
+	tests := []struct {
+		name            string
+		at              string
+		expectedOutside bool
+		expectedWhy     string
+	}{
+		{"weekday business hours", "2025-06-03T10:30:00Z", false, ""},
+		{"weekday evening", "2025-06-03T18:00:00Z", true, "changes allowed Mon-Fri 09:00-17:00, Sat 22:00-02:00"},
+		{"friday afternoon", "2025-06-06T15:30:00Z", true, "changes blocked Fri 15:00-17:00"},
+		{"saturday night", "2025-06-07T23:00:00Z", false, ""},
+		{"past midnight into sunday", "2025-06-08T01:30:00Z", false, ""},
+		{"sunday", "2025-06-08T10:00:00Z", true, "changes allowed Mon-Fri 09:00-17:00, Sat 22:00-02:00"},
+		{"holiday freeze", "2026-01-05T10:00:00Z", true, "changes blocked 2025-12-20/2026-01-05"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, err := time.Parse(time.RFC3339, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			outside, why := window.Outside(at)
+			if outside != tt.expectedOutside || why != tt.expectedWhy {
+				t.Errorf("Outside(%s) = %v, %q, expected %v, %q", tt.at, outside, why, tt.expectedOutside, tt.expectedWhy)
+			}
+		})
+	}
+
+	// Ranges are in the window's time zone
+	berlin := &ChangeWindow{Timezone: "Europe/Berlin", Allow: []string{"* 09:00-17:00"}}
+	if outside, _ := berlin.Outside(time.Date(2025, 6, 3, 7, 30, 0, 0, time.UTC)); outside {
+		t.Error("expected 09:30 in Berlin to be inside the window")
+	}
+}
+
+func TestChangeWindowFor(t *testing.T) {
+	cfg := &Config{
+		ChangeWindows: []ChangeWindow{
+			{Clusters: []string{"prod-eu-*"}, Allow: []string{"Mon-Fri"}},
+			{Block: []string{"Sat,Sun"}},
+		},
+	}
+	if w := cfg.ChangeWindowFor("prod-eu-west-1"); w != &cfg.ChangeWindows[0] {
+		t.Errorf("expected the first window for prod-eu-west-1, got %+v", w)
+	}
+	if w := cfg.ChangeWindowFor("prod-us-east-1"); w != &cfg.ChangeWindows[1] {
+		t.Errorf("expected the catch-all window for prod-us-east-1, got %+v", w)
+	}
+	if w := (&Config{}).ChangeWindowFor("prod"); w != nil {
+		t.Errorf("expected no window, got %+v", w)
+	}
+}
@@ -0,0 +1,104 @@
+// Package coverage keeps a local log of kubectl commands safekubectl did not
+// fully understand, and summarizes it for `safekubectl coverage`.
+package coverage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Entry is one command with parts the parser did not understand. The command
+// itself is not kept, since its arguments may hold secrets.
+type Entry struct {
+	Timestamp string   `json:"timestamp"`
+	Operation string   `json:"operation"`
+	Gaps      []string `json:"gaps"` // e.g. "flag --cascade", "operation node-shell"
+}
+
+// Record appends an entry for the gaps of a command to the log at path
+func Record(path, operation string, gaps []string, now time.Time) error {
+	data, err := json.Marshal(Entry{Timestamp: now.Format(time.RFC3339), Operation: operation, Gaps: gaps})
+	if err != nil {
+		return fmt.Errorf("failed to encode coverage entry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create coverage log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open coverage log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write coverage log: %w", err)
+	}
+	return nil
+}
+
+// Gap is how often one gap was seen
+type Gap struct {
+	Gap        string   `json:"gap"`
+	Count      int      `json:"count"`
+	Operations []string `json:"operations"` // operations it was seen with, sorted
+	LastSeen   string   `json:"lastSeen"`
+}
+
+// Summarize reads the log at path and counts each gap, most frequent first.
+// A missing log has no gaps; lines that are not entries are skipped.
+func Summarize(path string) ([]Gap, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open coverage log: %w", err)
+	}
+	defer f.Close()
+
+	byGap := make(map[string]*Gap)
+	operations := make(map[string]map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		for _, gap := range e.Gaps {
+			g := byGap[gap]
+			if g == nil {
+				g = &Gap{Gap: gap}
+				byGap[gap] = g
+				operations[gap] = make(map[string]bool)
+			}
+			g.Count++
+			if e.Timestamp > g.LastSeen {
+				g.LastSeen = e.Timestamp
+			}
+			if e.Operation != "" && !operations[gap][e.Operation] {
+				operations[gap][e.Operation] = true
+				g.Operations = append(g.Operations, e.Operation)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read coverage log: %w", err)
+	}
+
+	gaps := make([]Gap, 0, len(byGap))
+	for _, g := range byGap {
+		sort.Strings(g.Operations)
+		gaps = append(gaps, *g)
+	}
+	sort.Slice(gaps, func(i, j int) bool {
+		if gaps[i].Count != gaps[j].Count {
+			return gaps[i].Count > gaps[j].Count
+		}
+		return gaps[i].Gap < gaps[j].Gap
+	})
+	return gaps, nil
+}
@@ -0,0 +1,38 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "coverage", "coverage.log")
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	if gaps, err := Summarize(path); err != nil || gaps != nil {
+		t.Fatalf("expected no gaps before anything is recorded, got %v, %v", gaps, err)
+	}
+	Record(path, "delete", []string{"flag --cascade"}, now)
+	Record(path, "node-shell", []string{"operation node-shell"}, now.Add(time.Hour))
+	Record(path, "apply", []string{"flag --cascade", "flag --applyset"}, now.Add(2*time.Hour))
+
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("expected the log to be readable only by its owner, got %v, %v", info, err)
+	}
+
+	gaps, err := Summarize(path)
+	if err != nil {
+		t.Fatalf("Summarize returned error: %v", err)
+	}
+	expected := []Gap{
+		{Gap: "flag --cascade", Count: 2, Operations: []string{"apply", "delete"}, LastSeen: "2024-01-15T12:30:00Z"},
+		{Gap: "flag --applyset", Count: 1, Operations: []string{"apply"}, LastSeen: "2024-01-15T12:30:00Z"},
+		{Gap: "operation node-shell", Count: 1, Operations: []string{"node-shell"}, LastSeen: "2024-01-15T11:30:00Z"},
+	}
+	if !reflect.DeepEqual(gaps, expected) {
+		t.Errorf("got %+v\nexpected %+v", gaps, expected)
+	}
+}
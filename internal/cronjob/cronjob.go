@@ -0,0 +1,66 @@
+package cronjob
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SuspendFromPatch returns the .spec.suspend value a `kubectl patch` sets, and
+// whether the patch sets it at all. Merge and strategic patches (JSON or
+// YAML) and JSON patch replace/add operations are recognized.
+func SuspendFromPatch(patch []byte, patchType string) (bool, bool) {
+	if patchType == "json" {
+		var ops []struct {
+			Op    string          `json:"op"`
+			Path  string          `json:"path"`
+			Value json.RawMessage `json:"value"`
+		}
+		if err := json.Unmarshal(patch, &ops); err != nil {
+			return false, false
+		}
+		for _, op := range ops {
+			if op.Path != "/spec/suspend" || (op.Op != "replace" && op.Op != "add") {
+				continue
+			}
+			var suspend bool
+			if err := json.Unmarshal(op.Value, &suspend); err == nil {
+				return suspend, true
+			}
+		}
+		return false, false
+	}
+
+	var doc struct {
+		Spec struct {
+			Suspend *bool `yaml:"suspend"`
+		} `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal(patch, &doc); err != nil || doc.Spec.Suspend == nil {
+		return false, false
+	}
+	return *doc.Spec.Suspend, true
+}
+
+// ParseSuspend returns the .spec.suspend value of a CronJob (JSON or YAML).
+// An unset value means the CronJob is not suspended.
+func ParseSuspend(content []byte) (bool, error) {
+	var doc struct {
+		Spec struct {
+			Suspend bool `yaml:"suspend"`
+		} `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return false, fmt.Errorf("failed to parse cronjob: %w", err)
+	}
+	return doc.Spec.Suspend, nil
+}
+
+// SuspendReason describes the effect of suspending or resuming a CronJob
+func SuspendReason(display string, suspend bool) string {
+	if suspend {
+		return display + " is suspended: its scheduled runs are skipped until it is resumed"
+	}
+	return display + " is resumed: a run missed while suspended may start immediately and duplicate work done in the meantime"
+}
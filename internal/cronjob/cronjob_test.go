@@ -0,0 +1,46 @@
+package cronjob
+
+import (
+	"testing"
+)
+
+func TestSuspendFromPatch(t *testing.T) {
+	tests := []struct {
+		name            string
+		patch           string
+		patchType       string
+		expectedSuspend bool
+		expectedOK      bool
+	}{
+		{"merge suspend", `{"spec":{"suspend":true}}`, "merge", true, true},
+		{"strategic resume", `{"spec":{"suspend":false}}`, "strategic", false, true},
+		{"yaml patch", "spec:\n  suspend: true\n", "strategic", true, true},
+		{"json patch", `[{"op":"replace","path":"/spec/suspend","value":true}]`, "json", true, true},
+		{"json patch other path", `[{"op":"replace","path":"/spec/schedule","value":"0 * * * *"}]`, "json", false, false},
+		{"schedule only", `{"spec":{"schedule":"0 * * * *"}}`, "merge", false, false},
+		{"invalid", `{`, "merge", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suspend, ok := SuspendFromPatch([]byte(tt.patch), tt.patchType)
+			if suspend != tt.expectedSuspend || ok != tt.expectedOK {
+				t.Errorf("got (%v, %v), expected (%v, %v)", suspend, ok, tt.expectedSuspend, tt.expectedOK)
+			}
+		})
+	}
+}
+
+func TestParseSuspend(t *testing.T) {
+	suspended, err := ParseSuspend([]byte(`{"spec":{"suspend":true,"schedule":"0 * * * *"}}`))
+	if err != nil || !suspended {
+		t.Errorf("suspended cronjob: got (%v, %v)", suspended, err)
+	}
+	suspended, err = ParseSuspend([]byte(`{"spec":{"schedule":"0 * * * *"}}`))
+	if err != nil || suspended {
+		t.Errorf("unset suspend: got (%v, %v)", suspended, err)
+	}
+	if _, err := ParseSuspend([]byte("spec: [")); err == nil {
+		t.Error("expected error for invalid content")
+	}
+}
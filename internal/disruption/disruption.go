@@ -0,0 +1,111 @@
+// Package disruption works out what removing pods does to the workloads
+// they belong to: PodDisruptionBudgets pushed below their minimum, and
+// controllers left with no ready replicas. Pods and PDBs are the ones
+// parsed by the drain package, so both share one selector matcher.
+package disruption
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/drain"
+)
+
+// OnNodes returns the pods a drain of the nodes evicts: DaemonSet pods stay,
+// and finished pods disrupt nothing
+func OnNodes(pods []drain.Pod, nodes []string) []drain.Pod {
+	drained := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		drained[n] = true
+	}
+	var evicted []drain.Pod
+	for _, pod := range pods {
+		if drained[pod.NodeName] && !pod.Finished && !pod.DaemonSet() {
+			evicted = append(evicted, pod)
+		}
+	}
+	return evicted
+}
+
+// Named returns the pods of the namespace with the given names
+func Named(pods []drain.Pod, namespace string, names []string) []drain.Pod {
+	wanted := make(map[string]bool, len(names))
+	for _, n := range names {
+		wanted[n] = true
+	}
+	var named []drain.Pod
+	for _, pod := range pods {
+		if pod.Namespace == namespace && wanted[pod.Name] {
+			named = append(named, pod)
+		}
+	}
+	return named
+}
+
+// Reasons describes the PDBs that removing the pods takes below their desired
+// healthy count, and the controllers it leaves with no ready pods. pods is
+// every pod the PDBs and controllers could cover, removed ones included.
+// evict is true for a drain, whose evictions wait for the budget, and false
+// for a delete, which bypasses it.
+func Reasons(removed, pods []drain.Pod, pdbs []drain.PDB, evict bool) []string {
+	gone := make(map[string]bool, len(removed))
+	for _, pod := range removed {
+		if !pod.Finished {
+			gone[pod.Namespace+"/"+pod.Name] = true
+		}
+	}
+
+	var reasons []string
+	for _, pdb := range pdbs {
+		removedHealthy := 0
+		for _, pod := range pods {
+			if pod.Ready && gone[pod.Namespace+"/"+pod.Name] && pdb.Covers(pod) {
+				removedHealthy++
+			}
+		}
+		if removedHealthy == 0 || pdb.CurrentHealthy-removedHealthy >= pdb.DesiredHealthy {
+			continue
+		}
+		effect := "deleting pods bypasses the budget"
+		if evict {
+			effect = "the drain blocks until enough replacements are ready"
+		}
+		reasons = append(reasons, fmt.Sprintf("poddisruptionbudget/%s in %s needs %s: removing %d of %d leaves %d; %s",
+			pdb.Name, pdb.Namespace, countPods(pdb.DesiredHealthy, "healthy"), removedHealthy, pdb.CurrentHealthy, pdb.CurrentHealthy-removedHealthy, effect))
+	}
+
+	// Controllers whose every ready pod is removed
+	ready := make(map[string]int)
+	removedReady := make(map[string]int)
+	for _, pod := range pods {
+		if pod.Owner == "" || !pod.Ready || pod.DaemonSet() {
+			continue
+		}
+		key := pod.Namespace + "/" + pod.Owner
+		ready[key]++
+		if gone[pod.Namespace+"/"+pod.Name] {
+			removedReady[key]++
+		}
+	}
+	var emptied []string
+	for key, n := range removedReady {
+		if n == ready[key] {
+			emptied = append(emptied, key)
+		}
+	}
+	sort.Strings(emptied)
+	for _, key := range emptied {
+		namespace, owner, _ := strings.Cut(key, "/")
+		reasons = append(reasons, fmt.Sprintf("%s in %s has %s, all removed: it has no ready replicas until replacements start", owner, namespace, countPods(ready[key], "ready")))
+	}
+	return reasons
+}
+
+// countPods counts pods in a state, e.g. "1 ready pod" or "3 healthy pods"
+func countPods(n int, state string) string {
+	if n == 1 {
+		return "1 " + state + " pod"
+	}
+	return fmt.Sprintf("%d %s pods", n, state)
+}
@@ -0,0 +1,72 @@
+package disruption
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/drain"
+)
+
+func TestReasons(t *testing.T) {
+	web := map[string]string{"app": "web"}
+	pods := []drain.Pod{
+		{Namespace: "shop", Name: "web-1", NodeName: "node-1", Labels: web, Owner: "replicaset/web-7d9f", Ready: true},
+		{Namespace: "shop", Name: "web-2", NodeName: "node-1", Labels: web, Owner: "replicaset/web-7d9f", Ready: true},
+		{Namespace: "shop", Name: "web-3", NodeName: "node-2", Labels: web, Owner: "replicaset/web-7d9f", Ready: true},
+		{Namespace: "shop", Name: "db-0", NodeName: "node-1", Owner: "statefulset/db", Ready: true},
+		{Namespace: "kube-system", Name: "proxy-a", NodeName: "node-1", Owner: "daemonset/proxy", Ready: true},
+		{Namespace: "shop", Name: "migrate", NodeName: "node-1", Finished: true},
+	}
+	pdbs := []drain.PDB{{Namespace: "shop", Name: "web", MatchLabels: web, CurrentHealthy: 3, DesiredHealthy: 2}}
+
+	tests := []struct {
+		name     string
+		removed  []drain.Pod
+		evict    bool
+		expected []string
+	}{
+		{
+			name:    "drain takes the budget below minimum and empties a statefulset",
+			removed: OnNodes(pods, []string{"node-1"}),
+			evict:   true,
+			expected: []string{
+				"poddisruptionbudget/web in shop needs 2 healthy pods: removing 2 of 3 leaves 1; the drain blocks until enough replacements are ready",
+				"statefulset/db in shop has 1 ready pod, all removed: it has no ready replicas until replacements start",
+			},
+		},
+		{
+			name:     "deleting one pod stays within the budget",
+			removed:  Named(pods, "shop", []string{"web-1"}),
+			expected: nil,
+		},
+		{
+			name:    "deleting every replica",
+			removed: Named(pods, "shop", []string{"web-1", "web-2", "web-3"}),
+			expected: []string{
+				"poddisruptionbudget/web in shop needs 2 healthy pods: removing 3 of 3 leaves 0; deleting pods bypasses the budget",
+				"replicaset/web-7d9f in shop has 3 ready pods, all removed: it has no ready replicas until replacements start",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Reasons(tt.removed, pods, pdbs, tt.evict); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOnNodesSkipsDaemonSetsAndFinishedPods(t *testing.T) {
+	pods := []drain.Pod{
+		{Name: "web-1", NodeName: "node-1", Owner: "replicaset/web"},
+		{Name: "proxy-a", NodeName: "node-1", Owner: "daemonset/proxy"},
+		{Name: "migrate", NodeName: "node-1", Finished: true},
+		{Name: "web-2", NodeName: "node-2", Owner: "replicaset/web"},
+	}
+	got := OnNodes(pods, []string{"node-1"})
+	if len(got) != 1 || got[0].Name != "web-1" {
+		t.Errorf("got %+v, expected only web-1", got)
+	}
+}
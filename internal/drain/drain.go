@@ -0,0 +1,333 @@
+package drain
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// zoneLabel is the well-known node label holding the availability zone
+const zoneLabel = "topology.kubernetes.io/zone"
+
+// mirrorAnnotation marks the API copy of a static pod run by the kubelet
+const mirrorAnnotation = "kubernetes.io/config.mirror"
+
+// Node is a node selected for draining
+type Node struct {
+	Name string
+	Zone string // empty if the node has no zone label
+}
+
+// PDB is the subset of a PodDisruptionBudget needed for planning and impact
+// analysis
+type PDB struct {
+	Namespace          string
+	Name               string
+	MatchLabels        map[string]string
+	MatchExpressions   []Requirement
+	DisruptionsAllowed int
+	CurrentHealthy     int
+	DesiredHealthy     int // minAvailable or replicas minus maxUnavailable, as computed by the cluster
+}
+
+// Requirement is one matchExpressions entry of a label selector
+type Requirement struct {
+	Key      string
+	Operator string // In, NotIn, Exists or DoesNotExist
+	Values   []string
+}
+
+// Pod is the subset of a pod needed for planning, eviction previews and
+// impact analysis
+type Pod struct {
+	Namespace    string
+	Name         string
+	NodeName     string
+	Labels       map[string]string
+	Owner        string // controller as kind/name, e.g. replicaset/web-7d9f; empty for bare pods
+	Ready        bool
+	Finished     bool // Succeeded or Failed, so removing it disrupts nothing
+	LocalStorage bool // mounts an emptyDir volume
+	Mirror       bool // static pod, which drain leaves alone
+}
+
+// DaemonSet reports whether a DaemonSet controls the pod
+func (p Pod) DaemonSet() bool {
+	return strings.HasPrefix(p.Owner, "daemonset/")
+}
+
+// Step is one node drain in the plan. Steps with a blocking PDB come last.
+type Step struct {
+	Node        Node
+	Pods        int   // pods scheduled on the node
+	GuardedPods int   // pods covered by a PodDisruptionBudget
+	BlockingPDB []PDB // PDBs covering pods on this node that allow no disruptions
+}
+
+// Plan is an ordered list of node drains
+type Plan struct {
+	Selector string
+	Steps    []Step
+}
+
+// Covers reports whether the PDB selects the pod
+func (p PDB) Covers(pod Pod) bool {
+	if p.Namespace != pod.Namespace || (len(p.MatchLabels) == 0 && len(p.MatchExpressions) == 0) {
+		return false
+	}
+	for k, v := range p.MatchLabels {
+		if pod.Labels[k] != v {
+			return false
+		}
+	}
+	for _, req := range p.MatchExpressions {
+		if !req.Matches(pod.Labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches reports whether the labels satisfy the requirement. Unknown
+// operators match nothing.
+func (r Requirement) Matches(labels map[string]string) bool {
+	value, ok := labels[r.Key]
+	switch r.Operator {
+	case "In":
+		return ok && slices.Contains(r.Values, value)
+	case "NotIn":
+		return !ok || !slices.Contains(r.Values, value)
+	case "Exists":
+		return ok
+	case "DoesNotExist":
+		return !ok
+	}
+	return false
+}
+
+// BuildPlan orders nodes so that nodes with the fewest PDB-guarded pods drain
+// first within each zone, and consecutive drains rotate across zones to keep
+// workloads spread. Nodes with a PDB that allows no disruptions drain after
+// all others, since their evictions block.
+func BuildPlan(selector string, nodes []Node, pods []Pod, pdbs []PDB) *Plan {
+	steps := make(map[string]*Step, len(nodes))
+	for _, n := range nodes {
+		steps[n.Name] = &Step{Node: n}
+	}
+
+	for _, pod := range pods {
+		step, ok := steps[pod.NodeName]
+		if !ok {
+			continue
+		}
+		step.Pods++
+		guarded := false
+		for _, pdb := range pdbs {
+			if !pdb.Covers(pod) {
+				continue
+			}
+			guarded = true
+			if pdb.DisruptionsAllowed == 0 && !containsPDB(step.BlockingPDB, pdb) {
+				step.BlockingPDB = append(step.BlockingPDB, pdb)
+			}
+		}
+		if guarded {
+			step.GuardedPods++
+		}
+	}
+
+	// Nodes whose evictions would block go after the others
+	var free, blocked []*Step
+	for _, n := range nodes {
+		if len(steps[n.Name].BlockingPDB) > 0 {
+			blocked = append(blocked, steps[n.Name])
+		} else {
+			free = append(free, steps[n.Name])
+		}
+	}
+
+	plan := &Plan{Selector: selector}
+	plan.Steps = append(rotateZones(free), rotateZones(blocked)...)
+	return plan
+}
+
+// rotateZones orders steps least disruptive first within each zone, then
+// round-robins across zones
+func rotateZones(steps []*Step) []Step {
+	byZone := make(map[string][]*Step)
+	var zones []string
+	for _, step := range steps {
+		if _, ok := byZone[step.Node.Zone]; !ok {
+			zones = append(zones, step.Node.Zone)
+		}
+		byZone[step.Node.Zone] = append(byZone[step.Node.Zone], step)
+	}
+	sort.Strings(zones)
+	for _, zone := range zones {
+		group := byZone[zone]
+		sort.SliceStable(group, func(i, j int) bool {
+			if group[i].GuardedPods != group[j].GuardedPods {
+				return group[i].GuardedPods < group[j].GuardedPods
+			}
+			return group[i].Node.Name < group[j].Node.Name
+		})
+	}
+
+	ordered := make([]Step, 0, len(steps))
+	for len(ordered) < len(steps) {
+		for _, zone := range zones {
+			if len(byZone[zone]) == 0 {
+				continue
+			}
+			ordered = append(ordered, *byZone[zone][0])
+			byZone[zone] = byZone[zone][1:]
+		}
+	}
+	return ordered
+}
+
+// containsPDB returns true if the PDB is already in the list
+func containsPDB(pdbs []PDB, pdb PDB) bool {
+	for _, p := range pdbs {
+		if p.Namespace == pdb.Namespace && p.Name == pdb.Name {
+			return true
+		}
+	}
+	return false
+}
+
+type metadataJSON struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels"`
+}
+
+// ParseNodes parses `kubectl get nodes -o json` output
+func ParseNodes(content []byte) ([]Node, error) {
+	var list struct {
+		Items []struct {
+			Metadata metadataJSON `json:"metadata"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse nodes: %w", err)
+	}
+
+	nodes := make([]Node, 0, len(list.Items))
+	for _, item := range list.Items {
+		nodes = append(nodes, Node{
+			Name: item.Metadata.Name,
+			Zone: item.Metadata.Labels[zoneLabel],
+		})
+	}
+	return nodes, nil
+}
+
+// ParsePods parses `kubectl get pods -o json` output
+func ParsePods(content []byte) ([]Pod, error) {
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				metadataJSON
+				Annotations     map[string]string `json:"annotations"`
+				OwnerReferences []struct {
+					Kind       string `json:"kind"`
+					Name       string `json:"name"`
+					Controller bool   `json:"controller"`
+				} `json:"ownerReferences"`
+			} `json:"metadata"`
+			Spec struct {
+				NodeName string `json:"nodeName"`
+				Volumes  []struct {
+					EmptyDir any `json:"emptyDir"`
+				} `json:"volumes"`
+			} `json:"spec"`
+			Status struct {
+				Phase      string `json:"phase"`
+				Conditions []struct {
+					Type   string `json:"type"`
+					Status string `json:"status"`
+				} `json:"conditions"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse pods: %w", err)
+	}
+
+	pods := make([]Pod, 0, len(list.Items))
+	for _, item := range list.Items {
+		_, mirror := item.Metadata.Annotations[mirrorAnnotation]
+		pod := Pod{
+			Namespace: item.Metadata.Namespace,
+			Name:      item.Metadata.Name,
+			NodeName:  item.Spec.NodeName,
+			Labels:    item.Metadata.Labels,
+			Finished:  item.Status.Phase == "Succeeded" || item.Status.Phase == "Failed",
+			Mirror:    mirror,
+		}
+		for _, ref := range item.Metadata.OwnerReferences {
+			if ref.Controller {
+				pod.Owner = strings.ToLower(ref.Kind) + "/" + ref.Name
+			}
+		}
+		for _, c := range item.Status.Conditions {
+			if c.Type == "Ready" {
+				pod.Ready = c.Status == "True"
+			}
+		}
+		for _, v := range item.Spec.Volumes {
+			if v.EmptyDir != nil {
+				pod.LocalStorage = true
+			}
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+// ParsePDBs parses `kubectl get pdb -o json` output
+func ParsePDBs(content []byte) ([]PDB, error) {
+	var list struct {
+		Items []struct {
+			Metadata metadataJSON `json:"metadata"`
+			Spec     struct {
+				Selector struct {
+					MatchLabels      map[string]string `json:"matchLabels"`
+					MatchExpressions []struct {
+						Key      string   `json:"key"`
+						Operator string   `json:"operator"`
+						Values   []string `json:"values"`
+					} `json:"matchExpressions"`
+				} `json:"selector"`
+			} `json:"spec"`
+			Status struct {
+				DisruptionsAllowed int `json:"disruptionsAllowed"`
+				CurrentHealthy     int `json:"currentHealthy"`
+				DesiredHealthy     int `json:"desiredHealthy"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse pod disruption budgets: %w", err)
+	}
+
+	pdbs := make([]PDB, 0, len(list.Items))
+	for _, item := range list.Items {
+		pdb := PDB{
+			Namespace:          item.Metadata.Namespace,
+			Name:               item.Metadata.Name,
+			MatchLabels:        item.Spec.Selector.MatchLabels,
+			DisruptionsAllowed: item.Status.DisruptionsAllowed,
+			CurrentHealthy:     item.Status.CurrentHealthy,
+			DesiredHealthy:     item.Status.DesiredHealthy,
+		}
+		for _, expr := range item.Spec.Selector.MatchExpressions {
+			pdb.MatchExpressions = append(pdb.MatchExpressions, Requirement{Key: expr.Key, Operator: expr.Operator, Values: expr.Values})
+		}
+		pdbs = append(pdbs, pdb)
+	}
+	return pdbs, nil
+}
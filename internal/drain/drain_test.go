@@ -0,0 +1,209 @@
+package drain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func stepNames(plan *Plan) []string {
+	var names []string
+	for _, s := range plan.Steps {
+		names = append(names, s.Node.Name)
+	}
+	return names
+}
+
+func TestBuildPlanZoneSpread(t *testing.T) {
+	nodes := []Node{
+		{Name: "a1", Zone: "zone-a"},
+		{Name: "a2", Zone: "zone-a"},
+		{Name: "b1", Zone: "zone-b"},
+		{Name: "c1", Zone: "zone-c"},
+	}
+
+	plan := BuildPlan("pool=blue", nodes, nil, nil)
+
+	expected := []string{"a1", "b1", "c1", "a2"}
+	if !reflect.DeepEqual(stepNames(plan), expected) {
+		t.Errorf("order: got %v, expected %v", stepNames(plan), expected)
+	}
+	if plan.Selector != "pool=blue" {
+		t.Errorf("Selector: got %q, expected %q", plan.Selector, "pool=blue")
+	}
+}
+
+func TestBuildPlanGuardedPodsLast(t *testing.T) {
+	nodes := []Node{
+		{Name: "n1", Zone: "zone-a"},
+		{Name: "n2", Zone: "zone-a"},
+	}
+	pods := []Pod{
+		{Namespace: "web", Name: "web-1", NodeName: "n1", Labels: map[string]string{"app": "web"}},
+		{Namespace: "web", Name: "tmp", NodeName: "n2", Labels: map[string]string{"app": "tmp"}},
+	}
+	pdbs := []PDB{
+		{Namespace: "web", Name: "web-pdb", MatchLabels: map[string]string{"app": "web"}, DisruptionsAllowed: 0},
+	}
+
+	plan := BuildPlan("pool=blue", nodes, pods, pdbs)
+
+	expected := []string{"n2", "n1"}
+	if !reflect.DeepEqual(stepNames(plan), expected) {
+		t.Fatalf("order: got %v, expected %v", stepNames(plan), expected)
+	}
+
+	last := plan.Steps[1]
+	if last.Pods != 1 || last.GuardedPods != 1 {
+		t.Errorf("n1 counts: got pods=%d guarded=%d, expected 1/1", last.Pods, last.GuardedPods)
+	}
+	if len(last.BlockingPDB) != 1 || last.BlockingPDB[0].Name != "web-pdb" {
+		t.Errorf("expected web-pdb as blocking PDB, got %v", last.BlockingPDB)
+	}
+	if len(plan.Steps[0].BlockingPDB) != 0 {
+		t.Errorf("expected no blocking PDB on n2, got %v", plan.Steps[0].BlockingPDB)
+	}
+}
+
+func TestBuildPlanBlockedNodesLast(t *testing.T) {
+	nodes := []Node{
+		{Name: "a1", Zone: "zone-a"},
+		{Name: "a2", Zone: "zone-a"},
+		{Name: "b1", Zone: "zone-b"},
+		{Name: "b2", Zone: "zone-b"},
+	}
+	pods := []Pod{
+		{Namespace: "db", Name: "db-0", NodeName: "a1", Labels: map[string]string{"app": "db"}},
+		{Namespace: "web", Name: "web-1", NodeName: "a2", Labels: map[string]string{"app": "web"}},
+		{Namespace: "web", Name: "web-2", NodeName: "a2", Labels: map[string]string{"app": "web"}},
+		{Namespace: "web", Name: "web-3", NodeName: "b1", Labels: map[string]string{"app": "web"}},
+	}
+	pdbs := []PDB{
+		{Namespace: "db", Name: "db", MatchExpressions: []Requirement{{Key: "app", Operator: "In", Values: []string{"db"}}}, DisruptionsAllowed: 0},
+		{Namespace: "web", Name: "web", MatchLabels: map[string]string{"app": "web"}, DisruptionsAllowed: 1},
+	}
+
+	plan := BuildPlan("pool=blue", nodes, pods, pdbs)
+
+	// a1 has the fewest guarded pods in zone-a, but its PDB allows no disruptions
+	expected := []string{"a2", "b2", "b1", "a1"}
+	if !reflect.DeepEqual(stepNames(plan), expected) {
+		t.Errorf("order: got %v, expected %v", stepNames(plan), expected)
+	}
+	if last := plan.Steps[3]; len(last.BlockingPDB) != 1 || last.BlockingPDB[0].Name != "db" {
+		t.Errorf("expected db as blocking PDB on a1, got %v", last.BlockingPDB)
+	}
+}
+
+func TestPDBCovers(t *testing.T) {
+	byLabels := PDB{Namespace: "web", MatchLabels: map[string]string{"app": "web"}}
+	byExpressions := PDB{Namespace: "web", MatchExpressions: []Requirement{
+		{Key: "app", Operator: "In", Values: []string{"web", "api"}},
+		{Key: "tier", Operator: "NotIn", Values: []string{"canary"}},
+		{Key: "team", Operator: "Exists"},
+		{Key: "debug", Operator: "DoesNotExist"},
+	}}
+	both := PDB{Namespace: "web", MatchLabels: map[string]string{"team": "shop"}, MatchExpressions: []Requirement{{Key: "app", Operator: "In", Values: []string{"web"}}}}
+
+	tests := []struct {
+		name     string
+		pdb      PDB
+		pod      Pod
+		expected bool
+	}{
+		{"matching labels", byLabels, Pod{Namespace: "web", Labels: map[string]string{"app": "web", "tier": "x"}}, true},
+		{"other namespace", byLabels, Pod{Namespace: "api", Labels: map[string]string{"app": "web"}}, false},
+		{"missing label", byLabels, Pod{Namespace: "web", Labels: map[string]string{"tier": "x"}}, false},
+		{"matching expressions", byExpressions, Pod{Namespace: "web", Labels: map[string]string{"app": "api", "team": "shop"}}, true},
+		{"value not in set", byExpressions, Pod{Namespace: "web", Labels: map[string]string{"app": "db", "team": "shop"}}, false},
+		{"excluded value", byExpressions, Pod{Namespace: "web", Labels: map[string]string{"app": "web", "tier": "canary", "team": "shop"}}, false},
+		{"required key missing", byExpressions, Pod{Namespace: "web", Labels: map[string]string{"app": "web"}}, false},
+		{"forbidden key present", byExpressions, Pod{Namespace: "web", Labels: map[string]string{"app": "web", "team": "shop", "debug": "true"}}, false},
+		{"labels and expressions both match", both, Pod{Namespace: "web", Labels: map[string]string{"app": "web", "team": "shop"}}, true},
+		{"expression fails with labels matching", both, Pod{Namespace: "web", Labels: map[string]string{"app": "api", "team": "shop"}}, false},
+		{"unknown operator", PDB{Namespace: "web", MatchExpressions: []Requirement{{Key: "app", Operator: "Gt", Values: []string{"1"}}}}, Pod{Namespace: "web", Labels: map[string]string{"app": "2"}}, false},
+		{"empty selector", PDB{Namespace: "web"}, Pod{Namespace: "web", Labels: map[string]string{"app": "web"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pdb.Covers(tt.pod); got != tt.expected {
+				t.Errorf("Covers() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseNodes(t *testing.T) {
+	content := `{"items":[
+		{"metadata":{"name":"n1","labels":{"topology.kubernetes.io/zone":"zone-a"}}},
+		{"metadata":{"name":"n2"}}
+	]}`
+
+	nodes, err := ParseNodes([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseNodes() error = %v", err)
+	}
+
+	expected := []Node{{Name: "n1", Zone: "zone-a"}, {Name: "n2"}}
+	if !reflect.DeepEqual(nodes, expected) {
+		t.Errorf("got %v, expected %v", nodes, expected)
+	}
+}
+
+func TestParsePods(t *testing.T) {
+	pods, err := ParsePods([]byte(`{"items":[
+		{"metadata":{"name":"web-1","namespace":"shop","labels":{"app":"web"},"ownerReferences":[{"kind":"ReplicaSet","name":"web-7d9f","controller":true}]},
+		 "spec":{"nodeName":"node-1"},"status":{"phase":"Running","conditions":[{"type":"Ready","status":"True"}]}},
+		{"metadata":{"name":"cache-0","namespace":"shop","ownerReferences":[{"kind":"StatefulSet","name":"cache","controller":true}]},
+		 "spec":{"nodeName":"node-1","volumes":[{"name":"data","emptyDir":{}},{"name":"config","configMap":{"name":"cache"}}]},"status":{"phase":"Running"}},
+		{"metadata":{"name":"etcd-node-1","namespace":"kube-system","annotations":{"kubernetes.io/config.mirror":"abc"}},"spec":{"nodeName":"node-1"},"status":{"phase":"Running"}},
+		{"metadata":{"name":"migrate","namespace":"shop"},"spec":{"nodeName":"node-2"},"status":{"phase":"Succeeded"}}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParsePods() error = %v", err)
+	}
+	expected := []Pod{
+		{Namespace: "shop", Name: "web-1", NodeName: "node-1", Labels: map[string]string{"app": "web"}, Owner: "replicaset/web-7d9f", Ready: true},
+		{Namespace: "shop", Name: "cache-0", NodeName: "node-1", Owner: "statefulset/cache", LocalStorage: true},
+		{Namespace: "kube-system", Name: "etcd-node-1", NodeName: "node-1", Mirror: true},
+		{Namespace: "shop", Name: "migrate", NodeName: "node-2", Finished: true},
+	}
+	if !reflect.DeepEqual(pods, expected) {
+		t.Errorf("got %+v, expected %+v", pods, expected)
+	}
+	if _, err := ParsePods([]byte(`{`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestParsePDBs(t *testing.T) {
+	pdbs, err := ParsePDBs([]byte(`{"items":[
+		{"metadata":{"name":"web","namespace":"shop"},"spec":{"minAvailable":2,"selector":{"matchLabels":{"app":"web"}}},"status":{"currentHealthy":3,"desiredHealthy":2,"disruptionsAllowed":1}},
+		{"metadata":{"name":"db","namespace":"shop"},"spec":{"maxUnavailable":0,"selector":{"matchExpressions":[{"key":"app","operator":"In","values":["db","db-replica"]},{"key":"canary","operator":"DoesNotExist"}]}},"status":{"currentHealthy":2,"desiredHealthy":2}}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParsePDBs() error = %v", err)
+	}
+	expected := []PDB{
+		{Namespace: "shop", Name: "web", MatchLabels: map[string]string{"app": "web"}, DisruptionsAllowed: 1, CurrentHealthy: 3, DesiredHealthy: 2},
+		{Namespace: "shop", Name: "db", MatchExpressions: []Requirement{
+			{Key: "app", Operator: "In", Values: []string{"db", "db-replica"}},
+			{Key: "canary", Operator: "DoesNotExist"},
+		}, CurrentHealthy: 2, DesiredHealthy: 2},
+	}
+	if !reflect.DeepEqual(pdbs, expected) {
+		t.Errorf("got %+v, expected %+v", pdbs, expected)
+	}
+}
+
+func TestParseInvalidJSON(t *testing.T) {
+	if _, err := ParseNodes([]byte("not json")); err == nil {
+		t.Error("expected error for invalid nodes JSON")
+	}
+	if _, err := ParsePods([]byte("not json")); err == nil {
+		t.Error("expected error for invalid pods JSON")
+	}
+	if _, err := ParsePDBs([]byte("not json")); err == nil {
+		t.Error("expected error for invalid PDB JSON")
+	}
+}
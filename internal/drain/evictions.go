@@ -0,0 +1,102 @@
+package drain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxListedPods is how many pods a reason names before summarizing the rest
+const maxListedPods = 5
+
+// Evictions is what draining a node does to its pods
+type Evictions struct {
+	Node         string
+	ByNamespace  map[string]int // pods evicted per namespace
+	Unmanaged    []string       // running pods with no controller, as namespace/name
+	LocalStorage []string       // pods whose emptyDir data is lost
+	DaemonSet    []string       // DaemonSet pods, skipped only with --ignore-daemonsets
+}
+
+// Total returns the number of pods evicted
+func (e Evictions) Total() int {
+	total := 0
+	for _, n := range e.ByNamespace {
+		total += n
+	}
+	return total
+}
+
+// Summarize works out which pods on a node a drain evicts
+func Summarize(node string, pods []Pod) Evictions {
+	e := Evictions{Node: node, ByNamespace: make(map[string]int)}
+	for _, pod := range pods {
+		name := pod.Namespace + "/" + pod.Name
+		switch {
+		case pod.Mirror:
+			continue
+		case pod.DaemonSet():
+			e.DaemonSet = append(e.DaemonSet, name)
+			continue
+		case pod.Owner == "" && !pod.Finished:
+			e.Unmanaged = append(e.Unmanaged, name)
+		}
+		if pod.LocalStorage && !pod.Finished {
+			e.LocalStorage = append(e.LocalStorage, name)
+		}
+		e.ByNamespace[pod.Namespace]++
+	}
+	return e
+}
+
+// Reasons explains the pods kubectl drain stops at, as it would with the
+// given flags, and what overriding it does to them
+func (e Evictions) Reasons(force, deleteEmptyDir, ignoreDaemonSets bool) []string {
+	var reasons []string
+	switch {
+	case len(e.Unmanaged) > 0 && force:
+		reasons = append(reasons, fmt.Sprintf("node %s: --force deletes %s with no controller for good: %s", e.Node, countPods(len(e.Unmanaged)), listPods(e.Unmanaged)))
+	case len(e.Unmanaged) > 0:
+		reasons = append(reasons, fmt.Sprintf("node %s: %s with no controller: kubectl drain refuses such pods without --force, which deletes them for good: %s", e.Node, countPods(len(e.Unmanaged)), listPods(e.Unmanaged)))
+	}
+	switch {
+	case len(e.LocalStorage) > 0 && deleteEmptyDir:
+		reasons = append(reasons, fmt.Sprintf("node %s: --delete-emptydir-data discards the emptyDir data of %s: %s", e.Node, countPods(len(e.LocalStorage)), listPods(e.LocalStorage)))
+	case len(e.LocalStorage) > 0:
+		reasons = append(reasons, fmt.Sprintf("node %s: %s with local storage: kubectl drain refuses such pods without --delete-emptydir-data, which discards their emptyDir data: %s", e.Node, countPods(len(e.LocalStorage)), listPods(e.LocalStorage)))
+	}
+	if len(e.DaemonSet) > 0 && !ignoreDaemonSets {
+		reasons = append(reasons, fmt.Sprintf("node %s: %s managed by a DaemonSet: kubectl drain refuses such pods without --ignore-daemonsets: %s", e.Node, countPods(len(e.DaemonSet)), listPods(e.DaemonSet)))
+	}
+	return reasons
+}
+
+// Namespaces returns the namespaces pods are evicted from, most pods first
+func (e Evictions) Namespaces() []string {
+	namespaces := make([]string, 0, len(e.ByNamespace))
+	for ns := range e.ByNamespace {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		if e.ByNamespace[namespaces[i]] != e.ByNamespace[namespaces[j]] {
+			return e.ByNamespace[namespaces[i]] > e.ByNamespace[namespaces[j]]
+		}
+		return namespaces[i] < namespaces[j]
+	})
+	return namespaces
+}
+
+func countPods(n int) string {
+	if n == 1 {
+		return "1 pod"
+	}
+	return fmt.Sprintf("%d pods", n)
+}
+
+// listPods names the first pods and counts the rest
+func listPods(names []string) string {
+	if len(names) <= maxListedPods {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(names[:maxListedPods], ", "), len(names)-maxListedPods)
+}
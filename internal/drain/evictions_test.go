@@ -0,0 +1,49 @@
+package drain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSummarizeAndReasons(t *testing.T) {
+	e := Summarize("node-1", []Pod{
+		{Namespace: "shop", Name: "web-1", Owner: "replicaset/web-7d9f"},
+		{Namespace: "shop", Name: "cache-0", Owner: "statefulset/cache", LocalStorage: true},
+		{Namespace: "shop", Name: "debug", LocalStorage: true},
+		{Namespace: "shop", Name: "migrate", Finished: true},
+		{Namespace: "monitoring", Name: "prometheus-0", Owner: "statefulset/prometheus"},
+		{Namespace: "kube-system", Name: "kube-proxy-a", Owner: "daemonset/kube-proxy"},
+		{Namespace: "kube-system", Name: "etcd-node-1", Mirror: true},
+	})
+
+	if !reflect.DeepEqual(e.ByNamespace, map[string]int{"shop": 4, "monitoring": 1}) || e.Total() != 5 {
+		t.Errorf("evicted: got %v (total %d)", e.ByNamespace, e.Total())
+	}
+	if !reflect.DeepEqual(e.Namespaces(), []string{"shop", "monitoring"}) {
+		t.Errorf("namespaces: got %v", e.Namespaces())
+	}
+
+	expected := []string{
+		"node node-1: 1 pod with no controller: kubectl drain refuses such pods without --force, which deletes them for good: shop/debug",
+		"node node-1: 2 pods with local storage: kubectl drain refuses such pods without --delete-emptydir-data, which discards their emptyDir data: shop/cache-0, shop/debug",
+		"node node-1: 1 pod managed by a DaemonSet: kubectl drain refuses such pods without --ignore-daemonsets: kube-system/kube-proxy-a",
+	}
+	if got := e.Reasons(false, false, false); !reflect.DeepEqual(got, expected) {
+		t.Errorf("without flags: got %q, expected %q", got, expected)
+	}
+
+	expected = []string{
+		"node node-1: --force deletes 1 pod with no controller for good: shop/debug",
+		"node node-1: --delete-emptydir-data discards the emptyDir data of 2 pods: shop/cache-0, shop/debug",
+	}
+	if got := e.Reasons(true, true, true); !reflect.DeepEqual(got, expected) {
+		t.Errorf("with flags: got %q, expected %q", got, expected)
+	}
+}
+
+func TestListPodsTruncates(t *testing.T) {
+	got := listPods([]string{"a/1", "a/2", "a/3", "a/4", "a/5", "a/6", "a/7"})
+	if got != "a/1, a/2, a/3, a/4, a/5 and 2 more" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,130 @@
+package drain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// instanceTypeLabel is the well-known node label holding the cloud instance type
+const instanceTypeLabel = "node.kubernetes.io/instance-type"
+
+// poolLabels are the node labels naming a node pool, by provider, in the
+// order they are looked up
+var poolLabels = []string{
+	"cloud.google.com/gke-nodepool",
+	"eks.amazonaws.com/nodegroup",
+	"karpenter.sh/nodepool",
+	"kubernetes.azure.com/agentpool",
+	"agentpool",
+	"node-pool",
+	"nodepool",
+}
+
+// NodeInfo is the cloud metadata of a node shown before it is drained or cordoned
+type NodeInfo struct {
+	Name          string
+	Zone          string
+	InstanceType  string
+	Pool          string
+	Unschedulable bool // already cordoned
+}
+
+// Describe summarizes where the node runs, e.g.
+// "pool blue, zone us-east-1a, instance type m5.large"
+func (n NodeInfo) Describe() string {
+	var parts []string
+	if n.Pool != "" {
+		parts = append(parts, "pool "+n.Pool)
+	}
+	if n.Zone != "" {
+		parts = append(parts, "zone "+n.Zone)
+	}
+	if n.InstanceType != "" {
+		parts = append(parts, "instance type "+n.InstanceType)
+	}
+	if n.Unschedulable {
+		parts = append(parts, "already cordoned")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseNodeInfos parses `kubectl get nodes -o json` output
+func ParseNodeInfos(content []byte) ([]NodeInfo, error) {
+	var list struct {
+		Items []struct {
+			Metadata metadataJSON `json:"metadata"`
+			Spec     struct {
+				Unschedulable bool `json:"unschedulable"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse nodes: %w", err)
+	}
+
+	nodes := make([]NodeInfo, 0, len(list.Items))
+	for _, item := range list.Items {
+		labels := item.Metadata.Labels
+		node := NodeInfo{
+			Name:          item.Metadata.Name,
+			Zone:          labels[zoneLabel],
+			InstanceType:  labels[instanceTypeLabel],
+			Unschedulable: item.Spec.Unschedulable,
+		}
+		for _, label := range poolLabels {
+			if pool := labels[label]; pool != "" {
+				node.Pool = pool
+				break
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// LastSchedulable returns, for the pools and zones in which every
+// schedulable node is among targets, a description of what taking them out
+// leaves, e.g. "pool blue has no schedulable nodes left: ..."
+func LastSchedulable(nodes []NodeInfo, targets []string) []string {
+	targeted := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		targeted[t] = true
+	}
+
+	var reasons []string
+	for _, group := range []struct {
+		label  string
+		key    func(NodeInfo) string
+		effect string
+	}{
+		{"pool", func(n NodeInfo) string { return n.Pool }, "pods that select it have nowhere to go"},
+		{"zone", func(n NodeInfo) string { return n.Zone }, "pods with volumes in it have nowhere to go"},
+	} {
+		remaining := make(map[string]int)
+		hit := make(map[string]bool)
+		for _, n := range nodes {
+			key := group.key(n)
+			if key == "" || n.Unschedulable {
+				continue
+			}
+			if targeted[n.Name] {
+				hit[key] = true
+			} else {
+				remaining[key]++
+			}
+		}
+		var emptied []string
+		for key := range hit {
+			if remaining[key] == 0 {
+				emptied = append(emptied, key)
+			}
+		}
+		sort.Strings(emptied)
+		for _, key := range emptied {
+			reasons = append(reasons, fmt.Sprintf("%s %s has no schedulable nodes left: %s", group.label, key, group.effect))
+		}
+	}
+	return reasons
+}
@@ -0,0 +1,60 @@
+package drain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseNodeInfos(t *testing.T) {
+	nodes, err := ParseNodeInfos([]byte(`{"items":[
+		{"metadata":{"name":"n1","labels":{"topology.kubernetes.io/zone":"us-east-1a","node.kubernetes.io/instance-type":"m5.large","eks.amazonaws.com/nodegroup":"blue"}}},
+		{"metadata":{"name":"n2","labels":{"cloud.google.com/gke-nodepool":"default-pool"}},"spec":{"unschedulable":true}},
+		{"metadata":{"name":"n3"}}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseNodeInfos() error = %v", err)
+	}
+	expected := []NodeInfo{
+		{Name: "n1", Zone: "us-east-1a", InstanceType: "m5.large", Pool: "blue"},
+		{Name: "n2", Pool: "default-pool", Unschedulable: true},
+		{Name: "n3"},
+	}
+	if !reflect.DeepEqual(nodes, expected) {
+		t.Errorf("got %+v, expected %+v", nodes, expected)
+	}
+	if got := nodes[0].Describe(); got != "pool blue, zone us-east-1a, instance type m5.large" {
+		t.Errorf("Describe: got %q", got)
+	}
+	if got := nodes[1].Describe(); got != "pool default-pool, already cordoned" {
+		t.Errorf("Describe: got %q", got)
+	}
+	if _, err := ParseNodeInfos([]byte("not json")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestLastSchedulable(t *testing.T) {
+	nodes := []NodeInfo{
+		{Name: "blue-1", Pool: "blue", Zone: "a"},
+		{Name: "blue-2", Pool: "blue", Zone: "b", Unschedulable: true},
+		{Name: "green-1", Pool: "green", Zone: "a"},
+		{Name: "green-2", Pool: "green", Zone: "b"},
+	}
+
+	tests := []struct {
+		name     string
+		targets  []string
+		expected []string
+	}{
+		{"last in pool", []string{"blue-1"}, []string{"pool blue has no schedulable nodes left: pods that select it have nowhere to go"}},
+		{"others remain", []string{"green-1"}, nil},
+		{"last in zone", []string{"green-2"}, []string{"zone b has no schedulable nodes left: pods with volumes in it have nowhere to go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LastSchedulable(nodes, tt.targets); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
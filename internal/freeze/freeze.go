@@ -0,0 +1,55 @@
+// Package freeze tells whether an incident freeze locks down changes, from a
+// flag file or a freeze endpoint.
+package freeze
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds the endpoint lookup, which runs before dangerous commands
+const requestTimeout = 5 * time.Second
+
+// Status reports whether a freeze is active, and its reason. A freeze is
+// active while file exists, its content being the reason, or while url
+// answers {"frozen": true, "reason": "..."}. Either may be empty.
+func Status(file, url string) (bool, string, error) {
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err == nil {
+			return true, strings.TrimSpace(string(data)), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return false, "", fmt.Errorf("failed to read freeze file: %w", err)
+		}
+	}
+	if url == "" {
+		return false, "", nil
+	}
+	return endpointStatus(url)
+}
+
+func endpointStatus(url string) (bool, string, error) {
+	resp, err := (&http.Client{Timeout: requestTimeout}).Get(url)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to fetch freeze status: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, "", fmt.Errorf("failed to fetch freeze status: %s", resp.Status)
+	}
+	var body struct {
+		Frozen bool   `json:"frozen"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return false, "", fmt.Errorf("invalid freeze status: %w", err)
+	}
+	return body.Frozen, strings.TrimSpace(body.Reason), nil
+}
@@ -0,0 +1,68 @@
+package freeze
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/frozen":
+			w.Write([]byte(`{"frozen": true, "reason": "INC-123: database failover"}`))
+		case "/thawed":
+			w.Write([]byte(`{"frozen": false}`))
+		case "/garbage":
+			w.Write([]byte(`<html>`))
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "freeze")
+	if err := os.WriteFile(file, []byte("INC-456: payments outage\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		file   string
+		url    string
+		frozen bool
+		reason string
+		err    string
+	}{
+		{"nothing configured", "", "", false, "", ""},
+		{"flag file", file, "", true, "INC-456: payments outage", ""},
+		{"flag file wins over the endpoint", file, srv.URL + "/thawed", true, "INC-456: payments outage", ""},
+		{"no flag file", filepath.Join(dir, "missing"), "", false, "", ""},
+		{"endpoint frozen", filepath.Join(dir, "missing"), srv.URL + "/frozen", true, "INC-123: database failover", ""},
+		{"endpoint thawed", "", srv.URL + "/thawed", false, "", ""},
+		{"endpoint error", "", srv.URL + "/gone", false, "", "404"},
+		{"invalid response", "", srv.URL + "/garbage", false, "", "invalid freeze status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frozen, reason, err := Status(tt.file, tt.url)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if frozen != tt.frozen || reason != tt.reason {
+				t.Errorf("got (%v, %q), expected (%v, %q)", frozen, reason, tt.frozen, tt.reason)
+			}
+		})
+	}
+}
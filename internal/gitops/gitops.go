@@ -0,0 +1,105 @@
+package gitops
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Labels and annotations GitOps controllers put on the objects they manage
+const (
+	fluxKustomizationName      = "kustomize.toolkit.fluxcd.io/name"
+	fluxKustomizationNamespace = "kustomize.toolkit.fluxcd.io/namespace"
+	fluxHelmReleaseName        = "helm.toolkit.fluxcd.io/name"
+	fluxHelmReleaseNamespace   = "helm.toolkit.fluxcd.io/namespace"
+	argoTrackingID             = "argocd.argoproj.io/tracking-id"
+	argoInstance               = "argocd.argoproj.io/instance"
+	argoSkipReconcile          = "argocd.argoproj.io/skip-reconcile"
+)
+
+// Manager is the Flux or Argo CD object that reconciles a resource
+type Manager struct {
+	Tool      string // "Flux" or "Argo CD"
+	Kind      string // Kustomization, HelmRelease or Application
+	Name      string
+	Namespace string
+}
+
+func (m Manager) String() string {
+	return fmt.Sprintf("%s %s %s/%s", m.Tool, m.Kind, m.Namespace, m.Name)
+}
+
+// resource is the fully qualified kubectl resource name of the manager
+func (m Manager) resource() string {
+	switch m.Kind {
+	case "Kustomization":
+		return "kustomizations.kustomize.toolkit.fluxcd.io"
+	case "HelmRelease":
+		return "helmreleases.helm.toolkit.fluxcd.io"
+	default:
+		return "applications.argoproj.io"
+	}
+}
+
+// SuspendArgs returns the kubectl arguments that pause reconciliation: Flux
+// objects are suspended, Argo CD Applications are annotated to skip reconcile
+func (m Manager) SuspendArgs() []string {
+	if m.Tool == "Flux" {
+		return []string{"patch", m.resource(), m.Name, "-n", m.Namespace, "--type", "merge", "-p", `{"spec":{"suspend":true}}`}
+	}
+	return []string{"annotate", m.resource(), m.Name, "-n", m.Namespace, argoSkipReconcile + "=true", "--overwrite"}
+}
+
+// ResumeArgs returns the kubectl arguments that undo SuspendArgs
+func (m Manager) ResumeArgs() []string {
+	if m.Tool == "Flux" {
+		return []string{"patch", m.resource(), m.Name, "-n", m.Namespace, "--type", "merge", "-p", `{"spec":{"suspend":false}}`}
+	}
+	return []string{"annotate", m.resource(), m.Name, "-n", m.Namespace, argoSkipReconcile + "-"}
+}
+
+// ManagerOf returns the GitOps object managing a live object (JSON), and
+// whether it is managed at all. Argo CD Applications are assumed to live in
+// argoNamespace unless the tracking ID names their namespace.
+func ManagerOf(data []byte, argoNamespace string) (Manager, bool, error) {
+	var obj struct {
+		Metadata struct {
+			Namespace   string            `json:"namespace"`
+			Labels      map[string]string `json:"labels"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return Manager{}, false, fmt.Errorf("failed to parse object: %w", err)
+	}
+	labels := obj.Metadata.Labels
+
+	if name := labels[fluxKustomizationName]; name != "" {
+		return Manager{Tool: "Flux", Kind: "Kustomization", Name: name, Namespace: orDefault(labels[fluxKustomizationNamespace], obj.Metadata.Namespace)}, true, nil
+	}
+	if name := labels[fluxHelmReleaseName]; name != "" {
+		return Manager{Tool: "Flux", Kind: "HelmRelease", Name: name, Namespace: orDefault(labels[fluxHelmReleaseNamespace], obj.Metadata.Namespace)}, true, nil
+	}
+
+	// Tracking IDs look like "<app>:<group>/<kind>:<namespace>/<name>", where
+	// <app> is "<namespace>_<name>" for Applications outside argoNamespace
+	app := labels[argoInstance]
+	if id := obj.Metadata.Annotations[argoTrackingID]; id != "" {
+		app, _, _ = strings.Cut(id, ":")
+	}
+	if app == "" {
+		return Manager{}, false, nil
+	}
+	namespace := argoNamespace
+	if ns, name, ok := strings.Cut(app, "_"); ok {
+		namespace, app = ns, name
+	}
+	return Manager{Tool: "Argo CD", Kind: "Application", Name: app, Namespace: namespace}, true, nil
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
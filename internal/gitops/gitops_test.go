@@ -0,0 +1,94 @@
+package gitops
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestManagerOf(t *testing.T) {
+	tests := []struct {
+		name            string
+		object          string
+		expectedManager Manager
+		expectedOK      bool
+	}{
+		{
+			"flux kustomization",
+			`{"metadata":{"namespace":"shop","labels":{"kustomize.toolkit.fluxcd.io/name":"apps","kustomize.toolkit.fluxcd.io/namespace":"flux-system"}}}`,
+			Manager{Tool: "Flux", Kind: "Kustomization", Name: "apps", Namespace: "flux-system"},
+			true,
+		},
+		{
+			"flux helm release defaults to object namespace",
+			`{"metadata":{"namespace":"shop","labels":{"helm.toolkit.fluxcd.io/name":"web"}}}`,
+			Manager{Tool: "Flux", Kind: "HelmRelease", Name: "web", Namespace: "shop"},
+			true,
+		},
+		{
+			"argo tracking id",
+			`{"metadata":{"namespace":"shop","annotations":{"argocd.argoproj.io/tracking-id":"shop-web:apps/Deployment:shop/web"}}}`,
+			Manager{Tool: "Argo CD", Kind: "Application", Name: "shop-web", Namespace: "argocd"},
+			true,
+		},
+		{
+			"argo application in another namespace",
+			`{"metadata":{"annotations":{"argocd.argoproj.io/tracking-id":"team-a_web:apps/Deployment:shop/web"}}}`,
+			Manager{Tool: "Argo CD", Kind: "Application", Name: "web", Namespace: "team-a"},
+			true,
+		},
+		{
+			"argo instance label",
+			`{"metadata":{"labels":{"argocd.argoproj.io/instance":"shop-web"}}}`,
+			Manager{Tool: "Argo CD", Kind: "Application", Name: "shop-web", Namespace: "argocd"},
+			true,
+		},
+		{
+			"unmanaged",
+			`{"metadata":{"namespace":"shop","labels":{"app":"web"}}}`,
+			Manager{},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, ok, err := ManagerOf([]byte(tt.object), "argocd")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if manager != tt.expectedManager || ok != tt.expectedOK {
+				t.Errorf("got (%+v, %v), expected (%+v, %v)", manager, ok, tt.expectedManager, tt.expectedOK)
+			}
+		})
+	}
+
+	if _, _, err := ManagerOf([]byte(`{`), "argocd"); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestSuspendResumeArgs(t *testing.T) {
+	flux := Manager{Tool: "Flux", Kind: "Kustomization", Name: "apps", Namespace: "flux-system"}
+	expected := []string{"patch", "kustomizations.kustomize.toolkit.fluxcd.io", "apps", "-n", "flux-system", "--type", "merge", "-p", `{"spec":{"suspend":true}}`}
+	if got := flux.SuspendArgs(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("flux suspend: got %v, expected %v", got, expected)
+	}
+	expected = []string{"patch", "kustomizations.kustomize.toolkit.fluxcd.io", "apps", "-n", "flux-system", "--type", "merge", "-p", `{"spec":{"suspend":false}}`}
+	if got := flux.ResumeArgs(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("flux resume: got %v, expected %v", got, expected)
+	}
+
+	argo := Manager{Tool: "Argo CD", Kind: "Application", Name: "shop-web", Namespace: "argocd"}
+	expected = []string{"annotate", "applications.argoproj.io", "shop-web", "-n", "argocd", "argocd.argoproj.io/skip-reconcile=true", "--overwrite"}
+	if got := argo.SuspendArgs(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("argo suspend: got %v, expected %v", got, expected)
+	}
+	expected = []string{"annotate", "applications.argoproj.io", "shop-web", "-n", "argocd", "argocd.argoproj.io/skip-reconcile-"}
+	if got := argo.ResumeArgs(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("argo resume: got %v, expected %v", got, expected)
+	}
+
+	if got := flux.String(); got != "Flux Kustomization flux-system/apps" {
+		t.Errorf("String(): got %s", got)
+	}
+}
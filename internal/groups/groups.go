@@ -0,0 +1,78 @@
+// Package groups looks up the groups the operator belongs to, for group
+// policies: local OS groups, or a claim from an OIDC userinfo endpoint.
+package groups
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/user"
+	"time"
+)
+
+// userinfoTimeout bounds the userinfo request, which runs before every
+// dangerous command
+const userinfoTimeout = 10 * time.Second
+
+// Local returns the names of the OS groups the current user belongs to
+func Local() ([]string, error) {
+	u, err := user.Current()
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up current user: %w", err)
+	}
+	ids, err := u.GroupIds()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups of %s: %w", u.Username, err)
+	}
+	var names []string
+	for _, id := range ids {
+		// A group without a name can still be matched by its ID
+		if g, err := user.LookupGroupId(id); err == nil {
+			names = append(names, g.Name)
+		} else {
+			names = append(names, id)
+		}
+	}
+	return names, nil
+}
+
+// Userinfo returns the groups listed in claim of the OIDC userinfo response
+// for an access token. The claim may be a list or a single string.
+func Userinfo(url, token, claim string) ([]string, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid userinfo URL: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := (&http.Client{Timeout: userinfoTimeout}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch userinfo: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch userinfo: %s", resp.Status)
+	}
+
+	var claims map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&claims); err != nil {
+		return nil, fmt.Errorf("invalid userinfo response: %w", err)
+	}
+	switch value := claims[claim].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{value}, nil
+	case []any:
+		var names []string
+		for _, v := range value {
+			if name, ok := v.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names, nil
+	default:
+		return nil, fmt.Errorf("userinfo claim %s is not a list of groups", claim)
+	}
+}
@@ -0,0 +1,62 @@
+package groups
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUserinfo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"sub":"alice","groups":["sre-oncall","dev"],"team":"sre","level":3}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		token    string
+		claim    string
+		expected []string
+		err      string
+	}{
+		{"list claim", "token-1", "groups", []string{"sre-oncall", "dev"}, ""},
+		{"string claim", "token-1", "team", []string{"sre"}, ""},
+		{"missing claim", "token-1", "roles", nil, ""},
+		{"not groups", "token-1", "level", nil, "is not a list of groups"},
+		{"rejected token", "token-2", "groups", nil, "401"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Userinfo(srv.URL, tt.token, tt.claim)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLocal(t *testing.T) {
+	names, err := Local()
+	if err != nil {
+		t.Skipf("no local user: %v", err)
+	}
+	if len(names) == 0 {
+		t.Error("expected the current user to be in at least one group")
+	}
+}
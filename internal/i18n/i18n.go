@@ -0,0 +1,119 @@
+// Package i18n translates the safety-critical prompt messages: the warning,
+// the confirmation prompts and their outcome. Messages are looked up by their
+// English text, which is also what an unknown message or language shows.
+package i18n
+
+import (
+	"strings"
+)
+
+// English is the language messages are written in
+const English = "en"
+
+// Languages are the languages with bundled translations
+var Languages = []string{English, "id", "ja", "es"}
+
+// IsSupported reports whether lang has bundled translations
+func IsSupported(lang string) bool {
+	for _, l := range Languages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// translations map each English message to its translation, per language.
+// Messages with verbs keep them in the same order.
+var translations = map[string]map[string]string{
+	"id": {
+		"DANGEROUS OPERATION DETECTED": "OPERASI BERBAHAYA TERDETEKSI",
+		"Operation":                    "Operasi",
+		"Namespace":                    "Namespace",
+		"Cluster":                      "Klaster",
+		"Identity":                     "Identitas",
+		"Command":                      "Perintah",
+		"Resources affected":           "Sumber daya terdampak",
+		"Reasons":                      "Alasan",
+		"⚠ ALL NAMESPACES":             "⚠ SEMUA NAMESPACE",
+		"%s in namespace %s":           "%s di namespace %s",
+		"(cluster-scoped)":             "(lingkup klaster)",
+		"(unspecified)":                "(tidak ditentukan)",
+		"Proceed? [y/N]: ":             "Lanjutkan? [y/N]: ",
+		"Type %s to confirm: ":         "Ketik %s untuk konfirmasi: ",
+		"Operation aborted.":           "Operasi dibatalkan.",
+		"Proceeding with operation...": "Melanjutkan operasi...",
+		"Change ticket: ":              "Tiket perubahan: ",
+		"Reason for this change: ":     "Alasan perubahan ini: ",
+	},
+	"ja": {
+		"DANGEROUS OPERATION DETECTED": "危険な操作を検出しました",
+		"Operation":                    "操作",
+		"Namespace":                    "ネームスペース",
+		"Cluster":                      "クラスター",
+		"Identity":                     "実行ユーザー",
+		"Command":                      "コマンド",
+		"Resources affected":           "影響を受けるリソース",
+		"Reasons":                      "理由",
+		"⚠ ALL NAMESPACES":             "⚠ 全ネームスペース",
+		"%s in namespace %s":           "%s (ネームスペース %s)",
+		"(cluster-scoped)":             "(クラスタースコープ)",
+		"(unspecified)":                "(未指定)",
+		"Proceed? [y/N]: ":             "続行しますか? [y/N]: ",
+		"Type %s to confirm: ":         "確認のため %s と入力してください: ",
+		"Operation aborted.":           "操作を中止しました。",
+		"Proceeding with operation...": "操作を続行しています...",
+		"Change ticket: ":              "変更チケット: ",
+		"Reason for this change: ":     "この変更の理由: ",
+	},
+	"es": {
+		"DANGEROUS OPERATION DETECTED": "OPERACIÓN PELIGROSA DETECTADA",
+		"Operation":                    "Operación",
+		"Namespace":                    "Namespace",
+		"Cluster":                      "Clúster",
+		"Identity":                     "Identidad",
+		"Command":                      "Comando",
+		"Resources affected":           "Recursos afectados",
+		"Reasons":                      "Motivos",
+		"⚠ ALL NAMESPACES":             "⚠ TODOS LOS NAMESPACES",
+		"%s in namespace %s":           "%s en el namespace %s",
+		"(cluster-scoped)":             "(ámbito de clúster)",
+		"(unspecified)":                "(sin especificar)",
+		"Proceed? [y/N]: ":             "¿Continuar? [s/N]: ",
+		"Type %s to confirm: ":         "Escriba %s para confirmar: ",
+		"Operation aborted.":           "Operación cancelada.",
+		"Proceeding with operation...": "Continuando con la operación...",
+		"Change ticket: ":              "Ticket de cambio: ",
+		"Reason for this change: ":     "Motivo de este cambio: ",
+	},
+}
+
+// yes are the answers besides "y" and "yes" that confirm, per language
+var yes = map[string][]string{
+	"id": {"ya"},
+	"ja": {"はい"},
+	"es": {"s", "si", "sí"},
+}
+
+// Text returns message in lang, or message itself if it has no translation
+func Text(lang, message string) string {
+	if translated, ok := translations[lang][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// IsYes reports whether an answer to a yes/no prompt confirms. "y" and "yes"
+// confirm in every language, so muscle memory keeps working.
+func IsYes(lang, answer string) bool {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer == "y" || answer == "yes" {
+		return true
+	}
+	for _, word := range yes[lang] {
+		if answer == word {
+			return true
+		}
+	}
+	return false
+}
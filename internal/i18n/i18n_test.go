@@ -0,0 +1,78 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTranslationsComplete(t *testing.T) {
+	messages := translations["id"]
+	for _, lang := range Languages {
+		if lang == English {
+			continue
+		}
+		catalog, ok := translations[lang]
+		if !ok {
+			t.Fatalf("no translations for %s", lang)
+		}
+		if len(catalog) != len(messages) {
+			t.Errorf("%s: %d messages, expected %d", lang, len(catalog), len(messages))
+		}
+		for message := range messages {
+			translated, ok := catalog[message]
+			if !ok {
+				t.Errorf("%s: %q is not translated", lang, message)
+				continue
+			}
+			if strings.Count(translated, "%s") != strings.Count(message, "%s") {
+				t.Errorf("%s: %q changes the verbs of %q", lang, translated, message)
+			}
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		lang     string
+		message  string
+		expected string
+	}{
+		{"en", "Proceed? [y/N]: ", "Proceed? [y/N]: "},
+		{"id", "Operation aborted.", "Operasi dibatalkan."},
+		{"ja", "Reasons", "理由"},
+		{"es", "Type %s to confirm: ", "Escriba %s para confirmar: "},
+		{"es", "no such message", "no such message"},
+		{"fr", "Reasons", "Reasons"},
+	}
+
+	for _, tt := range tests {
+		if got := Text(tt.lang, tt.message); got != tt.expected {
+			t.Errorf("Text(%q, %q) = %q, expected %q", tt.lang, tt.message, got, tt.expected)
+		}
+	}
+}
+
+func TestIsYes(t *testing.T) {
+	tests := []struct {
+		lang     string
+		answer   string
+		expected bool
+	}{
+		{"en", "y", true},
+		{"en", " YES ", true},
+		{"en", "ya", false},
+		{"id", "ya", true},
+		{"ja", "はい", true},
+		{"ja", "y", true},
+		{"es", "sí", true},
+		{"es", "s", true},
+		{"es", "n", false},
+		{"en", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsYes(tt.lang, tt.answer); got != tt.expected {
+			t.Errorf("IsYes(%q, %q) = %v, expected %v", tt.lang, tt.answer, got, tt.expected)
+		}
+	}
+}
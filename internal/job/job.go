@@ -0,0 +1,52 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ParseActive returns the number of pods a Job reports as running (.status.active)
+func ParseActive(data []byte) (int, error) {
+	var doc struct {
+		Status struct {
+			Active int `json:"active"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return 0, fmt.Errorf("failed to parse job: %w", err)
+	}
+	return doc.Status.Active, nil
+}
+
+// IsOrphanCascade reports whether kubectl delete arguments keep dependents
+// running with --cascade=orphan (or the deprecated --cascade=false)
+func IsOrphanCascade(args []string) bool {
+	for i, arg := range args {
+		value := ""
+		switch {
+		case arg == "--":
+			return false
+		case arg == "--cascade" && i+1 < len(args):
+			value = args[i+1]
+		case strings.HasPrefix(arg, "--cascade="):
+			value = strings.TrimPrefix(arg, "--cascade=")
+		default:
+			continue
+		}
+		return value == "orphan" || value == "false"
+	}
+	return false
+}
+
+// ActiveReason describes the effect of deleting a Job while its pods run
+func ActiveReason(display string, active int, orphan bool) string {
+	pods := fmt.Sprintf("%d running pods", active)
+	if active == 1 {
+		pods = "1 running pod"
+	}
+	if orphan {
+		return fmt.Sprintf("%s still has %s: --cascade=orphan leaves them running with nothing tracking their completion", display, pods)
+	}
+	return fmt.Sprintf("%s still has %s: they are terminated mid-run", display, pods)
+}
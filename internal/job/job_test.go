@@ -0,0 +1,49 @@
+package job
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseActive(t *testing.T) {
+	active, err := ParseActive([]byte(`{"kind":"Job","status":{"active":2,"succeeded":1}}`))
+	if err != nil || active != 2 {
+		t.Errorf("running job: got (%v, %v)", active, err)
+	}
+	active, err = ParseActive([]byte(`{"kind":"Job","status":{"succeeded":1}}`))
+	if err != nil || active != 0 {
+		t.Errorf("finished job: got (%v, %v)", active, err)
+	}
+	if _, err := ParseActive([]byte(`{`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestIsOrphanCascade(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected bool
+	}{
+		{[]string{"delete", "job", "etl"}, false},
+		{[]string{"delete", "job", "etl", "--cascade=orphan"}, true},
+		{[]string{"delete", "job", "etl", "--cascade", "orphan"}, true},
+		{[]string{"delete", "job", "etl", "--cascade=false"}, true},
+		{[]string{"delete", "job", "etl", "--cascade=foreground"}, false},
+		{[]string{"delete", "job", "etl", "--", "--cascade=orphan"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsOrphanCascade(tt.args); got != tt.expected {
+			t.Errorf("IsOrphanCascade(%v): got %v, expected %v", tt.args, got, tt.expected)
+		}
+	}
+}
+
+func TestActiveReason(t *testing.T) {
+	if got := ActiveReason("job/etl", 1, false); !strings.Contains(got, "still has 1 running pod:") || !strings.Contains(got, "terminated mid-run") {
+		t.Errorf("unexpected reason: %s", got)
+	}
+	if got := ActiveReason("job/etl", 3, true); !strings.Contains(got, "still has 3 running pods") || !strings.Contains(got, "--cascade=orphan") {
+		t.Errorf("unexpected orphan reason: %s", got)
+	}
+}
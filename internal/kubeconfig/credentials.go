@@ -0,0 +1,67 @@
+package kubeconfig
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LongLivedCredentials describes the credentials of the named context (the
+// current one if name is empty) that do not expire on their own: a static
+// bearer token, or a client certificate valid for longer than maxValidity
+// from now. Exec plugins and auth providers issue short-lived tokens and are
+// never reported.
+func (k *Kubeconfig) LongLivedCredentials(name string, now time.Time, maxValidity time.Duration) []string {
+	c, ok := k.Context(name)
+	if !ok {
+		return nil
+	}
+	u, ok := k.Users[c.User]
+	if !ok || u.Exec != nil || u.AuthProvider != nil {
+		return nil
+	}
+
+	var found []string
+	if u.Token != "" || u.TokenFile != "" {
+		found = append(found, fmt.Sprintf("user %s authenticates with a static bearer token", c.User))
+	}
+	cert, err := u.certificate()
+	if err == nil && cert != nil && cert.NotAfter.Sub(now) > maxValidity {
+		found = append(found, fmt.Sprintf("user %s authenticates with a client certificate valid until %s", c.User, cert.NotAfter.UTC().Format("2006-01-02")))
+	}
+	return found
+}
+
+// certificate parses the user's client certificate, or returns nil if it has none
+func (u User) certificate() (*x509.Certificate, error) {
+	var data []byte
+	switch {
+	case u.ClientCertificateData != "":
+		decoded, err := base64.StdEncoding.DecodeString(u.ClientCertificateData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client-certificate-data: %w", err)
+		}
+		data = decoded
+	case u.ClientCertificate != "":
+		path := u.ClientCertificate
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(u.dir, path)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		data = content
+	default:
+		return nil, nil
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("client certificate is not PEM encoded")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
@@ -0,0 +1,90 @@
+package kubeconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testCertificate returns a PEM client certificate valid until notAfter
+func testCertificate(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "alice"},
+		NotBefore:    notAfter.AddDate(-10, 0, 0),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestLongLivedCredentials(t *testing.T) {
+	now := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "admin.crt"), string(testCertificate(t, now.AddDate(10, 0, 0))))
+	shortLived := base64.StdEncoding.EncodeToString(testCertificate(t, now.AddDate(0, 0, 30)))
+
+	path := filepath.Join(dir, "config")
+	writeFile(t, path, `
+contexts:
+- name: token
+  context: {cluster: prod, user: ci}
+- name: cert
+  context: {cluster: prod, user: admin}
+- name: short
+  context: {cluster: prod, user: short}
+- name: oidc
+  context: {cluster: prod, user: oidc}
+users:
+- name: ci
+  user:
+    token: abc123
+- name: admin
+  user:
+    client-certificate: admin.crt
+- name: short
+  user:
+    client-certificate-data: `+shortLived+`
+- name: oidc
+  user:
+    exec:
+      command: kubelogin
+`)
+	kc, err := Load([]string{path})
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	tests := []struct {
+		context  string
+		expected []string
+	}{
+		{"token", []string{"user ci authenticates with a static bearer token"}},
+		{"cert", []string{"user admin authenticates with a client certificate valid until 2034-01-15"}},
+		{"short", nil},
+		{"oidc", nil},
+		{"missing", nil},
+	}
+	for _, tt := range tests {
+		if got := kc.LongLivedCredentials(tt.context, now, 90*24*time.Hour); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: got %v, expected %v", tt.context, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package kubeconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Context is one kubeconfig context
+type Context struct {
+	Cluster   string `yaml:"cluster"`
+	User      string `yaml:"user"`
+	Namespace string `yaml:"namespace"`
+}
+
+// Cluster is one kubeconfig cluster
+type Cluster struct {
+	Server   string `yaml:"server"`    // API server URL
+	ProxyURL string `yaml:"proxy-url"` // proxy for requests to the server, instead of the environment's
+}
+
+// User is one kubeconfig user: how a context authenticates
+type User struct {
+	Token                 string   `yaml:"token"`
+	TokenFile             string   `yaml:"tokenFile"`
+	ClientCertificate     string   `yaml:"client-certificate"`
+	ClientCertificateData string   `yaml:"client-certificate-data"`
+	Exec                  any      `yaml:"exec"`
+	AuthProvider          any      `yaml:"auth-provider"`
+	As                    string   `yaml:"as"`        // user to impersonate
+	AsGroups              []string `yaml:"as-groups"` // groups to impersonate
+	AsUID                 string   `yaml:"as-uid"`    // UID to impersonate
+
+	dir string // directory of the file defining the user, which relative paths are resolved against
+}
+
+// Kubeconfig is the merged view of one or more kubeconfig files
+type Kubeconfig struct {
+	CurrentContext string
+	Contexts       map[string]Context
+	Clusters       map[string]Cluster
+	Users          map[string]User
+}
+
+type kubeconfigFile struct {
+	CurrentContext string `yaml:"current-context"`
+	Contexts       []struct {
+		Name    string  `yaml:"name"`
+		Context Context `yaml:"context"`
+	} `yaml:"contexts"`
+	Clusters []struct {
+		Name    string  `yaml:"name"`
+		Cluster Cluster `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User User   `yaml:"user"`
+	} `yaml:"users"`
+}
+
+// Files returns the kubeconfig files kubectl would read: the explicit
+// --kubeconfig path, otherwise the KUBECONFIG list, otherwise ~/.kube/config
+func Files(explicit string) []string {
+	if explicit != "" {
+		return []string{explicit}
+	}
+	if env := os.Getenv("KUBECONFIG"); env != "" {
+		var files []string
+		for _, path := range filepath.SplitList(env) {
+			if path != "" {
+				files = append(files, path)
+			}
+		}
+		return files
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return []string{filepath.Join(homeDir, ".kube", "config")}
+}
+
+// Load reads and merges kubeconfig files using kubectl's rules: the first file
+// to set current-context wins, as does the first definition of a context, cluster or user.
+// Missing files are skipped, but at least one file must be read.
+func Load(files []string) (*Kubeconfig, error) {
+	kc := &Kubeconfig{Contexts: make(map[string]Context), Clusters: make(map[string]Cluster), Users: make(map[string]User)}
+	loaded := 0
+
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		var file kubeconfigFile
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		loaded++
+
+		if kc.CurrentContext == "" {
+			kc.CurrentContext = file.CurrentContext
+		}
+		for _, c := range file.Contexts {
+			if _, seen := kc.Contexts[c.Name]; !seen {
+				kc.Contexts[c.Name] = c.Context
+			}
+		}
+		for _, c := range file.Clusters {
+			if _, seen := kc.Clusters[c.Name]; !seen {
+				kc.Clusters[c.Name] = c.Cluster
+			}
+		}
+		for _, u := range file.Users {
+			if _, seen := kc.Users[u.Name]; !seen {
+				u.User.dir = filepath.Dir(path)
+				kc.Users[u.Name] = u.User
+			}
+		}
+	}
+
+	if loaded == 0 {
+		return nil, fmt.Errorf("no kubeconfig file found in %v", files)
+	}
+	return kc, nil
+}
+
+// Context returns the named context, or the current context if name is empty
+func (k *Kubeconfig) Context(name string) (Context, bool) {
+	if name == "" {
+		name = k.CurrentContext
+	}
+	c, ok := k.Contexts[name]
+	return c, ok
+}
+
+// Server returns the cluster name and API server URL of the named context, or
+// of the current context if name is empty. Unknown contexts have neither.
+func (k *Kubeconfig) Server(name string) (string, string) {
+	c, ok := k.Context(name)
+	if !ok {
+		return "", ""
+	}
+	return c.Cluster, k.Clusters[c.Cluster].Server
+}
+
+// User returns the user of the named context, or of the current context if
+// name is empty
+func (k *Kubeconfig) User(name string) (User, bool) {
+	c, ok := k.Context(name)
+	if !ok {
+		return User{}, false
+	}
+	u, ok := k.Users[c.User]
+	return u, ok
+}
@@ -0,0 +1,189 @@
+package kubeconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	t.Setenv("HOME", "/home/alice")
+
+	t.Setenv("KUBECONFIG", "")
+	if got := Files(""); !reflect.DeepEqual(got, []string{"/home/alice/.kube/config"}) {
+		t.Errorf("default: got %v", got)
+	}
+
+	t.Setenv("KUBECONFIG", "/a/config"+string(os.PathListSeparator)+"/b/config")
+	if got := Files(""); !reflect.DeepEqual(got, []string{"/a/config", "/b/config"}) {
+		t.Errorf("KUBECONFIG: got %v", got)
+	}
+	if got := Files("/explicit"); !reflect.DeepEqual(got, []string{"/explicit"}) {
+		t.Errorf("explicit: got %v", got)
+	}
+}
+
+func TestLoadMergesFiles(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first")
+	second := filepath.Join(dir, "second")
+	writeFile(t, first, `
+clusters:
+- name: prod-cluster
+  cluster:
+    server: https://api.prod.example.com:6443
+contexts:
+- name: prod
+  context:
+    cluster: prod-cluster
+    user: admin
+    namespace: payments
+`)
+	writeFile(t, second, `
+current-context: prod
+contexts:
+- name: prod
+  context:
+    cluster: other
+    namespace: ignored
+- name: dev
+  context:
+    cluster: dev-cluster
+`)
+
+	kc, err := Load([]string{filepath.Join(dir, "missing"), first, second})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if kc.CurrentContext != "prod" {
+		t.Errorf("current context: got %q, expected %q", kc.CurrentContext, "prod")
+	}
+
+	tests := []struct {
+		name      string
+		expected  Context
+		expectsOK bool
+	}{
+		{"", Context{Cluster: "prod-cluster", User: "admin", Namespace: "payments"}, true},
+		{"prod", Context{Cluster: "prod-cluster", User: "admin", Namespace: "payments"}, true},
+		{"dev", Context{Cluster: "dev-cluster"}, true},
+		{"unknown", Context{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := kc.Context(tt.name)
+			if ok != tt.expectsOK || got != tt.expected {
+				t.Errorf("Context(%q): got %+v, %v, expected %+v, %v", tt.name, got, ok, tt.expected, tt.expectsOK)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Load([]string{filepath.Join(dir, "missing")}); err == nil {
+		t.Error("expected error when no file exists")
+	}
+
+	invalid := filepath.Join(dir, "invalid")
+	writeFile(t, invalid, "contexts: [")
+	if _, err := Load([]string{invalid}); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
+
+func TestServer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	writeFile(t, path, `
+current-context: renamed
+clusters:
+- name: prod-cluster
+  cluster:
+    server: https://api.prod.example.com:6443
+contexts:
+- name: renamed
+  context:
+    cluster: prod-cluster
+- name: orphan
+  context:
+    cluster: missing
+`)
+
+	kc, err := Load([]string{path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		context         string
+		expectedCluster string
+		expectedServer  string
+	}{
+		{"", "prod-cluster", "https://api.prod.example.com:6443"},
+		{"orphan", "missing", ""},
+		{"unknown", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.context, func(t *testing.T) {
+			cluster, server := kc.Server(tt.context)
+			if cluster != tt.expectedCluster || server != tt.expectedServer {
+				t.Errorf("Server(%q): got (%q, %q), expected (%q, %q)", tt.context, cluster, server, tt.expectedCluster, tt.expectedServer)
+			}
+		})
+	}
+}
+
+func TestUser(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	writeFile(t, path, `
+current-context: admin
+clusters:
+- name: prod-cluster
+  cluster:
+    server: https://api.prod.example.com:6443
+    proxy-url: socks5://localhost:1080
+contexts:
+- name: admin
+  context:
+    cluster: prod-cluster
+    user: breakglass
+- name: nobody
+  context:
+    cluster: prod-cluster
+    user: missing
+users:
+- name: breakglass
+  user:
+    token: abc
+    as: admin
+    as-groups: [system:masters]
+    as-uid: "42"
+`)
+
+	kc, err := Load([]string{path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := kc.Clusters["prod-cluster"].ProxyURL; got != "socks5://localhost:1080" {
+		t.Errorf("ProxyURL: got %q", got)
+	}
+
+	u, ok := kc.User("")
+	if !ok || u.As != "admin" || !reflect.DeepEqual(u.AsGroups, []string{"system:masters"}) || u.AsUID != "42" {
+		t.Errorf("User(\"\"): got %+v, %v", u, ok)
+	}
+	if _, ok := kc.User("nobody"); ok {
+		t.Error("User(\"nobody\"): expected no user")
+	}
+	if _, ok := kc.User("unknown"); ok {
+		t.Error("User(\"unknown\"): expected no user")
+	}
+}
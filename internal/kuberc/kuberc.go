@@ -0,0 +1,183 @@
+// Package kuberc reads kubectl's kuberc preferences file and expands the
+// aliases and default flags it defines the way kubectl does, so commands are
+// checked as kubectl will run them.
+package kuberc
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// Preference is a kuberc file (kubectl.config.k8s.io Preference)
+type Preference struct {
+	Aliases   []Alias    `yaml:"aliases"`
+	Defaults  []Defaults `yaml:"defaults"`
+	Overrides []Defaults `yaml:"overrides"` // v1alpha1 name of defaults
+}
+
+// Alias is a command name standing for another command with extra arguments
+type Alias struct {
+	Name        string   `yaml:"name"`
+	Command     string   `yaml:"command"`     // e.g. "get" or "create configmap"
+	PrependArgs []string `yaml:"prependArgs"` // inserted after the command
+	AppendArgs  []string `yaml:"appendArgs"`  // added after the user's arguments, ahead of any "--"
+	Options     []Option `yaml:"options"`
+}
+
+// Defaults sets default flags for a command
+type Defaults struct {
+	Command string   `yaml:"command"`
+	Options []Option `yaml:"options"`
+}
+
+// Option is a flag, by its long name, and the value used when the command
+// does not set it
+type Option struct {
+	Name    string `yaml:"name"`
+	Default string `yaml:"default"`
+}
+
+// shorthands are the one-letter forms of common flags, which set them as well
+var shorthands = map[string]string{
+	"namespace":      "-n",
+	"output":         "-o",
+	"filename":       "-f",
+	"selector":       "-l",
+	"container":      "-c",
+	"recursive":      "-R",
+	"all-namespaces": "-A",
+	"kustomize":      "-k",
+	"stdin":          "-i",
+	"tty":            "-t",
+}
+
+// Path returns the kuberc file kubectl reads for args: the --kuberc flag,
+// otherwise $KUBERC, otherwise ~/.kube/kuberc. It returns "" when kuberc is
+// turned off with "off" or KUBECTL_KUBERC=false.
+func Path(args []string) string {
+	if os.Getenv("KUBECTL_KUBERC") == "false" {
+		return ""
+	}
+	path := os.Getenv("KUBERC")
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "--kuberc" && i+1 < len(args) {
+			path = args[i+1]
+		} else if value, ok := strings.CutPrefix(arg, "--kuberc="); ok {
+			path = value
+		}
+	}
+	if path == "off" {
+		return ""
+	}
+	if path == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		path = filepath.Join(homeDir, ".kube", "kuberc")
+	}
+	return path
+}
+
+// Load reads a kuberc file. A missing file is no preferences, nil.
+func Load(path string) (*Preference, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kuberc: %w", err)
+	}
+	var pref Preference
+	if err := yaml.Unmarshal(data, &pref); err != nil {
+		return nil, fmt.Errorf("invalid kuberc %s: %w", path, err)
+	}
+	return &pref, nil
+}
+
+// Expand returns args with an alias used as the operation replaced by its
+// command and arguments, and the default flags of the alias and the command
+// added where args do not set them. Flags are added ahead of any "--".
+func (p *Preference) Expand(args []string) []string {
+	if p == nil {
+		return args
+	}
+	i := parser.OperationIndex(args)
+	if i < 0 {
+		return args
+	}
+	expanded := slices.Clone(args)
+	var options []Option
+	for _, alias := range p.Aliases {
+		if alias.Name != args[i] || alias.Command == "" {
+			continue
+		}
+		before, after := split(args[i+1:])
+		expanded = slices.Concat(args[:i], strings.Fields(alias.Command), alias.PrependArgs, before, alias.AppendArgs, after)
+		options = append(options, alias.Options...)
+		break
+	}
+	for _, d := range append(slices.Clone(p.Defaults), p.Overrides...) {
+		if isCommand(expanded, strings.Fields(d.Command)) {
+			options = append(options, d.Options...)
+		}
+	}
+
+	before, after := split(expanded)
+	for _, o := range options {
+		if o.Name != "" && !hasFlag(before, o.Name) {
+			before = append(before, "--"+o.Name+"="+o.Default)
+		}
+	}
+	return slices.Concat(before, after)
+}
+
+// split splits args at a "--" separator, which starts after
+func split(args []string) ([]string, []string) {
+	if i := slices.Index(args, "--"); i >= 0 {
+		return slices.Clone(args[:i]), args[i:]
+	}
+	return slices.Clone(args), nil
+}
+
+// isCommand reports whether the first non-flag arguments of args are words
+func isCommand(args, words []string) bool {
+	if len(words) == 0 {
+		return false
+	}
+	for _, word := range words {
+		i := parser.OperationIndex(args)
+		if i < 0 || args[i] != word {
+			return false
+		}
+		args = args[i+1:]
+	}
+	return true
+}
+
+// hasFlag reports whether args set the flag with the long name, or its
+// shorthand, e.g. -n, -n=x or -ox
+func hasFlag(args []string, name string) bool {
+	short := shorthands[name]
+	for _, arg := range args {
+		if arg == "--"+name || strings.HasPrefix(arg, "--"+name+"=") {
+			return true
+		}
+		if short != "" && strings.HasPrefix(arg, short) && !strings.HasPrefix(arg, "--") {
+			return true
+		}
+	}
+	return false
+}
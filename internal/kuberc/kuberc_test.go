@@ -0,0 +1,126 @@
+package kuberc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testKuberc = `apiVersion: kubectl.config.k8s.io/v1beta1
+kind: Preference
+aliases:
+  - name: getn
+    command: get
+    prependArgs: [namespace]
+    options:
+      - name: output
+        default: wide
+  - name: runx
+    command: run
+    appendArgs: [--restart=Never]
+  - name: mkcm
+    command: create configmap
+defaults:
+  - command: apply
+    options:
+      - name: server-side
+        default: "true"
+      - name: dry-run
+        default: server
+  - command: create configmap
+    options:
+      - name: dry-run
+        default: client
+`
+
+func TestExpand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kuberc")
+	if err := os.WriteFile(path, []byte(testKuberc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pref, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{"no alias or defaults", []string{"delete", "pod", "web"}, []string{"delete", "pod", "web"}},
+		{"alias with prepended args and an option", []string{"getn", "kube-system"}, []string{"get", "namespace", "kube-system", "--output=wide"}},
+		{"explicit flag wins over the option", []string{"getn", "-o", "yaml"}, []string{"get", "namespace", "-o", "yaml"}},
+		{"explicit shorthand with the value attached", []string{"getn", "-ojson"}, []string{"get", "namespace", "-ojson"}},
+		{"global flags ahead of the alias", []string{"--context", "prod", "getn"}, []string{"--context", "prod", "get", "namespace", "--output=wide"}},
+		{"appended args go ahead of --", []string{"runx", "debug", "--image=busybox", "--", "sh"}, []string{"run", "debug", "--image=busybox", "--restart=Never", "--", "sh"}},
+		{"command defaults", []string{"apply", "-f", "deploy.yaml"}, []string{"apply", "-f", "deploy.yaml", "--server-side=true", "--dry-run=server"}},
+		{"explicit default flag wins", []string{"apply", "-f", "deploy.yaml", "--dry-run=none"}, []string{"apply", "-f", "deploy.yaml", "--dry-run=none", "--server-side=true"}},
+		{"alias to a command with defaults", []string{"mkcm", "settings"}, []string{"create", "configmap", "settings", "--dry-run=client"}},
+		{"subcommand defaults need the subcommand", []string{"create", "secret", "generic", "x"}, []string{"create", "secret", "generic", "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pref.Expand(tt.args)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpandOverrides(t *testing.T) {
+	pref := &Preference{Overrides: []Defaults{{Command: "delete", Options: []Option{{Name: "interactive", Default: "true"}}}}}
+	got := pref.Expand([]string{"delete", "pod", "web"})
+	expected := []string{"delete", "pod", "web", "--interactive=true"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+}
+
+func TestPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name     string
+		env      string
+		gate     string
+		args     []string
+		expected string
+	}{
+		{"default", "", "", []string{"get", "pods"}, filepath.Join(home, ".kube", "kuberc")},
+		{"environment", "/etc/kuberc", "", []string{"get", "pods"}, "/etc/kuberc"},
+		{"flag wins over the environment", "/etc/kuberc", "", []string{"--kuberc", "/tmp/kuberc", "get", "pods"}, "/tmp/kuberc"},
+		{"flag with =", "", "", []string{"get", "pods", "--kuberc=/tmp/kuberc"}, "/tmp/kuberc"},
+		{"turned off", "off", "", []string{"get", "pods"}, ""},
+		{"turned off by the flag", "", "", []string{"--kuberc=off", "get", "pods"}, ""},
+		{"feature gate off", "", "false", []string{"get", "pods"}, ""},
+		{"flags after -- are the command's", "", "", []string{"exec", "web", "--", "kubectl", "--kuberc=/x"}, filepath.Join(home, ".kube", "kuberc")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("KUBERC", tt.env)
+			t.Setenv("KUBECTL_KUBERC", tt.gate)
+			if got := Path(tt.args); got != tt.expected {
+				t.Errorf("got %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if pref, err := Load(filepath.Join(dir, "missing")); pref != nil || err != nil {
+		t.Errorf("missing file: got %v, %v", pref, err)
+	}
+	invalid := filepath.Join(dir, "invalid")
+	os.WriteFile(invalid, []byte("aliases: {name: ["), 0644)
+	if _, err := Load(invalid); err == nil || !strings.Contains(err.Error(), "invalid kuberc") {
+		t.Errorf("expected an invalid kuberc error, got %v", err)
+	}
+}
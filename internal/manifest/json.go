@@ -12,7 +12,7 @@ type kubeResourceJSON struct {
 		Name      string `json:"name"`
 		Namespace string `json:"namespace"`
 	} `json:"metadata"`
-	Items []kubeResourceJSON `json:"items,omitempty"`
+	Items []json.RawMessage `json:"items,omitempty"`
 }
 
 func ParseJSON(content []byte, source string) ([]Resource, error) {
@@ -25,7 +25,11 @@ func ParseJSON(content []byte, source string) ([]Resource, error) {
 
 	// Handle List kind - return items, not the List itself
 	if doc.Kind == "List" {
-		for _, item := range doc.Items {
+		for _, raw := range doc.Items {
+			var item kubeResourceJSON
+			if err := json.Unmarshal(raw, &item); err != nil {
+				return nil, fmt.Errorf("failed to parse JSON list item from %s: %w", source, err)
+			}
 			if item.Kind == "" {
 				continue
 			}
@@ -35,6 +39,7 @@ func ParseJSON(content []byte, source string) ([]Resource, error) {
 				Name:       item.Metadata.Name,
 				Namespace:  item.Metadata.Namespace,
 				Source:     source,
+				Raw:        raw,
 			})
 		}
 		return resources, nil
@@ -47,6 +52,7 @@ func ParseJSON(content []byte, source string) ([]Resource, error) {
 			Name:       doc.Metadata.Name,
 			Namespace:  doc.Metadata.Namespace,
 			Source:     source,
+			Raw:        content,
 		})
 	}
 
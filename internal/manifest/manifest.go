@@ -10,11 +10,12 @@ import (
 
 // Resource represents a single parsed Kubernetes resource
 type Resource struct {
-	APIVersion string
-	Kind       string
-	Name       string
-	Namespace  string // empty if not specified in manifest
-	Source     string // file path or URL for display
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"` // empty if not specified in manifest
+	Source     string `json:"source"`    // file path or URL for display
+	Raw        []byte `json:"-"`         // the full document, for re-applying a subset of resources
 }
 
 // String returns a display string like "Deployment/nginx"
@@ -31,12 +32,16 @@ type ParseResult struct {
 	Source    string // file path or URL for display
 }
 
-// ParseFile parses a file based on its extension
+// ParseFile parses a file based on its extension. Files that are clearly
+// not manifests, such as kubeconfigs, are refused.
 func ParseFile(path string) ([]Resource, error) {
 	content, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
 	}
+	if err := checkManifest(content, path); err != nil {
+		return nil, err
+	}
 
 	ext := strings.ToLower(filepath.Ext(path))
 	switch ext {
@@ -49,6 +54,21 @@ func ParseFile(path string) ([]Resource, error) {
 	}
 }
 
+// StdinSource is the -f value that reads manifests from stdin
+const StdinSource = "-"
+
+// ParseStdin parses manifests read from stdin: JSON if the content is a JSON
+// object, YAML otherwise
+func ParseStdin(content []byte) ([]Resource, error) {
+	if err := checkManifest(content, "stdin"); err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(strings.TrimSpace(string(content)), "{") {
+		return ParseJSON(content, "stdin")
+	}
+	return ParseYAML(content, "stdin")
+}
+
 // isSupportedFile returns true if the file has a supported extension
 func isSupportedFile(path string) bool {
 	ext := strings.ToLower(filepath.Ext(path))
@@ -0,0 +1,97 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envLine matches a line of a .env file, e.g. "DB_PASSWORD=hunter2"
+var envLine = regexp.MustCompile(`^(export\s+)?[A-Za-z_][A-Za-z0-9_]*=`)
+
+// checkManifest refuses content that is clearly not a Kubernetes manifest,
+// such as a kubeconfig, a shell script, a .env file or a private key, since
+// passing it to kubectl would send its secrets to the API server
+func checkManifest(content []byte, source string) error {
+	what, hint := identify(content, source)
+	if what == "" {
+		return nil
+	}
+	return fmt.Errorf("%s is %s, not a Kubernetes manifest: refusing to send it to kubectl%s", source, what, hint)
+}
+
+// CheckFile refuses a local file given with -f that is clearly not a
+// manifest, without parsing it. Directories, URLs and unreadable files are
+// left to kubectl.
+func CheckFile(path string) error {
+	if path == StdinSource || IsURL(path) {
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return checkManifest(content, path)
+}
+
+// identify names what non-manifest content is, with a hint on what was
+// probably meant, or returns "" for content that may be a manifest. Secrets
+// may carry keys and credentials in their data, so only content that starts
+// with them, or is not YAML at all, is refused.
+func identify(content []byte, source string) (string, string) {
+	trimmed := bytes.TrimSpace(content)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("#!")):
+		return "a script", ""
+	case bytes.HasPrefix(trimmed, []byte("-----BEGIN ")) && bytes.Contains(trimmed, []byte("PRIVATE KEY-----")):
+		return "a private key", ""
+	case bytes.HasPrefix(trimmed, []byte("-----BEGIN CERTIFICATE-----")):
+		return "a certificate", ""
+	}
+	name := filepath.Base(source)
+	if name == ".env" || strings.HasPrefix(name, ".env.") || strings.HasSuffix(name, ".env") {
+		return "an environment file", ""
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(trimmed, &doc); err == nil {
+		_, clusters := doc["clusters"]
+		_, contexts := doc["contexts"]
+		_, users := doc["users"]
+		if doc["kind"] == "Config" || (clusters && (contexts || users)) {
+			hint := ""
+			if source != "stdin" {
+				hint = fmt.Sprintf(" (to connect with it, pass --kubeconfig %s)", source)
+			}
+			return "a kubeconfig", hint
+		}
+		if _, auths := doc["auths"]; auths && doc["kind"] == nil {
+			return "a container registry credentials file", ""
+		}
+	}
+
+	// Not a YAML map: .env and INI credentials files
+	if bytes.Contains(trimmed, []byte("aws_secret_access_key")) && doc == nil {
+		return "a cloud credentials file", ""
+	}
+	lines := 0
+	for _, line := range strings.Split(string(trimmed), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !envLine.MatchString(line) {
+			return "", ""
+		}
+		lines++
+	}
+	if lines > 0 {
+		return "an environment file", ""
+	}
+	return "", ""
+}
@@ -0,0 +1,319 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dependency is an object a resource needs to exist when it is applied
+type Dependency struct {
+	Kind      string // Namespace, CustomResourceDefinition, ConfigMap, Secret, ServiceAccount or PersistentVolumeClaim
+	Name      string // empty for a CustomResourceDefinition, which is matched by Defines
+	Namespace string // empty for Namespaces and CustomResourceDefinitions
+	Defines   string // group/Kind a CustomResourceDefinition must define
+}
+
+// fails reports whether applying the resource fails without the dependency,
+// rather than only its pods failing to start
+func (d Dependency) fails() bool {
+	return d.Kind == "Namespace" || d.Kind == "CustomResourceDefinition"
+}
+
+// relation describes how a resource depends on d
+func (d Dependency) relation() string {
+	switch d.Kind {
+	case "Namespace":
+		return "is created in"
+	case "CustomResourceDefinition":
+		return "needs its kind from"
+	}
+	return "references"
+}
+
+// satisfiedBy reports whether r is the object d needs
+func (d Dependency) satisfiedBy(r Resource) bool {
+	if r.Kind != d.Kind {
+		return false
+	}
+	if d.Kind == "CustomResourceDefinition" {
+		return definedKind(r) == d.Defines
+	}
+	return r.Name == d.Name && r.Namespace == d.Namespace
+}
+
+// orderRef is a reference by name, which pods do without when it is optional
+type orderRef struct {
+	Name     string `yaml:"name"`
+	Optional bool   `yaml:"optional"`
+}
+
+type orderContainer struct {
+	Env []struct {
+		ValueFrom struct {
+			ConfigMapKeyRef *orderRef `yaml:"configMapKeyRef"`
+			SecretKeyRef    *orderRef `yaml:"secretKeyRef"`
+		} `yaml:"valueFrom"`
+	} `yaml:"env"`
+	EnvFrom []struct {
+		ConfigMapRef *orderRef `yaml:"configMapRef"`
+		SecretRef    *orderRef `yaml:"secretRef"`
+	} `yaml:"envFrom"`
+}
+
+type orderPodSpec struct {
+	ServiceAccountName string `yaml:"serviceAccountName"`
+	Volumes            []struct {
+		ConfigMap *orderRef `yaml:"configMap"`
+		Secret    *struct {
+			SecretName string `yaml:"secretName"`
+			Optional   bool   `yaml:"optional"`
+		} `yaml:"secret"`
+		PersistentVolumeClaim *struct {
+			ClaimName string `yaml:"claimName"`
+		} `yaml:"persistentVolumeClaim"`
+	} `yaml:"volumes"`
+	Containers       []orderContainer `yaml:"containers"`
+	InitContainers   []orderContainer `yaml:"initContainers"`
+	ImagePullSecrets []orderRef       `yaml:"imagePullSecrets"`
+}
+
+type orderTemplate struct {
+	Spec orderPodSpec `yaml:"spec"`
+}
+
+type orderDoc struct {
+	Spec struct {
+		orderPodSpec `yaml:",inline"`
+		Template     orderTemplate `yaml:"template"`
+		JobTemplate  struct {
+			Spec struct {
+				Template orderTemplate `yaml:"template"`
+			} `yaml:"spec"`
+		} `yaml:"jobTemplate"`
+		// CustomResourceDefinition
+		Group string `yaml:"group"`
+		Names struct {
+			Kind string `yaml:"kind"`
+		} `yaml:"names"`
+	} `yaml:"spec"`
+}
+
+// Dependencies returns the objects a resource needs when it is applied: the
+// Namespace it is created in, a CustomResourceDefinition for its kind, and the
+// ConfigMaps, Secrets (imagePullSecrets included), ServiceAccount and
+// PersistentVolumeClaims its pod spec references and does not mark optional.
+// Namespaced dependencies are in the resource's namespace.
+func Dependencies(r Resource) []Dependency {
+	var deps []Dependency
+	if r.Namespace != "" && r.Kind != "Namespace" {
+		deps = append(deps, Dependency{Kind: "Namespace", Name: r.Namespace})
+	}
+	if group, _, ok := strings.Cut(r.APIVersion, "/"); ok && strings.Contains(group, ".") {
+		deps = append(deps, Dependency{Kind: "CustomResourceDefinition", Defines: group + "/" + r.Kind})
+	}
+
+	var doc orderDoc
+	if err := yaml.Unmarshal(r.Raw, &doc); err != nil {
+		return deps
+	}
+	var spec orderPodSpec
+	switch r.Kind {
+	case "Pod":
+		spec = doc.Spec.orderPodSpec
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job":
+		spec = doc.Spec.Template.Spec
+	case "CronJob":
+		spec = doc.Spec.JobTemplate.Spec.Template.Spec
+	default:
+		return deps
+	}
+
+	seen := make(map[Dependency]bool)
+	add := func(kind, name string) {
+		d := Dependency{Kind: kind, Name: name, Namespace: r.Namespace}
+		if name != "" && !seen[d] {
+			seen[d] = true
+			deps = append(deps, d)
+		}
+	}
+	addRef := func(kind string, ref *orderRef) {
+		if ref != nil && !ref.Optional {
+			add(kind, ref.Name)
+		}
+	}
+	if spec.ServiceAccountName != "default" {
+		add("ServiceAccount", spec.ServiceAccountName)
+	}
+	for _, v := range spec.Volumes {
+		addRef("ConfigMap", v.ConfigMap)
+		if v.Secret != nil && !v.Secret.Optional {
+			add("Secret", v.Secret.SecretName)
+		}
+		if v.PersistentVolumeClaim != nil {
+			add("PersistentVolumeClaim", v.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		for _, e := range c.Env {
+			addRef("ConfigMap", e.ValueFrom.ConfigMapKeyRef)
+			addRef("Secret", e.ValueFrom.SecretKeyRef)
+		}
+		for _, e := range c.EnvFrom {
+			addRef("ConfigMap", e.ConfigMapRef)
+			addRef("Secret", e.SecretRef)
+		}
+	}
+	for i := range spec.ImagePullSecrets {
+		addRef("Secret", &spec.ImagePullSecrets[i])
+	}
+	return deps
+}
+
+// definedKind returns the group/Kind a CustomResourceDefinition defines
+func definedKind(r Resource) string {
+	if r.Kind != "CustomResourceDefinition" {
+		return ""
+	}
+	var doc orderDoc
+	if err := yaml.Unmarshal(r.Raw, &doc); err != nil || doc.Spec.Names.Kind == "" {
+		return ""
+	}
+	return doc.Spec.Group + "/" + doc.Spec.Names.Kind
+}
+
+// KindOrder lists the kinds in the order kubectl applies the resources, which
+// is the order they are read in, counting consecutive resources of a kind,
+// e.g. ["Namespace", "ConfigMap (2)", "Deployment"]
+func KindOrder(resources []Resource) []string {
+	var order []string
+	for i := 0; i < len(resources); {
+		j := i + 1
+		for j < len(resources) && resources[j].Kind == resources[i].Kind {
+			j++
+		}
+		kind := resources[i].Kind
+		if j-i > 1 {
+			kind = fmt.Sprintf("%s (%d)", kind, j-i)
+		}
+		order = append(order, kind)
+		i = j
+	}
+	return order
+}
+
+// OrderReasons describes resources applied before the Namespace they are
+// created in or the CustomResourceDefinition of their kind, when the apply
+// creates those later: kubectl applies resources in the order they are read,
+// so the earlier ones fail
+func OrderReasons(resources []Resource) []string {
+	var reasons []string
+	for i, r := range resources {
+		for _, d := range Dependencies(r) {
+			if !d.fails() || satisfiedWithin(d, resources[:i]) {
+				continue
+			}
+			for _, later := range resources[i+1:] {
+				if d.satisfiedBy(later) {
+					reasons = append(reasons, fmt.Sprintf("apply order: %s %s %s, which comes later: it fails until applied again", r.String(), d.relation(), later.String()))
+					break
+				}
+			}
+		}
+	}
+	return reasons
+}
+
+// ExcludedReasons describes dependencies that none of the resources provide
+// but one of others, read from files left out of the apply, does
+func ExcludedReasons(resources, others []Resource) []string {
+	var reasons []string
+	for _, r := range resources {
+		for _, d := range Dependencies(r) {
+			if satisfiedWithin(d, resources) {
+				continue
+			}
+			for _, other := range others {
+				if !d.satisfiedBy(other) {
+					continue
+				}
+				effect := "its pods cannot start"
+				if d.fails() {
+					effect = "it fails"
+				}
+				reasons = append(reasons, fmt.Sprintf("apply order: %s %s %s, defined only in %s, which this apply leaves out: unless it already exists, %s", r.String(), d.relation(), other.String(), other.Source, effect))
+				break
+			}
+		}
+	}
+	return reasons
+}
+
+// Reference is a dependency of a resource's pod spec
+type Reference struct {
+	From Resource
+	Dependency
+}
+
+// UnresolvedReferences returns the ConfigMaps, Secrets, ServiceAccounts and
+// PersistentVolumeClaims the resources' pod specs need that none of the
+// resources provide, so they must already exist in the cluster
+func UnresolvedReferences(resources []Resource) []Reference {
+	var unresolved []Reference
+	for _, r := range resources {
+		for _, d := range Dependencies(r) {
+			if !d.fails() && !satisfiedWithin(d, resources) {
+				unresolved = append(unresolved, Reference{From: r, Dependency: d})
+			}
+		}
+	}
+	return unresolved
+}
+
+func satisfiedWithin(d Dependency, resources []Resource) bool {
+	for _, r := range resources {
+		if d.satisfiedBy(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Siblings parses the manifest files in the directories of the resources'
+// local source files that none of the resources come from, skipping files
+// that fail to parse
+func Siblings(resources []Resource) []Resource {
+	read := make(map[string]bool)
+	for _, r := range resources {
+		read[filepath.Clean(r.Source)] = true
+	}
+	dirs := make(map[string]bool)
+	var siblings []Resource
+	for _, r := range resources {
+		if r.Source == "" || r.Source == "stdin" || IsURL(r.Source) {
+			continue
+		}
+		dir := filepath.Dir(r.Source)
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			if entry.IsDir() || !isSupportedFile(path) || read[filepath.Clean(path)] {
+				continue
+			}
+			if res, err := ParseFile(path); err == nil {
+				siblings = append(siblings, res...)
+			}
+		}
+	}
+	return siblings
+}
@@ -0,0 +1,123 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDependencies(t *testing.T) {
+	deployment := Resource{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Name:       "web",
+		Namespace:  "shop",
+		Raw:        []byte("spec:\n  template:\n    spec:\n      serviceAccountName: web\n      volumes:\n      - name: config\n        configMap:\n          name: web-config\n      - name: data\n        persistentVolumeClaim:\n          claimName: web-data\n      containers:\n      - name: web\n        envFrom:\n        - secretRef:\n            name: web-env\n        env:\n        - name: MODE\n          valueFrom:\n            configMapKeyRef:\n              name: web-config\n              key: mode\n"),
+	}
+	expected := []Dependency{
+		{Kind: "Namespace", Name: "shop"},
+		{Kind: "ServiceAccount", Name: "web", Namespace: "shop"},
+		{Kind: "ConfigMap", Name: "web-config", Namespace: "shop"},
+		{Kind: "PersistentVolumeClaim", Name: "web-data", Namespace: "shop"},
+		{Kind: "Secret", Name: "web-env", Namespace: "shop"},
+	}
+	if got := Dependencies(deployment); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %+v, expected %+v", got, expected)
+	}
+
+	widget := Resource{APIVersion: "example.com/v1", Kind: "Widget", Name: "w"}
+	expected = []Dependency{{Kind: "CustomResourceDefinition", Defines: "example.com/Widget"}}
+	if got := Dependencies(widget); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %+v, expected %+v", got, expected)
+	}
+}
+
+func TestKindOrder(t *testing.T) {
+	resources := []Resource{{Kind: "Namespace"}, {Kind: "ConfigMap"}, {Kind: "ConfigMap"}, {Kind: "Deployment"}, {Kind: "ConfigMap"}}
+	expected := []string{"Namespace", "ConfigMap (2)", "Deployment", "ConfigMap"}
+	if got := KindOrder(resources); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+}
+
+func TestOrderReasons(t *testing.T) {
+	crd := Resource{Kind: "CustomResourceDefinition", Name: "widgets.example.com", Raw: []byte("spec:\n  group: example.com\n  names:\n    kind: Widget\n    plural: widgets\n")}
+	resources := []Resource{
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "settings", Namespace: "shop"},
+		{Kind: "Namespace", Name: "shop"},
+		{APIVersion: "v1", Kind: "Secret", Name: "token", Namespace: "shop"},
+		{APIVersion: "example.com/v1", Kind: "Widget", Name: "w", Namespace: "shop"},
+		crd,
+	}
+	expected := []string{
+		"apply order: ConfigMap/settings is created in Namespace/shop, which comes later: it fails until applied again",
+		"apply order: Widget/w needs its kind from CustomResourceDefinition/widgets.example.com, which comes later: it fails until applied again",
+	}
+	if got := OrderReasons(resources); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+
+	if got := OrderReasons([]Resource{resources[1], resources[0], crd, resources[3]}); got != nil {
+		t.Errorf("expected no reasons in dependency order, got %v", got)
+	}
+}
+
+func TestExcludedReasons(t *testing.T) {
+	pod := Resource{APIVersion: "v1", Kind: "Pod", Name: "web", Namespace: "shop", Raw: []byte("spec:\n  volumes:\n  - name: config\n    configMap:\n      name: web-config\n  - name: tls\n    secret:\n      secretName: web-tls\n")}
+	others := []Resource{
+		{Kind: "ConfigMap", Name: "web-config", Namespace: "shop", Source: "config.yaml"},
+		{Kind: "Secret", Name: "web-tls", Namespace: "other", Source: "tls.yaml"},
+	}
+	expected := []string{
+		"apply order: Pod/web references ConfigMap/web-config, defined only in config.yaml, which this apply leaves out: unless it already exists, its pods cannot start",
+	}
+	if got := ExcludedReasons([]Resource{pod}, others); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+	if got := ExcludedReasons([]Resource{others[0], pod}, others); got != nil {
+		t.Errorf("expected no reasons when the apply includes the ConfigMap, got %v", got)
+	}
+}
+
+func TestSiblings(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"app.yaml":    "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\n",
+		"config.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web-config\n",
+		"notes.txt":   "not a manifest\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resources, err := ParseFile(filepath.Join(dir, "app.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	siblings := Siblings(resources)
+	if len(siblings) != 1 || siblings[0].String() != "ConfigMap/web-config" {
+		t.Errorf("got %v, expected the ConfigMap from config.yaml", siblings)
+	}
+	if got := Siblings([]Resource{{Kind: "Pod", Name: "web", Source: "stdin"}}); got != nil {
+		t.Errorf("expected no siblings for stdin, got %v", got)
+	}
+}
+
+func TestUnresolvedReferences(t *testing.T) {
+	pod := Resource{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       "web",
+		Namespace:  "shop",
+		Raw:        []byte("spec:\n  imagePullSecrets:\n  - name: registry\n  volumes:\n  - name: config\n    configMap:\n      name: web-config\n  - name: extra\n    secret:\n      secretName: web-extra\n      optional: true\n  containers:\n  - name: web\n    env:\n    - name: TOKEN\n      valueFrom:\n        secretKeyRef:\n          name: web-token\n          key: token\n          optional: true\n"),
+	}
+	resources := []Resource{{Kind: "Namespace", Name: "shop"}, pod, {Kind: "ConfigMap", Name: "web-config", Namespace: "shop"}}
+
+	got := UnresolvedReferences(resources)
+	expected := []Reference{{From: pod, Dependency: Dependency{Kind: "Secret", Name: "registry", Namespace: "shop"}}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %+v, expected %+v", got, expected)
+	}
+}
@@ -0,0 +1,82 @@
+package manifest
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// commitSHA matches a full git commit hash
+var commitSHA = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// Provenance is where a remote manifest comes from, as far as its URL tells
+type Provenance struct {
+	Host string // e.g. github.com
+	Repo string // org/repo; empty when the URL is not a known code host's
+	Ref  string // branch, tag or commit
+	Path string // file path in the repository
+}
+
+// Pinned reports whether the ref is a commit, whose content cannot change
+func (p Provenance) Pinned() bool {
+	return commitSHA.MatchString(p.Ref)
+}
+
+// ProvenanceOf reads the repository, ref and path of a manifest URL on
+// GitHub (github.com blob and raw URLs, raw.githubusercontent.com) or GitLab
+// (gitlab.com /-/blob/ and /-/raw/ URLs). Other URLs only have a host.
+func ProvenanceOf(rawURL string) Provenance {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Provenance{}
+	}
+	p := Provenance{Host: u.Hostname()}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	switch p.Host {
+	case "raw.githubusercontent.com":
+		// /org/repo/ref/path
+		if len(parts) >= 4 {
+			p.Host = "github.com"
+			p.Repo, p.Ref, p.Path = parts[0]+"/"+parts[1], parts[2], strings.Join(parts[3:], "/")
+		}
+	case "github.com":
+		// /org/repo/blob|raw/ref/path
+		if len(parts) >= 5 && (parts[2] == "blob" || parts[2] == "raw") {
+			p.Repo, p.Ref, p.Path = parts[0]+"/"+parts[1], parts[3], strings.Join(parts[4:], "/")
+		}
+	case "gitlab.com":
+		// /group[/subgroup]/repo/-/blob|raw/ref/path
+		for i, part := range parts {
+			if part == "-" && i >= 2 && i+3 < len(parts) && (parts[i+1] == "blob" || parts[i+1] == "raw") {
+				p.Repo, p.Ref, p.Path = strings.Join(parts[:i], "/"), parts[i+2], strings.Join(parts[i+3:], "/")
+				break
+			}
+		}
+	}
+	return p
+}
+
+// RawURL returns the URL of the file itself for a GitHub or GitLab "blob"
+// URL, which serves an HTML page rather than the manifest
+func RawURL(blobURL string) (string, bool) {
+	u, err := url.Parse(blobURL)
+	if err != nil {
+		return "", false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch u.Hostname() {
+	case "github.com":
+		if len(parts) >= 5 && parts[2] == "blob" {
+			return "https://raw.githubusercontent.com/" + parts[0] + "/" + parts[1] + "/" + strings.Join(parts[3:], "/"), true
+		}
+	case "gitlab.com":
+		for i, part := range parts {
+			if part == "-" && i >= 2 && i+3 < len(parts) && parts[i+1] == "blob" {
+				parts[i+1] = "raw"
+				return "https://gitlab.com/" + strings.Join(parts, "/"), true
+			}
+		}
+	}
+	return "", false
+}
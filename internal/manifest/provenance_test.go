@@ -0,0 +1,54 @@
+package manifest
+
+import "testing"
+
+func TestProvenanceOf(t *testing.T) {
+	sha := "3f786850e387550fdab836ed7e6dc881de23001b"
+	tests := []struct {
+		url      string
+		expected Provenance
+		pinned   bool
+	}{
+		{"https://github.com/org/repo/blob/main/deploy/app.yaml", Provenance{"github.com", "org/repo", "main", "deploy/app.yaml"}, false},
+		{"https://github.com/org/repo/raw/v1.2.0/app.yaml", Provenance{"github.com", "org/repo", "v1.2.0", "app.yaml"}, false},
+		{"https://raw.githubusercontent.com/org/repo/" + sha + "/app.yaml", Provenance{"github.com", "org/repo", sha, "app.yaml"}, true},
+		{"https://gitlab.com/group/sub/repo/-/blob/main/k8s/app.yaml", Provenance{"gitlab.com", "group/sub/repo", "main", "k8s/app.yaml"}, false},
+		{"https://example.com/manifests/app.yaml", Provenance{Host: "example.com"}, false},
+		{"https://github.com/org/repo", Provenance{Host: "github.com"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got := ProvenanceOf(tt.url)
+			if got != tt.expected {
+				t.Errorf("got %+v, expected %+v", got, tt.expected)
+			}
+			if got.Pinned() != tt.pinned {
+				t.Errorf("pinned: got %v, expected %v", got.Pinned(), tt.pinned)
+			}
+		})
+	}
+}
+
+func TestRawURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+		ok       bool
+	}{
+		{"https://github.com/org/repo/blob/main/deploy/app.yaml", "https://raw.githubusercontent.com/org/repo/main/deploy/app.yaml", true},
+		{"https://gitlab.com/group/repo/-/blob/v1/app.yaml", "https://gitlab.com/group/repo/-/raw/v1/app.yaml", true},
+		{"https://raw.githubusercontent.com/org/repo/main/app.yaml", "", false},
+		{"https://github.com/org/repo/raw/main/app.yaml", "", false},
+		{"https://example.com/blob/app.yaml", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, ok := RawURL(tt.url)
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("got (%q, %v), expected (%q, %v)", got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// lastAppliedAnnotation marks objects kubectl apply created or updated; only
+// those are pruned
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// defaultPruneKinds are the kinds kubectl apply --prune deletes when no
+// --prune-allowlist is given
+var defaultPruneKinds = []string{
+	"ConfigMap", "Endpoints", "Namespace", "PersistentVolumeClaim", "PersistentVolume",
+	"Pod", "ReplicationController", "Secret", "Service", "Job.batch", "CronJob.batch",
+	"Ingress.networking.k8s.io", "DaemonSet.apps", "Deployment.apps", "ReplicaSet.apps", "StatefulSet.apps",
+}
+
+// PruneKinds returns the kinds kubectl apply --prune deletes, as kubectl get
+// takes them: the allowlist's GROUP/VERSION/KIND entries, or kubectl's
+// default kinds if it is empty
+func PruneKinds(allowlist []string) []string {
+	if len(allowlist) == 0 {
+		return defaultPruneKinds
+	}
+	kinds := make([]string, 0, len(allowlist))
+	for _, gvk := range allowlist {
+		parts := strings.Split(gvk, "/")
+		if len(parts) != 3 {
+			kinds = append(kinds, gvk)
+			continue
+		}
+		group, version, kind := parts[0], parts[1], parts[2]
+		if group == "core" || group == "" {
+			kinds = append(kinds, kind+"."+version)
+			continue
+		}
+		kinds = append(kinds, kind+"."+version+"."+group)
+	}
+	return kinds
+}
+
+// PruneCandidates returns the objects of a kubectl get -o json list that
+// kubectl apply --prune would delete: those last applied with kubectl apply
+// that are not among the applied resources
+func PruneCandidates(list []byte, applied []Resource) ([]Resource, error) {
+	var doc struct {
+		Items []struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name        string            `json:"name"`
+				Namespace   string            `json:"namespace"`
+				Annotations map[string]string `json:"annotations"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(list, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse object list: %w", err)
+	}
+
+	keep := make(map[string]bool, len(applied))
+	for _, r := range applied {
+		keep[r.Kind+"/"+r.Namespace+"/"+r.Name] = true
+	}
+	var pruned []Resource
+	for _, item := range doc.Items {
+		if _, ok := item.Metadata.Annotations[lastAppliedAnnotation]; !ok {
+			continue
+		}
+		if keep[item.Kind+"/"+item.Metadata.Namespace+"/"+item.Metadata.Name] {
+			continue
+		}
+		pruned = append(pruned, Resource{Kind: item.Kind, Name: item.Metadata.Name, Namespace: item.Metadata.Namespace})
+	}
+	return pruned, nil
+}
@@ -0,0 +1,46 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPruneKinds(t *testing.T) {
+	if got := PruneKinds(nil); !reflect.DeepEqual(got, defaultPruneKinds) {
+		t.Errorf("PruneKinds(nil) = %v, expected the default kinds", got)
+	}
+	got := PruneKinds([]string{"core/v1/ConfigMap", "apps/v1/Deployment", "Secret"})
+	expected := []string{"ConfigMap.v1", "Deployment.v1.apps", "Secret"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("PruneKinds() = %v, expected %v", got, expected)
+	}
+}
+
+func TestPruneCandidates(t *testing.T) {
+	list := []byte(`{"kind": "List", "items": [
+		{"kind": "Deployment", "metadata": {"name": "web", "namespace": "shop", "annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{}"}}},
+		{"kind": "Deployment", "metadata": {"name": "web-old", "namespace": "shop", "annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{}"}}},
+		{"kind": "ConfigMap", "metadata": {"name": "web", "namespace": "shop", "annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{}"}}},
+		{"kind": "Secret", "metadata": {"name": "web-tls", "namespace": "shop"}}
+	]}`)
+	applied := []Resource{
+		{Kind: "Deployment", Name: "web", Namespace: "shop"},
+		{Kind: "Service", Name: "web", Namespace: "shop"},
+	}
+
+	got, err := PruneCandidates(list, applied)
+	if err != nil {
+		t.Fatalf("PruneCandidates() error: %v", err)
+	}
+	expected := []Resource{
+		{Kind: "Deployment", Name: "web-old", Namespace: "shop"},
+		{Kind: "ConfigMap", Name: "web", Namespace: "shop"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("PruneCandidates() = %v, expected %v", got, expected)
+	}
+
+	if _, err := PruneCandidates([]byte("not json"), applied); err == nil {
+		t.Error("PruneCandidates() expected an error for invalid JSON")
+	}
+}
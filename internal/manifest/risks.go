@@ -0,0 +1,137 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type riskContainer struct {
+	Name            string `yaml:"name"`
+	Image           string `yaml:"image"`
+	SecurityContext struct {
+		Privileged bool `yaml:"privileged"`
+	} `yaml:"securityContext"`
+	Resources struct {
+		Limits map[string]interface{} `yaml:"limits"`
+	} `yaml:"resources"`
+}
+
+type riskPodSpec struct {
+	HostNetwork bool `yaml:"hostNetwork"`
+	Volumes     []struct {
+		Name     string `yaml:"name"`
+		HostPath *struct {
+			Path string `yaml:"path"`
+		} `yaml:"hostPath"`
+	} `yaml:"volumes"`
+	Containers     []riskContainer `yaml:"containers"`
+	InitContainers []riskContainer `yaml:"initContainers"`
+}
+
+type riskTemplate struct {
+	Spec riskPodSpec `yaml:"spec"`
+}
+
+type riskDoc struct {
+	Metadata struct {
+		OwnerReferences []interface{} `yaml:"ownerReferences"`
+	} `yaml:"metadata"`
+	Spec struct {
+		riskPodSpec `yaml:",inline"`
+		Template    riskTemplate `yaml:"template"`
+		JobTemplate struct {
+			Spec struct {
+				Template riskTemplate `yaml:"template"`
+			} `yaml:"spec"`
+		} `yaml:"jobTemplate"`
+	} `yaml:"spec"`
+}
+
+// podSpec returns the pod spec of a Pod, workload template or CronJob job
+// template, and false for other kinds
+func (d riskDoc) podSpec(kind string) (riskPodSpec, bool) {
+	switch kind {
+	case "Pod":
+		return d.Spec.riskPodSpec, true
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job":
+		return d.Spec.Template.Spec, true
+	case "CronJob":
+		return d.Spec.JobTemplate.Spec.Template.Spec, true
+	}
+	return riskPodSpec{}, false
+}
+
+// Risks lists risky patterns in the pod spec of a Pod, workload template or
+// CronJob job template: a bare Pod without an owning controller, hostPath
+// volumes, hostNetwork, privileged containers, images tagged latest and
+// containers without resource limits. Other kinds have none.
+func Risks(r Resource) ([]string, error) {
+	var doc riskDoc
+	if err := yaml.Unmarshal(r.Raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", r.String(), err)
+	}
+
+	spec, ok := doc.podSpec(r.Kind)
+	if !ok {
+		return nil, nil
+	}
+	var risks []string
+	if r.Kind == "Pod" && len(doc.Metadata.OwnerReferences) == 0 {
+		risks = append(risks, "is a bare Pod: no controller recreates it if it is evicted or its node fails")
+	}
+
+	for _, v := range spec.Volumes {
+		if v.HostPath != nil {
+			risks = append(risks, fmt.Sprintf("mounts hostPath %s as volume %s", v.HostPath.Path, v.Name))
+		}
+	}
+	if spec.HostNetwork {
+		risks = append(risks, "uses hostNetwork")
+	}
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		if c.SecurityContext.Privileged {
+			risks = append(risks, fmt.Sprintf("runs container %s privileged", c.Name))
+		}
+		if IsLatestImage(c.Image) {
+			risks = append(risks, fmt.Sprintf("runs container %s from %s, whose latest tag can change under it", c.Name, c.Image))
+		}
+	}
+	for _, c := range spec.Containers {
+		if len(c.Resources.Limits) == 0 {
+			risks = append(risks, fmt.Sprintf("sets no resource limits on container %s", c.Name))
+		}
+	}
+	return risks, nil
+}
+
+// Images maps each container and init container in the pod spec of a kind
+// to its image. content is a manifest or a live object from `kubectl get -o
+// json`, so the two can be compared. Kinds without a pod spec have none.
+func Images(kind string, content []byte) (map[string]string, error) {
+	var doc riskDoc
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", kind, err)
+	}
+	spec, ok := doc.podSpec(kind)
+	if !ok {
+		return nil, nil
+	}
+	images := make(map[string]string)
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		images[c.Name] = c.Image
+	}
+	return images, nil
+}
+
+// IsLatestImage reports whether an image reference is tagged latest, or has
+// neither a tag nor a digest and so defaults to latest
+func IsLatestImage(image string) bool {
+	if image == "" || strings.Contains(image, "@") {
+		return false
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	_, tag, tagged := strings.Cut(name, ":")
+	return !tagged || tag == "latest"
+}
@@ -0,0 +1,124 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRisks(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		raw      string
+		expected []string
+	}{
+		{
+			name: "bare pod",
+			kind: "Pod",
+			raw:  "spec:\n  hostNetwork: true\n  containers:\n  - name: debug\n    image: busybox\n    securityContext:\n      privileged: true\n",
+			expected: []string{
+				"is a bare Pod: no controller recreates it if it is evicted or its node fails",
+				"uses hostNetwork",
+				"runs container debug privileged",
+				"runs container debug from busybox, whose latest tag can change under it",
+				"sets no resource limits on container debug",
+			},
+		},
+		{
+			name:     "owned pod",
+			kind:     "Pod",
+			raw:      "metadata:\n  ownerReferences:\n  - kind: ReplicaSet\n    name: web-abc\nspec:\n  containers:\n  - name: web\n    image: nginx:1.27\n    resources:\n      limits:\n        memory: 128Mi\n",
+			expected: nil,
+		},
+		{
+			name: "deployment",
+			kind: "Deployment",
+			raw:  "spec:\n  template:\n    spec:\n      volumes:\n      - name: docker\n        hostPath:\n          path: /var/run/docker.sock\n      - name: cache\n        emptyDir: {}\n      containers:\n      - name: web\n        image: registry:5000/web:latest\n",
+			expected: []string{
+				"mounts hostPath /var/run/docker.sock as volume docker",
+				"runs container web from registry:5000/web:latest, whose latest tag can change under it",
+				"sets no resource limits on container web",
+			},
+		},
+		{
+			name:     "cronjob",
+			kind:     "CronJob",
+			raw:      `{"spec":{"jobTemplate":{"spec":{"template":{"spec":{"containers":[{"name":"report","image":"report@sha256:abc","resources":{"limits":{"cpu":"1"}}}]}}}}}}`,
+			expected: nil,
+		},
+		{
+			name:     "not a pod-bearing kind",
+			kind:     "Service",
+			raw:      "spec:\n  hostNetwork: true\n",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Risks(Resource{Kind: tt.kind, Name: "x", Raw: []byte(tt.raw)})
+			if err != nil {
+				t.Fatalf("Risks() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsLatestImage(t *testing.T) {
+	tests := map[string]bool{
+		"nginx":                   true,
+		"nginx:latest":            true,
+		"registry:5000/web":       true,
+		"registry:5000/web:1.2":   false,
+		"nginx:1.27":              false,
+		"nginx@sha256:0123456789": false,
+		"":                        false,
+	}
+	for image, expected := range tests {
+		if got := IsLatestImage(image); got != expected {
+			t.Errorf("IsLatestImage(%q): got %v, expected %v", image, got, expected)
+		}
+	}
+}
+
+func TestImages(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		content  string
+		expected map[string]string
+	}{
+		{
+			name:     "deployment manifest",
+			kind:     "Deployment",
+			content:  "spec:\n  template:\n    spec:\n      initContainers:\n      - name: migrate\n        image: web:2\n      containers:\n      - name: web\n        image: web:2\n      - name: proxy\n        image: envoy:1.30\n",
+			expected: map[string]string{"migrate": "web:2", "web": "web:2", "proxy": "envoy:1.30"},
+		},
+		{
+			name:     "live object as JSON",
+			kind:     "StatefulSet",
+			content:  `{"spec":{"template":{"spec":{"containers":[{"name":"db","image":"postgres:16"}]}}}}`,
+			expected: map[string]string{"db": "postgres:16"},
+		},
+		{
+			name:    "kind without a pod spec",
+			kind:    "Service",
+			content: "spec:\n  ports:\n  - port: 80\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Images(tt.kind, []byte(tt.content))
+			if err != nil {
+				t.Fatalf("Images() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
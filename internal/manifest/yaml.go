@@ -25,8 +25,8 @@ func ParseYAML(content []byte, source string) ([]Resource, error) {
 
 	decoder := yaml.NewDecoder(bytes.NewReader(content))
 	for {
-		var doc kubeResource
-		err := decoder.Decode(&doc)
+		var node yaml.Node
+		err := decoder.Decode(&node)
 		if err == io.EOF {
 			break
 		}
@@ -34,17 +34,28 @@ func ParseYAML(content []byte, source string) ([]Resource, error) {
 			return nil, fmt.Errorf("failed to parse YAML from %s: %w", source, err)
 		}
 
+		var doc kubeResource
+		if err := node.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML from %s: %w", source, err)
+		}
+
 		// Skip empty documents (can happen with --- separators)
 		if doc.Kind == "" {
 			continue
 		}
 
+		raw, err := yaml.Marshal(&node)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-encode YAML from %s: %w", source, err)
+		}
+
 		resources = append(resources, Resource{
 			APIVersion: doc.APIVersion,
 			Kind:       doc.Kind,
 			Name:       doc.Metadata.Name,
 			Namespace:  doc.Metadata.Namespace,
 			Source:     source,
+			Raw:        raw,
 		})
 	}
 
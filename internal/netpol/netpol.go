@@ -0,0 +1,139 @@
+package netpol
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy is the subset of a NetworkPolicy needed to spot lockouts
+type Policy struct {
+	Name           string
+	Namespace      string
+	MatchLabels    map[string]string
+	HasExpressions bool // podSelector uses matchExpressions, so the selected pods are not known
+	IsolateIngress bool
+	IsolateEgress  bool
+	IngressRules   int
+	EgressRules    int
+	EgressAllowDNS bool // some egress rule allows port 53
+}
+
+// SelectsAll reports whether the policy applies to every pod in its namespace
+func (p Policy) SelectsAll() bool {
+	return len(p.MatchLabels) == 0 && !p.HasExpressions
+}
+
+type portDoc struct {
+	Port any `yaml:"port"`
+}
+
+type policyDoc struct {
+	Metadata struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+	Spec struct {
+		PodSelector struct {
+			MatchLabels      map[string]string `yaml:"matchLabels"`
+			MatchExpressions []yaml.Node       `yaml:"matchExpressions"`
+		} `yaml:"podSelector"`
+		PolicyTypes []string    `yaml:"policyTypes"`
+		Ingress     []yaml.Node `yaml:"ingress"`
+		Egress      []struct {
+			Ports []portDoc `yaml:"ports"`
+		} `yaml:"egress"`
+	} `yaml:"spec"`
+}
+
+// Parse parses a NetworkPolicy manifest document or JSON object
+func Parse(content []byte) (Policy, error) {
+	var doc policyDoc
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return Policy{}, fmt.Errorf("failed to parse network policy: %w", err)
+	}
+
+	p := Policy{
+		Name:           doc.Metadata.Name,
+		Namespace:      doc.Metadata.Namespace,
+		MatchLabels:    doc.Spec.PodSelector.MatchLabels,
+		HasExpressions: len(doc.Spec.PodSelector.MatchExpressions) > 0,
+		IngressRules:   len(doc.Spec.Ingress),
+		EgressRules:    len(doc.Spec.Egress),
+	}
+
+	// Without policyTypes, Ingress always applies and Egress only with egress rules
+	if len(doc.Spec.PolicyTypes) == 0 {
+		p.IsolateIngress = true
+		p.IsolateEgress = p.EgressRules > 0
+	}
+	for _, t := range doc.Spec.PolicyTypes {
+		switch t {
+		case "Ingress":
+			p.IsolateIngress = true
+		case "Egress":
+			p.IsolateEgress = true
+		}
+	}
+
+	for _, rule := range doc.Spec.Egress {
+		// A rule without ports allows every port to its peers
+		if len(rule.Ports) == 0 {
+			p.EgressAllowDNS = true
+		}
+		for _, port := range rule.Ports {
+			if port.Port == nil || fmt.Sprint(port.Port) == "53" || fmt.Sprint(port.Port) == "dns" {
+				p.EgressAllowDNS = true
+			}
+		}
+	}
+	return p, nil
+}
+
+// ParseSelector parses a "k=v,k2=v2" label selector
+func ParseSelector(selector string) map[string]string {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(selector, ",") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(pair), "="); ok {
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+// LockoutReasons describes how the policy could cut off connectivity:
+// default-deny policies, egress that blocks DNS, and isolation of pods
+// carrying a critical label selector (e.g. "k8s-app=kube-dns")
+func LockoutReasons(p Policy, criticalSelectors []string) []string {
+	display := "networkpolicy/" + p.Name
+	var reasons []string
+
+	if p.SelectsAll() && p.IsolateIngress && p.IngressRules == 0 {
+		reasons = append(reasons, fmt.Sprintf("%s denies all ingress to every pod in %s", display, p.Namespace))
+	}
+	if p.SelectsAll() && p.IsolateEgress && p.EgressRules == 0 {
+		reasons = append(reasons, fmt.Sprintf("%s denies all egress (including DNS) from every pod in %s", display, p.Namespace))
+	} else if p.IsolateEgress && p.EgressRules > 0 && !p.EgressAllowDNS {
+		reasons = append(reasons, fmt.Sprintf("%s restricts egress without allowing DNS (port 53)", display))
+	}
+
+	if p.IsolateIngress && !p.HasExpressions {
+		for _, selector := range criticalSelectors {
+			if selects(p.MatchLabels, ParseSelector(selector)) {
+				reasons = append(reasons, fmt.Sprintf("%s isolates critical pods %s", display, selector))
+			}
+		}
+	}
+	return reasons
+}
+
+// selects reports whether a matchLabels selector selects pods carrying labels
+func selects(matchLabels, labels map[string]string) bool {
+	for k, v := range matchLabels {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package netpol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLockoutReasons(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		critical []string
+		expected []string
+	}{
+		{
+			name:     "default deny ingress",
+			content:  "metadata:\n  name: deny-all\nspec:\n  podSelector: {}\n",
+			expected: []string{"networkpolicy/deny-all denies all ingress to every pod in kube-system"},
+		},
+		{
+			name:    "default deny both directions",
+			content: "metadata:\n  name: deny-all\nspec:\n  podSelector: {}\n  policyTypes: [Ingress, Egress]\n",
+			expected: []string{
+				"networkpolicy/deny-all denies all ingress to every pod in kube-system",
+				"networkpolicy/deny-all denies all egress (including DNS) from every pod in kube-system",
+			},
+		},
+		{
+			name: "egress without dns",
+			content: `{"metadata":{"name":"db-only"},"spec":{"podSelector":{"matchLabels":{"app":"api"}},
+				"policyTypes":["Egress"],"egress":[{"ports":[{"port":5432}]}]}}`,
+			expected: []string{"networkpolicy/db-only restricts egress without allowing DNS (port 53)"},
+		},
+		{
+			name: "egress with dns",
+			content: `{"metadata":{"name":"db-and-dns"},"spec":{"podSelector":{"matchLabels":{"app":"api"}},
+				"policyTypes":["Egress"],"egress":[{"ports":[{"port":5432}]},{"ports":[{"port":53,"protocol":"UDP"}]}]}}`,
+			expected: nil,
+		},
+		{
+			name:     "isolates critical pods",
+			content:  "metadata:\n  name: dns\nspec:\n  podSelector:\n    matchLabels:\n      k8s-app: kube-dns\n  ingress:\n  - from:\n    - podSelector: {}\n",
+			critical: []string{"k8s-app=kube-dns"},
+			expected: []string{"networkpolicy/dns isolates critical pods k8s-app=kube-dns"},
+		},
+		{
+			name:     "unrelated selector",
+			content:  "metadata:\n  name: web\nspec:\n  podSelector:\n    matchLabels:\n      app: web\n  ingress:\n  - {}\n",
+			critical: []string{"k8s-app=kube-dns"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := Parse([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			policy.Namespace = "kube-system"
+			got := LockoutReasons(policy, tt.critical)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseSelector(t *testing.T) {
+	got := ParseSelector("k8s-app=kube-dns, tier=control-plane")
+	expected := map[string]string{"k8s-app": "kube-dns", "tier": "control-plane"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+}
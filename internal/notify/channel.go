@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds a channel post, which runs after the command
+const requestTimeout = 5 * time.Second
+
+// Event is a dangerous command that ran, as sent to notification channels
+type Event struct {
+	Operation string   `json:"operation"`
+	Resources []string `json:"resources"`
+	Namespace string   `json:"namespace,omitempty"`
+	Cluster   string   `json:"cluster"`
+	Identity  string   `json:"identity,omitempty"`
+	Severity  string   `json:"severity"`
+	Confirmed bool     `json:"confirmed"` // false when it ran without a confirmation, e.g. in warn-only mode
+	Reasons   []string `json:"reasons"`
+	Command   string   `json:"command"`
+	ExitCode  int      `json:"exitCode"`
+	Timestamp string   `json:"timestamp"`
+}
+
+// Text summarizes the event in one line, for chat channels
+func (e Event) Text() string {
+	how := "confirmed"
+	if !e.Confirmed {
+		how = "ran without confirmation"
+	}
+	text := fmt.Sprintf("[%s] %s on %s %s: kubectl %s", e.Severity, e.Operation, e.Cluster, how, e.Command)
+	if e.Identity != "" {
+		text += " (by " + e.Identity + ")"
+	}
+	if e.ExitCode != 0 {
+		text += fmt.Sprintf(", exit code %d", e.ExitCode)
+	}
+	if len(e.Reasons) > 0 {
+		text += "\n• " + strings.Join(e.Reasons, "\n• ")
+	}
+	return text
+}
+
+// Send posts the event to a channel: as a Slack message for "slack", and as
+// the event itself for "webhook"
+func Send(kind, url string, e Event) error {
+	var payload any = e
+	if kind == "slack" {
+		payload = map[string]string{"text": e.Text()}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	resp, err := (&http.Client{Timeout: requestTimeout}).Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to send notification: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+// Package notify builds the desktop notification safekubectl sends when a
+// dangerous command waits for confirmation (`notify.desktop`), and posts
+// dangerous commands that ran to Slack and webhook channels (`notify.channels`).
+package notify
+
+import (
+	"strings"
+)
+
+// Command returns the command that shows a desktop notification on the given
+// operating system: notify-send on Linux and the BSDs, osascript on macOS.
+// It returns nil where there is no such command.
+func Command(goos, title, message string) []string {
+	switch goos {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return []string{"notify-send", "--urgency=critical", "--app-name=safekubectl", title, message}
+	case "darwin":
+		script := "display notification " + appleScriptString(message) + " with title " + appleScriptString(title)
+		return []string{"osascript", "-e", script}
+	default:
+		return nil
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		goos     string
+		expected []string
+	}{
+		{"linux", []string{"notify-send", "--urgency=critical", "--app-name=safekubectl", "Confirm", `delete on "prod"`}},
+		{"darwin", []string{"osascript", "-e", `display notification "delete on \"prod\"" with title "Confirm"`}},
+		{"windows", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			got := Command(tt.goos, "Confirm", `delete on "prod"`)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Command(%q) = %q, expected %q", tt.goos, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSend(t *testing.T) {
+	var received []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, string(body))
+		if r.URL.Path == "/broken" {
+			http.Error(w, "gone", http.StatusGone)
+		}
+	}))
+	defer srv.Close()
+
+	e := Event{Operation: "delete", Cluster: "prod", Severity: "HIGH", Command: "delete pod web", Reasons: []string{"protected cluster: prod"}}
+	if err := Send("slack", srv.URL+"/slack", e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := Send("webhook", srv.URL+"/hook", e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := Send("webhook", srv.URL+"/broken", e); err == nil || !strings.Contains(err.Error(), "410") {
+		t.Errorf("expected the failed post to be reported, got %v", err)
+	}
+
+	expectedSlack := `{"text":"[HIGH] delete on prod ran without confirmation: kubectl delete pod web\n• protected cluster: prod"}`
+	if received[0] != expectedSlack {
+		t.Errorf("slack payload: got %s, expected %s", received[0], expectedSlack)
+	}
+	if !strings.Contains(received[1], `"severity":"HIGH"`) || !strings.Contains(received[1], `"confirmed":false`) {
+		t.Errorf("webhook payload: got %s", received[1])
+	}
+}
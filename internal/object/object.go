@@ -0,0 +1,87 @@
+// Package object summarizes a live object for a delete warning: how old it
+// is, its labels and what owns it.
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Summary is the metadata of a live object worth seeing before deleting it
+type Summary struct {
+	Created time.Time
+	Labels  map[string]string
+	Owners  []string // owner references as Kind/name
+}
+
+// Parse reads the metadata of an object printed by kubectl get -o json
+func Parse(data []byte) (Summary, error) {
+	var doc struct {
+		Metadata struct {
+			CreationTimestamp time.Time         `json:"creationTimestamp"`
+			Labels            map[string]string `json:"labels"`
+			OwnerReferences   []struct {
+				Kind string `json:"kind"`
+				Name string `json:"name"`
+			} `json:"ownerReferences"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Summary{}, fmt.Errorf("failed to parse object: %w", err)
+	}
+	s := Summary{Created: doc.Metadata.CreationTimestamp, Labels: doc.Metadata.Labels}
+	for _, ref := range doc.Metadata.OwnerReferences {
+		s.Owners = append(s.Owners, ref.Kind+"/"+ref.Name)
+	}
+	return s, nil
+}
+
+// Describe summarizes the object as of now, e.g. "created 412 days ago,
+// owned by ReplicaSet/payments-7d9f, labels app=payments"
+func Describe(s Summary, now time.Time) string {
+	var parts []string
+	if !s.Created.IsZero() {
+		parts = append(parts, "created "+Age(now.Sub(s.Created)))
+	}
+	if len(s.Owners) > 0 {
+		parts = append(parts, "owned by "+strings.Join(s.Owners, ", "))
+	}
+	if len(s.Labels) > 0 {
+		labels := make([]string, 0, len(s.Labels))
+		for k, v := range s.Labels {
+			labels = append(labels, k+"="+v)
+		}
+		sort.Strings(labels)
+		parts = append(parts, "labels "+strings.Join(labels, ","))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Age describes how long ago something happened in its largest whole unit
+func Age(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return ago(int(d/time.Minute), "minute")
+	case d < 24*time.Hour:
+		return ago(int(d/time.Hour), "hour")
+	default:
+		return ago(int(d/(24*time.Hour)), "day")
+	}
+}
+
+func ago(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit + " ago"
+	}
+	return fmt.Sprintf("%d %ss ago", n, unit)
+}
+
+// IsNotFound reports whether a kubectl get error says the object does not exist
+func IsNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "(NotFound)")
+}
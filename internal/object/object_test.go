@@ -0,0 +1,55 @@
+package object
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseAndDescribe(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s, err := Parse([]byte(`{"kind":"Pod","metadata":{"name":"payments-7d9f-x2","creationTimestamp":"2024-01-14T10:00:00Z","labels":{"tier":"backend","app":"payments"},"ownerReferences":[{"kind":"ReplicaSet","name":"payments-7d9f"}]}}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	expected := "created 412 days ago, owned by ReplicaSet/payments-7d9f, labels app=payments,tier=backend"
+	if got := Describe(s, now); got != expected {
+		t.Errorf("Describe: got %q, expected %q", got, expected)
+	}
+
+	s, err = Parse([]byte(`{"kind":"ConfigMap","metadata":{"name":"settings"}}`))
+	if err != nil || Describe(s, now) != "" {
+		t.Errorf("bare object: got (%q, %v)", Describe(s, now), err)
+	}
+	if _, err := Parse([]byte(`{`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestAge(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{30 * time.Second, "just now"},
+		{time.Minute, "1 minute ago"},
+		{45 * time.Minute, "45 minutes ago"},
+		{5 * time.Hour, "5 hours ago"},
+		{36 * time.Hour, "1 day ago"},
+		{412 * 24 * time.Hour, "412 days ago"},
+	}
+	for _, tt := range tests {
+		if got := Age(tt.d); got != tt.expected {
+			t.Errorf("Age(%v): got %q, expected %q", tt.d, got, tt.expected)
+		}
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	if !IsNotFound(errors.New(`Error from server (NotFound): pods "web-1" not found`)) {
+		t.Error("expected NotFound error to be recognized")
+	}
+	if IsNotFound(errors.New(`Error from server (Forbidden): pods "web-1" is forbidden`)) || IsNotFound(nil) {
+		t.Error("expected other errors not to be NotFound")
+	}
+}
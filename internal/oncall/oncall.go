@@ -0,0 +1,97 @@
+// Package oncall asks PagerDuty or Opsgenie who is on call for a schedule.
+package oncall
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Supported roster providers
+const (
+	PagerDuty = "pagerduty"
+	Opsgenie  = "opsgenie"
+)
+
+// requestTimeout bounds the roster lookup, which runs before dangerous commands
+const requestTimeout = 10 * time.Second
+
+// apiURLs are the providers' API base URLs, replaced in tests
+var apiURLs = map[string]string{
+	PagerDuty: "https://api.pagerduty.com",
+	Opsgenie:  "https://api.opsgenie.com",
+}
+
+// OnCall returns the emails of the users on call now for a schedule: a
+// schedule ID for PagerDuty, a schedule name for Opsgenie
+func OnCall(provider, token, schedule string) ([]string, error) {
+	switch provider {
+	case PagerDuty:
+		return pagerDutyOnCall(token, schedule)
+	case Opsgenie:
+		return opsgenieOnCall(token, schedule)
+	}
+	return nil, fmt.Errorf("unsupported on-call provider %q", provider)
+}
+
+func pagerDutyOnCall(token, schedule string) ([]string, error) {
+	query := url.Values{"schedule_ids[]": {schedule}, "include[]": {"users"}, "earliest": {"true"}}
+	var body struct {
+		OnCalls []struct {
+			User struct {
+				Email string `json:"email"`
+			} `json:"user"`
+		} `json:"oncalls"`
+	}
+	header := http.Header{
+		"Authorization": {"Token token=" + token},
+		"Accept":        {"application/vnd.pagerduty+json;version=2"},
+	}
+	if err := get(apiURLs[PagerDuty]+"/oncalls?"+query.Encode(), header, &body); err != nil {
+		return nil, err
+	}
+	var emails []string
+	for _, oc := range body.OnCalls {
+		if oc.User.Email != "" {
+			emails = append(emails, oc.User.Email)
+		}
+	}
+	return emails, nil
+}
+
+func opsgenieOnCall(token, schedule string) ([]string, error) {
+	query := url.Values{"scheduleIdentifierType": {"name"}, "flat": {"true"}}
+	var body struct {
+		Data struct {
+			OnCallRecipients []string `json:"onCallRecipients"`
+		} `json:"data"`
+	}
+	header := http.Header{"Authorization": {"GenieKey " + token}}
+	if err := get(apiURLs[Opsgenie]+"/v2/schedules/"+url.PathEscape(schedule)+"/on-calls?"+query.Encode(), header, &body); err != nil {
+		return nil, err
+	}
+	return body.Data.OnCallRecipients, nil
+}
+
+// get fetches a JSON document into v
+func get(u string, header http.Header, v any) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("invalid on-call request: %w", err)
+	}
+	req.Header = header
+	resp, err := (&http.Client{Timeout: requestTimeout}).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch on-call roster: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch on-call roster: %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid on-call roster: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package oncall
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOnCall(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/oncalls" && r.Header.Get("Authorization") == "Token token=pd" && r.URL.Query().Get("schedule_ids[]") == "PSCHED1":
+			w.Write([]byte(`{"oncalls":[{"user":{"email":"alice@example.com"}},{"user":{"email":"bob@example.com"}}]}`))
+		case r.URL.Path == "/v2/schedules/prod team/on-calls" && r.Header.Get("Authorization") == "GenieKey og":
+			w.Write([]byte(`{"data":{"onCallRecipients":["carol@example.com"]}}`))
+		default:
+			http.Error(w, "forbidden", http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+	for provider := range apiURLs {
+		original := apiURLs[provider]
+		apiURLs[provider] = srv.URL
+		t.Cleanup(func() { apiURLs[provider] = original })
+	}
+
+	tests := []struct {
+		name     string
+		provider string
+		token    string
+		schedule string
+		expected []string
+		err      string
+	}{
+		{"pagerduty", PagerDuty, "pd", "PSCHED1", []string{"alice@example.com", "bob@example.com"}, ""},
+		{"opsgenie", Opsgenie, "og", "prod team", []string{"carol@example.com"}, ""},
+		{"rejected token", PagerDuty, "wrong", "PSCHED1", nil, "403"},
+		{"unknown provider", "victorops", "x", "s", nil, "unsupported on-call provider"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OnCall(tt.provider, tt.token, tt.schedule)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
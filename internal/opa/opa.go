@@ -0,0 +1,100 @@
+package opa
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Policy actions, from least to most restrictive
+const (
+	Allow = "allow"
+	Warn  = "warn"
+	Deny  = "deny"
+)
+
+// DefaultQuery is the Rego rule evaluated unless another is configured
+const DefaultQuery = "data.safekubectl.decision"
+
+// Resource is one object a command operates on
+type Resource struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name,omitempty"`      // empty for type-only targets, e.g. delete pods --all
+	Namespace string `json:"namespace,omitempty"` // empty for cluster-scoped kinds
+}
+
+// Input is the document a policy sees as `input`
+type Input struct {
+	Operation        string     `json:"operation"`
+	Subcommand       string     `json:"subcommand,omitempty"`
+	Resources        []Resource `json:"resources"`
+	Namespace        string     `json:"namespace"`
+	Cluster          string     `json:"cluster"`
+	ProtectedCluster bool       `json:"protectedCluster"`
+	AllNamespaces    bool       `json:"allNamespaces"`
+	DryRun           bool       `json:"dryRun"`
+	Flags            []string   `json:"flags"` // flags as given, e.g. "--force", "--grace-period=0"
+	Args             []string   `json:"args"`
+	Dangerous        bool       `json:"dangerous"` // safekubectl's own verdict
+	Reasons          []string   `json:"reasons"`
+}
+
+// Decision is what a policy returns for a command
+type Decision struct {
+	Action   string   `json:"action"` // allow | warn | deny
+	Messages []string `json:"messages"`
+}
+
+// EvalArgs returns the `opa eval` arguments that evaluate query against the
+// policy files or bundle at path, reading the input from stdin
+func EvalArgs(path, query string) []string {
+	if query == "" {
+		query = DefaultQuery
+	}
+	return []string{"eval", "--format", "json", "--stdin-input", "--data", path, query}
+}
+
+// Flags returns the flags in kubectl arguments, ahead of any "--" separator
+func Flags(args []string) []string {
+	flags := []string{}
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+		}
+	}
+	return flags
+}
+
+// ParseDecision reads the decision from `opa eval --format json` output.
+// An undefined decision allows the command.
+func ParseDecision(data []byte) (Decision, error) {
+	var out struct {
+		Result []struct {
+			Expressions []struct {
+				Value json.RawMessage `json:"value"`
+			} `json:"expressions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return Decision{}, fmt.Errorf("failed to parse opa output: %w", err)
+	}
+	if len(out.Result) == 0 || len(out.Result[0].Expressions) == 0 {
+		return Decision{Action: Allow}, nil
+	}
+
+	var d Decision
+	if err := json.Unmarshal(out.Result[0].Expressions[0].Value, &d); err != nil {
+		return Decision{}, fmt.Errorf("policy decision must be an object with action and messages: %w", err)
+	}
+	switch d.Action {
+	case "":
+		d.Action = Allow
+	case Allow, Warn, Deny:
+	default:
+		return Decision{}, fmt.Errorf("invalid policy action %q: expected %q, %q or %q", d.Action, Allow, Warn, Deny)
+	}
+	return d, nil
+}
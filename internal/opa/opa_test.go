@@ -0,0 +1,88 @@
+package opa
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDecision(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      string
+		expected    Decision
+		expectedErr string
+	}{
+		{
+			name:     "deny with messages",
+			output:   `{"result":[{"expressions":[{"value":{"action":"deny","messages":["no deletes on Fridays"]},"text":"data.safekubectl.decision"}]}]}`,
+			expected: Decision{Action: Deny, Messages: []string{"no deletes on Fridays"}},
+		},
+		{
+			name:     "warn",
+			output:   `{"result":[{"expressions":[{"value":{"action":"warn","messages":["a","b"]}}]}]}`,
+			expected: Decision{Action: Warn, Messages: []string{"a", "b"}},
+		},
+		{
+			name:     "undefined decision allows",
+			output:   `{}`,
+			expected: Decision{Action: Allow},
+		},
+		{
+			name:     "missing action allows",
+			output:   `{"result":[{"expressions":[{"value":{"messages":["fyi"]}}]}]}`,
+			expected: Decision{Action: Allow, Messages: []string{"fyi"}},
+		},
+		{
+			name:        "unknown action",
+			output:      `{"result":[{"expressions":[{"value":{"action":"maybe"}}]}]}`,
+			expectedErr: `invalid policy action "maybe"`,
+		},
+		{
+			name:        "decision is not an object",
+			output:      `{"result":[{"expressions":[{"value":true}]}]}`,
+			expectedErr: "policy decision must be an object",
+		},
+		{
+			name:        "not JSON",
+			output:      `error: undefined ref`,
+			expectedErr: "failed to parse opa output",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDecision([]byte(tt.output))
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Errorf("error: got %v, expected %q", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %+v, expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEvalArgs(t *testing.T) {
+	expected := []string{"eval", "--format", "json", "--stdin-input", "--data", "/etc/policy", DefaultQuery}
+	if got := EvalArgs("/etc/policy", ""); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+	if got := EvalArgs("p.rego", "data.org.kubectl"); got[len(got)-1] != "data.org.kubectl" {
+		t.Errorf("expected custom query last, got %v", got)
+	}
+}
+
+func TestFlags(t *testing.T) {
+	got := Flags([]string{"delete", "pod", "web", "--force", "--grace-period=0", "-n", "prod", "--", "-x"})
+	expected := []string{"--force", "--grace-period=0", "-n"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"strings"
+)
+
+// kubectlCommands are kubectl's own commands; kubectl runs any other command
+// as a plugin
+var kubectlCommands = map[string]bool{
+	"create": true, "expose": true, "run": true, "set": true, "explain": true,
+	"get": true, "edit": true, "delete": true, "rollout": true, "scale": true,
+	"autoscale": true, "certificate": true, "cluster-info": true, "top": true,
+	"cordon": true, "uncordon": true, "drain": true, "taint": true,
+	"describe": true, "logs": true, "attach": true, "exec": true,
+	"port-forward": true, "proxy": true, "cp": true, "auth": true,
+	"debug": true, "events": true, "diff": true, "apply": true, "patch": true,
+	"replace": true, "wait": true, "kustomize": true, "label": true,
+	"annotate": true, "completion": true, "alpha": true, "api-resources": true,
+	"api-versions": true, "config": true, "plugin": true, "version": true,
+	"options": true, "help": true,
+}
+
+// IsKubectlCommand reports whether an operation is one of kubectl's own
+// commands rather than a plugin
+func IsKubectlCommand(operation string) bool {
+	return kubectlCommands[operation]
+}
+
+// booleanFlags are common kubectl flags that take no value, or only one
+// joined with "="; the parser knows the next argument is not theirs
+var booleanFlags = map[string]bool{
+	"-A": true, "--all-namespaces": true, "-R": true, "--recursive": true,
+	"--dry-run": true, "--all": true, "--force": true, "--now": true,
+	"--wait": true, "--overwrite": true, "--local": true, "--record": true,
+	"--server-side": true, "--force-conflicts": true, "--prune": true,
+	"--validate": true, "--cascade": true, "--save-config": true,
+	"--ignore-not-found": true, "--ignore-daemonsets": true,
+	"--delete-emptydir-data": true, "--delete-local-data": true,
+	"--disable-eviction": true, "-i": true, "--stdin": true, "-t": true,
+	"--tty": true, "-it": true, "-ti": true, "-w": true, "--watch": true,
+	"--watch-only": true, "--show-labels": true, "--no-headers": true,
+	"--show-kind": true, "--show-managed-fields": true, "-q": true,
+	"--quiet": true, "--previous": true, "--timestamps": true,
+	"--follow": true, "--all-containers": true, "--prefix": true,
+	"--insecure-skip-tls-verify": true, "--help": true, "-h": true,
+	"--rm": true, "--attach": true, "--keep-annotations": true,
+	"--include-uninitialized": true, "--allow-missing-template-keys": true,
+}
+
+// Gaps returns what the parser does not understand about the command: an
+// operation kubectl does not have (a plugin), a subcommand it does not know,
+// and flags it cannot tell take a value, so that the value may have been
+// taken for a target. Flag values are left out.
+func (k *KubectlCommand) Gaps() []string {
+	if k.Operation == "" {
+		return nil
+	}
+	if !IsKubectlCommand(k.Operation) {
+		return []string{"operation " + k.Operation}
+	}
+
+	var gaps []string
+	if len(operationsWithSubcommands[k.Operation]) > 0 && k.Subcommand == "" && len(k.Positionals) > 0 {
+		gaps = append(gaps, "subcommand "+k.Operation+" "+k.Positionals[0])
+	}
+	seen := make(map[string]bool)
+	for i := 0; i < len(k.Args); i++ {
+		arg := k.Args[i]
+		switch {
+		case arg == "--":
+			return gaps
+		case arg == "-" || !strings.HasPrefix(arg, "-") || strings.Contains(arg, "="):
+			continue
+		case needsValue(arg):
+			i++
+		case !booleanFlags[arg] && !seen[arg]:
+			seen[arg] = true
+			gaps = append(gaps, "flag "+arg)
+		}
+	}
+	return gaps
+}
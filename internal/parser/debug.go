@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"strings"
+)
+
+// Debug describes a kubectl debug command: what it attaches to and what runs there
+type Debug struct {
+	Target    string `json:"target"`              // node/NAME or pod/NAME
+	Container string `json:"container,omitempty"` // container whose processes an ephemeral container shares (--target)
+	Image     string `json:"image,omitempty"`     // image of the debug container (--image)
+	Profile   string `json:"profile,omitempty"`   // security profile (--profile), e.g. sysadmin
+	CopyTo    string `json:"copyTo,omitempty"`    // name of the pod copy debugged instead (--copy-to)
+}
+
+// IsNode reports whether the command debugs a node, which runs a pod in the
+// node's host namespaces with its root filesystem mounted
+func (d Debug) IsNode() bool {
+	kind, _, _ := strings.Cut(d.Target, "/")
+	return kind == "node"
+}
+
+// DebugOptions returns what a debug command attaches to and runs, or nil for
+// other operations. A bare NAME names a pod, as it does for kubectl.
+func (k *KubectlCommand) DebugOptions() *Debug {
+	if k.Operation != "debug" || len(k.Targets) == 0 {
+		return nil
+	}
+	t := k.Targets[0]
+	d := &Debug{
+		Container: lastFlagValue(k.Args, "--target"),
+		Image:     lastFlagValue(k.Args, "--image"),
+		Profile:   lastFlagValue(k.Args, "--profile"),
+		CopyTo:    lastFlagValue(k.Args, "--copy-to"),
+	}
+	switch kind := KindFor(t.Resource); {
+	case t.Name == "":
+		d.Target = "pod/" + t.Resource
+	case kind == "Node":
+		d.Target = "node/" + t.Name
+	case kind == "Pod":
+		d.Target = "pod/" + t.Name
+	default:
+		d.Target = t.Resource + "/" + t.Name
+	}
+	return d
+}
+
+// lastFlagValue returns the last value of a flag given as "flag value" or
+// "flag=value" ahead of any "--" separator, or "" if it is not given
+func lastFlagValue(args []string, flag string) string {
+	value := ""
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return value
+		case arg == flag && i+1 < len(args):
+			value = args[i+1]
+		case strings.HasPrefix(arg, flag+"="):
+			value = strings.TrimPrefix(arg, flag+"=")
+		}
+	}
+	return value
+}
@@ -13,14 +13,18 @@ type Target struct {
 // KubectlCommand represents a parsed kubectl command
 type KubectlCommand struct {
 	Operation     string   // e.g., delete, apply, get
+	Subcommand    string   // e.g., restart for rollout restart; empty if none
 	Targets       []Target // all positional targets (resource type + optional name)
+	Positionals   []string // non-flag args after the operation, including label/taint specs
 	Namespace     string   // from -n or --namespace flag
 	Context       string   // from --context flag
+	Kubeconfig    string   // from --kubeconfig flag; empty means KUBECONFIG or ~/.kube/config
 	Args          []string // original arguments
 	FileInputs    []string // paths/URLs from -f/--filename flags
 	Recursive     bool     // -R/--recursive flag present
 	AllNamespaces bool     // --all-namespaces or -A flag present
 	DryRun        bool     // --dry-run flag present
+	Cascade       string   // from --cascade: background, foreground or orphan; empty if not given
 }
 
 // Node-scoped operations that don't have a namespace
@@ -47,7 +51,7 @@ var fileInputOperations = map[string]bool{
 // Operations with subcommands (operation + subcommand + resource)
 var operationsWithSubcommands = map[string][]string{
 	"rollout": {"restart", "status", "undo", "history", "pause", "resume"},
-	"config":  {"view", "use-context", "set-context", "delete-context", "get-contexts", "current-context"},
+	"config":  {"view", "use-context", "set-context", "delete-context", "get-contexts", "current-context", "get-clusters", "get-users", "set-cluster", "set-credentials", "delete-cluster", "delete-user", "rename-context", "set", "unset"},
 	"set":     {"image", "env", "resources", "selector", "serviceaccount", "subject"},
 }
 
@@ -108,6 +112,13 @@ func Parse(args []string) *KubectlCommand {
 			continue
 		}
 
+		// Handle cascade flag
+		if args[i] == "--cascade" || strings.HasPrefix(args[i], "--cascade=") {
+			cmd.Cascade = cascadeMode(args[i])
+			i++
+			continue
+		}
+
 		// Handle dry-run flag
 		if args[i] == "--dry-run" || strings.HasPrefix(args[i], "--dry-run=") {
 			cmd.DryRun = true
@@ -125,6 +136,8 @@ func Parse(args []string) *KubectlCommand {
 				cmd.Namespace = strings.TrimPrefix(args[i], "--namespace=")
 			} else if strings.HasPrefix(args[i], "--context=") {
 				cmd.Context = strings.TrimPrefix(args[i], "--context=")
+			} else if strings.HasPrefix(args[i], "--kubeconfig=") {
+				cmd.Kubeconfig = strings.TrimPrefix(args[i], "--kubeconfig=")
 			}
 			i++
 		} else if needsValue(args[i]) && i+1 < len(args) {
@@ -136,6 +149,10 @@ func Parse(args []string) *KubectlCommand {
 			if args[i] == "--context" {
 				cmd.Context = args[i+1]
 			}
+			// Check for kubeconfig flag
+			if args[i] == "--kubeconfig" {
+				cmd.Kubeconfig = args[i+1]
+			}
 			i += 2
 		} else {
 			i++
@@ -154,6 +171,7 @@ func Parse(args []string) *KubectlCommand {
 		nextArg := args[i]
 		for _, sub := range subcommands {
 			if nextArg == sub {
+				cmd.Subcommand = sub
 				i++ // Skip the subcommand
 				break
 			}
@@ -203,6 +221,13 @@ func Parse(args []string) *KubectlCommand {
 			continue
 		}
 
+		// Handle cascade flag
+		if arg == "--cascade" || strings.HasPrefix(arg, "--cascade=") {
+			cmd.Cascade = cascadeMode(arg)
+			i++
+			continue
+		}
+
 		// Handle dry-run flag
 		if arg == "--dry-run" || strings.HasPrefix(arg, "--dry-run=") {
 			cmd.DryRun = true
@@ -240,6 +265,19 @@ func Parse(args []string) *KubectlCommand {
 			continue
 		}
 
+		// Handle kubeconfig flag anywhere in args
+		if arg == "--kubeconfig" {
+			if i+1 < len(args) {
+				cmd.Kubeconfig = args[i+1]
+				i += 2
+				continue
+			}
+		} else if strings.HasPrefix(arg, "--kubeconfig=") {
+			cmd.Kubeconfig = strings.TrimPrefix(arg, "--kubeconfig=")
+			i++
+			continue
+		}
+
 		// Skip other flags
 		if strings.HasPrefix(arg, "-") {
 			// If flag contains =, value is already embedded, don't skip next arg
@@ -258,13 +296,40 @@ func Parse(args []string) *KubectlCommand {
 		i++
 	}
 
-	cmd.Targets = buildTargets(positionals)
+	cmd.Positionals = positionals
+	// config commands name kubeconfig entries, not objects in the cluster
+	if operation != "config" {
+		cmd.Targets = buildTargets(positionals)
+	}
 
 	return cmd
 }
 
+// cascadeMode returns the deletion propagation a --cascade flag selects. Like
+// kubectl, a bare --cascade means background and takes no separate value, and
+// the deprecated true and false mean background and orphan.
+func cascadeMode(flag string) string {
+	value, ok := strings.CutPrefix(flag, "--cascade=")
+	switch {
+	case !ok, value == "true":
+		return "background"
+	case value == "false":
+		return "orphan"
+	}
+	return value
+}
+
 // findOperation scans args to find the operation (first non-flag argument)
 func findOperation(args []string) string {
+	if i := OperationIndex(args); i >= 0 {
+		return args[i]
+	}
+	return ""
+}
+
+// OperationIndex returns the index of the operation in args, skipping global
+// flags and their values, or -1 if there is none
+func OperationIndex(args []string) int {
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
 		if strings.HasPrefix(arg, "-") {
@@ -278,9 +343,9 @@ func findOperation(args []string) string {
 			}
 			continue
 		}
-		return arg
+		return i
 	}
-	return ""
+	return -1
 }
 
 // needsValue returns true if the flag requires a value
@@ -296,6 +361,10 @@ func needsValue(flag string) bool {
 		"--cluster",
 		"--user",
 		"--kubeconfig",
+		"--kuberc",
+		"--as",
+		"--as-group",
+		"--as-uid",
 		"-c", "--container",
 		"--field-selector",
 		"--sort-by",
@@ -310,8 +379,15 @@ func needsValue(flag string) bool {
 		"--limit-bytes",
 		"--address",
 		"--image",
+		"--profile",
+		"--target",
+		"--copy-to",
+		"--custom",
 		"--replicas",
 		"--for",
+		"--prune-allowlist",
+		"--prune-whitelist",
+		"--applyset",
 	}
 
 	// Strip = suffix if present
@@ -338,14 +414,56 @@ func (k *KubectlCommand) IsNodeScoped() bool {
 	return nodeScopedOperations[k.Operation]
 }
 
+// SwitchedContext returns the context kubectl config use-context makes
+// current, or "" for other commands
+func (k *KubectlCommand) SwitchedContext() string {
+	if k.Operation != "config" || k.Subcommand != "use-context" || len(k.Positionals) == 0 {
+		return ""
+	}
+	return k.Positionals[0]
+}
+
+// IsForceReplace reports whether the command is replace --force, which
+// deletes its objects and creates them again rather than updating them
+func (k *KubectlCommand) IsForceReplace() bool {
+	return k.Operation == "replace" && hasBoolFlag(k.Args, "--force")
+}
+
+// NodeNames returns the nodes a command names: the NODE or node/NODE
+// arguments of cordon, uncordon and drain, and node targets of other
+// operations such as taint or delete
+func (k *KubectlCommand) NodeNames() []string {
+	var nodes []string
+	switch k.Operation {
+	case "cordon", "uncordon", "drain":
+		for _, arg := range k.Positionals {
+			if kind, name, ok := strings.Cut(arg, "/"); ok {
+				if KindFor(kind) == "Node" && name != "" {
+					nodes = append(nodes, name)
+				}
+				continue
+			}
+			nodes = append(nodes, arg)
+		}
+		return nodes
+	}
+	for _, t := range k.Targets {
+		if t.Name != "" && KindFor(t.Resource) == "Node" {
+			nodes = append(nodes, t.Name)
+		}
+	}
+	return nodes
+}
+
 // buildTargets interprets positional args using kubectl's rules:
 // slash-form (TYPE/NAME ...) or type-spec form (TYPE[,TYPE...] [NAME ...]).
 // Args containing "=" are never targets (taint specs, env vars, set image
-// pairs) and are ignored.
+// pairs) and are ignored, as are "key-" removals (label, annotate, taint):
+// object names cannot end in "-".
 func buildTargets(positionals []string) []Target {
 	var targetArgs []string
 	for _, arg := range positionals {
-		if strings.Contains(arg, "=") {
+		if strings.Contains(arg, "=") || strings.HasSuffix(arg, "-") {
 			continue
 		}
 		targetArgs = append(targetArgs, arg)
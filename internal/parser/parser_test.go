@@ -261,6 +261,27 @@ func TestParse(t *testing.T) {
 				Args:      []string{"delete", "pod", "nginx", "-n", "production", "--context", "prod-cluster"},
 			},
 		},
+		{
+			name: "with kubeconfig flag before operation",
+			args: []string{"--kubeconfig", "/tmp/prod.yaml", "delete", "pod", "nginx"},
+			expected: &KubectlCommand{
+				Operation:  "delete",
+				Targets:    []Target{{Resource: "pod", Name: "nginx"}},
+				Kubeconfig: "/tmp/prod.yaml",
+				Args:       []string{"--kubeconfig", "/tmp/prod.yaml", "delete", "pod", "nginx"},
+			},
+		},
+		{
+			name: "with kubeconfig= flag after operation",
+			args: []string{"delete", "pod", "nginx", "--kubeconfig=/tmp/prod.yaml", "--context", "prod-cluster"},
+			expected: &KubectlCommand{
+				Operation:  "delete",
+				Targets:    []Target{{Resource: "pod", Name: "nginx"}},
+				Context:    "prod-cluster",
+				Kubeconfig: "/tmp/prod.yaml",
+				Args:       []string{"delete", "pod", "nginx", "--kubeconfig=/tmp/prod.yaml", "--context", "prod-cluster"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -283,6 +304,10 @@ func TestParse(t *testing.T) {
 				t.Errorf("Context: got %q, expected %q", result.Context, tt.expected.Context)
 			}
 
+			if result.Kubeconfig != tt.expected.Kubeconfig {
+				t.Errorf("Kubeconfig: got %q, expected %q", result.Kubeconfig, tt.expected.Kubeconfig)
+			}
+
 			if !reflect.DeepEqual(result.Args, tt.expected.Args) {
 				t.Errorf("Args: got %v, expected %v", result.Args, tt.expected.Args)
 			}
@@ -371,6 +396,53 @@ func TestIsNodeScoped(t *testing.T) {
 	}
 }
 
+func TestSwitchedContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"use-context", []string{"config", "use-context", "prod"}, "prod"},
+		{"with kubeconfig", []string{"--kubeconfig", "k", "config", "use-context", "prod"}, "prod"},
+		{"other config subcommand", []string{"config", "delete-context", "prod"}, ""},
+		{"missing context", []string{"config", "use-context"}, ""},
+		{"not config", []string{"get", "pods"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := Parse(tt.args)
+			if got := cmd.SwitchedContext(); got != tt.expected {
+				t.Errorf("SwitchedContext() = %q, expected %q", got, tt.expected)
+			}
+			if cmd.Operation == "config" && len(cmd.Targets) != 0 {
+				t.Errorf("Targets = %v, expected none for config commands", cmd.Targets)
+			}
+		})
+	}
+}
+
+func TestIsForceReplace(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected bool
+	}{
+		{"force", []string{"replace", "--force", "-f", "app.yaml"}, true},
+		{"force=true", []string{"replace", "-f", "app.yaml", "--force=true"}, true},
+		{"no force", []string{"replace", "-f", "app.yaml"}, false},
+		{"delete --force", []string{"delete", "pod", "web", "--force"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.args).IsForceReplace(); got != tt.expected {
+				t.Errorf("IsForceReplace() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestNeedsValue(t *testing.T) {
 	tests := []struct {
 		flag     string
@@ -393,6 +465,8 @@ func TestNeedsValue(t *testing.T) {
 		{"--type", true},
 		{"--timeout", true},
 		{"--grace-period", true},
+		{"--as", true},
+		{"--as-group", true},
 		{"--all", false},
 		{"--force", false},
 		{"--ignore-daemonsets", false},
@@ -673,24 +747,28 @@ func TestSetCommandSubcommands(t *testing.T) {
 	tests := []struct {
 		name             string
 		args             []string
+		expectedSub      string
 		expectedResource string
 		expectedName     string
 	}{
 		{
 			name:             "set image deployment/nginx",
 			args:             []string{"set", "image", "deployment/nginx", "nginx=nginx:1.16"},
+			expectedSub:      "image",
 			expectedResource: "deployment",
 			expectedName:     "nginx",
 		},
 		{
 			name:             "set env deployment nginx",
 			args:             []string{"set", "env", "deployment", "nginx", "DEBUG=true"},
+			expectedSub:      "env",
 			expectedResource: "deployment",
 			expectedName:     "nginx",
 		},
 		{
 			name:             "set resources deployment/nginx",
 			args:             []string{"set", "resources", "deployment/nginx", "--limits=cpu=200m"},
+			expectedSub:      "resources",
 			expectedResource: "deployment",
 			expectedName:     "nginx",
 		},
@@ -703,6 +781,9 @@ func TestSetCommandSubcommands(t *testing.T) {
 			if result.Operation != "set" {
 				t.Errorf("Operation = %q, expected %q", result.Operation, "set")
 			}
+			if result.Subcommand != tt.expectedSub {
+				t.Errorf("Subcommand = %q, expected %q", result.Subcommand, tt.expectedSub)
+			}
 			if firstTarget(result).Resource != tt.expectedResource {
 				t.Errorf("Resource = %q, expected %q", firstTarget(result).Resource, tt.expectedResource)
 			}
@@ -789,6 +870,13 @@ func TestParseMultipleTargets(t *testing.T) {
 				{Resource: "secret", Name: "c"},
 			},
 		},
+		{
+			name: "label removal is not a target",
+			args: []string{"label", "ns", "prod", "pod-security.kubernetes.io/enforce-"},
+			expected: []Target{
+				{Resource: "ns", Name: "prod"},
+			},
+		},
 		{
 			name: "comma-separated types without names",
 			args: []string{"delete", "pods,services", "-l", "app=nginx"},
@@ -954,3 +1042,200 @@ func TestParseFileInputs(t *testing.T) {
 		})
 	}
 }
+
+func TestKindFor(t *testing.T) {
+	tests := []struct {
+		resource string
+		expected string
+	}{
+		{"crd", "CustomResourceDefinition"},
+		{"customresourcedefinitions", "CustomResourceDefinition"},
+		{"CustomResourceDefinition", "CustomResourceDefinition"},
+		{"deploy", "Deployment"},
+		{"deployments.apps", "Deployment"},
+		{"pv", "PersistentVolume"},
+		{"ns", "Namespace"},
+		{"widgets", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.resource, func(t *testing.T) {
+			if got := KindFor(tt.resource); got != tt.expected {
+				t.Errorf("KindFor(%q) = %q, expected %q", tt.resource, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsClusterScoped(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected bool
+	}{
+		{"clusterrole", []string{"delete", "clusterrole", "admin"}, true},
+		{"crd slash form", []string{"delete", "crd/widgets.example.com"}, true},
+		{"storageclass and pv", []string{"delete", "sc,pv", "x"}, true},
+		{"pod", []string{"delete", "pod", "nginx"}, false},
+		{"mixed", []string{"delete", "pv/a", "pod/b"}, false},
+		{"no targets", []string{"apply", "-f", "x.yaml"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.args).IsClusterScoped(); got != tt.expected {
+				t.Errorf("IsClusterScoped() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNodeNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{"drain", []string{"drain", "node-1", "--ignore-daemonsets"}, []string{"node-1"}},
+		{"cordon several", []string{"cordon", "node-1", "node/node-2"}, []string{"node-1", "node-2"}},
+		{"taint", []string{"taint", "nodes", "node-1", "dedicated=gpu:NoSchedule"}, []string{"node-1"}},
+		{"delete", []string{"delete", "no", "node-1", "node-2"}, []string{"node-1", "node-2"}},
+		{"label other kind", []string{"label", "pod", "web", "tier=frontend"}, nil},
+		{"selector", []string{"drain", "-l", "pool=blue"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.args).NodeNames(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("NodeNames() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDebugOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected *Debug
+	}{
+		{"node", []string{"debug", "node/worker-1", "-it", "--image=busybox"}, &Debug{Target: "node/worker-1", Image: "busybox"}},
+		{"node with sysadmin profile", []string{"debug", "nodes", "worker-1", "--profile", "sysadmin", "--image", "ubuntu"}, &Debug{Target: "node/worker-1", Image: "ubuntu", Profile: "sysadmin"}},
+		{"bare pod name", []string{"debug", "web-0", "-n", "shop", "-it", "--image=busybox", "--target=web", "--", "sh"}, &Debug{Target: "pod/web-0", Container: "web", Image: "busybox"}},
+		{"pod copy", []string{"debug", "pod/web-0", "--copy-to=web-debug", "--", "sh", "--image=ignored"}, &Debug{Target: "pod/web-0", CopyTo: "web-debug"}},
+		{"other operation", []string{"exec", "web-0", "--", "sh"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.args).DebugOptions(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("DebugOptions() = %+v, expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSameResource(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"deployment", "deploy", true},
+		{"Deployment", "deployments.apps", true},
+		{"rc", "ReplicationController", true},
+		{"ns", "Namespace", true},
+		{"Certificate", "certificates.cert-manager.io", true},
+		{"NetworkPolicy", "networkpolicies", true},
+		{"ClusterIssuer", "clusterissuers", true},
+		{"IngressClass", "ingressclasses", true},
+		{"deploy", "statefulset", false},
+		{"Certificate", "CertificateRequest", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := SameResource(tt.a, tt.b); got != tt.expected {
+				t.Errorf("SameResource(%q, %q) = %v, expected %v", tt.a, tt.b, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGaps(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{"understood", []string{"delete", "pod", "web", "-n", "shop", "--force", "--grace-period", "0"}, nil},
+		{"unknown flag", []string{"delete", "deployment", "web", "--cascade", "orphan", "--applyset=x"}, nil},
+		{"unknown bare flag", []string{"apply", "-f", "app.yaml", "--field-manager", "ci", "--field-manager", "deploy"}, []string{"flag --field-manager"}},
+		{"plugin", []string{"node-shell", "worker-1", "--x"}, []string{"operation node-shell"}},
+		{"unknown subcommand", []string{"rollout", "frobnicate", "deployment/web"}, []string{"subcommand rollout frobnicate"}},
+		{"flags after --", []string{"exec", "web", "--", "ls", "--color"}, nil},
+		{"no operation", []string{"--help"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.args).Gaps(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Gaps() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseCascade(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"delete", "deployment", "web", "--cascade=orphan"}, "orphan"},
+		{[]string{"delete", "--cascade=foreground", "-f", "app.yaml"}, "foreground"},
+		{[]string{"delete", "deployment", "web", "--cascade"}, "background"},
+		{[]string{"delete", "deployment", "web", "--cascade=false"}, "orphan"},
+		{[]string{"delete", "deployment", "web", "--cascade=true"}, "background"},
+		{[]string{"delete", "deployment", "web"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.args[len(tt.args)-1], func(t *testing.T) {
+			cmd := Parse(tt.args)
+			if cmd.Cascade != tt.expected {
+				t.Errorf("Cascade = %q, expected %q", cmd.Cascade, tt.expected)
+			}
+			if !reflect.DeepEqual(cmd.GetResourceDisplays(), []string{"deployment/web"}) && len(cmd.FileInputs) == 0 {
+				t.Errorf("expected --cascade not to change the targets, got %v", cmd.GetResourceDisplays())
+			}
+		})
+	}
+}
+
+func TestPruneOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected *Prune
+	}{
+		{"no prune", []string{"apply", "-f", "app.yaml", "-l", "app=web"}, nil},
+		{"not apply", []string{"delete", "-f", "app.yaml", "--prune"}, nil},
+		{"selector", []string{"apply", "-f", "app.yaml", "--prune", "-l", "app=web"}, &Prune{Selector: "app=web"}},
+		{"all", []string{"apply", "--prune", "--all", "-f", "manifests/"}, &Prune{All: true}},
+		{"allowlist", []string{"apply", "-f", "app.yaml", "--prune", "--selector=app=web", "--prune-allowlist", "core/v1/ConfigMap", "--prune-whitelist=apps/v1/Deployment"},
+			&Prune{Selector: "app=web", Allowlist: []string{"core/v1/ConfigMap", "apps/v1/Deployment"}}},
+		{"applyset", []string{"apply", "-f", "app.yaml", "--prune", "--applyset=configmap/web-set"}, &Prune{ApplySet: "configmap/web-set"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := Parse(tt.args)
+			if got := cmd.PruneOptions(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("PruneOptions() = %+v, expected %+v", got, tt.expected)
+			}
+			if len(cmd.Targets) != 0 {
+				t.Errorf("Targets = %v, expected none", cmd.Targets)
+			}
+		})
+	}
+}
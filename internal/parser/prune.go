@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"strings"
+)
+
+// Prune describes what kubectl apply --prune deletes: live objects matching
+// the selector, or carrying the applyset, that are not in the applied manifests
+type Prune struct {
+	Selector  string   // from -l/--selector
+	All       bool     // --all: every object of the pruned kinds last applied with kubectl apply
+	Allowlist []string // GROUP/VERSION/KIND from --prune-allowlist or --prune-whitelist; empty prunes kubectl's default kinds
+	ApplySet  string   // from --applyset: the parent object that tracks the set
+}
+
+// PruneOptions returns what an apply --prune command deletes, or nil if the
+// command does not prune
+func (k *KubectlCommand) PruneOptions() *Prune {
+	if k.Operation != "apply" || !hasBoolFlag(k.Args, "--prune") {
+		return nil
+	}
+	p := &Prune{
+		Selector: lastFlagValue(k.Args, "--selector"),
+		All:      hasBoolFlag(k.Args, "--all"),
+		ApplySet: lastFlagValue(k.Args, "--applyset"),
+	}
+	if selector := lastFlagValue(k.Args, "-l"); selector != "" {
+		p.Selector = selector
+	}
+	p.Allowlist = append(flagValues(k.Args, "--prune-allowlist"), flagValues(k.Args, "--prune-whitelist")...)
+	return p
+}
+
+// hasBoolFlag reports whether a boolean flag is set ahead of any "--" separator
+func hasBoolFlag(args []string, flag string) bool {
+	for _, arg := range args {
+		switch arg {
+		case "--":
+			return false
+		case flag, flag + "=true":
+			return true
+		}
+	}
+	return false
+}
+
+// flagValues returns every value of a repeatable flag given as "flag value"
+// or "flag=value" ahead of any "--" separator
+func flagValues(args []string, flag string) []string {
+	var values []string
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return values
+		case arg == flag && i+1 < len(args):
+			values = append(values, args[i+1])
+		case strings.HasPrefix(arg, flag+"="):
+			values = append(values, strings.TrimPrefix(arg, flag+"="))
+		}
+	}
+	return values
+}
@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"strings"
+)
+
+// kindInfo describes a built-in Kubernetes kind
+type kindInfo struct {
+	names         []string // singular, plural and short names kubectl accepts
+	clusterScoped bool
+}
+
+// builtinKinds maps canonical kinds to the resource names kubectl accepts for them
+var builtinKinds = map[string]kindInfo{
+	// Cluster-scoped
+	"Namespace":                      {names: []string{"namespace", "namespaces", "ns"}, clusterScoped: true},
+	"Node":                           {names: []string{"node", "nodes", "no"}, clusterScoped: true},
+	"PersistentVolume":               {names: []string{"persistentvolume", "persistentvolumes", "pv"}, clusterScoped: true},
+	"StorageClass":                   {names: []string{"storageclass", "storageclasses", "sc"}, clusterScoped: true},
+	"CustomResourceDefinition":       {names: []string{"customresourcedefinition", "customresourcedefinitions", "crd", "crds"}, clusterScoped: true},
+	"ClusterRole":                    {names: []string{"clusterrole", "clusterroles"}, clusterScoped: true},
+	"ClusterRoleBinding":             {names: []string{"clusterrolebinding", "clusterrolebindings"}, clusterScoped: true},
+	"PriorityClass":                  {names: []string{"priorityclass", "priorityclasses", "pc"}, clusterScoped: true},
+	"IngressClass":                   {names: []string{"ingressclass", "ingressclasses"}, clusterScoped: true},
+	"RuntimeClass":                   {names: []string{"runtimeclass", "runtimeclasses"}, clusterScoped: true},
+	"CSIDriver":                      {names: []string{"csidriver", "csidrivers"}, clusterScoped: true},
+	"VolumeAttachment":               {names: []string{"volumeattachment", "volumeattachments"}, clusterScoped: true},
+	"APIService":                     {names: []string{"apiservice", "apiservices"}, clusterScoped: true},
+	"ComponentStatus":                {names: []string{"componentstatus", "componentstatuses", "cs"}, clusterScoped: true},
+	"CertificateSigningRequest":      {names: []string{"certificatesigningrequest", "certificatesigningrequests", "csr"}, clusterScoped: true},
+	"MutatingWebhookConfiguration":   {names: []string{"mutatingwebhookconfiguration", "mutatingwebhookconfigurations"}, clusterScoped: true},
+	"ValidatingWebhookConfiguration": {names: []string{"validatingwebhookconfiguration", "validatingwebhookconfigurations"}, clusterScoped: true},
+
+	// Namespaced
+	"Pod":                     {names: []string{"pod", "pods", "po"}},
+	"Service":                 {names: []string{"service", "services", "svc"}},
+	"Deployment":              {names: []string{"deployment", "deployments", "deploy"}},
+	"StatefulSet":             {names: []string{"statefulset", "statefulsets", "sts"}},
+	"DaemonSet":               {names: []string{"daemonset", "daemonsets", "ds"}},
+	"ReplicaSet":              {names: []string{"replicaset", "replicasets", "rs"}},
+	"ReplicationController":   {names: []string{"replicationcontroller", "replicationcontrollers", "rc"}},
+	"Job":                     {names: []string{"job", "jobs"}},
+	"CronJob":                 {names: []string{"cronjob", "cronjobs", "cj"}},
+	"ConfigMap":               {names: []string{"configmap", "configmaps", "cm"}},
+	"Secret":                  {names: []string{"secret", "secrets"}},
+	"ServiceAccount":          {names: []string{"serviceaccount", "serviceaccounts", "sa"}},
+	"Role":                    {names: []string{"role", "roles"}},
+	"RoleBinding":             {names: []string{"rolebinding", "rolebindings"}},
+	"Ingress":                 {names: []string{"ingress", "ingresses", "ing"}},
+	"PersistentVolumeClaim":   {names: []string{"persistentvolumeclaim", "persistentvolumeclaims", "pvc"}},
+	"NetworkPolicy":           {names: []string{"networkpolicy", "networkpolicies", "netpol"}},
+	"PodDisruptionBudget":     {names: []string{"poddisruptionbudget", "poddisruptionbudgets", "pdb"}},
+	"HorizontalPodAutoscaler": {names: []string{"horizontalpodautoscaler", "horizontalpodautoscalers", "hpa"}},
+	"ResourceQuota":           {names: []string{"resourcequota", "resourcequotas", "quota"}},
+	"LimitRange":              {names: []string{"limitrange", "limitranges", "limits"}},
+	"Endpoints":               {names: []string{"endpoints", "ep"}},
+	"EndpointSlice":           {names: []string{"endpointslice", "endpointslices"}},
+	"Lease":                   {names: []string{"lease", "leases"}},
+	"PodTemplate":             {names: []string{"podtemplate", "podtemplates"}},
+	"Event":                   {names: []string{"event", "events", "ev"}},
+
+	// Gateway API
+	"Gateway":   {names: []string{"gateway", "gateways", "gtw"}},
+	"HTTPRoute": {names: []string{"httproute", "httproutes"}},
+}
+
+// kindByName maps every accepted resource name to its canonical kind
+var kindByName = func() map[string]string {
+	m := make(map[string]string)
+	for kind, info := range builtinKinds {
+		for _, name := range info.names {
+			m[name] = kind
+		}
+	}
+	return m
+}()
+
+// KindFor returns the canonical kind for a kubectl resource name
+// (e.g. "deploy", "deployments.apps" -> "Deployment"), or "" if unknown
+func KindFor(resource string) string {
+	name := strings.ToLower(resource)
+	// Strip API group suffix: deployments.apps -> deployments
+	if idx := strings.Index(name, "."); idx >= 0 {
+		name = name[:idx]
+	}
+	return kindByName[name]
+}
+
+// NormalizeResource returns the name rules match a kind or kubectl resource
+// name by: the lowercase kind for built-in kinds, whatever name, plural or
+// short name is given (deploy, deployments.apps -> deployment), and the
+// lowercase singular, without API group, for others such as custom resources
+// (Certificate, certificates.cert-manager.io -> certificate)
+func NormalizeResource(resource string) string {
+	if kind := KindFor(resource); kind != "" {
+		return strings.ToLower(kind)
+	}
+	name, _, _ := strings.Cut(strings.ToLower(resource), ".")
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"), strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss"):
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
+
+// SameResource reports whether two kinds or kubectl resource names, such as
+// a configured rule's and a command's, name the same resource
+func SameResource(a, b string) bool {
+	return NormalizeResource(a) == NormalizeResource(b)
+}
+
+// IsClusterScopedKind returns true if the kind is a known cluster-scoped kind
+func IsClusterScopedKind(kind string) bool {
+	return builtinKinds[kind].clusterScoped
+}
+
+// IsClusterScoped returns true if every target is a known cluster-scoped resource
+func (k *KubectlCommand) IsClusterScoped() bool {
+	if len(k.Targets) == 0 {
+		return false
+	}
+	for _, t := range k.Targets {
+		if !IsClusterScopedKind(KindFor(t.Resource)) {
+			return false
+		}
+	}
+	return true
+}
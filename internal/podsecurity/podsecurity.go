@@ -0,0 +1,121 @@
+package podsecurity
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnforceLabel is the namespace label that sets the enforced Pod Security level
+const EnforceLabel = "pod-security.kubernetes.io/enforce"
+
+// LabelChanges splits `kubectl label` positionals into set labels and removed keys
+func LabelChanges(positionals []string) (map[string]string, []string) {
+	set := make(map[string]string)
+	var removed []string
+	for _, arg := range positionals {
+		if key, value, ok := strings.Cut(arg, "="); ok {
+			set[key] = value
+		} else if key, ok := strings.CutSuffix(arg, "-"); ok && key != "" {
+			removed = append(removed, key)
+		}
+	}
+	return set, removed
+}
+
+// WeakeningReasons describes namespace label changes that weaken Pod Security
+// admission: enforcing the privileged level or removing enforcement
+func WeakeningReasons(set map[string]string, removed []string) []string {
+	var reasons []string
+	if strings.EqualFold(set[EnforceLabel], "privileged") {
+		reasons = append(reasons, fmt.Sprintf("sets %s=privileged, disabling Pod Security enforcement", EnforceLabel))
+	}
+	for _, key := range removed {
+		if key == EnforceLabel {
+			reasons = append(reasons, fmt.Sprintf("removes %s, disabling Pod Security enforcement", EnforceLabel))
+		}
+	}
+	return reasons
+}
+
+type containerDoc struct {
+	Name            string `yaml:"name"`
+	SecurityContext struct {
+		Privileged bool `yaml:"privileged"`
+	} `yaml:"securityContext"`
+}
+
+type podSpecDoc struct {
+	HostPID        bool           `yaml:"hostPID"`
+	HostNetwork    bool           `yaml:"hostNetwork"`
+	HostIPC        bool           `yaml:"hostIPC"`
+	Containers     []containerDoc `yaml:"containers"`
+	InitContainers []containerDoc `yaml:"initContainers"`
+}
+
+type templateDoc struct {
+	Spec podSpecDoc `yaml:"spec"`
+}
+
+type workloadDoc struct {
+	Metadata struct {
+		Labels map[string]string `yaml:"labels"`
+	} `yaml:"metadata"`
+	Spec struct {
+		podSpecDoc  `yaml:",inline"`
+		Template    templateDoc `yaml:"template"`
+		JobTemplate struct {
+			Spec struct {
+				Template templateDoc `yaml:"template"`
+			} `yaml:"spec"`
+		} `yaml:"jobTemplate"`
+	} `yaml:"spec"`
+}
+
+// NamespaceReasons checks a Namespace manifest's labels for weakened Pod Security
+func NamespaceReasons(raw []byte) ([]string, error) {
+	var doc workloadDoc
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse namespace: %w", err)
+	}
+	return WeakeningReasons(doc.Metadata.Labels, nil), nil
+}
+
+// PrivilegedSettings lists the privileged settings in the pod spec of a Pod,
+// a workload template or a CronJob job template
+func PrivilegedSettings(kind string, raw []byte) ([]string, error) {
+	var doc workloadDoc
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", kind, err)
+	}
+
+	var spec podSpecDoc
+	switch kind {
+	case "Pod":
+		spec = doc.Spec.podSpecDoc
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+		spec = doc.Spec.Template.Spec
+	case "CronJob":
+		spec = doc.Spec.JobTemplate.Spec.Template.Spec
+	default:
+		return nil, nil
+	}
+
+	var settings []string
+	if spec.HostPID {
+		settings = append(settings, "hostPID")
+	}
+	if spec.HostNetwork {
+		settings = append(settings, "hostNetwork")
+	}
+	if spec.HostIPC {
+		settings = append(settings, "hostIPC")
+	}
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		if c.SecurityContext.Privileged {
+			settings = append(settings, "privileged container "+c.Name)
+		}
+	}
+	return settings, nil
+}
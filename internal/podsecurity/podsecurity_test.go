@@ -0,0 +1,62 @@
+package podsecurity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLabelChanges(t *testing.T) {
+	set, removed := LabelChanges([]string{"ns", "prod", "team=web", "pod-security.kubernetes.io/warn-"})
+	if !reflect.DeepEqual(set, map[string]string{"team": "web"}) {
+		t.Errorf("set: got %v", set)
+	}
+	if !reflect.DeepEqual(removed, []string{"pod-security.kubernetes.io/warn"}) {
+		t.Errorf("removed: got %v", removed)
+	}
+}
+
+func TestPrivilegedSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		raw      string
+		expected []string
+	}{
+		{
+			name:     "pod",
+			kind:     "Pod",
+			raw:      "spec:\n  hostPID: true\n  containers:\n  - name: debug\n    securityContext:\n      privileged: true\n",
+			expected: []string{"hostPID", "privileged container debug"},
+		},
+		{
+			name:     "cronjob",
+			kind:     "CronJob",
+			raw:      `{"spec":{"jobTemplate":{"spec":{"template":{"spec":{"hostIPC":true,"initContainers":[{"name":"init","securityContext":{"privileged":true}}]}}}}}}`,
+			expected: []string{"hostIPC", "privileged container init"},
+		},
+		{
+			name:     "unprivileged deployment",
+			kind:     "Deployment",
+			raw:      "spec:\n  template:\n    spec:\n      containers:\n      - name: web\n",
+			expected: nil,
+		},
+		{
+			name:     "not a pod-bearing kind",
+			kind:     "Service",
+			raw:      "spec:\n  hostNetwork: true\n",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PrivilegedSettings(tt.kind, []byte(tt.raw))
+			if err != nil {
+				t.Fatalf("PrivilegedSettings() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
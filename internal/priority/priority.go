@@ -0,0 +1,61 @@
+package priority
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SystemClasses are the built-in PriorityClasses reserved for critical cluster components
+var SystemClasses = map[string]bool{
+	"system-cluster-critical": true,
+	"system-node-critical":    true,
+}
+
+// SystemNamespace is where workloads may legitimately use system PriorityClasses
+const SystemNamespace = "kube-system"
+
+type podSpecDoc struct {
+	PriorityClassName string `yaml:"priorityClassName"`
+}
+
+type templateDoc struct {
+	Spec podSpecDoc `yaml:"spec"`
+}
+
+type workloadDoc struct {
+	Spec struct {
+		podSpecDoc  `yaml:",inline"`
+		Template    templateDoc `yaml:"template"`
+		JobTemplate struct {
+			Spec struct {
+				Template templateDoc `yaml:"template"`
+			} `yaml:"spec"`
+		} `yaml:"jobTemplate"`
+	} `yaml:"spec"`
+}
+
+// PodPriorityClass returns the priorityClassName in the pod spec of a Pod, a
+// workload template or a CronJob job template. Other kinds have none.
+func PodPriorityClass(kind string, raw []byte) (string, error) {
+	var doc workloadDoc
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", kind, err)
+	}
+
+	switch kind {
+	case "Pod":
+		return doc.Spec.PriorityClassName, nil
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+		return doc.Spec.Template.Spec.PriorityClassName, nil
+	case "CronJob":
+		return doc.Spec.JobTemplate.Spec.Template.Spec.PriorityClassName, nil
+	}
+	return "", nil
+}
+
+// IsSystemClassMisuse reports whether an ordinary workload (outside
+// kube-system) is assigned a system PriorityClass
+func IsSystemClassMisuse(class, namespace string) bool {
+	return SystemClasses[class] && namespace != SystemNamespace
+}
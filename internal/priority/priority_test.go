@@ -0,0 +1,58 @@
+package priority
+
+import (
+	"testing"
+)
+
+func TestPodPriorityClass(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		raw      string
+		expected string
+	}{
+		{"pod", "Pod", "spec:\n  priorityClassName: high\n", "high"},
+		{"deployment", "Deployment", "spec:\n  template:\n    spec:\n      priorityClassName: system-cluster-critical\n", "system-cluster-critical"},
+		{"cronjob", "CronJob", "spec:\n  jobTemplate:\n    spec:\n      template:\n        spec:\n          priorityClassName: batch\n", "batch"},
+		{"unset", "StatefulSet", "spec:\n  template:\n    spec: {}\n", ""},
+		{"other kind", "ConfigMap", "data:\n  priorityClassName: high\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PodPriorityClass(tt.kind, []byte(tt.raw))
+			if err != nil {
+				t.Fatalf("PodPriorityClass() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("got %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := PodPriorityClass("Pod", []byte("spec: [")); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
+
+func TestIsSystemClassMisuse(t *testing.T) {
+	tests := []struct {
+		class     string
+		namespace string
+		expected  bool
+	}{
+		{"system-cluster-critical", "shop", true},
+		{"system-node-critical", "default", true},
+		{"system-cluster-critical", "kube-system", false},
+		{"high", "shop", false},
+		{"", "shop", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.class+"/"+tt.namespace, func(t *testing.T) {
+			if got := IsSystemClassMisuse(tt.class, tt.namespace); got != tt.expected {
+				t.Errorf("IsSystemClassMisuse(%q, %q): got %v, expected %v", tt.class, tt.namespace, got, tt.expected)
+			}
+		})
+	}
+}
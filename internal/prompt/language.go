@@ -0,0 +1,48 @@
+package prompt
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/i18n"
+)
+
+// language is the language of warnings and confirmation prompts
+var language = i18n.English
+
+// SetLanguage sets the language of warnings and confirmation prompts; a
+// language without translations shows them in English
+func SetLanguage(lang string) {
+	language = lang
+}
+
+// t returns message in the prompt language
+func t(message string) string {
+	return i18n.Text(language, message)
+}
+
+// warningLabels are the labels of a warning's aligned fields
+var warningLabels = []string{"Operation", "Namespace", "Cluster", "Identity", "Command"}
+
+// label returns a warning field label in the prompt language, padded so the
+// field values line up, e.g. "Cluster:   "
+func label(message string) string {
+	width := 0
+	for _, l := range warningLabels {
+		width = max(width, displayWidth(t(l)))
+	}
+	text := t(message) + ":"
+	return text + strings.Repeat(" ", max(width+2-displayWidth(text), 1))
+}
+
+// displayWidth is the number of terminal columns s takes, counting CJK
+// characters as two
+func displayWidth(s string) int {
+	width := utf8.RuneCountInString(s)
+	for _, r := range s {
+		if r >= 0x2E80 && r <= 0xFFEF {
+			width++
+		}
+	}
+	return width
+}
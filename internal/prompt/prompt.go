@@ -1,18 +1,30 @@
 package prompt
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/zufardhiyaulhaq/safekubectl/internal/audit"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/backup"
 	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/coverage"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/drain"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/i18n"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
 )
 
 const (
 	colorRed    = "\033[31m"
 	colorYellow = "\033[33m"
+	colorCyan   = "\033[36m"
+	colorBanner = "\033[1;97;41m" // bold white on red
 	colorReset  = "\033[0m"
 )
 
@@ -23,17 +35,26 @@ func DisplayWarning(result *checker.CheckResult, args []string) {
 
 // DisplayWarningTo writes the warning to the specified writer
 func DisplayWarningTo(w io.Writer, result *checker.CheckResult, args []string) {
+	DisplayWarningVariantTo(w, result, args, config.WarningVariant{})
+}
+
+// DisplayWarningVariantTo writes the warning with a warning experiment
+// variant's headline and severity
+func DisplayWarningVariantTo(w io.Writer, result *checker.CheckResult, args []string, variant config.WarningVariant) {
 	fmt.Fprintln(w)
-	fmt.Fprintf(w, "%s%s  DANGEROUS OPERATION DETECTED%s\n", colorYellow, warningIcon(), colorReset)
-	fmt.Fprintf(w, "├── Operation: %s%s%s\n", colorRed, result.Operation, colorReset)
+	displayHeadlineTo(w, variant, result.Severity())
+	fmt.Fprintf(w, "├── %s%s%s%s\n", label("Operation"), colorRed, result.Operation, colorReset)
 	// Show namespace info based on scope
 	if result.IsAllNamespaces {
-		fmt.Fprintf(w, "├── Namespace: %s⚠ ALL NAMESPACES%s\n", colorRed, colorReset)
-	} else if !result.IsNodeScoped {
-		fmt.Fprintf(w, "├── Namespace: %s\n", result.Namespace)
+		fmt.Fprintf(w, "├── %s%s%s%s\n", label("Namespace"), colorRed, t("⚠ ALL NAMESPACES"), colorReset)
+	} else if !result.IsNodeScoped && !result.IsClusterScoped {
+		fmt.Fprintf(w, "├── %s%s\n", label("Namespace"), result.Namespace)
 	}
-	fmt.Fprintf(w, "├── Cluster:   %s\n", result.Cluster)
-	fmt.Fprintln(w, "├── Resources affected:")
+	fmt.Fprintf(w, "├── %s%s\n", label("Cluster"), result.Cluster)
+	if result.Identity != "" {
+		fmt.Fprintf(w, "├── %s%s\n", label("Identity"), result.Identity)
+	}
+	fmt.Fprintf(w, "├── %s:\n", t("Resources affected"))
 	resources := result.Resources
 	if len(resources) == 0 {
 		resources = []string{"<unknown>"}
@@ -45,10 +66,56 @@ func DisplayWarningTo(w io.Writer, result *checker.CheckResult, args []string) {
 		}
 		fmt.Fprintf(w, "%s %s\n", prefix, r)
 	}
-	fmt.Fprintf(w, "└── Command:   kubectl %s\n", strings.Join(args, " "))
+	if len(result.Reasons) == 0 {
+		fmt.Fprintf(w, "└── %skubectl %s\n", label("Command"), strings.Join(args, " "))
+		fmt.Fprintln(w)
+		return
+	}
+	fmt.Fprintf(w, "├── %skubectl %s\n", label("Command"), strings.Join(args, " "))
+	displayReasonsTo(w, result.Reasons)
 	fmt.Fprintln(w)
 }
 
+// severityColors are the headline colors of warning severities; an
+// unflagged result keeps the default yellow
+var severityColors = map[string]string{
+	checker.SeverityLow:      colorCyan,
+	checker.SeverityMedium:   colorYellow,
+	checker.SeverityHigh:     colorRed,
+	checker.SeverityCritical: colorBanner,
+}
+
+// displayHeadlineTo writes the first line of a warning: the variant's
+// headline or the default one, tagged with the severity and in its color.
+// Critical variants are at least red.
+func displayHeadlineTo(w io.Writer, variant config.WarningVariant, severity string) {
+	headline, color := t("DANGEROUS OPERATION DETECTED"), colorYellow
+	if variant.Headline != "" {
+		headline = variant.Headline
+	}
+	if c, ok := severityColors[severity]; ok {
+		color = c
+		headline += " [" + severity + "]"
+	}
+	if variant.Severity == "critical" && color != colorBanner {
+		color = colorRed
+	}
+	fmt.Fprintf(w, "%s%s  %s%s\n", color, warningIcon(), headline, colorReset)
+}
+
+// displayReasonsTo writes the closing Reasons branch of a warning tree
+func displayReasonsTo(w io.Writer, reasons []string) {
+	fmt.Fprintln(w, "│")
+	fmt.Fprintf(w, "└── %s:\n", t("Reasons"))
+	for i, reason := range reasons {
+		prefix := "    ├──"
+		if i == len(reasons)-1 {
+			prefix = "    └──"
+		}
+		fmt.Fprintf(w, "%s %s\n", prefix, reason)
+	}
+}
+
 // AskConfirmation prompts user for confirmation and returns true if confirmed
 func AskConfirmation() bool {
 	return AskConfirmationFrom(os.Stdin, os.Stdout)
@@ -56,16 +123,228 @@ func AskConfirmation() bool {
 
 // AskConfirmationFrom prompts for confirmation using the specified reader and writer
 func AskConfirmationFrom(r io.Reader, w io.Writer) bool {
-	reader := bufio.NewReader(r)
-	fmt.Fprint(w, "Proceed? [y/N]: ")
+	fmt.Fprint(w, t("Proceed? [y/N]: "))
 
-	response, err := reader.ReadString('\n')
+	response, err := readLine(r)
 	if err != nil {
 		return false
 	}
 
-	response = strings.TrimSpace(strings.ToLower(response))
-	return response == "y" || response == "yes"
+	return i18n.IsYes(language, response)
+}
+
+// readLine reads a single line without buffering past the newline, so several
+// prompts can share one reader. Returns an error if input ends before a newline.
+func readLine(r io.Reader) (string, error) {
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				return string(line), nil
+			}
+			line = append(line, buf[0])
+		}
+		if err != nil {
+			return string(line), err
+		}
+	}
+}
+
+// Answers to AskLargeOutputChoiceFrom
+const (
+	LargeOutputPage  = "p" // pipe the output through a pager
+	LargeOutputChunk = "c" // add --chunk-size
+	LargeOutputRun   = "r" // run the command as it is
+	LargeOutputAbort = "a"
+)
+
+// AskLargeOutputChoiceFrom asks how to run a command with very large output.
+// An empty answer pages; anything unrecognized aborts.
+func AskLargeOutputChoiceFrom(r io.Reader, w io.Writer, pager string) string {
+	fmt.Fprintf(w, "[P]age through %s, add --[c]hunk-size, [r]un as is, or [a]bort? [P/c/r/a]: ", pager)
+
+	response, err := readLine(r)
+	if err != nil {
+		return LargeOutputAbort
+	}
+
+	switch response = strings.TrimSpace(strings.ToLower(response)); response {
+	case "":
+		return LargeOutputPage
+	case LargeOutputPage, LargeOutputChunk, LargeOutputRun:
+		return response
+	}
+	return LargeOutputAbort
+}
+
+// Answers to the selective confirmation of a manifest that touches protected
+// and unprotected namespaces
+const (
+	SelectAll           = "a"
+	SelectSkipProtected = "s"
+	SelectChoose        = "c"
+	SelectAbort         = ""
+)
+
+// AskSelectiveConfirmationFrom asks whether to run a file-based command on all
+// its resources, skip those in protected namespaces, or choose which to skip.
+// Anything else aborts.
+func AskSelectiveConfirmationFrom(r io.Reader, w io.Writer, protected int) string {
+	fmt.Fprintf(w, "Proceed with [a]ll, [s]kip the %s in protected namespaces, or [c]hoose which to skip? [a/s/c/N]: ", count(protected, "resource", "resources"))
+
+	response, err := readLine(r)
+	if err != nil {
+		return SelectAbort
+	}
+	switch response = strings.TrimSpace(strings.ToLower(response)); response {
+	case SelectAll, "y", "yes":
+		return SelectAll
+	case SelectSkipProtected, SelectChoose:
+		return response
+	}
+	return SelectAbort
+}
+
+// AskResourcesToSkipFrom lists resources by number and reads the numbers of
+// those to skip, e.g. "1,3". An empty answer skips none; an invalid one
+// returns false.
+func AskResourcesToSkipFrom(r io.Reader, w io.Writer, resources []manifest.Resource) ([]int, bool) {
+	for i, res := range resources {
+		fmt.Fprintf(w, "  [%d] %s in namespace %s\n", i+1, res.String(), res.Namespace)
+	}
+	fmt.Fprint(w, "Numbers to skip, e.g. 1,3 (empty skips none): ")
+
+	response, err := readLine(r)
+	if err != nil {
+		return nil, false
+	}
+	var skip []int
+	for _, field := range strings.FieldsFunc(response, func(c rune) bool { return c == ',' || c == ' ' }) {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > len(resources) {
+			return nil, false
+		}
+		skip = append(skip, n-1)
+	}
+	return skip, true
+}
+
+// DisplaySkippedTo lists the resources left out of a selective apply
+func DisplaySkippedTo(w io.Writer, skipped []manifest.Resource) {
+	fmt.Fprintf(w, "%sSkipping %s:%s\n", colorYellow, count(len(skipped), "resource", "resources"), colorReset)
+	for i, res := range skipped {
+		prefix := "├──"
+		if i == len(skipped)-1 {
+			prefix = "└──"
+		}
+		fmt.Fprintf(w, "%s %s in namespace %s\n", prefix, res.String(), res.Namespace)
+	}
+}
+
+// AskTypedConfirmation prompts user to type the given phrase to confirm
+func AskTypedConfirmation(phrase string) bool {
+	return AskTypedConfirmationFrom(os.Stdin, os.Stdout, phrase)
+}
+
+// AskTypedConfirmationFrom prompts for typed confirmation using the specified reader and writer.
+// Only an exact match of the phrase (ignoring surrounding whitespace) confirms.
+func AskTypedConfirmationFrom(r io.Reader, w io.Writer, phrase string) bool {
+	fmt.Fprintf(w, t("Type %s to confirm: "), colorRed+phrase+colorReset)
+
+	response, err := readLine(r)
+	if err != nil {
+		return false
+	}
+
+	return strings.TrimSpace(response) == phrase
+}
+
+// AskTicketFrom asks for the change ticket a command is made under and
+// returns it trimmed, or "" if none is given
+func AskTicketFrom(r io.Reader, w io.Writer) string {
+	fmt.Fprint(w, t("Change ticket: "))
+
+	response, err := readLine(r)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(response)
+}
+
+// AskReasonFrom asks for a one-line justification of a confirmed command and
+// returns it trimmed, or "" if none is given
+func AskReasonFrom(r io.Reader, w io.Writer) string {
+	fmt.Fprint(w, t("Reason for this change: "))
+
+	response, err := readLine(r)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(response)
+}
+
+// DisplayTicketTo shows the change ticket a command runs under, as the
+// ticketing system reports it
+func DisplayTicketTo(w io.Writer, id, status, summary string) {
+	fmt.Fprintf(w, "Ticket %s: %s (%s)\n", id, summary, status)
+}
+
+// DisplayNamespacePreview shows the resources that a namespace deletion will destroy
+func DisplayNamespacePreview(namespace string, resources []string) {
+	DisplayNamespacePreviewTo(os.Stdout, namespace, resources)
+}
+
+// DisplayNamespacePreviewTo writes the namespace deletion preview to the specified writer
+func DisplayNamespacePreviewTo(w io.Writer, namespace string, resources []string) {
+	fmt.Fprintf(w, "%sResources that will be destroyed in namespace %s:%s\n", colorYellow, namespace, colorReset)
+	if len(resources) == 0 {
+		fmt.Fprintln(w, "└── (none found)")
+		fmt.Fprintln(w)
+		return
+	}
+	for i, r := range resources {
+		prefix := "├──"
+		if i == len(resources)-1 {
+			prefix = "└──"
+		}
+		fmt.Fprintf(w, "%s %s\n", prefix, r)
+	}
+	fmt.Fprintln(w)
+}
+
+// DisplayDrainPreviewTo lists how many pods a drain evicts from a node, by namespace
+func DisplayDrainPreviewTo(w io.Writer, e drain.Evictions) {
+	fmt.Fprintf(w, "%sPods that will be evicted from node %s: %d%s\n", colorYellow, e.Node, e.Total(), colorReset)
+	namespaces := e.Namespaces()
+	if len(namespaces) == 0 {
+		fmt.Fprintln(w, "└── (none found)")
+		fmt.Fprintln(w)
+		return
+	}
+	for i, ns := range namespaces {
+		prefix := "├──"
+		if i == len(namespaces)-1 {
+			prefix = "└──"
+		}
+		fmt.Fprintf(w, "%s %s: %d\n", prefix, ns, e.ByNamespace[ns])
+	}
+	fmt.Fprintln(w)
+}
+
+// DisplayApplyOrderTo shows the kinds of a multi-document apply in the order
+// kubectl applies them
+func DisplayApplyOrderTo(w io.Writer, order []string) {
+	fmt.Fprintf(w, "%sApply order:%s\n", colorYellow, colorReset)
+	for i, kind := range order {
+		prefix := "├──"
+		if i == len(order)-1 {
+			prefix = "└──"
+		}
+		fmt.Fprintf(w, "%s %d. %s\n", prefix, i+1, kind)
+	}
+	fmt.Fprintln(w)
 }
 
 // DisplayAborted shows the operation was aborted
@@ -75,7 +354,7 @@ func DisplayAborted() {
 
 // DisplayAbortedTo writes the aborted message to the specified writer
 func DisplayAbortedTo(w io.Writer) {
-	fmt.Fprintln(w, "Operation aborted.")
+	fmt.Fprintln(w, t("Operation aborted."))
 }
 
 // DisplayProceeding shows the operation is proceeding (warn-only mode)
@@ -85,7 +364,7 @@ func DisplayProceeding() {
 
 // DisplayProceedingTo writes the proceeding message to the specified writer
 func DisplayProceedingTo(w io.Writer) {
-	fmt.Fprintln(w, "Proceeding with operation...")
+	fmt.Fprintln(w, t("Proceeding with operation..."))
 	fmt.Fprintln(w)
 }
 
@@ -101,41 +380,63 @@ func DisplayResourceWarning(result *checker.ResourceCheckResult, args []string)
 
 // DisplayResourceWarningTo writes the resource warning to the specified writer
 func DisplayResourceWarningTo(w io.Writer, result *checker.ResourceCheckResult, args []string) {
+	DisplayResourceWarningVariantTo(w, result, args, config.WarningVariant{})
+}
+
+// DisplayResourceWarningVariantTo writes the resource warning with a warning
+// experiment variant's headline and severity
+func DisplayResourceWarningVariantTo(w io.Writer, result *checker.ResourceCheckResult, args []string, variant config.WarningVariant) {
 	fmt.Fprintln(w)
-	fmt.Fprintf(w, "%s%s  DANGEROUS OPERATION DETECTED%s\n", colorYellow, warningIcon(), colorReset)
-	fmt.Fprintf(w, "├── Operation: %s%s%s\n", colorRed, result.Operation, colorReset)
-	fmt.Fprintf(w, "├── Cluster:   %s\n", result.Cluster)
-	fmt.Fprintf(w, "├── Command:   kubectl %s\n", strings.Join(args, " "))
+	displayHeadlineTo(w, variant, result.Severity())
+	fmt.Fprintf(w, "├── %s%s%s%s\n", label("Operation"), colorRed, result.Operation, colorReset)
+	fmt.Fprintf(w, "├── %s%s\n", label("Cluster"), result.Cluster)
+	if result.Identity != "" {
+		fmt.Fprintf(w, "├── %s%s\n", label("Identity"), result.Identity)
+	}
+	fmt.Fprintf(w, "├── %skubectl %s\n", label("Command"), strings.Join(args, " "))
 	fmt.Fprintln(w, "│")
-	fmt.Fprintln(w, "├── Resources affected:")
+	fmt.Fprintf(w, "├── %s:\n", t("Resources affected"))
 
 	for i, r := range result.Resources {
 		prefix := "│   ├──"
 		if i == len(result.Resources)-1 {
 			prefix = "│   └──"
 		}
+		if r.Namespace == "" && parser.IsClusterScopedKind(r.Kind) {
+			fmt.Fprintf(w, "%s %s %s\n", prefix, r.String(), t("(cluster-scoped)"))
+			continue
+		}
 		ns := r.Namespace
 		if ns == "" {
-			ns = "(unspecified)"
+			ns = t("(unspecified)")
 		}
-		fmt.Fprintf(w, "%s %s in namespace %s\n", prefix, r.String(), ns)
+		fmt.Fprintf(w, "%s %s\n", prefix, fmt.Sprintf(t("%s in namespace %s"), r.String(), ns))
 	}
 
 	if len(result.Reasons) > 0 {
-		fmt.Fprintln(w, "│")
-		fmt.Fprintln(w, "└── Reasons:")
-		for i, reason := range result.Reasons {
-			prefix := "    ├──"
-			if i == len(result.Reasons)-1 {
-				prefix = "    └──"
-			}
-			fmt.Fprintf(w, "%s %s\n", prefix, reason)
-		}
+		displayReasonsTo(w, result.Reasons)
 	}
 
 	fmt.Fprintln(w)
 }
 
+// DisplayContextBannerTo reminds the operator that the current context is a
+// protected cluster, which commands without --context run against
+func DisplayContextBannerTo(w io.Writer, cluster string) {
+	fmt.Fprintf(w, "%s %s  PROTECTED CONTEXT: %s %s\n", colorBanner, warningIcon(), cluster, colorReset)
+}
+
+// DisplayLargeOutputWarningTo warns that a command prints every object of a
+// type in the cluster in full
+func DisplayLargeOutputWarningTo(w io.Writer, args []string, resource string) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s%s  LARGE OUTPUT WARNING%s\n", colorYellow, warningIcon(), colorReset)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "kubectl %s prints every %s in the cluster in full,\n", strings.Join(args, " "), resource)
+	fmt.Fprintln(w, "which can freeze the terminal on a large cluster.")
+	fmt.Fprintln(w)
+}
+
 // DisplayURLWarning shows the warning before fetching a remote manifest
 func DisplayURLWarning(url string) {
 	DisplayURLWarningTo(os.Stdout, url)
@@ -148,7 +449,383 @@ func DisplayURLWarningTo(w io.Writer, url string) {
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, "You are about to fetch a manifest from:")
 	fmt.Fprintf(w, "  %s\n", url)
+	p := manifest.ProvenanceOf(url)
+	if p.Repo == "" {
+		fmt.Fprintf(w, "  └── Host: %s\n", p.Host)
+	} else {
+		ref := p.Ref + " (branch or tag: its content can change; a commit pins it)"
+		if p.Pinned() {
+			ref = p.Ref + " (commit)"
+		}
+		fmt.Fprintf(w, "  ├── Host: %s\n", p.Host)
+		fmt.Fprintf(w, "  ├── Repo: %s\n", p.Repo)
+		fmt.Fprintf(w, "  ├── Ref:  %s\n", ref)
+		fmt.Fprintf(w, "  └── Path: %s\n", p.Path)
+	}
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, "Fetching remote manifests can be risky.")
 	fmt.Fprintln(w)
 }
+
+// DisplayRawURLTo shows that a blob URL, which serves a web page, is
+// replaced by the raw URL of the file
+func DisplayRawURLTo(w io.Writer, blobURL, rawURL string) {
+	fmt.Fprintf(w, "%s is a web page, not the manifest: using %s\n", blobURL, rawURL)
+}
+
+// DisplayDrainPlan shows the computed drain order before confirmation
+func DisplayDrainPlan(plan *drain.Plan, cluster string, args, reasons []string) {
+	DisplayDrainPlanTo(os.Stdout, plan, cluster, args, reasons)
+}
+
+// DisplayDrainPlanTo writes the drain plan to the specified writer, followed
+// by the reasons it needs care, such as protected nodes
+func DisplayDrainPlanTo(w io.Writer, plan *drain.Plan, cluster string, args, reasons []string) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s%s  DRAIN PLAN%s\n", colorYellow, warningIcon(), colorReset)
+	fmt.Fprintf(w, "├── Selector:  %s\n", plan.Selector)
+	fmt.Fprintf(w, "├── Cluster:   %s\n", cluster)
+	fmt.Fprintf(w, "├── Drain flags: %s\n", strings.Join(args, " "))
+	branch, indent := "└──", "    "
+	if len(reasons) > 0 {
+		branch, indent = "├──", "│   "
+	}
+	fmt.Fprintf(w, "%s Order:\n", branch)
+	for i, step := range plan.Steps {
+		prefix := indent + "├──"
+		if i == len(plan.Steps)-1 {
+			prefix = indent + "└──"
+		}
+		zone := step.Node.Zone
+		if zone == "" {
+			zone = "(no zone)"
+		}
+		fmt.Fprintf(w, "%s %d. %s zone=%s pods=%d pdb-guarded=%d\n", prefix, i+1, step.Node.Name, zone, step.Pods, step.GuardedPods)
+		for _, pdb := range step.BlockingPDB {
+			fmt.Fprintf(w, "%s│      %s⚠ PDB %s/%s allows no disruptions, drain stops here if it still does%s\n", indent, colorRed, pdb.Namespace, pdb.Name, colorReset)
+		}
+	}
+	if len(reasons) > 0 {
+		displayReasonsTo(w, reasons)
+	}
+	fmt.Fprintln(w)
+}
+
+// DisplayDrainProgressTo writes the progress line before draining a node
+func DisplayDrainProgressTo(w io.Writer, current, total int, node string) {
+	fmt.Fprintf(w, "[%d/%d] draining node %s...\n", current, total, node)
+}
+
+// DisplayDrainPacingTo writes the pacing settings applied between node drains
+func DisplayDrainPacingTo(w io.Writer, pacing config.DrainConfig) {
+	if pacing.PauseBetweenNodes <= 0 && pacing.MaxPendingPods <= 0 {
+		return
+	}
+	fmt.Fprintln(w, "Between nodes:")
+	if pacing.PauseBetweenNodes > 0 {
+		fmt.Fprintf(w, "├── pause %s\n", pacing.PauseBetweenNodes)
+	}
+	if pacing.MaxPendingPods > 0 {
+		fmt.Fprintf(w, "├── wait until pending pods < %d (timeout %s)\n", pacing.MaxPendingPods, pacing.HealthCheckTimeout)
+	}
+	fmt.Fprintln(w, "└── stop on failure")
+	fmt.Fprintln(w)
+}
+
+// DisplayDrainPauseTo writes the pause message between node drains
+func DisplayDrainPauseTo(w io.Writer, d time.Duration) {
+	fmt.Fprintf(w, "Pausing %s before next node...\n", d)
+}
+
+// DisplayPendingPodsTo writes the pending pod wait message between node drains
+func DisplayPendingPodsTo(w io.Writer, pending, max int) {
+	fmt.Fprintf(w, "Waiting for pending pods to settle: %d pending (need < %d)...\n", pending, max)
+}
+
+// DisplaySnapshotTo writes where the objects about to be deleted were saved
+// and how to create them again
+func DisplaySnapshotTo(w io.Writer, paths []string, restore string) {
+	fmt.Fprintf(w, "Saved %s before deleting:\n", count(len(paths), "object", "objects"))
+	for _, path := range paths {
+		fmt.Fprintf(w, "├── %s\n", path)
+	}
+	fmt.Fprintf(w, "└── To restore: %s\n", restore)
+}
+
+// DisplayBackupsTo writes the snapshots taken before deletes, newest first
+func DisplayBackupsTo(w io.Writer, backups []backup.Backup) {
+	if len(backups) == 0 {
+		fmt.Fprintln(w, "No backups.")
+		return
+	}
+	for _, b := range backups {
+		fmt.Fprintf(w, "%s (%s)\n", b.ID, count(len(b.Files), "object", "objects"))
+		for i, file := range b.Files {
+			branch := "├──"
+			if i == len(b.Files)-1 {
+				branch = "└──"
+			}
+			fmt.Fprintf(w, "%s %s\n", branch, file)
+		}
+	}
+}
+
+// DisplayRestoreTo writes which backup is being restored, and how
+func DisplayRestoreTo(w io.Writer, id string, args []string) {
+	fmt.Fprintf(w, "Restoring backup %s\n", id)
+	fmt.Fprintf(w, "└── kubectl %s\n", strings.Join(args, " "))
+}
+
+// DisplayCoverageTo writes the parts of commands safekubectl did not
+// understand, most frequent first, as recorded in the coverage log at path
+func DisplayCoverageTo(w io.Writer, path string, gaps []coverage.Gap) {
+	if len(gaps) == 0 {
+		fmt.Fprintf(w, "No coverage gaps recorded in %s.\n", path)
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "GAP\tCOUNT\tOPERATIONS\tLAST SEEN")
+	for _, g := range gaps {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", g.Gap, g.Count, strings.Join(g.Operations, ","), g.LastSeen)
+	}
+	tw.Flush()
+}
+
+// DisplaySupportBundleTo writes where a support bundle was saved and what is in it
+func DisplaySupportBundleTo(w io.Writer, path string, files []string) {
+	fmt.Fprintf(w, "Support bundle written to %s\n", path)
+	for _, f := range files {
+		fmt.Fprintf(w, "├── %s\n", f)
+	}
+	fmt.Fprintln(w, "└── Credentials are masked, but review the bundle before sharing it")
+}
+
+// DisplayBatchProgressTo writes how far a change run cluster by cluster has
+// got; total is 0 when the clusters cannot be counted
+func DisplayBatchProgressTo(w io.Writer, id string, confirmed, denied, total int) {
+	progress := count(confirmed, "cluster", "clusters")
+	if total > 0 {
+		progress = fmt.Sprintf("%d of %s", confirmed, count(total, "cluster", "clusters"))
+	}
+	fmt.Fprintf(w, "Batch %s: %s confirmed so far", id, progress)
+	if denied > 0 {
+		fmt.Fprintf(w, " (%d denied)", denied)
+	}
+	fmt.Fprintln(w)
+}
+
+// DisplayRecreationWaitTo writes the message shown while waiting for a replacement
+func DisplayRecreationWaitTo(w io.Writer, target string, timeout time.Duration) {
+	fmt.Fprintf(w, "Waiting up to %s for %s to be recreated and ready...\n", timeout, target)
+}
+
+// DisplayRecreationResultTo writes whether the replacement became ready
+func DisplayRecreationResultTo(w io.Writer, target string, ready bool) {
+	if ready {
+		fmt.Fprintf(w, "✔ %s replacement is ready\n", target)
+		return
+	}
+	fmt.Fprintf(w, "%s✘ %s replacement did not become ready in time%s\n", colorRed, target, colorReset)
+}
+
+// DisplayRolloutWaitTo writes that a workload's rollout is being waited on
+func DisplayRolloutWaitTo(w io.Writer, target string, timeout time.Duration) {
+	fmt.Fprintf(w, "Waiting up to %s for %s to roll out...\n", timeout, target)
+}
+
+// DisplayRolloutResultTo writes whether the rollout completed
+func DisplayRolloutResultTo(w io.Writer, target string, ok bool) {
+	if ok {
+		fmt.Fprintf(w, "✔ %s rolled out\n", target)
+		return
+	}
+	fmt.Fprintf(w, "%s✘ %s did not roll out in time%s\n", colorRed, target, colorReset)
+}
+
+// DisplayRollbackOfferTo writes the rollback command offered after a failed rollout
+func DisplayRollbackOfferTo(w io.Writer, undoArgs []string) {
+	fmt.Fprintf(w, "%sRoll back with: kubectl %s%s\n", colorYellow, strings.Join(undoArgs, " "), colorReset)
+}
+
+// DisplaySuspendOfferTo writes the command offered to suspend GitOps reconciliation
+func DisplaySuspendOfferTo(w io.Writer, manager string, suspendArgs []string) {
+	fmt.Fprintf(w, "%s%s will revert this change. Suspend its reconciliation first with: kubectl %s%s\n", colorYellow, manager, strings.Join(suspendArgs, " "), colorReset)
+}
+
+// DisplayResumeReminderTo writes the reminder to resume suspended reconciliation
+func DisplayResumeReminderTo(w io.Writer, manager string, resumeArgs []string) {
+	fmt.Fprintf(w, "%sReconciliation of %s is still suspended. Once the change is in Git, resume it with: kubectl %s%s\n", colorYellow, manager, strings.Join(resumeArgs, " "), colorReset)
+}
+
+// DisplayVerifyOfferTo writes a follow-up command that checks the operation's result
+func DisplayVerifyOfferTo(w io.Writer, args []string) {
+	fmt.Fprintf(w, "Verify with: kubectl %s\n", strings.Join(args, " "))
+}
+
+// DisplayCanaryPhaseTo writes the resources about to be applied in a canary phase
+func DisplayCanaryPhaseTo(w io.Writer, phase string, resources []manifest.Resource) {
+	fmt.Fprintf(w, "Applying %s phase (%d resources):\n", phase, len(resources))
+	for i, r := range resources {
+		prefix := "├──"
+		if i == len(resources)-1 {
+			prefix = "└──"
+		}
+		fmt.Fprintf(w, "%s %s\n", prefix, r.String())
+	}
+}
+
+// DisplayCanaryHealthTo writes the rollout status of a canary workload
+func DisplayCanaryHealthTo(w io.Writer, r manifest.Resource, healthy bool) {
+	if healthy {
+		fmt.Fprintf(w, "✔ %s rolled out\n", r.String())
+		return
+	}
+	fmt.Fprintf(w, "%s✘ %s did not roll out successfully%s\n", colorRed, r.String(), colorReset)
+}
+
+// DisplayCanaryPauseTo writes the pause message before applying the remaining resources
+func DisplayCanaryPauseTo(w io.Writer, remaining int, healthy bool) {
+	fmt.Fprintln(w)
+	if !healthy {
+		fmt.Fprintf(w, "%s%s  CANARY UNHEALTHY%s\n", colorRed, warningIcon(), colorReset)
+	}
+	fmt.Fprintf(w, "Canary applied. %d resources remaining.\n", remaining)
+}
+
+// DisplayConfigValidTo writes the config files that passed validation
+func DisplayConfigValidTo(w io.Writer, sources []string) {
+	if len(sources) == 0 {
+		fmt.Fprintln(w, "✔ No config files found, using the built-in defaults")
+		return
+	}
+	fmt.Fprintln(w, "✔ Configuration is valid:")
+	for i, source := range sources {
+		prefix := "├──"
+		if i == len(sources)-1 {
+			prefix = "└──"
+		}
+		fmt.Fprintf(w, "%s %s\n", prefix, source)
+	}
+}
+
+// DisplayAuditVerifyTo writes the result of checking an audit log's hash chain
+func DisplayAuditVerifyTo(w io.Writer, path string, chained int, breaks []audit.Break) {
+	if len(breaks) == 0 {
+		fmt.Fprintf(w, "✔ %s: hash chain intact (%s checked)\n", path, count(chained, "entry", "entries"))
+		return
+	}
+	fmt.Fprintf(w, "%s✘ %s: hash chain broken (%s checked):%s\n", colorRed, path, count(chained, "entry", "entries"), colorReset)
+	for i, b := range breaks {
+		prefix := "├──"
+		if i == len(breaks)-1 {
+			prefix = "└──"
+		}
+		fmt.Fprintf(w, "%s line %d: %s\n", prefix, b.Line, b.Problem)
+	}
+}
+
+// DisplayReplayTo writes which audited command is being replayed, and how
+func DisplayReplayTo(w io.Writer, entry audit.Entry, args []string) {
+	fmt.Fprintf(w, "Replaying audit entry %s (%s %s on %s) with its archived manifest (%d bytes, sha256 %s)\n",
+		entry.ID, entry.Timestamp, entry.Status, orDash(entry.Cluster), entry.Stdin.Bytes, entry.Stdin.SHA256)
+	fmt.Fprintf(w, "└── kubectl %s\n", strings.Join(args, " "))
+}
+
+// DisplayAuditEntriesTo writes audit entries as a table, oldest first
+func DisplayAuditEntriesTo(w io.Writer, entries []audit.Entry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No matching audit entries.")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTIME\tSTATUS\tOPERATION\tCLUSTER\tNAMESPACE\tUSER\tRESOURCES")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			orDash(e.ID), e.Timestamp, e.Status, e.Operation, orDash(e.Cluster), orDash(e.Namespace), orDash(e.User), orDash(strings.Join(e.Resources, ",")))
+	}
+	tw.Flush()
+}
+
+// DisplayAuditStatsTo writes an audit summary with a table per operation,
+// namespace and cluster
+func DisplayAuditStatsTo(w io.Writer, stats audit.Stats) {
+	if stats.Overall.Total() == 0 {
+		fmt.Fprintln(w, "No matching audit entries.")
+		return
+	}
+	fmt.Fprintf(w, "%s from %s to %s: %d executed, %d denied (%.0f%% denied)\n",
+		count(stats.Overall.Total(), "operation", "operations"), stats.First, stats.Last,
+		stats.Overall.Executed, stats.Overall.Denied, stats.Overall.DenialRate()*100)
+
+	sections := []struct {
+		heading string
+		counts  []audit.Count
+	}{
+		{"OPERATION", stats.ByOperation},
+		{"NAMESPACE", stats.ByNamespace},
+		{"CLUSTER", stats.ByCluster},
+	}
+	for _, section := range sections {
+		fmt.Fprintln(w)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "%s\tTOTAL\tEXECUTED\tDENIED\tDENIED %%\n", section.heading)
+		for _, c := range section.counts {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.0f%%\n", c.Key, c.Total(), c.Executed, c.Denied, c.DenialRate()*100)
+		}
+		tw.Flush()
+	}
+}
+
+// DisplayNearMissesTo writes a near-miss report with a table per day, user,
+// cluster and rule, marking which prompts prevent incidents and which are noise
+func DisplayNearMissesTo(w io.Writer, summary audit.NearMisses) {
+	if summary.Overall.Prompted() == 0 {
+		fmt.Fprintln(w, "No prompted operations in the audit log.")
+		return
+	}
+	fmt.Fprintf(w, "%s from %s to %s: %d denied, %d confirmed (%.0f%% denied)\n",
+		count(summary.Overall.Prompted(), "prompted operation", "prompted operations"), summary.First, summary.Last,
+		summary.Overall.Denied, summary.Overall.Confirmed, summary.Overall.DenialRate()*100)
+
+	sections := []struct {
+		heading string
+		counts  []audit.NearMissCount
+	}{
+		{"DAY", summary.ByDay},
+		{"USER", summary.ByUser},
+		{"CLUSTER", summary.ByCluster},
+		{"RULE", summary.ByRule},
+		{"VARIANT", summary.ByVariant},
+	}
+	for _, section := range sections {
+		if len(section.counts) == 0 {
+			continue
+		}
+		fmt.Fprintln(w)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "%s\tPROMPTED\tDENIED\tSEVERE\tDENIED %%\tVERDICT\n", section.heading)
+		for _, c := range section.counts {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.0f%%\t%s\n", c.Key, c.Prompted(), c.Denied, c.Severe, c.DenialRate()*100, orDash(c.Verdict()))
+		}
+		tw.Flush()
+	}
+
+	fmt.Fprintf(w, "\n%s: denials are frequent or severe, so the prompt is stopping mistakes.\n", audit.VerdictPreventing)
+	fmt.Fprintf(w, "%s: almost always confirmed; consider tuning the rule.\n", audit.VerdictNoise)
+}
+
+// orDash renders an empty table cell as "-"
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// count renders n with the singular or plural noun
+func count(n int, singular, plural string) string {
+	if n == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
@@ -2,10 +2,12 @@ package prompt
 
 import (
 	"bytes"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/drain"
 	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
 )
 
@@ -317,3 +319,335 @@ func TestDisplayURLWarning(t *testing.T) {
 		t.Error("Expected URL in output")
 	}
 }
+
+func TestDisplayURLWarningProvenance(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected []string
+	}{
+		{"https://raw.githubusercontent.com/org/repo/main/deploy/app.yaml", []string{"Host: github.com", "Repo: org/repo", "Ref:  main (branch or tag: its content can change; a commit pins it)", "Path: deploy/app.yaml"}},
+		{"https://raw.githubusercontent.com/org/repo/3f786850e387550fdab836ed7e6dc881de23001b/app.yaml", []string{"Ref:  3f786850e387550fdab836ed7e6dc881de23001b (commit)"}},
+		{"https://example.com/manifest.yaml", []string{"└── Host: example.com"}},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		DisplayURLWarningTo(&buf, tt.url)
+		for _, expected := range tt.expected {
+			if !strings.Contains(buf.String(), expected) {
+				t.Errorf("%s: expected %q in:\n%s", tt.url, expected, buf.String())
+			}
+		}
+	}
+}
+
+func TestAskTypedConfirmationFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{"exact match", "foo\n", true},
+		{"match with spaces", "  foo  \n", true},
+		{"y is not enough", "y\n", false},
+		{"wrong case", "FOO\n", false},
+		{"empty", "\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			result := AskTypedConfirmationFrom(strings.NewReader(tt.input), &output, "foo")
+			if result != tt.expected {
+				t.Errorf("AskTypedConfirmationFrom(%q) = %v, expected %v", tt.input, result, tt.expected)
+			}
+			if !strings.Contains(output.String(), "to confirm:") {
+				t.Error("expected prompt to be written to output")
+			}
+		})
+	}
+}
+
+func TestDisplayNamespacePreviewTo(t *testing.T) {
+	var buf bytes.Buffer
+	DisplayNamespacePreviewTo(&buf, "foo", []string{"pod/a", "service/b"})
+	output := buf.String()
+
+	for _, part := range []string{"namespace foo", "├── pod/a", "└── service/b"} {
+		if !strings.Contains(output, part) {
+			t.Errorf("expected output to contain %q, got:\n%s", part, output)
+		}
+	}
+}
+
+func TestDisplayNamespacePreviewToEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	DisplayNamespacePreviewTo(&buf, "foo", nil)
+
+	if !strings.Contains(buf.String(), "(none found)") {
+		t.Errorf("expected empty marker, got:\n%s", buf.String())
+	}
+}
+
+func TestDisplayWarningShowsReasons(t *testing.T) {
+	result := &checker.CheckResult{
+		Operation: "delete",
+		Resources: []string{"ns/foo"},
+		Cluster:   "prod-cluster",
+		Reasons:   []string{"dangerous operation: delete", "namespace deletion removes ALL resources in: foo"},
+	}
+
+	var buf bytes.Buffer
+	DisplayWarningTo(&buf, result, []string{"delete", "ns", "foo"})
+	output := buf.String()
+
+	if !strings.Contains(output, "Reasons:") {
+		t.Errorf("expected Reasons section, got:\n%s", output)
+	}
+	if !strings.Contains(output, "└── namespace deletion removes ALL resources in: foo") {
+		t.Errorf("expected closing reason branch, got:\n%s", output)
+	}
+}
+
+func TestDisplayDrainPlanTo(t *testing.T) {
+	plan := &drain.Plan{
+		Selector: "pool=blue",
+		Steps: []drain.Step{
+			{Node: drain.Node{Name: "n1", Zone: "zone-a"}, Pods: 3},
+			{Node: drain.Node{Name: "n2"}, Pods: 2, GuardedPods: 1, BlockingPDB: []drain.PDB{{Namespace: "web", Name: "web-pdb"}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	DisplayDrainPlanTo(&buf, plan, "prod-cluster", []string{"--ignore-daemonsets"}, nil)
+	output := buf.String()
+
+	for _, part := range []string{"DRAIN PLAN", "pool=blue", "prod-cluster", "--ignore-daemonsets", "└── Order:", "1. n1 zone=zone-a", "2. n2 zone=(no zone)", "web/web-pdb"} {
+		if !strings.Contains(output, part) {
+			t.Errorf("expected output to contain %q, got:\n%s", part, output)
+		}
+	}
+
+	buf.Reset()
+	DisplayDrainPlanTo(&buf, plan, "prod-cluster", nil, []string{"protected node: n2"})
+	output = buf.String()
+	for _, part := range []string{"├── Order:", "│   ├── 1. n1", "│   └── 2. n2", "└── Reasons:", "    └── protected node: n2"} {
+		if !strings.Contains(output, part) {
+			t.Errorf("expected output to contain %q, got:\n%s", part, output)
+		}
+	}
+}
+
+func TestDisplayWarningClusterScoped(t *testing.T) {
+	result := &checker.CheckResult{
+		Operation:       "delete",
+		Resources:       []string{"crd/widgets.example.com"},
+		Namespace:       "default",
+		Cluster:         "prod-cluster",
+		IsClusterScoped: true,
+	}
+
+	var buf bytes.Buffer
+	DisplayWarningTo(&buf, result, []string{"delete", "crd/widgets.example.com"})
+
+	if strings.Contains(buf.String(), "Namespace:") {
+		t.Errorf("cluster-scoped operations should not show namespace, got: %s", buf.String())
+	}
+}
+
+func TestDisplayResourceWarningClusterScoped(t *testing.T) {
+	result := &checker.ResourceCheckResult{
+		Operation: "apply",
+		Cluster:   "prod-cluster",
+		Resources: []manifest.Resource{{Kind: "ClusterRole", Name: "admin"}},
+	}
+
+	var buf bytes.Buffer
+	DisplayResourceWarningTo(&buf, result, []string{"apply", "-f", "role.yaml"})
+
+	if !strings.Contains(buf.String(), "ClusterRole/admin (cluster-scoped)") {
+		t.Errorf("expected cluster-scoped marker, got:\n%s", buf.String())
+	}
+}
+
+func TestAskConfirmationFromSharedReader(t *testing.T) {
+	// Consecutive prompts on one reader must each consume exactly one line
+	input := strings.NewReader("y\nn\nfoo\n")
+	var output bytes.Buffer
+
+	if !AskConfirmationFrom(input, &output) {
+		t.Error("expected first prompt to read 'y'")
+	}
+	if AskConfirmationFrom(input, &output) {
+		t.Error("expected second prompt to read 'n'")
+	}
+	if !AskTypedConfirmationFrom(input, &output, "foo") {
+		t.Error("expected third prompt to read 'foo'")
+	}
+}
+
+func TestDisplayCanaryTo(t *testing.T) {
+	var buf bytes.Buffer
+	DisplayCanaryPhaseTo(&buf, "canary", []manifest.Resource{{Kind: "Deployment", Name: "web"}})
+	DisplayCanaryHealthTo(&buf, manifest.Resource{Kind: "Deployment", Name: "web"}, false)
+	DisplayCanaryPauseTo(&buf, 3, false)
+	output := buf.String()
+
+	for _, part := range []string{"Applying canary phase (1 resources)", "└── Deployment/web", "did not roll out", "CANARY UNHEALTHY", "3 resources remaining"} {
+		if !strings.Contains(output, part) {
+			t.Errorf("expected output to contain %q, got:\n%s", part, output)
+		}
+	}
+}
+
+func TestDisplayWarningShowsIdentity(t *testing.T) {
+	result := &checker.CheckResult{
+		Operation: "delete",
+		Resources: []string{"pod/nginx"},
+		Namespace: "production",
+		Cluster:   "prod-cluster",
+		Identity:  "system:serviceaccount:ci:deployer",
+	}
+
+	var buf bytes.Buffer
+	DisplayWarningTo(&buf, result, []string{"delete", "pod", "nginx"})
+	if !strings.Contains(buf.String(), "├── Identity:  system:serviceaccount:ci:deployer") {
+		t.Errorf("expected identity line, got:\n%s", buf.String())
+	}
+
+	result.Identity = ""
+	buf.Reset()
+	DisplayWarningTo(&buf, result, []string{"delete", "pod", "nginx"})
+	if strings.Contains(buf.String(), "Identity:") {
+		t.Errorf("expected no identity line when unknown, got:\n%s", buf.String())
+	}
+}
+
+func TestAskResourcesToSkipFrom(t *testing.T) {
+	resources := []manifest.Resource{
+		{Kind: "ConfigMap", Name: "dns", Namespace: "kube-system"},
+		{Kind: "ConfigMap", Name: "proxy", Namespace: "kube-system"},
+	}
+	tests := []struct {
+		input    string
+		expected []int
+		ok       bool
+	}{
+		{"1,2\n", []int{0, 1}, true},
+		{"2\n", []int{1}, true},
+		{"\n", nil, true},
+		{"3\n", nil, false},
+		{"x\n", nil, false},
+	}
+
+	for _, tt := range tests {
+		var output bytes.Buffer
+		skip, ok := AskResourcesToSkipFrom(strings.NewReader(tt.input), &output, resources)
+		if ok != tt.ok || !reflect.DeepEqual(skip, tt.expected) {
+			t.Errorf("input %q: got %v %v, expected %v %v", tt.input, skip, ok, tt.expected, tt.ok)
+		}
+		if !strings.Contains(output.String(), "[2] ConfigMap/proxy in namespace kube-system") {
+			t.Errorf("expected numbered resources, got:\n%s", output.String())
+		}
+	}
+}
+
+func TestDisplayWarningSeverity(t *testing.T) {
+	tests := []struct {
+		name             string
+		reasons          []string
+		expectedHeadline string
+	}{
+		{"medium", []string{"dangerous operation: delete"}, colorYellow + warningIcon() + "  DANGEROUS OPERATION DETECTED [MEDIUM]"},
+		{"high", []string{"dangerous operation: delete", "protected cluster: prod"}, colorRed + warningIcon() + "  DANGEROUS OPERATION DETECTED [HIGH]"},
+		{"critical", []string{"dangerous operation: delete", "protected namespace: kube-system", "protected cluster: prod"}, colorBanner + warningIcon() + "  DANGEROUS OPERATION DETECTED [CRITICAL]"},
+		{"low", []string{"secret exposure: get secret db -o yaml prints its data"}, colorCyan + warningIcon() + "  DANGEROUS OPERATION DETECTED [LOW]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &checker.CheckResult{IsDangerous: true, Operation: "delete", Cluster: "prod", Reasons: tt.reasons}
+			var buf bytes.Buffer
+			DisplayWarningTo(&buf, result, []string{"delete", "pod", "web"})
+			if !strings.Contains(buf.String(), tt.expectedHeadline) {
+				t.Errorf("expected headline %q, got:\n%s", tt.expectedHeadline, buf.String())
+			}
+		})
+	}
+}
+
+func TestDisplayWarningTemplate(t *testing.T) {
+	result := &checker.CheckResult{IsDangerous: true, Operation: "delete", Cluster: "prod", Reasons: []string{"dangerous operation: delete", "protected cluster: prod"}}
+	text := `{{ .Severity }}: {{ .Command }} on {{ .Cluster }}
+{{ join .Reasons "; " }}
+Runbook: https://wiki.example.com/runbooks/{{ .Operation }}`
+
+	var buf bytes.Buffer
+	if err := DisplayWarningTemplateTo(&buf, text, result, []string{"delete", "pod", "web"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "\nHIGH: kubectl delete pod web on prod\ndangerous operation: delete; protected cluster: prod\nRunbook: https://wiki.example.com/runbooks/delete\n\n"
+	if buf.String() != expected {
+		t.Errorf("got %q, expected %q", buf.String(), expected)
+	}
+
+	buf.Reset()
+	if err := DisplayWarningTemplateTo(&buf, "{{ .NoSuchField }}", result, nil); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing written for a failed render, got %q", buf.String())
+	}
+
+	resources := &checker.ResourceCheckResult{IsDangerous: true, Operation: "apply", Resources: []manifest.Resource{{Kind: "Deployment", Name: "web"}}}
+	buf.Reset()
+	if err := DisplayResourceWarningTemplateTo(&buf, "{{ range .Resources }}{{ . }} {{ end }}", resources, []string{"apply", "-f", "web.yaml"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Deployment/web") {
+		t.Errorf("expected the resources in the warning, got %q", buf.String())
+	}
+}
+
+func TestDisplayWarningLanguage(t *testing.T) {
+	SetLanguage("es")
+	t.Cleanup(func() { SetLanguage("en") })
+
+	result := &checker.CheckResult{
+		IsDangerous: true,
+		Operation:   "delete",
+		Resources:   []string{"pod/nginx"},
+		Namespace:   "production",
+		Cluster:     "prod-cluster",
+		Reasons:     []string{"protected cluster: prod-cluster"},
+	}
+	var buf bytes.Buffer
+	DisplayWarningTo(&buf, result, []string{"delete", "pod", "nginx"})
+	for _, want := range []string{
+		"OPERACIÓN PELIGROSA DETECTADA",
+		"├── Operación: ",
+		"├── Clúster:   prod-cluster",
+		"├── Recursos afectados:",
+		"└── Motivos:",
+		"protected cluster: prod-cluster",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output, got:\n%s", want, buf.String())
+		}
+	}
+
+	var output bytes.Buffer
+	if !AskConfirmationFrom(strings.NewReader("sí\n"), &output) {
+		t.Error("expected sí to confirm in Spanish")
+	}
+	if output.String() != "¿Continuar? [s/N]: " {
+		t.Errorf("unexpected prompt %q", output.String())
+	}
+	output.Reset()
+	if !AskTypedConfirmationFrom(strings.NewReader("prod-cluster\n"), &output, "prod-cluster") {
+		t.Error("expected the typed phrase to confirm")
+	}
+	if !strings.HasPrefix(output.String(), "Escriba ") {
+		t.Errorf("unexpected prompt %q", output.String())
+	}
+}
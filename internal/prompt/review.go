@@ -0,0 +1,152 @@
+package prompt
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// clusterScopedGroup names the review group of resources without a namespace
+const clusterScopedGroup = "(cluster-scoped)"
+
+// reviewGroup is the resources of one namespace in a review
+type reviewGroup struct {
+	namespace string
+	resources []manifest.Resource
+}
+
+// groupByNamespace groups resources by namespace, cluster-scoped ones first,
+// then namespaces by name
+func groupByNamespace(resources []manifest.Resource) []reviewGroup {
+	index := map[string]int{}
+	var groups []reviewGroup
+	for _, r := range resources {
+		ns := r.Namespace
+		if ns == "" && parser.IsClusterScopedKind(r.Kind) {
+			ns = clusterScopedGroup
+		}
+		i, ok := index[ns]
+		if !ok {
+			i = len(groups)
+			index[ns] = i
+			groups = append(groups, reviewGroup{namespace: ns})
+		}
+		groups[i].resources = append(groups[i].resources, r)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if (groups[i].namespace == clusterScopedGroup) != (groups[j].namespace == clusterScopedGroup) {
+			return groups[i].namespace == clusterScopedGroup
+		}
+		return groups[i].namespace < groups[j].namespace
+	})
+	return groups
+}
+
+// kindSummary counts a group's resources per kind, e.g. "3 Deployment, 1 Service"
+func kindSummary(resources []manifest.Resource) string {
+	counts := map[string]int{}
+	var kinds []string
+	for _, r := range resources {
+		if counts[r.Kind] == 0 {
+			kinds = append(kinds, r.Kind)
+		}
+		counts[r.Kind]++
+	}
+	parts := make([]string, len(kinds))
+	for i, kind := range kinds {
+		parts[i] = fmt.Sprintf("%d %s", counts[kind], kind)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ReviewResourcesFrom lets the user page through the resources of a large
+// file-based operation, grouped by namespace, expanding any namespace to see
+// its resources, before confirming or aborting. Pages are turned with ">" and
+// "<" (or "]" and "[") so that "n", as at every other prompt, means no. Only
+// "y" or "yes" confirms; "n", "no", anything unrecognized, or the end of
+// input aborts.
+func ReviewResourcesFrom(r io.Reader, w io.Writer, result *checker.ResourceCheckResult, args []string, variant config.WarningVariant, pageSize int) bool {
+	groups := groupByNamespace(result.Resources)
+	pages := (len(groups) + pageSize - 1) / pageSize
+	expanded := map[int]bool{}
+	page := 0
+
+	for {
+		displayReviewPageTo(w, result, args, variant, groups, expanded, page, pageSize)
+		if pages > 1 {
+			fmt.Fprint(w, "[>] next/[<] previous page, ")
+		}
+		fmt.Fprint(w, "NUMBER to expand/collapse a namespace, [y]es to proceed or [n]o to abort: ")
+
+		response, err := readLine(r)
+		if err != nil {
+			return false
+		}
+		response = strings.TrimSpace(strings.ToLower(response))
+		switch response {
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		case ">", "]":
+			if page < pages-1 {
+				page++
+			}
+			continue
+		case "<", "[":
+			if page > 0 {
+				page--
+			}
+			continue
+		}
+		if n, err := strconv.Atoi(response); err == nil && n >= 1 && n <= len(groups) {
+			expanded[n-1] = !expanded[n-1]
+			page = (n - 1) / pageSize
+			continue
+		}
+		return false
+	}
+}
+
+// displayReviewPageTo writes the warning header and one page of namespace groups
+func displayReviewPageTo(w io.Writer, result *checker.ResourceCheckResult, args []string, variant config.WarningVariant, groups []reviewGroup, expanded map[int]bool, page, pageSize int) {
+	fmt.Fprintln(w)
+	displayHeadlineTo(w, variant, result.Severity())
+	fmt.Fprintf(w, "├── %s%s%s%s\n", label("Operation"), colorRed, result.Operation, colorReset)
+	fmt.Fprintf(w, "├── %s%s\n", label("Cluster"), result.Cluster)
+	if result.Identity != "" {
+		fmt.Fprintf(w, "├── %s%s\n", label("Identity"), result.Identity)
+	}
+	fmt.Fprintf(w, "├── %skubectl %s\n", label("Command"), strings.Join(args, " "))
+	fmt.Fprintf(w, "├── Resources: %s in %s (page %d of %d)\n",
+		count(len(result.Resources), "resource", "resources"), count(len(groups), "namespace", "namespaces"),
+		page+1, (len(groups)+pageSize-1)/pageSize)
+
+	end := min((page+1)*pageSize, len(groups))
+	for i := page * pageSize; i < end; i++ {
+		g := groups[i]
+		fmt.Fprintf(w, "│   [%d] %s: %s\n", i+1, g.namespace, kindSummary(g.resources))
+		if !expanded[i] {
+			continue
+		}
+		for j, res := range g.resources {
+			prefix := "│       ├──"
+			if j == len(g.resources)-1 {
+				prefix = "│       └──"
+			}
+			fmt.Fprintf(w, "%s %s (%s)\n", prefix, res.String(), res.Source)
+		}
+	}
+
+	if len(result.Reasons) > 0 {
+		displayReasonsTo(w, result.Reasons)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,108 @@
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+)
+
+func reviewResult() *checker.ResourceCheckResult {
+	result := &checker.ResourceCheckResult{Operation: "apply", Cluster: "prod", Reasons: []string{"dangerous operation: apply"}}
+	result.Resources = append(result.Resources, manifest.Resource{Kind: "ClusterRole", Name: "reader", Source: "k8s/rbac.yaml"})
+	for i := 0; i < 3; i++ {
+		ns := fmt.Sprintf("team-%d", i)
+		result.Resources = append(result.Resources,
+			manifest.Resource{Kind: "Deployment", Name: "web", Namespace: ns, Source: "k8s/" + ns + ".yaml"},
+			manifest.Resource{Kind: "Service", Name: "web", Namespace: ns, Source: "k8s/" + ns + ".yaml"},
+		)
+	}
+	return result
+}
+
+func TestGroupByNamespace(t *testing.T) {
+	groups := groupByNamespace(reviewResult().Resources)
+	var names []string
+	for _, g := range groups {
+		names = append(names, fmt.Sprintf("%s:%d", g.namespace, len(g.resources)))
+	}
+	expected := "(cluster-scoped):1 team-0:2 team-1:2 team-2:2"
+	if got := strings.Join(names, " "); got != expected {
+		t.Errorf("got %s, expected %s", got, expected)
+	}
+}
+
+func TestReviewResourcesFrom(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      bool
+		expectedParts []string
+		absentParts   []string
+	}{
+		{
+			name:          "confirm on the first page",
+			input:         "y\n",
+			expected:      true,
+			expectedParts: []string{"7 resources in 4 namespaces (page 1 of 2)", "[1] (cluster-scoped): 1 ClusterRole", "[2] team-0: 1 Deployment, 1 Service", "dangerous operation: apply"},
+			absentParts:   []string{"[3] team-1", "Deployment/web (k8s/team-0.yaml)"},
+		},
+		{
+			name:          "next page",
+			input:         ">\ny\n",
+			expected:      true,
+			expectedParts: []string{"(page 2 of 2)", "[3] team-1", "[4] team-2"},
+		},
+		{
+			name:          "next and previous page with brackets",
+			input:         "]\n[\ny\n",
+			expected:      true,
+			expectedParts: []string{"(page 2 of 2)", "[3] team-1"},
+		},
+		{
+			name:          "expand a namespace",
+			input:         "4\ny\n",
+			expected:      true,
+			expectedParts: []string{"├── Deployment/web (k8s/team-2.yaml)", "└── Service/web (k8s/team-2.yaml)"},
+		},
+		{
+			name:     "abort",
+			input:    ">\n<\na\n",
+			expected: false,
+		},
+		{
+			name:          "no aborts",
+			input:         "n\ny\n",
+			expected:      false,
+			expectedParts: []string{"(page 1 of 2)"},
+			absentParts:   []string{"(page 2 of 2)"},
+		},
+		{
+			name:     "end of input aborts",
+			input:    ">\n",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		got := ReviewResourcesFrom(strings.NewReader(tt.input), &buf, reviewResult(), []string{"apply", "-f", "k8s/", "-R"}, config.WarningVariant{}, 2)
+		if got != tt.expected {
+			t.Errorf("%s: got %v, expected %v", tt.name, got, tt.expected)
+		}
+		for _, part := range tt.expectedParts {
+			if !strings.Contains(buf.String(), part) {
+				t.Errorf("%s: expected %q in output, got:\n%s", tt.name, part, buf.String())
+			}
+		}
+		for _, part := range tt.absentParts {
+			if strings.Contains(buf.String(), part) {
+				t.Errorf("%s: expected no %q in output, got:\n%s", tt.name, part, buf.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+)
+
+// warningData is what a templates.warning template sees: the check result's
+// fields and methods, such as .Reasons and .Severity, and the kubectl command
+type warningData struct {
+	*checker.CheckResult
+	Command string
+}
+
+// resourceWarningData is what a templates.resourceWarning template sees
+type resourceWarningData struct {
+	*checker.ResourceCheckResult
+	Command string
+}
+
+// DisplayWarningTemplateTo writes the warning rendered from a
+// templates.warning template. Nothing is written if it fails to render.
+func DisplayWarningTemplateTo(w io.Writer, text string, result *checker.CheckResult, args []string) error {
+	return displayTemplateTo(w, "templates.warning", text, warningData{CheckResult: result, Command: "kubectl " + strings.Join(args, " ")})
+}
+
+// DisplayResourceWarningTemplateTo writes the warning for a file-based command
+// rendered from a templates.resourceWarning template
+func DisplayResourceWarningTemplateTo(w io.Writer, text string, result *checker.ResourceCheckResult, args []string) error {
+	return displayTemplateTo(w, "templates.resourceWarning", text, resourceWarningData{ResourceCheckResult: result, Command: "kubectl " + strings.Join(args, " ")})
+}
+
+// displayTemplateTo renders a warning template in full before writing it, so
+// a failed render leaves room for the built-in warning
+func displayTemplateTo(w io.Writer, key, text string, data any) error {
+	tmpl, err := config.ParseTemplate(key, text)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", key, err)
+	}
+	fmt.Fprintln(w)
+	w.Write(out.Bytes())
+	if !bytes.HasSuffix(out.Bytes(), []byte("\n")) {
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+	return nil
+}
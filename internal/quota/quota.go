@@ -0,0 +1,129 @@
+package quota
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds are the namespace guardrails whose removal or weakening is flagged
+var Kinds = map[string]bool{
+	"ResourceQuota": true,
+	"LimitRange":    true,
+}
+
+type guardrailDoc struct {
+	Spec struct {
+		Hard   map[string]string `yaml:"hard"`
+		Limits []struct {
+			Type string            `yaml:"type"`
+			Max  map[string]string `yaml:"max"`
+			Min  map[string]string `yaml:"min"`
+		} `yaml:"limits"`
+	} `yaml:"spec"`
+}
+
+// limit is one ceiling or floor a guardrail enforces
+type limit struct {
+	value string
+	floor bool // a minimum: lowering it weakens the guardrail
+}
+
+// limits flattens a ResourceQuota's hard limits or a LimitRange's min/max
+// into keys like "hard limits.memory" or "Container max cpu"
+func limits(content []byte) (map[string]limit, error) {
+	var doc guardrailDoc
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse guardrail: %w", err)
+	}
+
+	out := make(map[string]limit)
+	for resource, value := range doc.Spec.Hard {
+		out["hard "+resource] = limit{value: value}
+	}
+	for _, l := range doc.Spec.Limits {
+		for resource, value := range l.Max {
+			out[l.Type+" max "+resource] = limit{value: value}
+		}
+		for resource, value := range l.Min {
+			out[l.Type+" min "+resource] = limit{value: value, floor: true}
+		}
+	}
+	return out, nil
+}
+
+// WeakeningReasons compares a live ResourceQuota or LimitRange with the desired
+// one (YAML or JSON) and describes removed limits, raised maximums and lowered
+// minimums
+func WeakeningReasons(live, desired []byte) ([]string, error) {
+	before, err := limits(live)
+	if err != nil {
+		return nil, err
+	}
+	after, err := limits(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(before))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var reasons []string
+	for _, key := range keys {
+		old := before[key]
+		updated, ok := after[key]
+		if !ok {
+			reasons = append(reasons, fmt.Sprintf("removes %s (was %s)", key, old.value))
+			continue
+		}
+		oldValue, err1 := ParseQuantity(old.value)
+		newValue, err2 := ParseQuantity(updated.value)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if (!old.floor && newValue > oldValue) || (old.floor && newValue < oldValue) {
+			direction := "raises"
+			if old.floor {
+				direction = "lowers"
+			}
+			reasons = append(reasons, fmt.Sprintf("%s %s from %s to %s", direction, key, old.value, updated.value))
+		}
+	}
+	return reasons, nil
+}
+
+// quantitySuffixes are the Kubernetes quantity suffixes and their multipliers
+var quantitySuffixes = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40}, {"Pi", 1 << 50}, {"Ei", 1 << 60},
+	{"n", 1e-9}, {"u", 1e-6}, {"m", 1e-3},
+	{"k", 1e3}, {"M", 1e6}, {"G", 1e9}, {"T", 1e12}, {"P", 1e15}, {"E", 1e18},
+}
+
+// ParseQuantity converts a Kubernetes resource quantity such as "500m",
+// "10Gi" or "1e3" to a number
+func ParseQuantity(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	for _, q := range quantitySuffixes {
+		if number, ok := strings.CutSuffix(s, q.suffix); ok && number != "" {
+			value, err := strconv.ParseFloat(number, 64)
+			if err == nil {
+				return value * q.multiplier, nil
+			}
+		}
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("invalid quantity %q", s)
+	}
+	return value, nil
+}
@@ -0,0 +1,84 @@
+package quota
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"10", 10},
+		{"500m", 0.5},
+		{"2Gi", 2 << 30},
+		{"1.5k", 1500},
+		{"1e3", 1000},
+		{"4M", 4e6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseQuantity(tt.input)
+			if err != nil {
+				t.Fatalf("ParseQuantity(%q) error = %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseQuantity(%q): got %v, expected %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"", "lots", "Gi"} {
+		if _, err := ParseQuantity(invalid); err == nil {
+			t.Errorf("ParseQuantity(%q): expected error", invalid)
+		}
+	}
+}
+
+func TestWeakeningReasons(t *testing.T) {
+	tests := []struct {
+		name     string
+		live     string
+		desired  string
+		expected []string
+	}{
+		{
+			name:    "quota raised and removed",
+			live:    `{"spec":{"hard":{"limits.memory":"10Gi","pods":"20","requests.cpu":"4"}}}`,
+			desired: "spec:\n  hard:\n    limits.memory: 100Gi\n    pods: 10\n",
+			expected: []string{
+				"raises hard limits.memory from 10Gi to 100Gi",
+				"removes hard requests.cpu (was 4)",
+			},
+		},
+		{
+			name:     "quota tightened",
+			live:     `{"spec":{"hard":{"pods":"20"}}}`,
+			desired:  "spec:\n  hard:\n    pods: \"10\"\n    services: 5\n",
+			expected: nil,
+		},
+		{
+			name:    "limit range max raised and min lowered",
+			live:    `{"spec":{"limits":[{"type":"Container","max":{"cpu":"2"},"min":{"memory":"64Mi"}}]}}`,
+			desired: "spec:\n  limits:\n  - type: Container\n    max:\n      cpu: 2500m\n    min:\n      memory: 32Mi\n",
+			expected: []string{
+				"raises Container max cpu from 2 to 2500m",
+				"lowers Container min memory from 64Mi to 32Mi",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WeakeningReasons([]byte(tt.live), []byte(tt.desired))
+			if err != nil {
+				t.Fatalf("WeakeningReasons() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
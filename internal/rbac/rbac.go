@@ -0,0 +1,76 @@
+package rbac
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ServiceAccountTokenType is the Secret type of long-lived ServiceAccount tokens
+const ServiceAccountTokenType = "kubernetes.io/service-account-token"
+
+// Subject is a user, group or ServiceAccount a binding grants its role to
+type Subject struct {
+	Kind      string // User, Group or ServiceAccount
+	Name      string
+	Namespace string // ServiceAccounts only
+}
+
+// String returns a display string like "serviceaccount/ci/deployer" or "user/alice"
+func (s Subject) String() string {
+	if s.Kind == "ServiceAccount" {
+		return "serviceaccount/" + s.Namespace + "/" + s.Name
+	}
+	return strings.ToLower(s.Kind) + "/" + s.Name
+}
+
+type bindingJSON struct {
+	Metadata struct {
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Subjects []struct {
+		Kind      string `json:"kind"`
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"subjects"`
+}
+
+// ParseSubjects parses a RoleBinding or ClusterRoleBinding (JSON) and returns
+// its subjects. ServiceAccounts without a namespace default to the binding's.
+func ParseSubjects(content []byte) ([]Subject, error) {
+	var b bindingJSON
+	if err := json.Unmarshal(content, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse binding: %w", err)
+	}
+
+	subjects := make([]Subject, 0, len(b.Subjects))
+	for _, s := range b.Subjects {
+		subject := Subject{Kind: s.Kind, Name: s.Name}
+		if s.Kind == "ServiceAccount" {
+			subject.Namespace = s.Namespace
+			if subject.Namespace == "" {
+				subject.Namespace = b.Metadata.Namespace
+			}
+		}
+		subjects = append(subjects, subject)
+	}
+	return subjects, nil
+}
+
+// TokenOwner parses a Secret (JSON) and returns the ServiceAccount it holds a
+// token for, or "" if it is not a ServiceAccount token
+func TokenOwner(content []byte) (string, error) {
+	var secret struct {
+		Type     string `json:"type"`
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(content, &secret); err != nil {
+		return "", fmt.Errorf("failed to parse secret: %w", err)
+	}
+	if secret.Type != ServiceAccountTokenType {
+		return "", nil
+	}
+	return secret.Metadata.Annotations["kubernetes.io/service-account.name"], nil
+}
@@ -0,0 +1,68 @@
+package rbac
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSubjects(t *testing.T) {
+	content := []byte(`{"metadata":{"name":"deployers","namespace":"ci"},"subjects":[
+		{"kind":"ServiceAccount","name":"deployer"},
+		{"kind":"ServiceAccount","name":"runner","namespace":"build"},
+		{"kind":"User","name":"alice","apiGroup":"rbac.authorization.k8s.io"},
+		{"kind":"Group","name":"devs","apiGroup":"rbac.authorization.k8s.io"}]}`)
+
+	subjects, err := ParseSubjects(content)
+	if err != nil {
+		t.Fatalf("ParseSubjects() error = %v", err)
+	}
+
+	var got []string
+	for _, s := range subjects {
+		got = append(got, s.String())
+	}
+	expected := []string{"serviceaccount/ci/deployer", "serviceaccount/build/runner", "user/alice", "group/devs"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("subjects: got %v, expected %v", got, expected)
+	}
+}
+
+func TestTokenOwner(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "service account token",
+			content:  `{"type":"kubernetes.io/service-account-token","metadata":{"annotations":{"kubernetes.io/service-account.name":"deployer"}}}`,
+			expected: "deployer",
+		},
+		{
+			name:     "opaque secret",
+			content:  `{"type":"Opaque","metadata":{"annotations":{"kubernetes.io/service-account.name":"deployer"}}}`,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner, err := TokenOwner([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("TokenOwner() error = %v", err)
+			}
+			if owner != tt.expected {
+				t.Errorf("owner: got %q, expected %q", owner, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	if _, err := ParseSubjects([]byte("not json")); err == nil {
+		t.Error("ParseSubjects: expected error for invalid JSON")
+	}
+	if _, err := TokenOwner([]byte("not json")); err == nil {
+		t.Error("TokenOwner: expected error for invalid JSON")
+	}
+}
@@ -0,0 +1,187 @@
+// Package redact masks secrets in kubectl's output before it reaches the
+// terminal: the data values of Secrets printed as YAML or JSON, and anything
+// matching configured patterns.
+package redact
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Mask replaces every redacted value
+const Mask = "<redacted>"
+
+// lastAppliedKey is the annotation kubectl apply keeps the whole applied
+// object in, Secret data included
+const lastAppliedKey = "kubectl.kubernetes.io/last-applied-configuration"
+
+var (
+	yamlDataBlock   = regexp.MustCompile(`^(\s*)(- )?(data|stringData):\s*$`)
+	yamlKeyValue    = regexp.MustCompile(`^(\s*)("[^"]*"|[^\s:][^:]*):(\s+)(.+)$`)
+	yamlLastApplied = regexp.MustCompile(`^(\s*)` + regexp.QuoteMeta(lastAppliedKey) + `:(\s+)(.+)$`)
+	jsonDataBlock   = regexp.MustCompile(`^(\s*)"(data|stringData)": \{\s*$`)
+	jsonKeyValue    = regexp.MustCompile(`^(\s*"[^"]*": )"(?:[^"\\]|\\.)*"(,?)\s*$`)
+	jsonLastApplied = regexp.MustCompile(`^(\s*"` + regexp.QuoteMeta(lastAppliedKey) + `": )"(?:[^"\\]|\\.)*"(,?)\s*$`)
+)
+
+// Redactor masks secrets in output, line by line
+type Redactor struct {
+	secrets  bool // the output may hold Secrets, so data blocks are masked
+	patterns []*regexp.Regexp
+}
+
+// New returns a Redactor masking the matches of patterns, or only their
+// first group if they have one. With secrets set, the values under data and
+// stringData and the last-applied-configuration annotation are masked too.
+func New(patterns []string, secrets bool) (*Redactor, error) {
+	r := &Redactor{secrets: secrets}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Writer returns a writer that masks what is written to it and passes it on
+// to w a line at a time. Close flushes a final unterminated line.
+func (r *Redactor) Writer(w io.Writer) io.WriteCloser {
+	return &writer{redactor: r, out: w, blockIndent: -1}
+}
+
+type writer struct {
+	redactor *Redactor
+	out      io.Writer
+	pending  []byte
+
+	blockIndent int  // indentation of the data key whose values are masked; -1 outside one
+	jsonBlock   bool // the block is a JSON object rather than a YAML mapping
+	valueIndent int  // indentation of the YAML keys in the block; 0 until the first is seen
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := w.redactLine(string(w.pending[:i]))
+		w.pending = w.pending[i+1:]
+		if _, err := io.WriteString(w.out, line+"\n"); err != nil {
+			return len(p), err
+		}
+	}
+}
+
+func (w *writer) Close() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	line := w.redactLine(string(w.pending))
+	w.pending = nil
+	_, err := io.WriteString(w.out, line)
+	return err
+}
+
+// redactLine masks one line of output
+func (w *writer) redactLine(line string) string {
+	if w.redactor.secrets {
+		line = w.redactSecretData(line)
+	}
+	for _, re := range w.redactor.patterns {
+		line = maskMatches(re, line)
+	}
+	return line
+}
+
+// redactSecretData masks the values of data blocks, YAML or JSON, and the
+// last-applied-configuration annotation
+func (w *writer) redactSecretData(line string) string {
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+
+	if w.blockIndent >= 0 {
+		switch {
+		case w.jsonBlock && strings.HasPrefix(strings.TrimSpace(line), "}") && indent <= w.blockIndent:
+			w.blockIndent = -1
+		case w.jsonBlock:
+			if m := jsonKeyValue.FindStringSubmatch(line); m != nil {
+				return m[1] + `"` + Mask + `"` + m[2]
+			}
+			return line
+		case strings.TrimSpace(line) == "":
+			return line
+		case indent <= w.blockIndent:
+			w.blockIndent = -1
+		default:
+			if w.valueIndent == 0 {
+				w.valueIndent = indent
+			}
+			if m := yamlKeyValue.FindStringSubmatch(line); indent == w.valueIndent && m != nil {
+				return m[1] + m[2] + ":" + m[3] + maskScalar(m[4])
+			}
+			// Continuation of a multi-line value
+			return line[:indent] + Mask
+		}
+	}
+
+	if m := yamlDataBlock.FindStringSubmatch(line); m != nil {
+		w.blockIndent, w.jsonBlock, w.valueIndent = len(m[1]), false, 0
+		if m[2] != "" {
+			w.blockIndent += len(m[2])
+		}
+		return line
+	}
+	if m := jsonDataBlock.FindStringSubmatch(line); m != nil {
+		w.blockIndent, w.jsonBlock = len(m[1]), true
+		return line
+	}
+	if m := yamlLastApplied.FindStringSubmatch(line); m != nil {
+		// A block scalar's lines follow, more indented: mask them as a block
+		if strings.HasPrefix(m[3], "|") || strings.HasPrefix(m[3], ">") {
+			w.blockIndent, w.jsonBlock, w.valueIndent = indent, false, 0
+			return line
+		}
+		return m[1] + lastAppliedKey + ":" + m[2] + Mask
+	}
+	if m := jsonLastApplied.FindStringSubmatch(line); m != nil {
+		return m[1] + `"` + Mask + `"` + m[2]
+	}
+	return line
+}
+
+// maskScalar masks a YAML value, keeping a block scalar indicator so the
+// lines after it still read as its content
+func maskScalar(value string) string {
+	if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+		return value
+	}
+	return Mask
+}
+
+// maskMatches masks every match of re in line, or only the first group of
+// each match if re has groups
+func maskMatches(re *regexp.Regexp, line string) string {
+	matches := re.FindAllStringSubmatchIndex(line, -1)
+	if len(matches) == 0 {
+		return line
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) > 2 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		b.WriteString(line[last:start])
+		b.WriteString(Mask)
+		last = end
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
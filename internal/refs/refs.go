@@ -0,0 +1,146 @@
+package refs
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Pod records the ConfigMaps and Secrets one pod mounts or references from
+// env, and the ServiceAccount and PriorityClass it runs with
+type Pod struct {
+	Name           string
+	ServiceAccount string
+	PriorityClass  string
+	ConfigMaps     map[string]bool
+	Secrets        map[string]bool
+}
+
+type nameRef struct {
+	Name string `json:"name"`
+}
+
+type containerJSON struct {
+	Env []struct {
+		ValueFrom struct {
+			ConfigMapKeyRef *nameRef `json:"configMapKeyRef"`
+			SecretKeyRef    *nameRef `json:"secretKeyRef"`
+		} `json:"valueFrom"`
+	} `json:"env"`
+	EnvFrom []struct {
+		ConfigMapRef *nameRef `json:"configMapRef"`
+		SecretRef    *nameRef `json:"secretRef"`
+	} `json:"envFrom"`
+}
+
+type podJSON struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec struct {
+		ServiceAccountName string `json:"serviceAccountName"`
+		PriorityClassName  string `json:"priorityClassName"`
+		Volumes            []struct {
+			ConfigMap *nameRef `json:"configMap"`
+			Secret    *struct {
+				SecretName string `json:"secretName"`
+			} `json:"secret"`
+			Projected *struct {
+				Sources []struct {
+					ConfigMap *nameRef `json:"configMap"`
+					Secret    *nameRef `json:"secret"`
+				} `json:"sources"`
+			} `json:"projected"`
+		} `json:"volumes"`
+		Containers       []containerJSON `json:"containers"`
+		InitContainers   []containerJSON `json:"initContainers"`
+		ImagePullSecrets []nameRef       `json:"imagePullSecrets"`
+	} `json:"spec"`
+}
+
+func (p podJSON) toPod() Pod {
+	pod := Pod{
+		Name:           p.Metadata.Name,
+		ServiceAccount: p.Spec.ServiceAccountName,
+		PriorityClass:  p.Spec.PriorityClassName,
+		ConfigMaps:     map[string]bool{},
+		Secrets:        map[string]bool{},
+	}
+	if pod.ServiceAccount == "" {
+		pod.ServiceAccount = "default"
+	}
+	add := func(set map[string]bool, ref *nameRef) {
+		if ref != nil && ref.Name != "" {
+			set[ref.Name] = true
+		}
+	}
+
+	for _, v := range p.Spec.Volumes {
+		add(pod.ConfigMaps, v.ConfigMap)
+		if v.Secret != nil {
+			add(pod.Secrets, &nameRef{Name: v.Secret.SecretName})
+		}
+		if v.Projected != nil {
+			for _, s := range v.Projected.Sources {
+				add(pod.ConfigMaps, s.ConfigMap)
+				add(pod.Secrets, s.Secret)
+			}
+		}
+	}
+	for _, c := range append(p.Spec.Containers, p.Spec.InitContainers...) {
+		for _, e := range c.Env {
+			add(pod.ConfigMaps, e.ValueFrom.ConfigMapKeyRef)
+			add(pod.Secrets, e.ValueFrom.SecretKeyRef)
+		}
+		for _, e := range c.EnvFrom {
+			add(pod.ConfigMaps, e.ConfigMapRef)
+			add(pod.Secrets, e.SecretRef)
+		}
+	}
+	for i := range p.Spec.ImagePullSecrets {
+		add(pod.Secrets, &p.Spec.ImagePullSecrets[i])
+	}
+	return pod
+}
+
+// ParsePods parses `kubectl get pods -o json` output
+func ParsePods(content []byte) ([]Pod, error) {
+	var list struct {
+		Items []podJSON `json:"items"`
+	}
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse pods: %w", err)
+	}
+
+	pods := make([]Pod, 0, len(list.Items))
+	for _, item := range list.Items {
+		pods = append(pods, item.toPod())
+	}
+	return pods, nil
+}
+
+// Count returns how many pods reference the named ConfigMap or Secret, or run
+// with the named ServiceAccount or PriorityClass. Other kinds are never referenced.
+func Count(pods []Pod, kind, name string) int {
+	count := 0
+	for _, pod := range pods {
+		switch kind {
+		case "ConfigMap":
+			if pod.ConfigMaps[name] {
+				count++
+			}
+		case "Secret":
+			if pod.Secrets[name] {
+				count++
+			}
+		case "ServiceAccount":
+			if pod.ServiceAccount == name {
+				count++
+			}
+		case "PriorityClass":
+			if pod.PriorityClass == name {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,56 @@
+package refs
+
+import (
+	"testing"
+)
+
+const podsJSON = `{"items":[
+	{"metadata":{"name":"web-1"},"spec":{"serviceAccountName":"web",
+		"volumes":[{"configMap":{"name":"app-config"}},{"secret":{"secretName":"tls"}}],
+		"containers":[{"env":[{"name":"DB","valueFrom":{"secretKeyRef":{"name":"db","key":"password"}}}]}]}},
+	{"metadata":{"name":"web-2"},"spec":{"serviceAccountName":"web",
+		"volumes":[{"projected":{"sources":[{"configMap":{"name":"app-config"}},{"secret":{"name":"db"}}]}}],
+		"initContainers":[{"envFrom":[{"configMapRef":{"name":"init-config"}}]}],
+		"imagePullSecrets":[{"name":"registry"}]}},
+	{"metadata":{"name":"worker"},"spec":{"priorityClassName":"batch","containers":[{"env":[{"name":"PLAIN","value":"x"}]}]}}
+]}`
+
+func TestCount(t *testing.T) {
+	pods, err := ParsePods([]byte(podsJSON))
+	if err != nil {
+		t.Fatalf("ParsePods() error = %v", err)
+	}
+
+	tests := []struct {
+		kind     string
+		name     string
+		expected int
+	}{
+		{"ConfigMap", "app-config", 2},
+		{"ConfigMap", "init-config", 1},
+		{"Secret", "db", 2},
+		{"Secret", "tls", 1},
+		{"Secret", "registry", 1},
+		{"Secret", "app-config", 0},
+		{"ConfigMap", "unused", 0},
+		{"Service", "app-config", 0},
+		{"ServiceAccount", "web", 2},
+		{"ServiceAccount", "default", 1},
+		{"PriorityClass", "batch", 1},
+		{"PriorityClass", "high", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind+"/"+tt.name, func(t *testing.T) {
+			if got := Count(pods, tt.kind, tt.name); got != tt.expected {
+				t.Errorf("Count(%s, %s): got %d, expected %d", tt.kind, tt.name, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParsePodsInvalid(t *testing.T) {
+	if _, err := ParsePods([]byte("not json")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
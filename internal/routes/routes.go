@@ -0,0 +1,151 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Route is an object that routes traffic for hostnames
+type Route struct {
+	Kind      string // Ingress, HTTPRoute or Gateway
+	Namespace string
+	Name      string
+	Hosts     []string
+}
+
+// String returns a display string like "ingress/shop/web"
+func (r Route) String() string {
+	return strings.ToLower(r.Kind) + "/" + r.Namespace + "/" + r.Name
+}
+
+// Kinds are the kinds whose hosts are checked
+var Kinds = map[string]bool{
+	"Ingress":   true,
+	"HTTPRoute": true,
+	"Gateway":   true,
+}
+
+type routeDoc struct {
+	Kind     string `yaml:"kind" json:"kind"`
+	Metadata struct {
+		Name      string `yaml:"name" json:"name"`
+		Namespace string `yaml:"namespace" json:"namespace"`
+	} `yaml:"metadata" json:"metadata"`
+	Spec struct {
+		Rules []struct {
+			Host string `yaml:"host" json:"host"`
+		} `yaml:"rules" json:"rules"`
+		TLS []struct {
+			Hosts []string `yaml:"hosts" json:"hosts"`
+		} `yaml:"tls" json:"tls"`
+		Hostnames []string `yaml:"hostnames" json:"hostnames"`
+		Listeners []struct {
+			Hostname string `yaml:"hostname" json:"hostname"`
+		} `yaml:"listeners" json:"listeners"`
+	} `yaml:"spec" json:"spec"`
+}
+
+func (d routeDoc) toRoute() Route {
+	seen := make(map[string]bool)
+	var hosts []string
+	add := func(host string) {
+		if host != "" && !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+
+	for _, rule := range d.Spec.Rules {
+		add(rule.Host)
+	}
+	for _, tls := range d.Spec.TLS {
+		for _, h := range tls.Hosts {
+			add(h)
+		}
+	}
+	for _, h := range d.Spec.Hostnames {
+		add(h)
+	}
+	for _, l := range d.Spec.Listeners {
+		add(l.Hostname)
+	}
+	sort.Strings(hosts)
+
+	return Route{Kind: d.Kind, Namespace: d.Metadata.Namespace, Name: d.Metadata.Name, Hosts: hosts}
+}
+
+// Parse parses one Ingress, HTTPRoute or Gateway from JSON or a manifest document
+func Parse(content []byte) (Route, error) {
+	var doc routeDoc
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return Route{}, fmt.Errorf("failed to parse route: %w", err)
+	}
+	return doc.toRoute(), nil
+}
+
+// ParseList parses `kubectl get <kind> -A -o json` output. Items without a
+// kind are given the listed kind.
+func ParseList(kind string, content []byte) ([]Route, error) {
+	var list struct {
+		Items []routeDoc `json:"items"`
+	}
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse routes: %w", err)
+	}
+	routes := make([]Route, 0, len(list.Items))
+	for _, item := range list.Items {
+		if item.Kind == "" {
+			item.Kind = kind
+		}
+		routes = append(routes, item.toRoute())
+	}
+	return routes, nil
+}
+
+// MatchHost reports whether host matches pattern. A "*." prefix matches
+// exactly one extra DNS label, as in Ingress and Gateway API wildcards.
+func MatchHost(pattern, host string) bool {
+	pattern, host = strings.ToLower(pattern), strings.ToLower(host)
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		label, rest, found := strings.Cut(host, ".")
+		return found && label != "" && label != "*" && rest == suffix
+	}
+	return pattern == host
+}
+
+// CriticalHosts returns the route's hosts that match any critical host pattern
+func CriticalHosts(route Route, critical []string) []string {
+	var matched []string
+	for _, host := range route.Hosts {
+		for _, pattern := range critical {
+			if MatchHost(pattern, host) || MatchHost(host, pattern) {
+				matched = append(matched, host)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// Collisions describes hosts of route that other existing routes of the same
+// kind already serve. The route itself (same namespace and name) is skipped.
+func Collisions(route Route, existing []Route) []string {
+	var reasons []string
+	for _, other := range existing {
+		if other.Kind != route.Kind || (other.Namespace == route.Namespace && other.Name == route.Name) {
+			continue
+		}
+		for _, host := range route.Hosts {
+			for _, otherHost := range other.Hosts {
+				if host == otherHost {
+					reasons = append(reasons, fmt.Sprintf("host %s is already routed by %s", host, other))
+				}
+			}
+		}
+	}
+	return reasons
+}
@@ -0,0 +1,100 @@
+package routes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected Route
+	}{
+		{
+			name: "ingress rules and tls",
+			content: `{"kind":"Ingress","metadata":{"name":"web","namespace":"shop"},
+				"spec":{"rules":[{"host":"shop.example.com"},{"host":"www.example.com"}],"tls":[{"hosts":["shop.example.com"]}]}}`,
+			expected: Route{Kind: "Ingress", Namespace: "shop", Name: "web", Hosts: []string{"shop.example.com", "www.example.com"}},
+		},
+		{
+			name:     "httproute manifest",
+			content:  "kind: HTTPRoute\nmetadata:\n  name: api\nspec:\n  hostnames:\n  - api.example.com\n",
+			expected: Route{Kind: "HTTPRoute", Name: "api", Hosts: []string{"api.example.com"}},
+		},
+		{
+			name:     "gateway listeners",
+			content:  `{"kind":"Gateway","metadata":{"name":"public"},"spec":{"listeners":[{"hostname":"*.example.com"},{"name":"any"}]}}`,
+			expected: Route{Kind: "Gateway", Name: "public", Hosts: []string{"*.example.com"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, err := Parse([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(route, tt.expected) {
+				t.Errorf("got %+v, expected %+v", route, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchHost(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		host     string
+		expected bool
+	}{
+		{"shop.example.com", "shop.example.com", true},
+		{"shop.example.com", "SHOP.example.com", true},
+		{"*.example.com", "shop.example.com", true},
+		{"*.example.com", "a.shop.example.com", false},
+		{"*.example.com", "example.com", false},
+		{"shop.example.com", "api.example.com", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchHost(tt.pattern, tt.host); got != tt.expected {
+			t.Errorf("MatchHost(%q, %q): got %v, expected %v", tt.pattern, tt.host, got, tt.expected)
+		}
+	}
+}
+
+func TestCriticalHosts(t *testing.T) {
+	route := Route{Kind: "Ingress", Name: "web", Hosts: []string{"shop.example.com", "internal.corp"}}
+	got := CriticalHosts(route, []string{"*.example.com"})
+	if !reflect.DeepEqual(got, []string{"shop.example.com"}) {
+		t.Errorf("got %v, expected [shop.example.com]", got)
+	}
+
+	gateway := Route{Kind: "Gateway", Name: "public", Hosts: []string{"*.example.com"}}
+	if got := CriticalHosts(gateway, []string{"checkout.example.com"}); len(got) != 1 {
+		t.Errorf("expected a wildcard listener to serve a critical host, got %v", got)
+	}
+}
+
+func TestCollisions(t *testing.T) {
+	existing, err := ParseList("Ingress", []byte(`{"items":[
+		{"metadata":{"name":"web","namespace":"shop"},"spec":{"rules":[{"host":"shop.example.com"}]}},
+		{"metadata":{"name":"legacy","namespace":"old"},"spec":{"rules":[{"host":"shop.example.com"}]}},
+		{"metadata":{"name":"api","namespace":"shop"},"spec":{"rules":[{"host":"api.example.com"}]}}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseList() error = %v", err)
+	}
+
+	route := Route{Kind: "Ingress", Namespace: "shop", Name: "web", Hosts: []string{"shop.example.com"}}
+	got := Collisions(route, existing)
+	expected := []string{"host shop.example.com is already routed by ingress/old/legacy"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+
+	httproute := Route{Kind: "HTTPRoute", Namespace: "shop", Name: "web", Hosts: []string{"api.example.com"}}
+	if got := Collisions(httproute, existing); len(got) != 0 {
+		t.Errorf("expected routes of other kinds to be ignored, got %v", got)
+	}
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/watch"
+)
+
+// Port identifies one service port
+type Port struct {
+	Port     int    `yaml:"port"`
+	Protocol string `yaml:"protocol"`
+}
+
+func (p Port) String() string {
+	return fmt.Sprintf("%d/%s", p.Port, p.protocol())
+}
+
+// protocol defaults to TCP like the API server does
+func (p Port) protocol() string {
+	if p.Protocol == "" {
+		return "TCP"
+	}
+	return p.Protocol
+}
+
+// Service is the subset of a Service that decides which pods receive traffic
+type Service struct {
+	Name      string
+	Namespace string
+	Selector  map[string]string
+	Ports     []Port
+}
+
+type serviceDoc struct {
+	Metadata struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Selector map[string]string `yaml:"selector"`
+		Ports    []Port            `yaml:"ports"`
+	} `yaml:"spec"`
+}
+
+// Parse parses a Service from `kubectl get service -o json` output or a manifest document
+func Parse(content []byte) (Service, error) {
+	var doc serviceDoc
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return Service{}, fmt.Errorf("failed to parse service: %w", err)
+	}
+	return Service{
+		Name:      doc.Metadata.Name,
+		Namespace: doc.Metadata.Namespace,
+		Selector:  doc.Spec.Selector,
+		Ports:     doc.Spec.Ports,
+	}, nil
+}
+
+// ApplyPatch returns the live service with a merge or strategic merge patch
+// applied to its selector and ports. JSON patches are not supported.
+func ApplyPatch(live Service, patch []byte, patchType string) (Service, error) {
+	if patchType == "json" {
+		return Service{}, fmt.Errorf("json patches are not supported")
+	}
+
+	var doc struct {
+		Spec map[string]yaml.Node `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal(patch, &doc); err != nil {
+		return Service{}, fmt.Errorf("failed to parse patch: %w", err)
+	}
+
+	updated := live
+	if node, ok := doc.Spec["selector"]; ok {
+		updated.Selector = patchSelector(live.Selector, &node)
+	}
+	if node, ok := doc.Spec["ports"]; ok {
+		var ports []Port
+		if err := node.Decode(&ports); err != nil {
+			return Service{}, fmt.Errorf("failed to parse patch ports: %w", err)
+		}
+		if patchType == "merge" {
+			updated.Ports = ports
+		} else {
+			updated.Ports = mergePorts(live.Ports, ports)
+		}
+	}
+	return updated, nil
+}
+
+// patchSelector merges selector keys; null removes a key or the whole selector
+func patchSelector(live map[string]string, node *yaml.Node) map[string]string {
+	if node.Tag == "!!null" {
+		return nil
+	}
+	merged := make(map[string]string, len(live))
+	for k, v := range live {
+		merged[k] = v
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		if value.Tag == "!!null" {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = value.Value
+	}
+	return merged
+}
+
+// mergePorts merges ports by port number, as a strategic merge patch does
+func mergePorts(live, patch []Port) []Port {
+	merged := append([]Port{}, live...)
+	for _, p := range patch {
+		replaced := false
+		for i := range merged {
+			if merged[i].Port == p.Port {
+				merged[i] = p
+				replaced = true
+			}
+		}
+		if !replaced {
+			merged = append(merged, p)
+		}
+	}
+	return merged
+}
+
+// Impact describes the traffic the updated service stops sending: ready pods
+// that match the live selector but not the new one, and removed ports
+func Impact(live, updated Service, pods []watch.Pod) []string {
+	display := "service/" + live.Name
+	var reasons []string
+
+	if !reflect.DeepEqual(normalize(live.Selector), normalize(updated.Selector)) {
+		endpoints, dropped := 0, 0
+		for _, pod := range pods {
+			if !pod.Ready || !Matches(live.Selector, pod.Labels) {
+				continue
+			}
+			endpoints++
+			if !Matches(updated.Selector, pod.Labels) {
+				dropped++
+			}
+		}
+		if dropped > 0 {
+			reasons = append(reasons, fmt.Sprintf("%s selector change (%s → %s) drops %d of %d endpoints",
+				display, selectorString(live.Selector), selectorString(updated.Selector), dropped, endpoints))
+		}
+	}
+
+	kept := make(map[string]bool)
+	for _, p := range updated.Ports {
+		kept[p.String()] = true
+	}
+	for _, p := range live.Ports {
+		if !kept[p.String()] {
+			reasons = append(reasons, fmt.Sprintf("%s removes port %s", display, p))
+		}
+	}
+
+	return reasons
+}
+
+// Matches reports whether labels satisfy a service selector. An empty
+// selector matches nothing: such services have no managed endpoints.
+func Matches(selector, labels map[string]string) bool {
+	if len(selector) == 0 {
+		return false
+	}
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func normalize(selector map[string]string) map[string]string {
+	if len(selector) == 0 {
+		return nil
+	}
+	return selector
+}
+
+func selectorString(selector map[string]string) string {
+	if len(selector) == 0 {
+		return "<none>"
+	}
+	return watch.Selector(selector)
+}
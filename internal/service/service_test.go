@@ -0,0 +1,146 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/watch"
+)
+
+const liveJSON = `{"metadata":{"name":"web","namespace":"shop"},
+	"spec":{"selector":{"app":"web","tier":"frontend"},"ports":[{"port":80,"protocol":"TCP"},{"port":443}]}}`
+
+func TestParse(t *testing.T) {
+	svc, err := Parse([]byte(liveJSON))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	expected := Service{
+		Name:      "web",
+		Namespace: "shop",
+		Selector:  map[string]string{"app": "web", "tier": "frontend"},
+		Ports:     []Port{{Port: 80, Protocol: "TCP"}, {Port: 443}},
+	}
+	if !reflect.DeepEqual(svc, expected) {
+		t.Errorf("got %+v, expected %+v", svc, expected)
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	live, _ := Parse([]byte(liveJSON))
+
+	tests := []struct {
+		name             string
+		patch            string
+		patchType        string
+		expectedSelector map[string]string
+		expectedPorts    []string
+		expectErr        bool
+	}{
+		{
+			name:             "strategic selector key change",
+			patch:            `{"spec":{"selector":{"app":"wbe"}}}`,
+			patchType:        "strategic",
+			expectedSelector: map[string]string{"app": "wbe", "tier": "frontend"},
+			expectedPorts:    []string{"80/TCP", "443/TCP"},
+		},
+		{
+			name:             "null removes a selector key",
+			patch:            `{"spec":{"selector":{"tier":null}}}`,
+			patchType:        "merge",
+			expectedSelector: map[string]string{"app": "web"},
+			expectedPorts:    []string{"80/TCP", "443/TCP"},
+		},
+		{
+			name:             "merge patch replaces ports",
+			patch:            "spec:\n  ports:\n  - port: 8080\n",
+			patchType:        "merge",
+			expectedSelector: map[string]string{"app": "web", "tier": "frontend"},
+			expectedPorts:    []string{"8080/TCP"},
+		},
+		{
+			name:             "strategic patch merges ports",
+			patch:            `{"spec":{"ports":[{"port":8080}]}}`,
+			patchType:        "strategic",
+			expectedSelector: map[string]string{"app": "web", "tier": "frontend"},
+			expectedPorts:    []string{"80/TCP", "443/TCP", "8080/TCP"},
+		},
+		{
+			name:      "json patch unsupported",
+			patch:     `[{"op":"remove","path":"/spec/selector"}]`,
+			patchType: "json",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, err := ApplyPatch(live, []byte(tt.patch), tt.patchType)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyPatch() error = %v", err)
+			}
+			if !reflect.DeepEqual(updated.Selector, tt.expectedSelector) {
+				t.Errorf("selector: got %v, expected %v", updated.Selector, tt.expectedSelector)
+			}
+			var ports []string
+			for _, p := range updated.Ports {
+				ports = append(ports, p.String())
+			}
+			if !reflect.DeepEqual(ports, tt.expectedPorts) {
+				t.Errorf("ports: got %v, expected %v", ports, tt.expectedPorts)
+			}
+		})
+	}
+}
+
+func TestImpact(t *testing.T) {
+	live, _ := Parse([]byte(liveJSON))
+	pods := []watch.Pod{
+		{Name: "web-1", Labels: map[string]string{"app": "web", "tier": "frontend"}, Ready: true},
+		{Name: "web-2", Labels: map[string]string{"app": "web", "tier": "frontend"}, Ready: true},
+		{Name: "web-3", Labels: map[string]string{"app": "web", "tier": "frontend"}, Ready: false},
+		{Name: "api-1", Labels: map[string]string{"app": "api"}, Ready: true},
+	}
+
+	tests := []struct {
+		name     string
+		updated  Service
+		expected []string
+	}{
+		{
+			name:     "unchanged",
+			updated:  live,
+			expected: nil,
+		},
+		{
+			name:     "selector typo drops every endpoint",
+			updated:  Service{Name: "web", Selector: map[string]string{"app": "wbe", "tier": "frontend"}, Ports: live.Ports},
+			expected: []string{"service/web selector change (app=web,tier=frontend → app=wbe,tier=frontend) drops 2 of 2 endpoints"},
+		},
+		{
+			name:     "narrower selector keeps matching pods",
+			updated:  Service{Name: "web", Selector: map[string]string{"app": "web"}, Ports: live.Ports},
+			expected: nil,
+		},
+		{
+			name:     "removed port",
+			updated:  Service{Name: "web", Selector: live.Selector, Ports: []Port{{Port: 80, Protocol: "TCP"}}},
+			expected: []string{"service/web removes port 443/TCP"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Impact(live, tt.updated, pods)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Package ticket looks up change tickets in Jira or ServiceNow.
+package ticket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Supported ticketing systems
+const (
+	Jira       = "jira"
+	ServiceNow = "servicenow"
+)
+
+// requestTimeout bounds the lookup, which runs before dangerous commands
+const requestTimeout = 10 * time.Second
+
+// ErrNotFound is returned for a ticket the system does not know
+var ErrNotFound = errors.New("ticket not found")
+
+// Ticket is a change ticket as the ticketing system reports it
+type Ticket struct {
+	ID      string
+	Status  string // e.g. "In Progress" in Jira, "Implement" in ServiceNow
+	Summary string
+}
+
+// ValidID rejects IDs that would change the meaning of a lookup instead of
+// naming one ticket, such as "X^ORnumberISNOTEMPTY" in a ServiceNow query
+func ValidID(id string) error {
+	if strings.ContainsAny(id, "^=") {
+		return fmt.Errorf("invalid change ticket %q: it must not contain ^ or =", id)
+	}
+	return nil
+}
+
+// Lookup fetches a ticket by ID from the ticketing system at baseURL.
+// authorization is sent as the Authorization header, e.g. "Bearer <token>".
+func Lookup(provider, baseURL, authorization, id string) (Ticket, error) {
+	if err := ValidID(id); err != nil {
+		return Ticket{}, err
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+	switch provider {
+	case Jira:
+		return jiraTicket(baseURL, authorization, id)
+	case ServiceNow:
+		return serviceNowTicket(baseURL, authorization, id)
+	}
+	return Ticket{}, fmt.Errorf("unsupported ticketing provider %q", provider)
+}
+
+func jiraTicket(baseURL, authorization, id string) (Ticket, error) {
+	var body struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary string `json:"summary"`
+			Status  struct {
+				Name string `json:"name"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	u := baseURL + "/rest/api/2/issue/" + url.PathEscape(id) + "?fields=summary,status"
+	if err := get(u, authorization, &body); err != nil {
+		return Ticket{}, err
+	}
+	return Ticket{ID: body.Key, Status: body.Fields.Status.Name, Summary: body.Fields.Summary}, nil
+}
+
+func serviceNowTicket(baseURL, authorization, id string) (Ticket, error) {
+	query := url.Values{
+		"sysparm_query":         {"number=" + id},
+		"sysparm_fields":        {"number,state,short_description"},
+		"sysparm_display_value": {"true"},
+		"sysparm_limit":         {"1"},
+	}
+	var body struct {
+		Result []struct {
+			Number           string `json:"number"`
+			State            string `json:"state"`
+			ShortDescription string `json:"short_description"`
+		} `json:"result"`
+	}
+	if err := get(baseURL+"/api/now/table/change_request?"+query.Encode(), authorization, &body); err != nil {
+		return Ticket{}, err
+	}
+	if len(body.Result) == 0 {
+		return Ticket{}, ErrNotFound
+	}
+	r := body.Result[0]
+	return Ticket{ID: r.Number, Status: r.State, Summary: r.ShortDescription}, nil
+}
+
+// get fetches a JSON document into v
+func get(u, authorization string, v any) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("invalid ticket request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := (&http.Client{Timeout: requestTimeout}).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch ticket: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch ticket: %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid ticket response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package ticket
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/rest/api/2/issue/OPS-42":
+			w.Write([]byte(`{"key":"OPS-42","fields":{"summary":"Rotate ingress certs","status":{"name":"In Progress"}}}`))
+		case r.URL.Path == "/api/now/table/change_request" && r.URL.Query().Get("sysparm_query") == "number=CHG0031337":
+			w.Write([]byte(`{"result":[{"number":"CHG0031337","state":"Implement","short_description":"Scale payments"}]}`))
+		case r.URL.Path == "/api/now/table/change_request":
+			w.Write([]byte(`{"result":[]}`))
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name          string
+		provider      string
+		authorization string
+		id            string
+		expected      Ticket
+		err           string
+	}{
+		{"jira", Jira, "Bearer secret", "OPS-42", Ticket{ID: "OPS-42", Status: "In Progress", Summary: "Rotate ingress certs"}, ""},
+		{"jira unknown issue", Jira, "Bearer secret", "OPS-404", Ticket{}, "ticket not found"},
+		{"servicenow", ServiceNow, "Bearer secret", "CHG0031337", Ticket{ID: "CHG0031337", Status: "Implement", Summary: "Scale payments"}, ""},
+		{"servicenow unknown change", ServiceNow, "Bearer secret", "CHG0000000", Ticket{}, "ticket not found"},
+		{"rejected credentials", Jira, "Bearer wrong", "OPS-42", Ticket{}, "401"},
+		{"unknown provider", "remedy", "", "X", Ticket{}, "unsupported ticketing provider"},
+		{"servicenow query injection", ServiceNow, "Bearer secret", "X^ORnumberISNOTEMPTY", Ticket{}, "must not contain ^ or ="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Lookup(tt.provider, srv.URL+"/", tt.authorization, tt.id)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				if tt.err == "ticket not found" && !errors.Is(err, ErrNotFound) {
+					t.Errorf("expected ErrNotFound, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("got %+v, expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Pod is the subset of a pod needed to judge recreation
+type Pod struct {
+	UID    string
+	Name   string
+	Labels map[string]string
+	Ready  bool
+}
+
+// Deployment is the subset of a deployment needed to judge recreation
+type Deployment struct {
+	UID               string
+	Replicas          int
+	AvailableReplicas int
+}
+
+type podJSON struct {
+	Metadata struct {
+		UID    string            `json:"uid"`
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels"`
+	} `json:"metadata"`
+	Status struct {
+		Conditions []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+func (p podJSON) toPod() Pod {
+	pod := Pod{UID: p.Metadata.UID, Name: p.Metadata.Name, Labels: p.Metadata.Labels}
+	for _, c := range p.Status.Conditions {
+		if c.Type == "Ready" && c.Status == "True" {
+			pod.Ready = true
+		}
+	}
+	return pod
+}
+
+// ParsePod parses `kubectl get pod NAME -o json` output
+func ParsePod(content []byte) (Pod, error) {
+	var p podJSON
+	if err := json.Unmarshal(content, &p); err != nil {
+		return Pod{}, fmt.Errorf("failed to parse pod: %w", err)
+	}
+	return p.toPod(), nil
+}
+
+// ParsePods parses `kubectl get pods -o json` output
+func ParsePods(content []byte) ([]Pod, error) {
+	var list struct {
+		Items []podJSON `json:"items"`
+	}
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse pods: %w", err)
+	}
+	pods := make([]Pod, 0, len(list.Items))
+	for _, item := range list.Items {
+		pods = append(pods, item.toPod())
+	}
+	return pods, nil
+}
+
+// ParseDeployment parses `kubectl get deployment NAME -o json` output
+func ParseDeployment(content []byte) (Deployment, error) {
+	var d struct {
+		Metadata struct {
+			UID string `json:"uid"`
+		} `json:"metadata"`
+		Spec struct {
+			Replicas *int `json:"replicas"`
+		} `json:"spec"`
+		Status struct {
+			AvailableReplicas int `json:"availableReplicas"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(content, &d); err != nil {
+		return Deployment{}, fmt.Errorf("failed to parse deployment: %w", err)
+	}
+
+	// Kubernetes defaults spec.replicas to 1
+	replicas := 1
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return Deployment{UID: d.Metadata.UID, Replicas: replicas, AvailableReplicas: d.Status.AvailableReplicas}, nil
+}
+
+// Selector renders pod labels as a kubectl label selector, sorted for stable output
+func Selector(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// ParseSelector returns the label selector for the pods behind an object:
+// its spec.selector.matchLabels for workloads, otherwise its own labels.
+// The result is empty if the object has neither.
+func ParseSelector(content []byte) (string, error) {
+	var obj struct {
+		Metadata struct {
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+		Spec struct {
+			Selector json.RawMessage `json:"selector"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(content, &obj); err != nil {
+		return "", fmt.Errorf("failed to parse object: %w", err)
+	}
+
+	var selector struct {
+		MatchLabels map[string]string `json:"matchLabels"`
+	}
+	if len(obj.Spec.Selector) > 0 && json.Unmarshal(obj.Spec.Selector, &selector) == nil && len(selector.MatchLabels) > 0 {
+		return Selector(selector.MatchLabels), nil
+	}
+	return Selector(obj.Metadata.Labels), nil
+}
+
+// CountReady counts ready pods, skipping the given UIDs
+func CountReady(pods []Pod, exclude map[string]bool) int {
+	n := 0
+	for _, p := range pods {
+		if p.Ready && !exclude[p.UID] {
+			n++
+		}
+	}
+	return n
+}
+
+// DeploymentRecreated returns true if a new deployment (different UID) is fully available
+func DeploymentRecreated(oldUID string, d Deployment) bool {
+	return d.UID != "" && d.UID != oldUID && d.AvailableReplicas >= d.Replicas
+}
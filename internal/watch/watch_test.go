@@ -0,0 +1,120 @@
+package watch
+
+import (
+	"testing"
+)
+
+func TestParsePod(t *testing.T) {
+	content := `{"metadata":{"uid":"u1","name":"web-1","labels":{"app":"web"}},
+		"status":{"conditions":[{"type":"Ready","status":"True"}]}}`
+
+	pod, err := ParsePod([]byte(content))
+	if err != nil {
+		t.Fatalf("ParsePod() error = %v", err)
+	}
+	if pod.UID != "u1" || pod.Name != "web-1" || !pod.Ready || pod.Labels["app"] != "web" {
+		t.Errorf("unexpected pod: %+v", pod)
+	}
+}
+
+func TestParsePodsNotReady(t *testing.T) {
+	content := `{"items":[{"metadata":{"uid":"u2"},"status":{"conditions":[{"type":"Ready","status":"False"}]}}]}`
+
+	pods, err := ParsePods([]byte(content))
+	if err != nil {
+		t.Fatalf("ParsePods() error = %v", err)
+	}
+	if len(pods) != 1 || pods[0].Ready {
+		t.Errorf("expected one not-ready pod, got %+v", pods)
+	}
+}
+
+func TestParseDeploymentDefaultsReplicas(t *testing.T) {
+	d, err := ParseDeployment([]byte(`{"metadata":{"uid":"d1"},"spec":{},"status":{"availableReplicas":1}}`))
+	if err != nil {
+		t.Fatalf("ParseDeployment() error = %v", err)
+	}
+	if d.Replicas != 1 || d.AvailableReplicas != 1 || d.UID != "d1" {
+		t.Errorf("unexpected deployment: %+v", d)
+	}
+}
+
+func TestParseInvalidJSON(t *testing.T) {
+	if _, err := ParsePod([]byte("x")); err == nil {
+		t.Error("expected error for invalid pod JSON")
+	}
+	if _, err := ParsePods([]byte("x")); err == nil {
+		t.Error("expected error for invalid pods JSON")
+	}
+	if _, err := ParseDeployment([]byte("x")); err == nil {
+		t.Error("expected error for invalid deployment JSON")
+	}
+}
+
+func TestSelector(t *testing.T) {
+	got := Selector(map[string]string{"tier": "fe", "app": "web"})
+	if got != "app=web,tier=fe" {
+		t.Errorf("Selector() = %q, expected %q", got, "app=web,tier=fe")
+	}
+}
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		object   string
+		expected string
+	}{
+		{"pod labels", `{"metadata":{"labels":{"app":"web","tier":"fe"}}}`, "app=web,tier=fe"},
+		{"deployment match labels", `{"metadata":{"labels":{"team":"a"}},"spec":{"selector":{"matchLabels":{"app":"web"}}}}`, "app=web"},
+		{"service selector map", `{"metadata":{"labels":{"app":"web"}},"spec":{"selector":{"app":"web-v2"}}}`, "app=web"},
+		{"no labels", `{"metadata":{}}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSelector([]byte(tt.object))
+			if err != nil || got != tt.expected {
+				t.Errorf("got (%q, %v), expected %q", got, err, tt.expected)
+			}
+		})
+	}
+
+	if _, err := ParseSelector([]byte(`{`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestCountReady(t *testing.T) {
+	pods := []Pod{
+		{UID: "old", Ready: true},
+		{UID: "a", Ready: true},
+		{UID: "b", Ready: false},
+	}
+	if got := CountReady(pods, map[string]bool{"old": true}); got != 1 {
+		t.Errorf("CountReady() = %d, expected 1", got)
+	}
+	if got := CountReady(pods, nil); got != 2 {
+		t.Errorf("CountReady(nil) = %d, expected 2", got)
+	}
+}
+
+func TestDeploymentRecreated(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        Deployment
+		expected bool
+	}{
+		{"same deployment", Deployment{UID: "old", Replicas: 2, AvailableReplicas: 2}, false},
+		{"new but not available", Deployment{UID: "new", Replicas: 2, AvailableReplicas: 1}, false},
+		{"new and available", Deployment{UID: "new", Replicas: 2, AvailableReplicas: 2}, true},
+		{"missing", Deployment{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DeploymentRecreated("old", tt.d); got != tt.expected {
+				t.Errorf("DeploymentRecreated() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"github.com/zufardhiyaulhaq/safekubectl/internal/job"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// jobTarget is a named Job a delete removes
+type jobTarget struct {
+	name      string
+	namespace string
+}
+
+// commandJobs returns the named Job targets of a command
+func commandJobs(cmd *parser.KubectlCommand, namespace string) []jobTarget {
+	var jobs []jobTarget
+	for _, t := range cmd.Targets {
+		if t.Name != "" && parser.KindFor(t.Resource) == "Job" {
+			jobs = append(jobs, jobTarget{name: t.Name, namespace: namespace})
+		}
+	}
+	return jobs
+}
+
+// manifestJobs returns the Jobs in manifests
+func manifestJobs(resources []manifest.Resource) []jobTarget {
+	var jobs []jobTarget
+	for _, res := range resources {
+		if res.Kind == "Job" && res.Name != "" {
+			jobs = append(jobs, jobTarget{name: res.Name, namespace: res.Namespace})
+		}
+	}
+	return jobs
+}
+
+// activeJobReasons warns about Jobs that still have running pods, which a
+// delete terminates mid-run or, with --cascade=orphan, leaves untracked.
+// Finished Jobs, or Jobs that cannot be looked up, produce no reasons.
+func (r *Runner) activeJobReasons(jobs []jobTarget, orphan bool, kubeContext string) []string {
+	var reasons []string
+	for _, j := range jobs {
+		args := []string{"get", "job", j.name, "-o", "json"}
+		if j.namespace != "" {
+			args = append(args, "-n", j.namespace)
+		}
+		out, err := r.queryKubectl(append(args, kubectlContextArgs(kubeContext)...))
+		if err != nil {
+			continue
+		}
+		if active, err := job.ParseActive(out); err == nil && active > 0 {
+			reasons = append(reasons, job.ActiveReason("job/"+j.name, active, orphan))
+		}
+	}
+	return reasons
+}
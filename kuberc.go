@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/kuberc"
+)
+
+// expandKuberc returns args with the aliases and default flags of kubectl's
+// kuberc file expanded, so the command is checked, and run, as kubectl would
+// run it. An unreadable kuberc is reported and leaves args as they are.
+func (r *Runner) expandKuberc(args []string) []string {
+	if r.loadKuberc == nil {
+		return args
+	}
+	pref, err := r.loadKuberc(args)
+	if err != nil {
+		fmt.Fprintf(r.stderr, "warning: kuberc aliases and defaults not applied: %s\n", err)
+		return args
+	}
+	return pref.Expand(args)
+}
+
+// loadKuberc reads the kuberc file kubectl uses for args, if any
+func loadKuberc(args []string) (*kuberc.Preference, error) {
+	path := kuberc.Path(args)
+	if path == "" {
+		return nil, nil
+	}
+	return kuberc.Load(path)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+)
+
+// largeOutputChunkSize is the --chunk-size offered for very large listings
+const largeOutputChunkSize = "--chunk-size=100"
+
+// isLargeOutput reports whether a command prints every object of a type
+// across all namespaces in full
+func isLargeOutput(cmd *parser.KubectlCommand) bool {
+	return cmd.Operation == "get" && cmd.AllNamespaces && fullOutputFormats[outputFormat(cmd.Args)]
+}
+
+// guardLargeOutput warns before a very large listing reaches the terminal and
+// lets the user page it, chunk it, run it as is, or abort. It returns the
+// runner and args to continue with, or false if the user aborted.
+func (r *Runner) guardLargeOutput(cmd *parser.KubectlCommand, args []string, largeOutput config.LargeOutputConfig) (*Runner, []string, bool) {
+	resource := "object"
+	if len(cmd.Targets) > 0 {
+		resource = cmd.Targets[0].Resource
+	}
+	pager := pagerCommand(largeOutput.Pager)
+
+	prompt.DisplayLargeOutputWarningTo(r.stdout, args, resource)
+	switch prompt.AskLargeOutputChoiceFrom(r.stdin, r.stdout, pager[0]) {
+	case prompt.LargeOutputPage:
+		return r.withPager(pager), args, true
+	case prompt.LargeOutputChunk:
+		return r, withFlags(args, largeOutputChunkSize), true
+	case prompt.LargeOutputRun:
+		return r, args, true
+	}
+	prompt.DisplayAbortedTo(r.stdout)
+	return r, args, false
+}
+
+// withPager returns a copy of the runner whose kubectl output goes through a pager
+func (r *Runner) withPager(pager []string) *Runner {
+	wrapped := *r
+	if page := r.pageKubectl; page != nil {
+		wrapped.executeKubectl = func(args []string) error { return page(args, pager) }
+	}
+	return &wrapped
+}
+
+// pagerCommand returns the configured pager, else $PAGER, else less
+func pagerCommand(configured string) []string {
+	if fields := strings.Fields(configured); len(fields) > 0 {
+		return fields
+	}
+	if fields := strings.Fields(os.Getenv("PAGER")); len(fields) > 0 {
+		return fields
+	}
+	return []string{"less"}
+}
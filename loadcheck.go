@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// fullOutputFormats are -o formats that serialize every field of every object
+var fullOutputFormats = map[string]bool{
+	"yaml": true,
+	"json": true,
+}
+
+// controlPlaneLoadReasons flags commands that put a load spike on the API
+// server and etcd: delete --all of many objects, and on protected clusters,
+// full yaml/json listings across all namespaces
+func (r *Runner) controlPlaneLoadReasons(cmd *parser.KubectlCommand, namespace string, protected bool, load config.ControlPlaneLoadConfig) []string {
+	var reasons []string
+	switch {
+	case cmd.Operation == "delete" && hasFlag(cmd.Args, "--all") && r.queryKubectl != nil:
+		for _, t := range cmd.Targets {
+			if t.Name != "" {
+				continue
+			}
+			n, ok := r.countObjects(t.Resource, namespace, cmd.AllNamespaces, cmd.Context)
+			if !ok || n < load.MaxObjects {
+				continue
+			}
+			scope := " in " + namespace
+			if cmd.AllNamespaces {
+				scope = " across all namespaces"
+			}
+			reasons = append(reasons, fmt.Sprintf("control plane load: delete --all removes %d %s%s at once, a burst of writes to the API server and etcd", n, t.Resource, scope))
+		}
+	case cmd.Operation == "get" && cmd.AllNamespaces && protected:
+		if format := outputFormat(cmd.Args); fullOutputFormats[format] {
+			for _, t := range cmd.Targets {
+				reasons = append(reasons, fmt.Sprintf("control plane load: get -A -o %s reads every %s in the cluster in full; narrow it with -n, -l or --field-selector", format, t.Resource))
+			}
+		}
+	}
+	return reasons
+}
+
+// manifestLoadReasons flags applying more objects than the control plane
+// should take in one go
+func manifestLoadReasons(operation string, objects int, load config.ControlPlaneLoadConfig) []string {
+	if operation != "apply" || objects < load.MaxObjects {
+		return nil
+	}
+	return []string{fmt.Sprintf("control plane load: applying %d objects at once; consider smaller batches or --sk-canary", objects)}
+}
+
+// countObjects counts the objects of a resource type, or reports false if
+// they cannot be listed
+func (r *Runner) countObjects(resource, namespace string, allNamespaces bool, kubeContext string) (int, bool) {
+	args := []string{"get", resource, "-o", "name"}
+	switch {
+	case allNamespaces:
+		args = append(args, "-A")
+	case !parser.IsClusterScopedKind(parser.KindFor(resource)):
+		args = append(args, "-n", namespace)
+	}
+	out, err := r.queryKubectl(append(args, kubectlContextArgs(kubeContext)...))
+	if err != nil {
+		return 0, false
+	}
+	n := 0
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n, true
+}
+
+// hasFlag reports whether a boolean flag is set ahead of any "--" separator
+func hasFlag(args []string, flag string) bool {
+	for _, arg := range args {
+		switch arg {
+		case "--":
+			return false
+		case flag, flag + "=true":
+			return true
+		}
+	}
+	return false
+}
+
+// outputFormat returns the -o/--output format ahead of any "--" separator,
+// without a template such as jsonpath's
+func outputFormat(args []string) string {
+	for i, arg := range args {
+		value := ""
+		switch {
+		case arg == "--":
+			return ""
+		case (arg == "-o" || arg == "--output") && i+1 < len(args):
+			value = args[i+1]
+		case strings.HasPrefix(arg, "--output="):
+			value = strings.TrimPrefix(arg, "--output=")
+		case strings.HasPrefix(arg, "-o") && arg != "-o":
+			value = strings.TrimPrefix(strings.TrimPrefix(arg, "-o"), "=")
+		default:
+			continue
+		}
+		format, _, _ := strings.Cut(value, "=")
+		return format
+	}
+	return ""
+}
@@ -1,52 +1,171 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/zufardhiyaulhaq/safekubectl/internal/audit"
 	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
 	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/drain"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/gitops"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/job"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/kubeconfig"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/kuberc"
 	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/notify"
 	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
 	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/redact"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/ticket"
 )
 
 func main() {
 	runner := &Runner{
-		stdin:               os.Stdin,
-		stdout:              os.Stdout,
-		stderr:              os.Stderr,
-		getCluster:          getCurrentCluster,
-		getContextNamespace: getContextDefaultNamespace,
-		executeKubectl:      executeKubectl,
-		loadConfig:          config.Load,
+		stdin:                 os.Stdin,
+		stdout:                os.Stdout,
+		stderr:                os.Stderr,
+		getCluster:            getCurrentCluster,
+		getContextNamespace:   getContextDefaultNamespace,
+		getClusterServer:      getClusterServer,
+		getNamespaceResources: getNamespaceResources,
+		getIdentity:           getIdentity,
+		getCredentials:        getLongLivedCredentials,
+		getConnection:         getConnection,
+		getContexts:           getContexts,
+		getGroups:             lookupGroups,
+		getOnCall:             lookupOnCall,
+		getFreeze:             lookupFreeze,
+		getTicket:             lookupTicket,
+		sendNotification:      sendNotification,
+		loadKuberc:            loadKuberc,
+		queryKubectl:          queryKubectl,
+		executeKubectl:        executeKubectl,
+		pageKubectl:           pageKubectl,
+		filterKubectl:         filterKubectl,
+		isTerminal:            stdoutIsTerminal,
+		openTerminal:          openTerminal,
+		runHook:               runHook,
+		loadConfig:            config.Load,
+		loadCachedConfig:      config.LoadCached,
+		sleep:                 time.Sleep,
+		randIntn:              rand.Intn,
 	}
 
 	if err := runner.Run(os.Args[1:]); err != nil {
-		fmt.Fprintf(os.Stderr, "safekubectl: %s\n", err)
-		os.Exit(1)
+		// --sk-output=json has already printed the verdict its exit code encodes
+		var verdictErr *verdictExitError
+		if errors.As(err, &verdictErr) {
+			os.Exit(verdictErr.code)
+		}
+
+		// kubectl has already reported its own failure; only added context is printed
+		var exitErr *kubectlExitError
+		if !errors.As(err, &exitErr) {
+			fmt.Fprintf(os.Stderr, "safekubectl: %s\n", err)
+			os.Exit(1)
+		}
+		if err != error(exitErr) {
+			fmt.Fprintf(os.Stderr, "safekubectl: %s\n", err)
+		}
+		os.Exit(exitErr.code)
 	}
 }
 
+// writeOperations create or update objects from a full definition
+var writeOperations = map[string]bool{
+	"apply":   true,
+	"create":  true,
+	"replace": true,
+}
+
 // Runner encapsulates the main execution logic
 type Runner struct {
-	stdin               io.Reader
-	stdout              io.Writer
-	stderr              io.Writer
-	getCluster          func() string
-	getContextNamespace func(context string) string // context param: empty = current, otherwise use specified
-	executeKubectl      func(args []string) error
-	loadConfig          func() (*config.Config, error)
+	stdin                 io.Reader
+	stdout                io.Writer
+	stderr                io.Writer
+	getCluster            func(kubeconfig string) string                           // kubeconfig param: empty = KUBECONFIG or ~/.kube/config
+	getContextNamespace   func(kubeconfig, context string) string                  // context param: empty = current, otherwise use specified
+	getClusterServer      func(kubeconfig, context string) (string, string)        // kubeconfig cluster name and API server URL of a context
+	getNamespaceResources func(kubeconfig, context, namespace string) []string     // lists resources inside a namespace
+	getIdentity           func(kubeconfig, context string) string                  // user the command runs as; empty if unknown
+	getCredentials        func(kubeconfig, context string) []string                // long-lived credentials the context authenticates with
+	getConnection         func(kubeconfig, context string) connection              // API server, proxy and impersonation of a context
+	getContexts           func(string) map[string]kubeconfig.Context               // contexts in the kubeconfig at a path, by name
+	getGroups             func(cfg config.GroupsConfig) ([]string, error)          // groups the operator belongs to, for groupPolicies
+	getOnCall             func(config.OnCallConfig, string) ([]string, error)      // emails of the users on call for a schedule
+	getFreeze             func(config.FreezeConfig) (bool, string, error)          // whether an incident freeze is active, and its reason
+	getTicket             func(config.TicketConfig, string) (ticket.Ticket, error) // looks up a change ticket in the ticketing system
+	sendNotification      func(config.NotifyChannel, notify.Event) error           // posts a dangerous command that ran to a notify channel
+	queryKubectl          func(args []string) ([]byte, error)                      // runs a read-only kubectl command, returns stdout
+	executeKubectl        func(args []string) error
+	pageKubectl           func(args, pager []string) error                          // runs kubectl with its output piped through a pager
+	filterKubectl         func(args []string, stdout, stderr io.Writer) error       // runs kubectl with its output written to the given writers
+	isTerminal            func() bool                                               // reports whether stdout is a terminal
+	openTerminal          func() (io.ReadCloser, error)                             // opens the terminal for prompts when stdin carries a manifest
+	runHook               func(command, env []string, stdin []byte) ([]byte, error) // runs an external program with extra env and stdin, returns stdout
+	loadConfig            func() (*config.Config, error)
+	loadCachedConfig      func() (*config.Config, error)                  // loads the config without fetching the policy bundle or rulesets
+	loadKuberc            func(args []string) (*kuberc.Preference, error) // kubectl's kuberc aliases and default flags for args; nil if none
+	sleep                 func(d time.Duration)
+	randIntn              func(n int) int  // picks warning experiment variants; nil always picks the first
+	now                   func() time.Time // current time, for changeWindows and notify quiet hours; nil uses time.Now
+
+	redactor *redact.Redactor // masks secrets in kubectl's output, with redact
+	capture  *outputCapture   // keeps what kubectl prints for the audit log, with audit.captureOutput
 }
 
 // Run executes the main logic
 func (r *Runner) Run(args []string) error {
-	// If no args, just pass through to kubectl
-	if len(args) == 0 {
+	// safekubectl's own --sk-* flags are never passed to kubectl
+	args, skFlags, err := splitSafekubectlFlags(args)
+	if err != nil {
+		return err
+	}
+
+	// --sk-output=json prints the verdict instead of running the command
+	checkOnly, err := checkOnlyOutput(skFlags)
+	if err != nil {
+		return err
+	}
+	if checkOnly && len(args) == 0 {
+		return fmt.Errorf("--sk-output needs a kubectl command to check")
+	}
+
+	// --sk-prompt-info prints the current context's risk for a shell prompt
+	if format := skFlags["prompt-info"]; format != "" {
+		return r.runPromptInfo(args, format)
+	}
+
+	// If no args, or a completion script calling back, just pass through to kubectl
+	if len(args) == 0 || completionRequests[args[0]] {
+		return r.executeKubectl(args)
+	}
+	if args[0] == completionCommand {
+		return r.runCompletion(args[1:], skFlags)
+	}
+
+	// validate-config loads the config itself to report what is wrong with it
+	if args[0] == validateConfigCommand {
+		return r.runValidateConfig()
+	}
+
+	// kubectl expands kuberc aliases and default flags; check what it will run
+	args = r.expandKuberc(args)
+
+	// Parse kubectl command
+	cmd := parser.Parse(args)
+
+	// Built-in safe operations skip config loading and context lookups
+	if config.IsBuiltinSafeOperation(cmd.Operation, cmd.Subcommand) && !checkOnly {
 		return r.executeKubectl(args)
 	}
 
@@ -55,24 +174,91 @@ func (r *Runner) Run(args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	prompt.SetLanguage(cfg.Language)
 
-	// Parse kubectl command
-	cmd := parser.Parse(args)
+	// safekubectl's own subcommands
+	if args[0] == drainPlanCommand {
+		return r.runDrainPlan(args[1:], cfg, skFlags)
+	}
+	if args[0] == auditCommand {
+		return r.runAudit(args[1:], cfg)
+	}
+	if args[0] == restoreCommand {
+		return r.runRestore(args[1:], cfg)
+	}
+	if args[0] == supportBundleCommand {
+		return r.runSupportBundle(args[1:], cfg)
+	}
+	if args[0] == coverageCommand {
+		return r.runCoverage(args[1:], cfg)
+	}
 
-	// Get cluster context - use parsed --context flag if provided, otherwise get current context
-	cluster := cmd.Context
+	// Note what the checks below cannot see, with coverage.enabled
+	r.recordCoverage(cfg.Coverage, cmd)
+
+	// Offer a pager before every object in the cluster is dumped to the terminal
+	if cfg.LargeOutput.Enabled && !checkOnly && isLargeOutput(cmd) && r.isTerminal != nil && r.isTerminal() {
+		var proceed bool
+		if r, args, proceed = r.guardLargeOutput(cmd, args, cfg.LargeOutput); !proceed {
+			return nil
+		}
+	}
+
+	// Mask secrets in kubectl's output on screen, in place of any pager chosen
+	// above, and keep what it prints for the audit log. Piped output is left
+	// alone for scripts unless redact.piped says otherwise.
+	if !checkOnly && !needsTerminal(cmd) {
+		if cfg.Redact.Enabled && (cfg.Redact.Piped || (r.isTerminal != nil && r.isTerminal())) {
+			redactor, err := redact.New(cfg.Redact.Patterns, mayPrintSecrets(cmd))
+			if err != nil {
+				return err
+			}
+			r = r.withRedaction(redactor)
+		}
+		if cfg.Audit.Enabled && cfg.Audit.CaptureOutput {
+			r = r.withOutputCapture(cfg.Audit.CaptureOutputLimit)
+		}
+	}
+
+	if cfg.IsSafeOperation(cmd.Operation, cmd.Subcommand) && !checkOnly {
+		return r.executeKubectl(args)
+	}
+
+	// Lookups and follow-up commands must use the kubeconfig the command uses
+	if cmd.Kubeconfig != "" {
+		r = r.withKubeconfig(cmd.Kubeconfig)
+	}
+
+	// Get cluster context - use parsed --context flag if provided, otherwise get current context.
+	// A context switch is checked against the context it switches to.
+	kubeContext := cmd.Context
+	if switched := cmd.SwitchedContext(); switched != "" {
+		kubeContext = switched
+	}
+	cluster := kubeContext
 	if cluster == "" {
-		cluster = r.getCluster()
+		cluster = r.getCluster(cmd.Kubeconfig)
+	}
+
+	// Protected clusters may be named by kubeconfig cluster or API server, not just context
+	if len(cfg.ProtectedClusters) > 0 && r.getClusterServer != nil {
+		clusterName, server := r.getClusterServer(cmd.Kubeconfig, kubeContext)
+		cfg.ResolveProtectedCluster(cluster, clusterName, server)
+	}
+
+	// Remind which protected cluster the current context points at
+	if !checkOnly && cmd.SwitchedContext() == "" {
+		r.contextBanner(cfg, cmd, cluster)
 	}
 
 	// Handle file-based commands
 	if len(cmd.FileInputs) > 0 {
-		return r.runWithFileInputs(cmd, cfg, cluster, args)
+		return r.runWithFileInputs(cmd, cfg, cluster, args, skFlags)
 	}
 
 	// Resolve namespace from context if not explicitly provided
-	if cmd.Namespace == "" && !cmd.IsNodeScoped() && r.getContextNamespace != nil {
-		contextNS := r.getContextNamespace(cmd.Context) // Use specified --context or empty for current
+	if cmd.Namespace == "" && !cmd.IsNodeScoped() && !cmd.IsClusterScoped() && r.getContextNamespace != nil {
+		contextNS := r.getContextNamespace(cmd.Kubeconfig, cmd.Context) // Use specified --context or empty for current
 		if contextNS != "" {
 			cmd.Namespace = contextNS
 		}
@@ -85,18 +271,281 @@ func (r *Runner) Run(args []string) error {
 	// Initialize audit logger
 	auditLogger := audit.New(cfg)
 
+	// Heavy commands are flagged whether or not they are dangerous
+	if cfg.ControlPlaneLoad.Enabled {
+		if reasons := r.controlPlaneLoadReasons(cmd, result.Namespace, cfg.IsProtectedCluster(cluster), cfg.ControlPlaneLoad); len(reasons) > 0 {
+			result.IsDangerous = true
+			result.Reasons = append(result.Reasons, reasons...)
+			if cfg.ControlPlaneLoad.Confirm {
+				result.RequireConfirmation()
+			}
+		}
+	}
+
+	// Pruning deletes live objects that are missing from the manifests
+	if prune := cmd.PruneOptions(); prune != nil {
+		result.IsDangerous = true
+		result.RequireConfirmation()
+		result.Reasons = append(result.Reasons, pruneReason(prune))
+	}
+
+	// A Rego policy can warn about or deny any checked command
+	if cfg.OPA.Enabled {
+		reasons, err := r.policyReasons(cfg.OPA, commandPolicyInput(cmd, result, cfg.IsProtectedCluster(cluster)))
+		if err != nil && checkOnly {
+			return r.printCommandVerdict(result, err)
+		}
+		if err != nil {
+			if logErr := auditLogger.Log(result, args, false, false); logErr != nil {
+				fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+			}
+			return err
+		}
+		if len(reasons) > 0 {
+			result.IsDangerous = true
+			result.Reasons = append(result.Reasons, reasons...)
+		}
+	}
+
+	// Namespace owners can block manual changes from everyone
+	if cfg.NamespacePolicy {
+		err := r.namespacePolicyBlock(cmd.Operation, commandPolicyNamespaces(cmd, result.Namespace), cmd.Context)
+		if err != nil && checkOnly {
+			return r.printCommandVerdict(result, err)
+		}
+		if err != nil {
+			if logErr := auditLogger.Log(result, args, false, false); logErr != nil {
+				fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+			}
+			return err
+		}
+	}
+
+	// Protected nodes can be closed to manual changes altogether
+	if err := protectedNodeBlock(cfg, result.ProtectedNodes); err != nil {
+		if checkOnly {
+			return r.printCommandVerdict(result, err)
+		}
+		if logErr := auditLogger.Log(result, args, false, false); logErr != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+		}
+		return err
+	}
+
+	// Rule plugins add an organization's own checks
+	if cfg.Plugins.Enabled {
+		reasons, confirm, err := r.runPlugins(cfg.Plugins, commandPolicyInput(cmd, result, cfg.IsProtectedCluster(cluster)))
+		if err != nil && checkOnly {
+			return r.printCommandVerdict(result, err)
+		}
+		if err != nil {
+			if logErr := auditLogger.Log(result, args, false, false); logErr != nil {
+				fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+			}
+			return err
+		}
+		if len(reasons) > 0 {
+			result.IsDangerous = true
+			result.Reasons = append(result.Reasons, reasons...)
+			if confirm {
+				result.RequireConfirmation()
+			}
+		}
+	}
+
 	// If not dangerous, execute directly
+	if !result.IsDangerous && checkOnly {
+		return r.printCommandVerdict(result, nil)
+	}
 	if !result.IsDangerous {
-		return r.executeKubectl(args)
+		if err := r.runWithHooks(cfg.Hooks, commandHookEvent(result, args), func() error { return r.executeKubectl(args) }); err != nil {
+			return err
+		}
+		if cmd.SwitchedContext() != "" {
+			r.contextBanner(cfg, cmd, cluster)
+		}
+		return nil
+	}
+
+	// Incident commanders can lock down ad-hoc changes everywhere at once
+	if err := r.freezeBlock(cfg.Freeze, cluster); err != nil {
+		if checkOnly {
+			return r.printCommandVerdict(result, err)
+		}
+		if logErr := auditLogger.Log(result, args, false, false); logErr != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+		}
+		return err
+	}
+
+	// Shared config objects have a bigger blast radius than their size suggests
+	if cfg.CountConfigReferences && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.configReferenceReasons(commandConfigObjects(cmd, result.Namespace), cmd.Context)...)
+	}
+
+	// Workloads and CI systems authenticate with ServiceAccounts and their bindings
+	if cmd.Operation == "delete" && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.authBreakageReasons(commandAuthObjects(cmd, result.Namespace), cmd.Context)...)
+		result.Reasons = append(result.Reasons, r.priorityClassUsageReasons(commandPriorityClasses(cmd), cmd.Context)...)
+	}
+
+	// Typos and misunderstood objects show up before anything is removed
+	if cmd.Operation == "delete" && cfg.CheckDeleteTargets && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.existenceReasons(commandSnapshotTargets(cmd, result.Namespace), cmd.Context)...)
 	}
 
-	// Display warning
-	prompt.DisplayWarningTo(r.stdout, result, args)
+	// Batch jobs are easy to kill mid-run while "cleaning up"
+	if cmd.Operation == "delete" && cfg.CheckActiveJobs && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.activeJobReasons(commandJobs(cmd, result.Namespace), job.IsOrphanCascade(cmd.Args), cmd.Context)...)
+	}
+
+	// Removing pods can take a workload below its disruption budget or to zero
+	if (cmd.Operation == "delete" || cmd.Operation == "drain") && cfg.CheckDisruption && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.disruptionReasons(cmd, result.Namespace)...)
+	}
+
+	// Where a node runs is easy to get wrong from its name alone
+	if (cmd.Operation == "drain" || cmd.Operation == "cordon") && cfg.DescribeNodes && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.nodeReasons(cmd)...)
+	}
+
+	// A drain evicts everything on the node; list it, and what kubectl drain stops at
+	var evictions []drain.Evictions
+	if cmd.Operation == "drain" && cfg.PreviewDrain && r.queryKubectl != nil {
+		evictions = r.drainEvictions(cmd)
+		result.Reasons = append(result.Reasons, drainReasons(evictions, args)...)
+	}
+
+	// Routing changes are instantly customer-visible
+	if cmd.Operation == "delete" && len(cfg.Routes.CriticalHosts) > 0 && r.queryKubectl != nil {
+		if reasons := r.deleteRouteReasons(cmd, result.Namespace, cfg.Routes.CriticalHosts); len(reasons) > 0 {
+			result.Reasons = append(result.Reasons, reasons...)
+			result.RequireConfirmation()
+		}
+	}
+
+	// Selector typos silently blackhole traffic
+	if cmd.Operation == "patch" && cfg.IsProtectedCluster(cluster) && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.patchServiceReasons(cmd, result.Namespace)...)
+	}
+
+	// Suspending or resuming a CronJob skips or replays scheduled runs
+	if cmd.Operation == "patch" && r.queryKubectl != nil {
+		reasons, previous := r.cronJobSuspendReasons(cmd, result.Namespace)
+		result.Reasons = append(result.Reasons, reasons...)
+		result.Previous = append(result.Previous, previous...)
+	}
+
+	// Destructive deletes on protected clusters must be recoverable
+	var backups []backupTarget
+	if cmd.Operation == "delete" && cfg.IsProtectedCluster(cluster) {
+		backups = commandBackupTargets(cmd, result.Namespace, cfg)
+		result.Reasons = append(result.Reasons, backupReasons(backups)...)
+	}
+
+	// Flux and Argo CD revert manual changes to the objects they manage
+	var managers []gitops.Manager
+	if (cfg.GitOps.SuspendReconciliation || cfg.GitOps.Warn) && gitopsOperations[cmd.Operation] && r.queryKubectl != nil {
+		var reasons []string
+		reasons, managers = r.gitopsManagers(commandGitOpsTargets(cmd, result.Namespace), cmd.Context, cfg.GitOps.ArgoCDNamespace, cfg.GitOps.SuspendReconciliation)
+		result.Reasons = append(result.Reasons, reasons...)
+	}
+
+	// Surface admission/validation errors before the user confirms
+	if cfg.Preflight.Has(config.PreflightServerDryRun) && writeOperations[cmd.Operation] && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.serverDryRunReasons(args)...)
+	}
+	if cfg.Preflight.Has(config.PreflightCanI) && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.canIReasons(commandCanIQueries(cmd, result.Namespace), cmd.Context)...)
+	}
+	if cfg.Preflight.Has(config.PreflightCredentials) && cfg.IsProtectedCluster(cluster) {
+		result.Reasons = append(result.Reasons, r.credentialReasons(cmd.Kubeconfig, cmd.Context, cluster)...)
+	}
+
+	// -n and --context are easily mixed up
+	if r.getContexts != nil {
+		if reasons := flagConfusionReasons(cmd, r.getContexts(cmd.Kubeconfig)); len(reasons) > 0 {
+			result.Reasons = append(result.Reasons, reasons...)
+			result.RequireConfirmation()
+		}
+	}
+
+	// Friction follows the operator's role
+	policy, reason, roster := r.groupPolicy(cfg, cluster)
+	result.Roster = roster
+	if policy != nil {
+		result.Reasons = append(result.Reasons, reason)
+		result.RequiresConfirmation, result.ConfirmationPhrase = groupConfirmation(policy, cluster, result.RequiresConfirmation, result.ModeConfirmation, result.ConfirmationPhrase)
+	}
+
+	// Protected clusters may only be changed inside their change windows
+	if reason, window := r.outsideChangeWindow(cfg, cluster); window != nil {
+		result.Reasons = append(result.Reasons, reason)
+		var err error
+		result.RequiresConfirmation, result.ConfirmationPhrase, err = changeWindowConfirmation(window, cluster, result.ConfirmationPhrase)
+		if err != nil && checkOnly {
+			return r.printCommandVerdict(result, err)
+		}
+		if err != nil {
+			if logErr := auditLogger.Log(result, args, false, false); logErr != nil {
+				fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+			}
+			return err
+		}
+	}
+
+	// Show who will perform the operation, and through which proxy
+	if r.getIdentity != nil {
+		result.Identity = r.getIdentity(cmd.Kubeconfig, cmd.Context)
+	}
+	result.Proxy, result.ActingAs = r.interception(args, cmd.Kubeconfig, cmd.Context)
+	result.Reasons = append(result.Reasons, interceptionReasons(result.Proxy, result.ActingAs)...)
+	if checkOnly {
+		return r.printCommandVerdict(result, nil)
+	}
+
+	// Organization policy hooks can block the command before it is confirmed
+	if err := r.runPreExecHooks(cfg.Hooks.PreExec, commandHookEvent(result, args)); err != nil {
+		if logErr := auditLogger.Log(result, args, false, false); logErr != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+		}
+		return err
+	}
+
+	// A change looped over contexts is tracked as one batch
+	fanOut := r.openBatch(skFlags["batch"], cfg.Batch.Dir)
+	result.Reasons = append(result.Reasons, batchReasons(fanOut, args)...)
+
+	// Display warning, in the wording of a warning experiment variant if one applies
+	var resource string
+	if len(cmd.Targets) > 0 {
+		resource = cmd.Targets[0].Resource
+	}
+	variant := r.warningVariant(cfg.WarningExperiments, cmd.Operation, resource)
+	result.Variant = variant.Name
+	r.displayWarning(cfg.Templates.Warning, result, args, variant)
+
+	// Show what a namespace deletion will take with it
+	if cfg.PreviewNamespaceDeletion && r.getNamespaceResources != nil {
+		for _, ns := range result.CascadeNamespaces {
+			prompt.DisplayNamespacePreviewTo(r.stdout, ns, r.getNamespaceResources(cmd.Kubeconfig, cmd.Context, ns))
+		}
+	}
+	for _, e := range evictions {
+		prompt.DisplayDrainPreviewTo(r.stdout, e)
+	}
 
 	// Handle based on confirmation requirement
+	r.showBatchProgress(fanOut, cmd.Kubeconfig, cluster)
 	confirmed := false
 	if result.RequiresConfirmation {
-		confirmed = prompt.AskConfirmationFrom(r.stdin, r.stdout)
+		r.notifyPrompt(cfg.Notify, result.Severity(), result.Operation, result.Cluster)
+		if result.ConfirmationPhrase != "" {
+			confirmed = prompt.AskTypedConfirmationFrom(r.stdin, r.stdout, result.ConfirmationPhrase)
+		} else {
+			confirmed = prompt.AskConfirmationFrom(r.stdin, r.stdout)
+		}
+		r.recordBatch(fanOut, cfg.Batch.Dir, cluster, args, confirmed)
 		if !confirmed {
 			prompt.DisplayAbortedTo(r.stdout)
 			// Log denied operation
@@ -109,74 +558,490 @@ func (r *Runner) Run(args []string) error {
 		// Warn-only mode (unless protected)
 		prompt.DisplayProceedingTo(r.stdout)
 		confirmed = true
+		r.recordBatch(fanOut, cfg.Batch.Dir, cluster, args, confirmed)
 	}
 
-	// Log the operation
-	if err := auditLogger.Log(result, args, confirmed, true); err != nil {
-		fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", err)
+	// Changes to protected clusters are made under a change ticket
+	ticketID, err := r.changeTicket(cfg.Ticket, cfg.IsProtectedCluster(cluster), skFlags["ticket"])
+	if err != nil {
+		if logErr := auditLogger.Log(result, args, confirmed, false); logErr != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+		}
+		return err
 	}
+	result.Ticket = ticketID
 
-	// Execute kubectl
-	return r.executeKubectl(args)
+	// Ask why the change is made, with audit.requireReason
+	justification, err := r.changeReason(cfg.Audit, skFlags["reason"])
+	if err != nil {
+		if logErr := auditLogger.Log(result, args, confirmed, false); logErr != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+		}
+		return err
+	}
+	result.Justification = justification
+
+	// Back up before deleting; a failed backup stops the delete
+	backupNames, err := r.runBackups(cfg.BackupRequired.Hook, backups, cluster)
+	if err != nil {
+		if logErr := auditLogger.Log(result, args, confirmed, false); logErr != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+		}
+		return err
+	}
+
+	// Offer the emergency path that Git will not undo: suspend, change, resume
+	if cfg.GitOps.SuspendReconciliation && result.RequiresConfirmation && len(managers) > 0 {
+		suspended := r.suspendReconciliation(managers, cmd.Context, cluster, auditLogger)
+		defer r.remindResume(suspended, cmd.Context)
+	}
+
+	// Capture what a restart-by-delete should bring back
+	var watches []recreationWatch
+	if cfg.WatchRecreation.Enabled && cmd.Operation == "delete" && r.queryKubectl != nil {
+		watches = r.prepareRecreationWatches(cmd, result.Namespace)
+	}
+
+	// Follow-up checks read their targets before the operation changes them
+	verifications := r.verifyCommands(cfg.VerifyCommandsFor(cmd.Operation, cmd.Subcommand), commandVerifyTargets(cmd, result.Namespace), cmd.Context)
+
+	// Keep a copy of what is deleted, so a mistake can be undone
+	if cfg.Snapshot.Enabled && cmd.Operation == "delete" && r.queryKubectl != nil {
+		if dir := r.snapshot(commandSnapshotTargets(cmd, result.Namespace), cfg.Snapshot.Dir, cmd.Context); dir != "" {
+			backupNames = append(backupNames, dir)
+		}
+	}
+
+	// Execute kubectl, then log the operation with its outcome
+	execution, err := r.execute(args)
+	execution.Backups = backupNames
+	if logErr := auditLogger.LogExecuted(result, args, confirmed, execution); logErr != nil {
+		fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+	}
+	r.runPostExecHooks(cfg.Hooks.PostExec, commandHookEvent(result, args), execution)
+	r.notifyChannels(cfg.Notify.Channels, commandHookEvent(result, args), result.Severity(), result.RequiresConfirmation, execution)
+	if err != nil {
+		return err
+	}
+	if cmd.SwitchedContext() != "" {
+		r.contextBanner(cfg, cmd, cluster)
+	}
+
+	// Hold the terminal until an image change has rolled out
+	if cfg.RolloutGate.Enabled && cfg.IsProtectedCluster(cluster) && cmd.Operation == "set" && cmd.Subcommand == "image" && r.queryKubectl != nil {
+		if err := r.gateRollouts(setImageRolloutTargets(cmd, result.Namespace), cfg.RolloutGate, cmd.Context, cluster, auditLogger); err != nil {
+			return err
+		}
+	}
+
+	for _, w := range watches {
+		prompt.DisplayRecreationWaitTo(r.stdout, w.display, cfg.WatchRecreation.Timeout)
+		ready := r.awaitRecreation(w, result.Namespace, cmd.Context, cfg.WatchRecreation.Timeout)
+		prompt.DisplayRecreationResultTo(r.stdout, w.display, ready)
+		if err := auditLogger.LogRecreation(result, w.display, args, ready); err != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", err)
+		}
+	}
+
+	r.offerVerification(verifications, result.RequiresConfirmation)
+	return nil
 }
 
 // runWithFileInputs handles commands with -f flags
-func (r *Runner) runWithFileInputs(cmd *parser.KubectlCommand, cfg *config.Config, cluster string, args []string) error {
+func (r *Runner) runWithFileInputs(cmd *parser.KubectlCommand, cfg *config.Config, cluster string, args []string, skFlags map[string]string) error {
+	checkOnly := skFlags["output"] != ""
+
+	// GitHub and GitLab blob URLs serve a web page; kubectl needs the file itself
+	for i, fileInput := range cmd.FileInputs {
+		if rawURL, ok := manifest.RawURL(fileInput); ok {
+			prompt.DisplayRawURLTo(r.stderr, fileInput, rawURL)
+			args = withFileInput(args, fileInput, rawURL)
+			cmd.FileInputs[i] = rawURL
+		}
+	}
+
 	// Dry-run commands are safe - execute directly
+	if cmd.DryRun && checkOnly {
+		return r.printResourcesVerdict(&checker.ResourceCheckResult{Operation: cmd.Operation, Cluster: cluster, Reasons: []string{}}, nil)
+	}
 	if cmd.DryRun {
+		// A server-side dry run still uploads the file
+		for _, fileInput := range cmd.FileInputs {
+			if err := manifest.CheckFile(fileInput); err != nil {
+				return err
+			}
+		}
 		return r.executeKubectl(args)
 	}
 
+	canarySpec := skFlags["canary"]
+	if canarySpec != "" && cmd.Operation != "apply" {
+		return fmt.Errorf("--sk-canary is only supported for apply, got %s", cmd.Operation)
+	}
+
 	// Collect all resources from all file inputs
 	var allResources []manifest.Resource
 
 	confirmURL := func(url string) bool {
+		// Checking is read-only and must not prompt, so URLs are fetched as is
+		if checkOnly {
+			return true
+		}
 		prompt.DisplayURLWarningTo(r.stdout, url)
 		return prompt.AskConfirmationFrom(r.stdin, r.stdout)
 	}
 
+	var stdinContent []byte
 	for _, fileInput := range cmd.FileInputs {
-		resources, err := manifest.Parse(fileInput, cmd.Recursive, confirmURL)
+		var resources []manifest.Resource
+		var err error
+		if fileInput == manifest.StdinSource {
+			if stdinContent, err = io.ReadAll(r.stdin); err != nil {
+				return fmt.Errorf("failed to read stdin: %w", err)
+			}
+			resources, err = manifest.ParseStdin(stdinContent)
+		} else {
+			resources, err = manifest.Parse(fileInput, cmd.Recursive, confirmURL)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to parse %s: %w", fileInput, err)
 		}
 		allResources = append(allResources, resources...)
 	}
 
+	// Stdin carried the manifests, so kubectl reads them from a file and
+	// prompts read from the terminal
+	execArgs := args
+	if stdinContent != nil && !checkOnly {
+		path, err := writeStdinManifest(stdinContent)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(path)
+		execArgs = withStdinFile(args, path)
+
+		var closeTerminal func()
+		r, closeTerminal = r.withTerminalInput()
+		defer closeTerminal()
+	}
+
 	// Resolve empty namespaces
 	fallbackNS := cmd.Namespace
 	if fallbackNS == "" && r.getContextNamespace != nil {
-		fallbackNS = r.getContextNamespace(cmd.Context) // Use specified --context or empty for current
+		fallbackNS = r.getContextNamespace(cmd.Kubeconfig, cmd.Context) // Use specified --context or empty for current
 	}
 	if fallbackNS == "" {
 		fallbackNS = "default"
 	}
 
-	for i := range allResources {
-		if allResources[i].Namespace == "" {
-			allResources[i].Namespace = fallbackNS
-		}
-	}
+	defaultNamespaces(allResources, fallbackNS)
 
 	// Check resources
 	chk := checker.New(cfg)
-	result := chk.CheckResources(cmd.Operation, allResources, cluster)
+	var result *checker.ResourceCheckResult
+	if cmd.IsForceReplace() {
+		// replace --force deletes and recreates its objects, so it is checked as a delete
+		result = chk.CheckForceReplace(allResources, cluster)
+	} else {
+		result = chk.CheckResources(cmd.Operation, allResources, cluster)
+	}
 
 	// Initialize audit logger
 	auditLogger := audit.New(cfg)
+	if stdinContent != nil {
+		if err := auditLogger.RecordStdin(stdinContent); err != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", err)
+		}
+	}
+
+	// Say what happens to the dependents of the deleted objects
+	if reason := checker.CascadeReason(cmd.Operation, cmd.Cascade); reason != "" && result.IsDangerous {
+		result.Reasons = append(result.Reasons, reason)
+	}
+
+	// Pruning deletes live objects that are missing from the manifests
+	if prune := cmd.PruneOptions(); prune != nil {
+		result.IsDangerous = true
+		result.RequireConfirmation()
+		result.Reasons = append(result.Reasons, pruneReason(prune))
+		if cfg.PreviewPrune && r.queryKubectl != nil {
+			result.Reasons = append(result.Reasons, r.prunedReasons(cmd, prune, result.Resources, fallbackNS)...)
+		}
+	}
+
+	if cfg.ControlPlaneLoad.Enabled {
+		if reasons := manifestLoadReasons(cmd.Operation, len(result.Resources), cfg.ControlPlaneLoad); len(reasons) > 0 {
+			result.IsDangerous = true
+			result.Reasons = append(result.Reasons, reasons...)
+			if cfg.ControlPlaneLoad.Confirm {
+				result.RequireConfirmation()
+			}
+		}
+	}
+
+	if cfg.CheckManifestRisks && writeOperations[cmd.Operation] {
+		if reasons := manifestRiskReasons(result.Resources); len(reasons) > 0 {
+			result.IsDangerous = true
+			result.Reasons = append(result.Reasons, reasons...)
+		}
+	}
+
+	if cfg.PreviewApplyOrder && cmd.Operation == "apply" {
+		siblings := manifest.Siblings(result.Resources)
+		defaultNamespaces(siblings, fallbackNS)
+		reasons := append(manifest.OrderReasons(result.Resources), manifest.ExcludedReasons(result.Resources, siblings)...)
+		if len(reasons) > 0 {
+			result.IsDangerous = true
+			result.Reasons = append(result.Reasons, reasons...)
+		}
+	}
+
+	if cfg.OPA.Enabled {
+		reasons, err := r.policyReasons(cfg.OPA, resourcesPolicyInput(cmd, result, cfg.IsProtectedCluster(cluster)))
+		if err != nil && checkOnly {
+			return r.printResourcesVerdict(result, err)
+		}
+		if err != nil {
+			if logErr := auditLogger.LogResources(result, args, false, false); logErr != nil {
+				fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+			}
+			return err
+		}
+		if len(reasons) > 0 {
+			result.IsDangerous = true
+			result.Reasons = append(result.Reasons, reasons...)
+		}
+	}
+
+	if cfg.NamespacePolicy {
+		err := r.namespacePolicyBlock(cmd.Operation, manifestPolicyNamespaces(result.Resources), cmd.Context)
+		if err != nil && checkOnly {
+			return r.printResourcesVerdict(result, err)
+		}
+		if err != nil {
+			if logErr := auditLogger.LogResources(result, args, false, false); logErr != nil {
+				fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+			}
+			return err
+		}
+	}
+
+	if err := protectedNodeBlock(cfg, result.ProtectedNodes); err != nil {
+		if checkOnly {
+			return r.printResourcesVerdict(result, err)
+		}
+		if logErr := auditLogger.LogResources(result, args, false, false); logErr != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+		}
+		return err
+	}
+
+	if cfg.Plugins.Enabled {
+		reasons, confirm, err := r.runPlugins(cfg.Plugins, resourcesPolicyInput(cmd, result, cfg.IsProtectedCluster(cluster)))
+		if err != nil && checkOnly {
+			return r.printResourcesVerdict(result, err)
+		}
+		if err != nil {
+			if logErr := auditLogger.LogResources(result, args, false, false); logErr != nil {
+				fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+			}
+			return err
+		}
+		if len(reasons) > 0 {
+			result.IsDangerous = true
+			result.Reasons = append(result.Reasons, reasons...)
+			if confirm {
+				result.RequireConfirmation()
+			}
+		}
+	}
 
 	// If not dangerous, execute directly
+	if !result.IsDangerous && checkOnly {
+		return r.printResourcesVerdict(result, nil)
+	}
 	if !result.IsDangerous {
-		return r.executeKubectl(args)
+		return r.runWithHooks(cfg.Hooks, resourcesHookEvent(result, args), func() error {
+			if canarySpec != "" {
+				return r.runCanaryApply(cmd, canarySpec, result, auditLogger, args)
+			}
+			return r.executeKubectl(execArgs)
+		})
+	}
+
+	if err := r.freezeBlock(cfg.Freeze, cluster); err != nil {
+		if checkOnly {
+			return r.printResourcesVerdict(result, err)
+		}
+		if logErr := auditLogger.LogResources(result, args, false, false); logErr != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+		}
+		return err
+	}
+
+	if cfg.CountConfigReferences && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.configReferenceReasons(manifestConfigObjects(result.Resources), cmd.Context)...)
+	}
+	if cfg.CheckMissingReferences && writeOperations[cmd.Operation] && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.missingReferenceReasons(result.Resources, cmd.Context)...)
+	}
+	if cmd.Operation == "apply" && cfg.IsProtectedCluster(cluster) && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.manifestServiceReasons(result.Resources, cmd.Context)...)
+	}
+	if cmd.Operation == "delete" && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.authBreakageReasons(manifestAuthObjects(result.Resources), cmd.Context)...)
+		result.Reasons = append(result.Reasons, r.priorityClassUsageReasons(manifestPriorityClasses(result.Resources), cmd.Context)...)
 	}
 
-	// Display warning
-	prompt.DisplayResourceWarningTo(r.stdout, result, args)
+	if cmd.Operation == "delete" && cfg.CheckDeleteTargets && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.existenceReasons(manifestSnapshotTargets(result.Resources, cmd.Namespace), cmd.Context)...)
+	}
+	if cmd.Operation == "delete" && cfg.CheckActiveJobs && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.activeJobReasons(manifestJobs(result.Resources), job.IsOrphanCascade(cmd.Args), cmd.Context)...)
+	}
+	var backups []backupTarget
+	if cmd.Operation == "delete" && cfg.IsProtectedCluster(cluster) {
+		backups = manifestBackupTargets(result.Resources, cfg)
+		result.Reasons = append(result.Reasons, backupReasons(backups)...)
+	}
+	if writeOperations[cmd.Operation] {
+		if reasons := networkPolicyLockoutReasons(result.Resources, cfg); len(reasons) > 0 {
+			result.Reasons = append(result.Reasons, reasons...)
+			result.RequireConfirmation()
+		}
+	}
+	if writeOperations[cmd.Operation] && r.queryKubectl != nil {
+		if reasons := r.manifestGuardrailReasons(result.Resources, cfg, cmd.Context); len(reasons) > 0 {
+			result.Reasons = append(result.Reasons, reasons...)
+			result.RequireConfirmation()
+		}
+	}
+	if r.queryKubectl != nil {
+		reasons, critical := r.manifestRouteReasons(cmd.Operation, result.Resources, cfg.Routes, cmd.Context)
+		result.Reasons = append(result.Reasons, reasons...)
+		if critical {
+			result.RequireConfirmation()
+		}
+	}
+	if cfg.Preflight.Has(config.PreflightServerDryRun) && writeOperations[cmd.Operation] && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.serverDryRunReasons(execArgs)...)
+	}
+	if cfg.Preflight.Has(config.PreflightCanI) && r.queryKubectl != nil {
+		result.Reasons = append(result.Reasons, r.canIReasons(manifestCanIQueries(cmd.Operation, result.Resources), cmd.Context)...)
+	}
+	if cfg.Preflight.Has(config.PreflightCredentials) && cfg.IsProtectedCluster(cluster) {
+		result.Reasons = append(result.Reasons, r.credentialReasons(cmd.Kubeconfig, cmd.Context, cluster)...)
+	}
+
+	var managers []gitops.Manager
+	if (cfg.GitOps.SuspendReconciliation || cfg.GitOps.Warn) && manifestGitOpsOperations[cmd.Operation] && r.queryKubectl != nil {
+		var reasons []string
+		reasons, managers = r.gitopsManagers(manifestGitOpsTargets(result.Resources), cmd.Context, cfg.GitOps.ArgoCDNamespace, cfg.GitOps.SuspendReconciliation)
+		result.Reasons = append(result.Reasons, reasons...)
+	}
+
+	if r.getContexts != nil {
+		if reasons := flagConfusionReasons(cmd, r.getContexts(cmd.Kubeconfig)); len(reasons) > 0 {
+			result.Reasons = append(result.Reasons, reasons...)
+			result.RequireConfirmation()
+		}
+	}
+
+	policy, reason, roster := r.groupPolicy(cfg, cluster)
+	result.Roster = roster
+	if policy != nil {
+		result.Reasons = append(result.Reasons, reason)
+		result.RequiresConfirmation, result.ConfirmationPhrase = groupConfirmation(policy, cluster, result.RequiresConfirmation, result.ModeConfirmation, result.ConfirmationPhrase)
+	}
+
+	if reason, window := r.outsideChangeWindow(cfg, cluster); window != nil {
+		result.Reasons = append(result.Reasons, reason)
+		var err error
+		result.RequiresConfirmation, result.ConfirmationPhrase, err = changeWindowConfirmation(window, cluster, result.ConfirmationPhrase)
+		if err != nil && checkOnly {
+			return r.printResourcesVerdict(result, err)
+		}
+		if err != nil {
+			if logErr := auditLogger.LogResources(result, args, false, false); logErr != nil {
+				fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+			}
+			return err
+		}
+	}
+
+	if r.getIdentity != nil {
+		result.Identity = r.getIdentity(cmd.Kubeconfig, cmd.Context)
+	}
+	result.Proxy, result.ActingAs = r.interception(args, cmd.Kubeconfig, cmd.Context)
+	result.Reasons = append(result.Reasons, interceptionReasons(result.Proxy, result.ActingAs)...)
+	if checkOnly {
+		return r.printResourcesVerdict(result, nil)
+	}
+
+	if err := r.runPreExecHooks(cfg.Hooks.PreExec, resourcesHookEvent(result, args)); err != nil {
+		if logErr := auditLogger.LogResources(result, args, false, false); logErr != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+		}
+		return err
+	}
+
+	fanOut := r.openBatch(skFlags["batch"], cfg.Batch.Dir)
+	result.Reasons = append(result.Reasons, batchReasons(fanOut, args)...)
+
+	// Display warning, in the wording of a warning experiment variant if one applies
+	var kind string
+	if len(allResources) > 0 {
+		kind = allResources[0].Kind
+	}
+	variant := r.warningVariant(cfg.WarningExperiments, cmd.Operation, kind)
+	result.Variant = variant.Name
+	// Large operations are reviewed namespace by namespace instead of scrolling past
+	review := cfg.Review.Enabled && result.RequiresConfirmation && len(result.Resources) >= cfg.Review.MinResources && r.isTerminal != nil && r.isTerminal()
+	if !review {
+		r.displayResourceWarning(cfg.Templates.ResourceWarning, result, args, variant)
+	}
+	if cfg.PreviewApplyOrder && cmd.Operation == "apply" {
+		if order := manifest.KindOrder(result.Resources); len(order) > 1 {
+			prompt.DisplayApplyOrderTo(r.stdout, order)
+		}
+	}
 
 	// Handle confirmation
+	r.showBatchProgress(fanOut, cmd.Kubeconfig, cluster)
 	confirmed := false
 	if result.RequiresConfirmation {
-		confirmed = prompt.AskConfirmationFrom(r.stdin, r.stdout)
+		r.notifyPrompt(cfg.Notify, result.Severity(), result.Operation, result.Cluster)
+		protected := protectedResources(result.Resources, cfg)
+		switch {
+		case result.ConfirmationPhrase != "":
+			confirmed = prompt.AskTypedConfirmationFrom(r.stdin, r.stdout, result.ConfirmationPhrase)
+		case review:
+			confirmed = prompt.ReviewResourcesFrom(r.stdin, r.stdout, result, args, variant, cfg.Review.PageSize)
+		case cfg.SelectiveApply && canarySpec == "" && len(protected) > 0:
+			var skip []manifest.Resource
+			if skip, confirmed = r.selectResources(protected); confirmed && len(skip) > 0 {
+				prompt.DisplaySkippedTo(r.stdout, skip)
+				// The skipped resources are audited as denied, the rest run from a filtered manifest
+				skipped := *result
+				skipped.Resources = skip
+				if err := auditLogger.LogResources(&skipped, args, false, false); err != nil {
+					fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", err)
+				}
+				result.Resources = withoutResources(result.Resources, skip)
+				if backups != nil {
+					backups = manifestBackupTargets(result.Resources, cfg)
+				}
+				path, err := writeSubsetManifest(result.Resources)
+				if err != nil {
+					return err
+				}
+				defer os.Remove(path)
+				execArgs = append(withoutFileArgs(args), "-f", path)
+			}
+		default:
+			confirmed = prompt.AskConfirmationFrom(r.stdin, r.stdout)
+		}
+		r.recordBatch(fanOut, cfg.Batch.Dir, cluster, args, confirmed)
 		if !confirmed {
 			prompt.DisplayAbortedTo(r.stdout)
 			// Log denied operation
@@ -188,47 +1053,449 @@ func (r *Runner) runWithFileInputs(cmd *parser.KubectlCommand, cfg *config.Confi
 	} else {
 		prompt.DisplayProceedingTo(r.stdout)
 		confirmed = true
+		r.recordBatch(fanOut, cfg.Batch.Dir, cluster, args, confirmed)
+	}
+
+	ticketID, err := r.changeTicket(cfg.Ticket, cfg.IsProtectedCluster(cluster), skFlags["ticket"])
+	if err != nil {
+		if logErr := auditLogger.LogResources(result, args, confirmed, false); logErr != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+		}
+		return err
+	}
+	result.Ticket = ticketID
+
+	justification, err := r.changeReason(cfg.Audit, skFlags["reason"])
+	if err != nil {
+		if logErr := auditLogger.LogResources(result, args, confirmed, false); logErr != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+		}
+		return err
+	}
+	result.Justification = justification
+
+	backupNames, err := r.runBackups(cfg.BackupRequired.Hook, backups, cluster)
+	if err != nil {
+		if logErr := auditLogger.LogResources(result, args, confirmed, false); logErr != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+		}
+		return err
+	}
+
+	if cfg.GitOps.SuspendReconciliation && result.RequiresConfirmation && len(managers) > 0 {
+		suspended := r.suspendReconciliation(managers, cmd.Context, cluster, auditLogger)
+		defer r.remindResume(suspended, cmd.Context)
+	}
+
+	// Canary mode applies and audits in phases
+	if canarySpec != "" {
+		start := time.Now()
+		err := r.runCanaryApply(cmd, canarySpec, result, auditLogger, args)
+		execution := audit.Execution{ExitCode: exitCode(err), Duration: time.Since(start)}
+		r.runPostExecHooks(cfg.Hooks.PostExec, resourcesHookEvent(result, args), execution)
+		r.notifyChannels(cfg.Notify.Channels, resourcesHookEvent(result, args), result.Severity(), result.RequiresConfirmation, execution)
+		return err
+	}
+
+	verifications := r.verifyCommands(cfg.VerifyCommandsFor(cmd.Operation, cmd.Subcommand), manifestVerifyTargets(result.Resources), cmd.Context)
+
+	if cfg.Snapshot.Enabled && cmd.Operation == "delete" && r.queryKubectl != nil {
+		if dir := r.snapshot(manifestSnapshotTargets(result.Resources, cmd.Namespace), cfg.Snapshot.Dir, cmd.Context); dir != "" {
+			backupNames = append(backupNames, dir)
+		}
+	}
+
+	// Compare images with the live workloads while they still hold the old ones
+	var rollouts []rolloutTarget
+	if cfg.RolloutGate.Enabled && cfg.IsProtectedCluster(cluster) && cmd.Operation == "apply" && r.queryKubectl != nil {
+		rollouts = r.manifestRolloutTargets(result.Resources, cmd.Context)
+	}
+
+	// Execute kubectl, then log the operation with its outcome
+	execution, err := r.execute(execArgs)
+	execution.Backups = backupNames
+	if logErr := auditLogger.LogResourcesExecuted(result, args, confirmed, execution); logErr != nil {
+		fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+	}
+	r.runPostExecHooks(cfg.Hooks.PostExec, resourcesHookEvent(result, args), execution)
+	r.notifyChannels(cfg.Notify.Channels, resourcesHookEvent(result, args), result.Severity(), result.RequiresConfirmation, execution)
+	if err != nil {
+		return err
+	}
+
+	// Hold the terminal until workloads whose images changed have rolled out
+	if len(rollouts) > 0 {
+		if err := r.gateRollouts(rollouts, cfg.RolloutGate, cmd.Context, cluster, auditLogger); err != nil {
+			return err
+		}
+	}
+
+	r.offerVerification(verifications, result.RequiresConfirmation)
+	return nil
+}
+
+// defaultNamespaces puts namespaced resources that name no namespace in the
+// namespace kubectl applies them to
+func defaultNamespaces(resources []manifest.Resource, namespace string) {
+	for i := range resources {
+		if resources[i].Namespace == "" && !parser.IsClusterScopedKind(resources[i].Kind) {
+			resources[i].Namespace = namespace
+		}
+	}
+}
+
+// skValueFlags are the --sk-* flags that need a value
+var skValueFlags = map[string]bool{
+	"ticket": true,
+	"reason": true,
+	"batch":  true,
+	"canary": true,
+	"output": true,
+	"alias":  true,
+}
+
+// splitSafekubectlFlags separates safekubectl's own --sk-NAME[=VALUE] flags from
+// the kubectl args. Flags in skValueFlags take their value as --sk-NAME=VALUE
+// or from the next argument, and are an error without one; other flags
+// without a value are set to "true". Args after "--" belong to the executed
+// command and are left untouched.
+func splitSafekubectlFlags(args []string) ([]string, map[string]string, error) {
+	flags := make(map[string]string)
+	kubectlArgs := make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			kubectlArgs = append(kubectlArgs, args[i:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "--sk-") {
+			kubectlArgs = append(kubectlArgs, arg)
+			continue
+		}
+		name, value, found := strings.Cut(strings.TrimPrefix(arg, "--sk-"), "=")
+		if !found && skValueFlags[name] {
+			if i+1 == len(args) || strings.HasPrefix(args[i+1], "-") {
+				return nil, nil, fmt.Errorf("--sk-%s needs a value: --sk-%s=VALUE", name, name)
+			}
+			i++
+			value, found = args[i], true
+		}
+		if !found {
+			value = "true"
+		}
+		flags[name] = value
 	}
 
-	// Log the operation
-	if err := auditLogger.LogResources(result, args, confirmed, true); err != nil {
-		fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", err)
+	return kubectlArgs, flags, nil
+}
+
+// kubeconfigs caches in-process kubeconfig reads by explicit path, so context
+// lookups do not shell out to kubectl or re-read the files
+var kubeconfigs = struct {
+	sync.Mutex
+	loaded map[string]*kubeconfig.Kubeconfig
+}{loaded: make(map[string]*kubeconfig.Kubeconfig)}
+
+// loadKubeconfig reads the kubeconfig kubectl would use with --kubeconfig=path,
+// or the default kubeconfig files when path is empty
+func loadKubeconfig(path string) (*kubeconfig.Kubeconfig, error) {
+	kubeconfigs.Lock()
+	defer kubeconfigs.Unlock()
+
+	if kc, ok := kubeconfigs.loaded[path]; ok {
+		return kc, nil
+	}
+	kc, err := kubeconfig.Load(kubeconfig.Files(path))
+	if err != nil {
+		return nil, err
 	}
+	kubeconfigs.loaded[path] = kc
+	return kc, nil
+}
 
-	// Execute kubectl
-	return r.executeKubectl(args)
+// kubeconfigArgs returns the --kubeconfig flag for an explicit kubeconfig
+func kubeconfigArgs(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return []string{"--kubeconfig", path}
+}
+
+// withKubeconfig returns a copy of the runner whose kubectl queries and
+// follow-up commands use the given kubeconfig unless they already set one
+func (r *Runner) withKubeconfig(path string) *Runner {
+	withFlag := func(args []string) []string {
+		for _, arg := range args {
+			if arg == "--" {
+				break
+			}
+			if arg == "--kubeconfig" || strings.HasPrefix(arg, "--kubeconfig=") {
+				return args
+			}
+		}
+		return withFlags(args, kubeconfigArgs(path)...)
+	}
+
+	wrapped := *r
+	if query := r.queryKubectl; query != nil {
+		wrapped.queryKubectl = func(args []string) ([]byte, error) { return query(withFlag(args)) }
+	}
+	if execute := r.executeKubectl; execute != nil {
+		wrapped.executeKubectl = func(args []string) error { return execute(withFlag(args)) }
+	}
+	return &wrapped
 }
 
 // getCurrentCluster gets the current kubernetes context/cluster name
-func getCurrentCluster() string {
-	cmd := exec.Command("kubectl", "config", "current-context")
-	output, err := cmd.Output()
+// from the kubeconfig, falling back to kubectl when it cannot be read
+func getCurrentCluster(kubeconfigPath string) string {
+	if kc, err := loadKubeconfig(kubeconfigPath); err == nil && kc.CurrentContext != "" {
+		return kc.CurrentContext
+	}
+
+	args := append([]string{"config", "current-context"}, kubeconfigArgs(kubeconfigPath)...)
+	output, err := exec.Command("kubectl", args...).Output()
 	if err != nil {
 		return "<unknown>"
 	}
 	return strings.TrimSpace(string(output))
 }
 
+// getClusterServer gets the kubeconfig cluster name and API server URL of the
+// specified context. If context is empty, uses the current context
+func getClusterServer(kubeconfigPath, context string) (string, string) {
+	kc, err := loadKubeconfig(kubeconfigPath)
+	if err != nil {
+		return "", ""
+	}
+	return kc.Server(context)
+}
+
+// getContexts returns the contexts the kubeconfig defines, or nil if it
+// cannot be read
+func getContexts(kubeconfigPath string) map[string]kubeconfig.Context {
+	kc, err := loadKubeconfig(kubeconfigPath)
+	if err != nil {
+		return nil
+	}
+	return kc.Contexts
+}
+
 // getContextDefaultNamespace gets the default namespace from the specified context
 // If context is empty, uses the current context
-func getContextDefaultNamespace(context string) string {
-	var cmd *exec.Cmd
+func getContextDefaultNamespace(kubeconfigPath, context string) string {
+	if kc, err := loadKubeconfig(kubeconfigPath); err == nil {
+		if ctx, ok := kc.Context(context); ok {
+			return ctx.Namespace
+		}
+	}
+
+	var args []string
 	if context == "" {
 		// Get namespace from current context
-		cmd = exec.Command("kubectl", "config", "view", "--minify", "-o", "jsonpath={.contexts[0].context.namespace}")
+		args = []string{"config", "view", "--minify", "-o", "jsonpath={.contexts[0].context.namespace}"}
 	} else {
 		// Get namespace from specific context
 		// Use jsonpath to find the namespace for the specified context
 		jsonpath := fmt.Sprintf("jsonpath={.contexts[?(@.name==\"%s\")].context.namespace}", context)
-		cmd = exec.Command("kubectl", "config", "view", "-o", jsonpath)
+		args = []string{"config", "view", "-o", jsonpath}
 	}
-	output, err := cmd.Output()
+	output, err := exec.Command("kubectl", append(args, kubeconfigArgs(kubeconfigPath)...)...).Output()
 	if err != nil {
 		return ""
 	}
 	return strings.TrimSpace(string(output))
 }
 
+// getIdentity returns the authenticated user via kubectl auth whoami, falling
+// back to the kubeconfig user of the context when whoami is unavailable.
+// If context is empty, uses the current context
+func getIdentity(kubeconfigPath, context string) string {
+	configArgs := append(kubeconfigArgs(kubeconfigPath), kubectlContextArgs(context)...)
+
+	whoami := append([]string{"auth", "whoami", "-o", "jsonpath={.status.userInfo.username}"}, configArgs...)
+	if output, err := exec.Command("kubectl", whoami...).Output(); err == nil {
+		if user := strings.TrimSpace(string(output)); user != "" {
+			return user
+		}
+	}
+
+	if kc, err := loadKubeconfig(kubeconfigPath); err == nil {
+		if ctx, ok := kc.Context(context); ok {
+			return ctx.User
+		}
+	}
+
+	view := append([]string{"config", "view", "--minify", "-o", "jsonpath={.contexts[0].context.user}"}, configArgs...)
+	output, err := exec.Command("kubectl", view...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// getNamespaceResources lists the resources inside a namespace via kubectl get all
+// If context is empty, uses the current context
+func getNamespaceResources(kubeconfigPath, context, namespace string) []string {
+	args := []string{"get", "all", "-n", namespace, "-o", "name"}
+	args = append(args, kubeconfigArgs(kubeconfigPath)...)
+	if context != "" {
+		args = append(args, "--context", context)
+	}
+	output, err := exec.Command("kubectl", args...).Output()
+	if err != nil {
+		return nil
+	}
+
+	var resources []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			resources = append(resources, line)
+		}
+	}
+	return resources
+}
+
+// runHook runs an external program with extra environment variables and
+// optional stdin, and returns its stdout. A non-zero exit returns its stderr
+// as the error.
+func runHook(command, env []string, stdin []byte) ([]byte, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("hook command is empty")
+	}
+	c := exec.Command(command[0], command[1:]...)
+	c.Env = append(os.Environ(), env...)
+	if stdin != nil {
+		c.Stdin = bytes.NewReader(stdin)
+	}
+	output, err := c.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return output, fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return output, err
+}
+
+// queryKubectl runs a read-only kubectl command and returns its stdout.
+// On failure the error carries kubectl's stderr.
+func queryKubectl(args []string) ([]byte, error) {
+	output, err := exec.Command("kubectl", args...).Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return output, fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return output, err
+}
+
+// kubectlExitError reports that kubectl ran and exited non-zero
+type kubectlExitError struct {
+	code int
+}
+
+func (e *kubectlExitError) Error() string {
+	return fmt.Sprintf("kubectl exited with code %d", e.code)
+}
+
+// exitCode returns the exit code to record for an executeKubectl error.
+// Failures to run kubectl at all are recorded as 1, like safekubectl's own.
+func exitCode(err error) int {
+	var exitErr *kubectlExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		return exitErr.code
+	default:
+		return 1
+	}
+}
+
+// execute runs kubectl and measures how it finished, for the audit log
+func (r *Runner) execute(args []string) (audit.Execution, error) {
+	if r.capture != nil {
+		r.capture.reset()
+	}
+	start := time.Now()
+	err := r.executeKubectl(args)
+	execution := audit.Execution{ExitCode: exitCode(err), Duration: time.Since(start)}
+	if r.capture != nil {
+		execution.Output, execution.OutputTruncated = r.capture.result()
+	}
+	return execution, err
+}
+
+// pageKubectl runs kubectl with its output piped through a pager, and
+// returns kubectl's exit status
+func pageKubectl(args, pager []string) error {
+	kubectl, err := exec.LookPath("kubectl")
+	if err != nil {
+		return fmt.Errorf("kubectl not found in PATH: %w", err)
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	pagerCmd := exec.Command(pager[0], pager[1:]...)
+	pagerCmd.Stdin = reader
+	pagerCmd.Stdout = os.Stdout
+	pagerCmd.Stderr = os.Stderr
+	cmd := exec.Command(kubectl, args...)
+	cmd.Stdout = writer
+	cmd.Stderr = os.Stderr
+
+	if err := pagerCmd.Start(); err != nil {
+		reader.Close()
+		writer.Close()
+		return fmt.Errorf("failed to start pager %s: %w", pager[0], err)
+	}
+	err = cmd.Start()
+	// The children hold their own ends; the pager sees EOF once kubectl exits
+	reader.Close()
+	writer.Close()
+	if err == nil {
+		err = cmd.Wait()
+	}
+	pagerCmd.Wait()
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return &kubectlExitError{code: exitErr.ExitCode()}
+	}
+	return err
+}
+
+// filterKubectl runs kubectl with its output written to stdout and stderr, and returns
+// kubectl's exit status
+func filterKubectl(args []string, stdout, stderr io.Writer) error {
+	kubectl, err := exec.LookPath("kubectl")
+	if err != nil {
+		return fmt.Errorf("kubectl not found in PATH: %w", err)
+	}
+
+	cmd := exec.Command(kubectl, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return &kubectlExitError{code: exitErr.ExitCode()}
+		}
+		return err
+	}
+	return nil
+}
+
+// stdoutIsTerminal reports whether stdout is a terminal rather than a pipe or file
+func stdoutIsTerminal() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// openTerminal opens the controlling terminal for reading
+func openTerminal() (io.ReadCloser, error) {
+	return os.Open("/dev/tty")
+}
+
 // executeKubectl runs kubectl with the given arguments
 func executeKubectl(args []string) error {
 	kubectl, err := exec.LookPath("kubectl")
@@ -243,7 +1510,7 @@ func executeKubectl(args []string) error {
 
 	if err := cmd.Run(); err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
-			os.Exit(exitErr.ExitCode())
+			return &kubectlExitError{code: exitErr.ExitCode()}
 		}
 		return err
 	}
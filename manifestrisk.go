@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+)
+
+// manifestRiskReasons flags risky patterns in the pod specs of manifests being
+// applied, such as bare Pods, hostPath volumes and images tagged latest
+func manifestRiskReasons(resources []manifest.Resource) []string {
+	var reasons []string
+	for _, res := range resources {
+		risks, err := manifest.Risks(res)
+		if err != nil {
+			continue
+		}
+		for _, risk := range risks {
+			reasons = append(reasons, "manifest risk: "+res.String()+" "+risk)
+		}
+	}
+	return reasons
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+)
+
+// missingReferenceReasons describes the ConfigMaps, Secrets, ServiceAccounts
+// and PersistentVolumeClaims manifests reference that are neither part of the
+// apply nor in the cluster. Namespaces that cannot be listed are skipped.
+func (r *Runner) missingReferenceReasons(resources []manifest.Resource, kubeContext string) []string {
+	existing := make(map[string]map[string]bool)
+	var reasons []string
+	for _, ref := range manifest.UnresolvedReferences(resources) {
+		names, seen := existing[ref.Namespace]
+		if !seen {
+			names = r.referencedObjects(ref.Namespace, kubeContext)
+			existing[ref.Namespace] = names
+		}
+		if names == nil || names[strings.ToLower(ref.Kind)+"/"+ref.Name] {
+			continue
+		}
+		reasons = append(reasons, fmt.Sprintf("missing reference: %s references %s/%s, which is neither in namespace %s nor in this apply: its pods may not start",
+			ref.From.String(), ref.Kind, ref.Name, ref.Namespace))
+	}
+	return reasons
+}
+
+// referencedObjects lists the ConfigMaps, Secrets, ServiceAccounts and
+// PersistentVolumeClaims in a namespace as kind/name, or nil if they cannot
+// be listed
+func (r *Runner) referencedObjects(namespace, kubeContext string) map[string]bool {
+	out, err := r.queryKubectl(append([]string{"get", "configmaps,secrets,serviceaccounts,persistentvolumeclaims", "-n", namespace, "-o", "name"}, kubectlContextArgs(kubeContext)...))
+	if err != nil {
+		return nil
+	}
+	names := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			names[line] = true
+		}
+	}
+	return names
+}
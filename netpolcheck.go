@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/netpol"
+)
+
+// networkPolicyLockoutReasons checks NetworkPolicies applied to protected
+// namespaces for default-deny rules, blocked DNS and isolated critical pods
+func networkPolicyLockoutReasons(resources []manifest.Resource, cfg *config.Config) []string {
+	var reasons []string
+	for _, res := range resources {
+		if res.Kind != "NetworkPolicy" || !cfg.IsProtectedNamespace(res.Namespace) {
+			continue
+		}
+		policy, err := netpol.Parse(res.Raw)
+		if err != nil {
+			continue
+		}
+		policy.Namespace = res.Namespace
+		for _, reason := range netpol.LockoutReasons(policy, cfg.CriticalSelectors(res.Namespace)) {
+			reasons = append(reasons, "possible connectivity lockout: "+reason)
+		}
+	}
+	return reasons
+}
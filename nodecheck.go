@@ -0,0 +1,38 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/drain"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// nodeReasons shows the pool, zone and instance type of each node a drain or
+// cordon names, and flags pools and zones it leaves with no schedulable
+// node, so the wrong node or the last one of a pool stands out. Nothing is
+// reported if the nodes cannot be listed.
+func (r *Runner) nodeReasons(cmd *parser.KubectlCommand) []string {
+	targets := commandNodes(cmd)
+	if len(targets) == 0 {
+		return nil
+	}
+	out, err := r.queryKubectl(append([]string{"get", "nodes", "-o", "json"}, kubectlContextArgs(cmd.Context)...))
+	if err != nil {
+		return nil
+	}
+	nodes, err := drain.ParseNodeInfos(out)
+	if err != nil {
+		return nil
+	}
+
+	var reasons []string
+	for _, n := range nodes {
+		if !slices.Contains(targets, n.Name) {
+			continue
+		}
+		if description := n.Describe(); description != "" {
+			reasons = append(reasons, "node "+n.Name+": "+description)
+		}
+	}
+	return append(reasons, drain.LastSchedulable(nodes, targets)...)
+}
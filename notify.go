@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/audit"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/notify"
+)
+
+// notifyPrompt alerts the operator, as notify configures for the severity,
+// that a dangerous command is about to wait for confirmation. The bell goes
+// to stderr, which still reaches the terminal when stdout is piped.
+func (r *Runner) notifyPrompt(cfg config.NotifyConfig, severity, operation, cluster string) {
+	bell, desktop := cfg.Alerts(severity)
+	if bell {
+		fmt.Fprint(r.stderr, "\a")
+	}
+	if !desktop || r.runHook == nil {
+		return
+	}
+	message := fmt.Sprintf("%s %s on %s is waiting for confirmation", severity, operation, cluster)
+	command := notify.Command(runtime.GOOS, "safekubectl: confirmation needed", message)
+	if command == nil {
+		return
+	}
+	if _, err := r.runHook(command, nil, nil); err != nil {
+		fmt.Fprintf(r.stderr, "warning: failed to send desktop notification: %s\n", err)
+	}
+}
+
+// notifyChannels tells the notify.channels that want it, by severity, cluster
+// and quiet hours, about a dangerous command that ran. prompted is whether it
+// was confirmed at a prompt. The command has already run, so failures are
+// only reported.
+func (r *Runner) notifyChannels(channels []config.NotifyChannel, event hookEvent, severity string, prompted bool, execution audit.Execution) {
+	if len(channels) == 0 || !event.Dangerous || r.sendNotification == nil {
+		return
+	}
+	now := time.Now()
+	if r.now != nil {
+		now = r.now()
+	}
+	e := notify.Event{
+		Operation: event.Operation,
+		Resources: event.Resources,
+		Namespace: event.Namespace,
+		Cluster:   event.Cluster,
+		Identity:  event.Identity,
+		Severity:  severity,
+		Confirmed: prompted,
+		Reasons:   event.Reasons,
+		Command:   strings.Join(event.Args, " "),
+		ExitCode:  execution.ExitCode,
+		Timestamp: now.Format(time.RFC3339),
+	}
+	for _, ch := range channels {
+		if !ch.Wants(severity, event.Cluster, now) {
+			continue
+		}
+		if err := r.sendNotification(ch, e); err != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to notify channel %s: %s\n", ch.Name, err)
+		}
+	}
+}
+
+// sendNotification posts an event to a channel, at the URL in its urlEnv
+func sendNotification(ch config.NotifyChannel, e notify.Event) error {
+	url := os.Getenv(ch.URLEnv)
+	if url == "" {
+		return fmt.Errorf("%s is not set", ch.URLEnv)
+	}
+	return notify.Send(ch.Type, url, e)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// namespacePolicyAnnotation lets namespace owners opt a namespace into
+// stronger protection for everyone running safekubectl, without their configs
+const namespacePolicyAnnotation = "safekubectl.io/policy"
+
+// blockManualMutations is the namespacePolicyAnnotation value that blocks
+// every command that changes the namespace
+const blockManualMutations = "block-manual-mutations"
+
+// commandPolicyNamespaces returns the namespaces a command changes: its
+// namespace, and the namespaces it deletes or changes by name
+func commandPolicyNamespaces(cmd *parser.KubectlCommand, namespace string) []string {
+	var namespaces []string
+	if namespace != "" && !cmd.IsClusterScoped() && !cmd.IsNodeScoped() {
+		namespaces = append(namespaces, namespace)
+	}
+	for _, t := range cmd.Targets {
+		if t.Name != "" && parser.KindFor(t.Resource) == "Namespace" && !slices.Contains(namespaces, t.Name) {
+			namespaces = append(namespaces, t.Name)
+		}
+	}
+	return namespaces
+}
+
+// manifestPolicyNamespaces returns the namespaces manifest resources are in,
+// and the Namespaces they define
+func manifestPolicyNamespaces(resources []manifest.Resource) []string {
+	var namespaces []string
+	for _, res := range resources {
+		ns := res.Namespace
+		if res.Kind == "Namespace" {
+			ns = res.Name
+		}
+		if ns != "" && !slices.Contains(namespaces, ns) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// namespacePolicyBlock returns an error if the operation changes a namespace
+// annotated to block manual mutations. Namespaces that cannot be read, e.g.
+// for lack of RBAC, are not blocked.
+func (r *Runner) namespacePolicyBlock(operation string, namespaces []string, kubeContext string) error {
+	if checker.IsReadOnlyOperation(operation) || r.queryKubectl == nil {
+		return nil
+	}
+	jsonPath := "jsonpath={.metadata.annotations." + strings.ReplaceAll(namespacePolicyAnnotation, ".", `\.`) + "}"
+	var blocked []string
+	for _, ns := range namespaces {
+		out, err := r.queryKubectl(append([]string{"get", "namespace", ns, "-o", jsonPath}, kubectlContextArgs(kubeContext)...))
+		if err != nil {
+			continue
+		}
+		if strings.TrimSpace(string(out)) == blockManualMutations {
+			blocked = append(blocked, ns)
+		}
+	}
+	if len(blocked) == 0 {
+		return nil
+	}
+	return fmt.Errorf("namespace %s blocks manual changes (%s: %s): make the change through the namespace's deployment pipeline",
+		strings.Join(blocked, ", "), namespacePolicyAnnotation, blockManualMutations)
+}
+
+// protectedNodeBlock returns an error if blockProtectedNodes is set and the
+// operation changes protected nodes
+func protectedNodeBlock(cfg *config.Config, nodes []string) error {
+	if !cfg.BlockProtectedNodes || len(nodes) == 0 {
+		return nil
+	}
+	return fmt.Errorf("node %s is protected and blockProtectedNodes is set: manual changes to it are not allowed", strings.Join(nodes, ", "))
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/opa"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// commandPolicyInput describes a CLI command to the Rego policy
+func commandPolicyInput(cmd *parser.KubectlCommand, result *checker.CheckResult, protected bool) opa.Input {
+	resources := []opa.Resource{}
+	for _, t := range cmd.Targets {
+		kind := parser.KindFor(t.Resource)
+		if kind == "" {
+			kind = t.Resource
+		}
+		res := opa.Resource{Kind: kind, Name: t.Name}
+		if !cmd.AllNamespaces && !cmd.IsNodeScoped() && !parser.IsClusterScopedKind(kind) {
+			res.Namespace = result.Namespace
+		}
+		resources = append(resources, res)
+	}
+	return opa.Input{
+		Operation:        cmd.Operation,
+		Subcommand:       cmd.Subcommand,
+		Resources:        resources,
+		Namespace:        result.Namespace,
+		Cluster:          result.Cluster,
+		ProtectedCluster: protected,
+		AllNamespaces:    cmd.AllNamespaces,
+		DryRun:           cmd.DryRun,
+		Flags:            opa.Flags(cmd.Args),
+		Args:             cmd.Args,
+		Dangerous:        result.IsDangerous,
+		Reasons:          result.Reasons,
+	}
+}
+
+// resourcesPolicyInput describes a file-based command to the Rego policy
+func resourcesPolicyInput(cmd *parser.KubectlCommand, result *checker.ResourceCheckResult, protected bool) opa.Input {
+	resources := []opa.Resource{}
+	for _, res := range result.Resources {
+		resources = append(resources, opa.Resource{Kind: res.Kind, Name: res.Name, Namespace: res.Namespace})
+	}
+	return opa.Input{
+		Operation:        cmd.Operation,
+		Resources:        resources,
+		Cluster:          result.Cluster,
+		ProtectedCluster: protected,
+		DryRun:           cmd.DryRun,
+		Flags:            opa.Flags(cmd.Args),
+		Args:             cmd.Args,
+		Dangerous:        result.IsDangerous,
+		Reasons:          result.Reasons,
+	}
+}
+
+// policyReasons evaluates the Rego policy with `opa eval`. A deny, or a
+// policy that cannot be evaluated, is returned as an error so the command is
+// blocked; warnings are returned as reasons.
+func (r *Runner) policyReasons(opaCfg config.OPAConfig, input opa.Input) ([]string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode policy input: %w", err)
+	}
+	out, err := r.runHook(append([]string{opaCfg.Binary}, opa.EvalArgs(opaCfg.Policy, opaCfg.Query)...), nil, data)
+	if err != nil {
+		return nil, fmt.Errorf("policy evaluation failed: %w", err)
+	}
+	decision, err := opa.ParseDecision(out)
+	if err != nil {
+		return nil, fmt.Errorf("policy evaluation failed: %w", err)
+	}
+
+	switch decision.Action {
+	case opa.Deny:
+		if len(decision.Messages) == 0 {
+			return nil, fmt.Errorf("denied by policy")
+		}
+		return nil, fmt.Errorf("denied by policy: %s", strings.Join(decision.Messages, "; "))
+	case opa.Warn:
+		if len(decision.Messages) == 0 {
+			return []string{"policy: warns about this command"}, nil
+		}
+		var reasons []string
+		for _, m := range decision.Messages {
+			reasons = append(reasons, "policy: "+m)
+		}
+		return reasons, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,112 @@
+// Package plugin is the SDK for safekubectl rule plugins: separate
+// executables, discovered in the plugins directory, that add an
+// organization's own checks such as CMDB or service catalog lookups.
+//
+// safekubectl runs each plugin for every checked command with a Request as
+// JSON on stdin, and reads a Response as JSON from stdout. A plugin is
+// usually a small main package:
+//
+//	func main() {
+//		plugin.Serve(func(req plugin.Request) ([]plugin.Finding, error) {
+//			if req.Operation == "delete" && req.ProtectedCluster {
+//				return []plugin.Finding{{Severity: plugin.Confirm, Message: "change freeze in effect"}}, nil
+//			}
+//			return nil, nil
+//		})
+//	}
+//
+// The Request is the same document an OPA policy sees as input.
+//
+// Built with GOOS=wasip1 GOARCH=wasm, the same plugin is a .wasm module that
+// safekubectl runs sandboxed in a WASI runtime.
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Finding severities, from least to most restrictive
+const (
+	Warn    = "warn"    // shown as a reason in the warning
+	Confirm = "confirm" // shown as a reason, and the command needs confirmation
+	Deny    = "deny"    // the command is blocked
+)
+
+// Resource is one object a command operates on
+type Resource struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name,omitempty"`      // empty for type-only targets, e.g. delete pods --all
+	Namespace string `json:"namespace,omitempty"` // empty for cluster-scoped kinds
+}
+
+// Request describes the command being checked
+type Request struct {
+	Operation        string     `json:"operation"`
+	Subcommand       string     `json:"subcommand,omitempty"`
+	Resources        []Resource `json:"resources"`
+	Namespace        string     `json:"namespace"` // empty for file-based commands
+	Cluster          string     `json:"cluster"`
+	ProtectedCluster bool       `json:"protectedCluster"`
+	AllNamespaces    bool       `json:"allNamespaces"`
+	DryRun           bool       `json:"dryRun"`
+	Flags            []string   `json:"flags"` // flags as given, e.g. "--force", "--grace-period=0"
+	Args             []string   `json:"args"`
+	Dangerous        bool       `json:"dangerous"` // safekubectl's own verdict
+	Reasons          []string   `json:"reasons"`
+}
+
+// Finding is one result of a plugin's checks
+type Finding struct {
+	Severity string `json:"severity"` // warn | confirm | deny
+	Message  string `json:"message"`
+}
+
+// Response is what a plugin returns for a command
+type Response struct {
+	Findings []Finding `json:"findings"`
+}
+
+// Serve runs a plugin's check against the Request on stdin and writes the
+// Response to stdout. If the check fails, its error goes to stderr and the
+// plugin exits 1.
+func Serve(check func(Request) ([]Finding, error)) {
+	if err := Run(os.Stdin, os.Stdout, check); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Run is Serve with explicit streams, for testing plugins
+func Run(in io.Reader, out io.Writer, check func(Request) ([]Finding, error)) error {
+	var req Request
+	if err := json.NewDecoder(in).Decode(&req); err != nil {
+		return fmt.Errorf("failed to read request: %w", err)
+	}
+	findings, err := check(req)
+	if err != nil {
+		return err
+	}
+	if findings == nil {
+		findings = []Finding{}
+	}
+	return json.NewEncoder(out).Encode(Response{Findings: findings})
+}
+
+// ParseResponse reads a plugin's Response, rejecting unknown severities
+func ParseResponse(data []byte) (Response, error) {
+	var resp Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return Response{}, fmt.Errorf("invalid plugin response: %w", err)
+	}
+	for _, f := range resp.Findings {
+		switch f.Severity {
+		case Warn, Confirm, Deny:
+		default:
+			return Response{}, fmt.Errorf("invalid finding severity %q: expected %q, %q or %q", f.Severity, Warn, Confirm, Deny)
+		}
+	}
+	return resp, nil
+}
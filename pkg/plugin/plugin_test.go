@@ -0,0 +1,91 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/opa"
+)
+
+func TestRun(t *testing.T) {
+	in := strings.NewReader(`{"operation":"delete","resources":[{"kind":"Deployment","name":"web","namespace":"shop"}],"cluster":"prod","protectedCluster":true}`)
+	var out bytes.Buffer
+	err := Run(in, &out, func(req Request) ([]Finding, error) {
+		if req.Operation != "delete" || !req.ProtectedCluster || req.Resources[0].Name != "web" {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		return []Finding{{Severity: Confirm, Message: "web is a tier-1 service"}}, nil
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if expected := `{"findings":[{"severity":"confirm","message":"web is a tier-1 service"}]}` + "\n"; out.String() != expected {
+		t.Errorf("got %s, expected %s", out.String(), expected)
+	}
+
+	out.Reset()
+	if err := Run(strings.NewReader(`{}`), &out, func(Request) ([]Finding, error) { return nil, nil }); err != nil || out.String() != `{"findings":[]}`+"\n" {
+		t.Errorf("no findings: got %q, %v", out.String(), err)
+	}
+
+	if err := Run(strings.NewReader(`{}`), &out, func(Request) ([]Finding, error) { return nil, errors.New("cmdb unreachable") }); err == nil || err.Error() != "cmdb unreachable" {
+		t.Errorf("expected the check's error, got %v", err)
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	resp, err := ParseResponse([]byte(`{"findings":[{"severity":"warn","message":"a"},{"severity":"deny","message":"b"}]}`))
+	if err != nil {
+		t.Fatalf("ParseResponse returned error: %v", err)
+	}
+	if len(resp.Findings) != 2 || resp.Findings[1].Severity != Deny {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	if _, err := ParseResponse([]byte(`{"findings":[{"severity":"block","message":"a"}]}`)); err == nil || !strings.Contains(err.Error(), `invalid finding severity "block"`) {
+		t.Errorf("expected invalid severity error, got %v", err)
+	}
+	if _, err := ParseResponse([]byte(`not json`)); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+// The Request must decode exactly what safekubectl sends: the OPA input
+func TestRequestMatchesPolicyInput(t *testing.T) {
+	input := opa.Input{
+		Operation:        "rollout",
+		Subcommand:       "restart",
+		Resources:        []opa.Resource{{Kind: "Deployment", Name: "web", Namespace: "shop"}},
+		Namespace:        "shop",
+		Cluster:          "prod",
+		ProtectedCluster: true,
+		AllNamespaces:    true,
+		DryRun:           true,
+		Flags:            []string{"--force"},
+		Args:             []string{"rollout", "restart", "deployment/web", "--force"},
+		Dangerous:        true,
+		Reasons:          []string{"dangerous operation: rollout"},
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var req Request
+	if err := json.Unmarshal(data, &req); err != nil {
+		t.Fatal(err)
+	}
+	back, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var a, b map[string]interface{}
+	json.Unmarshal(data, &a)
+	json.Unmarshal(back, &b)
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("request does not round-trip the policy input:\n%s\n%s", data, back)
+	}
+}
@@ -0,0 +1,93 @@
+// Package safekubectl exposes safekubectl's danger detection for embedding in
+// other CLIs, bots and CI checks: parse kubectl arguments or manifests, load
+// a config, and evaluate them the same way the safekubectl command does.
+//
+// The types are aliases of safekubectl's own, so results match the CLI
+// exactly. Checks that query a live cluster (RBAC, PodDisruptionBudgets,
+// GitOps ownership and the like) are not part of this API.
+package safekubectl
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// Command is a parsed kubectl command
+type Command = parser.KubectlCommand
+
+// Target is one object a command operates on
+type Target = parser.Target
+
+// Config is safekubectl's configuration
+type Config = config.Config
+
+// Resource is one object from a manifest
+type Resource = manifest.Resource
+
+// Result is the verdict on a kubectl command
+type Result = checker.CheckResult
+
+// ResourceResult is the verdict on a file-based command
+type ResourceResult = checker.ResourceCheckResult
+
+// ParseCommand parses kubectl arguments, without the leading "kubectl"
+func ParseCommand(args []string) *Command {
+	return parser.Parse(args)
+}
+
+// ParseManifests reads the objects in a manifest file or directory.
+// URLs are not fetched; fetch them yourself and use ParseManifestYAML.
+func ParseManifests(path string, recursive bool) ([]Resource, error) {
+	if manifest.IsURL(path) {
+		return nil, fmt.Errorf("cannot parse %s: fetching URLs is not supported", path)
+	}
+	return manifest.Parse(path, recursive, nil)
+}
+
+// ParseManifestYAML reads the objects in YAML content; source names it in errors
+func ParseManifestYAML(content []byte, source string) ([]Resource, error) {
+	return manifest.ParseYAML(content, source)
+}
+
+// DefaultConfig returns the built-in configuration
+func DefaultConfig() *Config {
+	return config.DefaultConfig()
+}
+
+// LoadConfig loads the configuration the safekubectl command would use: the
+// layered config files, environment overrides and policy bundle
+func LoadConfig() (*Config, error) {
+	return config.Load()
+}
+
+// LoadConfigFile loads one config file on top of the defaults
+func LoadConfigFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	cfg, err := config.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Evaluate checks a command against the config for a cluster, the
+// kubeconfig context name matched against protectedClusters. A command
+// without a namespace is evaluated in "default"; set cmd.Namespace to the
+// kubeconfig context's namespace first if it has one.
+func Evaluate(cfg *Config, cmd *Command, cluster string) *Result {
+	return checker.New(cfg).Check(cmd, cluster)
+}
+
+// EvaluateManifests checks a file-based operation (apply, delete, ...) on
+// manifest objects against the config for a cluster
+func EvaluateManifests(cfg *Config, operation string, resources []Resource, cluster string) *ResourceResult {
+	return checker.New(cfg).CheckResources(operation, resources, cluster)
+}
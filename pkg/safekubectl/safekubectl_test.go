@@ -0,0 +1,73 @@
+package safekubectl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProtectedClusters = []string{"prod"}
+
+	tests := []struct {
+		name      string
+		args      []string
+		cluster   string
+		dangerous bool
+	}{
+		{"get is safe", []string{"get", "pods", "-n", "web"}, "prod", false},
+		{"delete is dangerous", []string{"delete", "pod", "nginx", "-n", "web"}, "dev", true},
+		{"delete on a protected cluster", []string{"delete", "deployment", "api"}, "prod", true},
+	}
+
+	for _, tt := range tests {
+		result := Evaluate(cfg, ParseCommand(tt.args), tt.cluster)
+		if result.IsDangerous != tt.dangerous {
+			t.Errorf("%s: dangerous: got %v, expected %v", tt.name, result.IsDangerous, tt.dangerous)
+		}
+	}
+}
+
+func TestEvaluateManifests(t *testing.T) {
+	resources, err := ParseManifestYAML([]byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n  namespace: kube-system\n"), "web.yaml")
+	if err != nil {
+		t.Fatalf("ParseManifestYAML returned error: %v", err)
+	}
+
+	result := EvaluateManifests(DefaultConfig(), "delete", resources, "dev")
+	if !result.IsDangerous || len(result.Resources) != 1 {
+		t.Errorf("expected a dangerous delete of 1 resource, got %+v", result)
+	}
+}
+
+func TestParseManifestsRejectsURLs(t *testing.T) {
+	if _, err := ParseManifests("https://example.com/app.yaml", false); err == nil {
+		t.Error("expected an error for a URL")
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("protectedClusters:\n  - prod\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile returned error: %v", err)
+	}
+	if !cfg.IsProtectedCluster("prod") {
+		t.Errorf("expected prod to be protected, got %v", cfg.ProtectedClusters)
+	}
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(invalid, []byte("protectedClusterz: [prod]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfigFile(invalid); err == nil || !strings.Contains(err.Error(), invalid) {
+		t.Errorf("expected an error naming %s, got %v", invalid, err)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/opa"
+	"github.com/zufardhiyaulhaq/safekubectl/pkg/plugin"
+)
+
+// discoverPlugins lists the executable files and .wasm modules in the plugins
+// directory, by name. A missing directory has no plugins.
+func discoverPlugins(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugins directory: %w", err)
+	}
+	var plugins []string
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || (info.Mode().Perm()&0111 == 0 && !isWASMPlugin(entry.Name())) {
+			continue
+		}
+		plugins = append(plugins, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(plugins)
+	return plugins, nil
+}
+
+// pluginReasons runs each plugin with the same input an OPA policy sees and
+// merges their findings: warnings and confirmations become reasons, and the
+// first deny blocks the command. A plugin that fails or answers nonsense
+// cannot vouch for the command, so it requires confirmation.
+func (r *Runner) pluginReasons(plugins, wasmRuntime []string, input opa.Input) (reasons []string, confirm bool, err error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode plugin request: %w", err)
+	}
+
+	for _, path := range plugins {
+		name := filepath.Base(path)
+		out, err := r.runHook(pluginCommand(path, wasmRuntime), nil, data)
+		var resp plugin.Response
+		if err == nil {
+			resp, err = plugin.ParseResponse(out)
+		}
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("plugin %s failed: %s", name, err))
+			confirm = true
+			continue
+		}
+
+		for _, f := range resp.Findings {
+			switch f.Severity {
+			case plugin.Deny:
+				return nil, false, fmt.Errorf("denied by plugin %s: %s", name, f.Message)
+			case plugin.Confirm:
+				confirm = true
+			}
+			reasons = append(reasons, fmt.Sprintf("%s: %s", name, f.Message))
+		}
+	}
+	return reasons, confirm, nil
+}
+
+// runPlugins discovers the plugins in the configured directory and runs them
+func (r *Runner) runPlugins(cfg config.PluginsConfig, input opa.Input) ([]string, bool, error) {
+	plugins, err := discoverPlugins(cfg.Dir)
+	if err != nil {
+		return nil, false, err
+	}
+	return r.pluginReasons(plugins, cfg.WASMRuntime, input)
+}
+
+// isWASMPlugin reports whether a plugin file is a WASM module
+func isWASMPlugin(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".wasm")
+}
+
+// pluginCommand returns the command that runs a plugin. WASM modules run in
+// the WASI runtime, sandboxed from the filesystem, network and environment,
+// with the same JSON on stdin and stdout as native plugins.
+func pluginCommand(path string, wasmRuntime []string) []string {
+	if isWASMPlugin(path) {
+		return append(append([]string{}, wasmRuntime...), path)
+	}
+	return []string{path}
+}
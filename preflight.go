@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+)
+
+// serverDryRunReasons runs the command with --dry-run=server and returns the
+// admission/validation errors it reports, one reason per line
+func (r *Runner) serverDryRunReasons(args []string) []string {
+	_, err := r.queryKubectl(withServerDryRun(args))
+	if err == nil {
+		return nil
+	}
+
+	var reasons []string
+	for _, line := range strings.Split(strings.TrimSpace(err.Error()), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			reasons = append(reasons, "server dry-run failed: "+line)
+		}
+	}
+	return reasons
+}
+
+// withServerDryRun adds --dry-run=server ahead of any "--" separator
+func withServerDryRun(args []string) []string {
+	return withFlags(args, "--dry-run=server")
+}
+
+// withFlags adds kubectl flags ahead of any "--" separator
+func withFlags(args []string, flags ...string) []string {
+	out := make([]string, 0, len(args)+len(flags))
+	for i, arg := range args {
+		if arg == "--" {
+			out = append(out, flags...)
+			return append(out, args[i:]...)
+		}
+		out = append(out, arg)
+	}
+	return append(out, flags...)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/refs"
+)
+
+// commandPriorityClasses returns the named PriorityClass targets of a command
+func commandPriorityClasses(cmd *parser.KubectlCommand) []string {
+	var names []string
+	for _, t := range cmd.Targets {
+		if t.Name != "" && parser.KindFor(t.Resource) == "PriorityClass" {
+			names = append(names, t.Name)
+		}
+	}
+	return names
+}
+
+// manifestPriorityClasses returns the PriorityClasses in manifests
+func manifestPriorityClasses(resources []manifest.Resource) []string {
+	var names []string
+	for _, res := range resources {
+		if res.Kind == "PriorityClass" && res.Name != "" {
+			names = append(names, res.Name)
+		}
+	}
+	return names
+}
+
+// priorityClassUsageReasons counts the pods in every namespace that run with
+// each PriorityClass being deleted. Unused classes, or pods that cannot be
+// listed, produce no reasons.
+func (r *Runner) priorityClassUsageReasons(names []string, kubeContext string) []string {
+	if len(names) == 0 {
+		return nil
+	}
+	out, err := r.queryKubectl(append([]string{"get", "pods", "--all-namespaces", "-o", "json"}, kubectlContextArgs(kubeContext)...))
+	if err != nil {
+		return nil
+	}
+	pods, err := refs.ParsePods(out)
+	if err != nil {
+		return nil
+	}
+
+	var reasons []string
+	for _, name := range names {
+		if count := refs.Count(pods, "PriorityClass", name); count > 0 {
+			reasons = append(reasons, fmt.Sprintf("priorityclass/%s is used by %s: new pods that reference it are rejected until it is recreated", name, podCount(count)))
+		}
+	}
+	return reasons
+}
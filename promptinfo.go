@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// promptInfo is the --sk-prompt-info=json document. Protected is null and
+// mode empty when the config could not be loaded.
+type promptInfo struct {
+	Context   string      `json:"context"`
+	Protected *bool       `json:"protected"`
+	Mode      config.Mode `json:"mode,omitempty"`
+}
+
+// runPromptInfo handles `safekubectl --sk-prompt-info[=json] [--context NAME]
+// [--kubeconfig PATH]`, which prints the current context and whether it is
+// protected for a shell prompt. It reads the kubeconfig, config files and the
+// cached policy bundle and rulesets only, never the network, so it is fast
+// enough to run on every prompt: "⚠ prod" for a protected context, the bare
+// name otherwise, or a JSON document with json. If the config cannot be
+// loaded, the context is marked "?" rather than shown as safe.
+func (r *Runner) runPromptInfo(args []string, format string) error {
+	if format != "true" && format != "json" {
+		return fmt.Errorf("invalid --sk-prompt-info %q: expected json, or no value", format)
+	}
+	cmd := parser.Parse(args)
+	info := promptInfo{Context: cmd.Context}
+	if info.Context == "" {
+		info.Context = r.getCluster(cmd.Kubeconfig)
+	}
+
+	if cfg, err := r.loadCachedConfig(); err == nil {
+		if len(cfg.ProtectedClusters) > 0 && r.getClusterServer != nil {
+			clusterName, server := r.getClusterServer(cmd.Kubeconfig, cmd.Context)
+			cfg.ResolveProtectedCluster(info.Context, clusterName, server)
+		}
+		protected := cfg.IsProtectedCluster(info.Context)
+		info.Protected = &protected
+		info.Mode = cfg.Mode
+	}
+
+	if format == "json" {
+		data, err := json.Marshal(info)
+		if err != nil {
+			return fmt.Errorf("failed to encode prompt info: %w", err)
+		}
+		fmt.Fprintln(r.stdout, string(data))
+		return nil
+	}
+	fmt.Fprintln(r.stdout, promptLabel(info))
+	return nil
+}
+
+// promptLabel is the short prompt text for a context, e.g. "⚠ prod", or
+// "? prod" when it is not known whether the context is protected
+func promptLabel(info promptInfo) string {
+	switch {
+	case info.Protected == nil:
+		return "? " + info.Context
+	case *info.Protected:
+		return "⚠ " + info.Context
+	}
+	return info.Context
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// maxPrunedListed caps how many pruned objects the warning names
+const maxPrunedListed = 20
+
+// pruneReason explains what apply --prune deletes
+func pruneReason(p *parser.Prune) string {
+	var scope string
+	switch {
+	case p.ApplySet != "":
+		scope = "objects in applyset " + p.ApplySet
+	case p.Selector != "":
+		scope = "live objects labeled " + p.Selector
+	case p.All:
+		scope = "every live object last applied with kubectl apply"
+	default:
+		scope = "live objects"
+	}
+	reason := "apply --prune: deletes " + scope + " that are not in the applied manifests"
+	if len(p.Allowlist) > 0 {
+		reason += ", of kinds " + strings.Join(p.Allowlist, ", ")
+	}
+	return reason
+}
+
+// pruneNamespaces returns the namespaces kubectl prunes in: the one given
+// with -n, or those of the applied resources
+func pruneNamespaces(cmd *parser.KubectlCommand, resources []manifest.Resource, fallback string) []string {
+	if cmd.Namespace != "" {
+		return []string{cmd.Namespace}
+	}
+	seen := make(map[string]bool)
+	var namespaces []string
+	for _, res := range resources {
+		if res.Namespace != "" && !seen[res.Namespace] {
+			seen[res.Namespace] = true
+			namespaces = append(namespaces, res.Namespace)
+		}
+	}
+	if len(namespaces) == 0 {
+		return []string{fallback}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// prunedReasons lists the live objects apply --prune would delete, found by
+// listing the pruned kinds that match its selector in each namespace. An
+// applyset is tracked by labels safekubectl does not compute, so it is not
+// previewed. Namespaces whose objects cannot be listed are left out.
+func (r *Runner) prunedReasons(cmd *parser.KubectlCommand, p *parser.Prune, resources []manifest.Resource, fallbackNS string) []string {
+	if p.ApplySet != "" || (p.Selector == "" && !p.All) {
+		return nil
+	}
+	kinds := strings.Join(manifest.PruneKinds(p.Allowlist), ",")
+	seen := make(map[string]bool)
+	var pruned []string
+	for _, namespace := range pruneNamespaces(cmd, resources, fallbackNS) {
+		args := []string{"get", kinds, "-n", namespace, "-o", "json"}
+		if p.Selector != "" {
+			args = append(args, "-l", p.Selector)
+		}
+		out, err := r.queryKubectl(append(args, kubectlContextArgs(cmd.Context)...))
+		if err != nil {
+			continue
+		}
+		candidates, err := manifest.PruneCandidates(out, resources)
+		if err != nil {
+			continue
+		}
+		for _, c := range candidates {
+			name := c.String()
+			if c.Namespace != "" {
+				name = c.Namespace + "/" + name
+			}
+			if !seen[name] {
+				seen[name] = true
+				pruned = append(pruned, name)
+			}
+		}
+	}
+	if len(pruned) == 0 {
+		return nil
+	}
+	listed := pruned
+	if len(listed) > maxPrunedListed {
+		listed = listed[:maxPrunedListed]
+	}
+	reason := fmt.Sprintf("apply --prune: %d live object(s) not in the manifests will be deleted: %s", len(pruned), strings.Join(listed, ", "))
+	if len(pruned) > len(listed) {
+		reason += fmt.Sprintf(" and %d more", len(pruned)-len(listed))
+	}
+	return []string{reason}
+}
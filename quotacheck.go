@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/quota"
+)
+
+// manifestGuardrailReasons compares ResourceQuota and LimitRange manifests
+// headed for protected namespaces with their live versions and reports the
+// limits they remove or loosen. Objects that do not exist yet are skipped.
+func (r *Runner) manifestGuardrailReasons(resources []manifest.Resource, cfg *config.Config, kubeContext string) []string {
+	var reasons []string
+	for _, res := range resources {
+		if !quota.Kinds[res.Kind] || res.Name == "" || !cfg.IsProtectedNamespace(res.Namespace) {
+			continue
+		}
+		out, err := r.queryKubectl(append([]string{"get", strings.ToLower(res.Kind), res.Name, "-n", res.Namespace, "-o", "json"}, kubectlContextArgs(kubeContext)...))
+		if err != nil {
+			continue
+		}
+		weakened, err := quota.WeakeningReasons(out, res.Raw)
+		if err != nil {
+			continue
+		}
+		for _, reason := range weakened {
+			reasons = append(reasons, fmt.Sprintf("governance: %s in protected namespace %s %s", res.String(), res.Namespace, reason))
+		}
+	}
+	return reasons
+}
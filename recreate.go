@@ -0,0 +1,132 @@
+package main
+
+import (
+	"time"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/watch"
+)
+
+// recreationPollInterval is how often the replacement is checked after a delete
+const recreationPollInterval = 2 * time.Second
+
+// recreationWatch captures the pre-delete state of one deleted target
+type recreationWatch struct {
+	display     string // e.g. pod/nginx
+	kind        string // Pod or Deployment
+	name        string
+	selector    string          // pods: labels shared by the replacement
+	exclude     map[string]bool // pods: UIDs of the deleted pods
+	readyBefore int             // pods: ready pods matching selector before delete
+	oldUID      string          // deployments: UID of the deleted deployment
+}
+
+// prepareRecreationWatches records the state needed to confirm that deleted
+// pods and deployments come back. Targets that cannot be looked up are skipped.
+func (r *Runner) prepareRecreationWatches(cmd *parser.KubectlCommand, namespace string) []recreationWatch {
+	contextArgs := kubectlContextArgs(cmd.Context)
+	var watches []recreationWatch
+
+	for _, t := range cmd.Targets {
+		if t.Name == "" {
+			continue
+		}
+		switch parser.KindFor(t.Resource) {
+		case "Pod":
+			out, err := r.queryKubectl(append([]string{"get", "pod", t.Name, "-n", namespace, "-o", "json"}, contextArgs...))
+			if err != nil {
+				continue
+			}
+			pod, err := watch.ParsePod(out)
+			if err != nil || len(pod.Labels) == 0 {
+				continue // bare pods without labels have no replacement to watch for
+			}
+			selector := watch.Selector(pod.Labels)
+			out, err = r.queryKubectl(append([]string{"get", "pods", "-n", namespace, "-l", selector, "-o", "json"}, contextArgs...))
+			if err != nil {
+				continue
+			}
+			siblings, err := watch.ParsePods(out)
+			if err != nil {
+				continue
+			}
+			watches = append(watches, recreationWatch{
+				display:     "pod/" + t.Name,
+				kind:        "Pod",
+				name:        t.Name,
+				selector:    selector,
+				exclude:     map[string]bool{pod.UID: true},
+				readyBefore: watch.CountReady(siblings, nil),
+			})
+		case "Deployment":
+			out, err := r.queryKubectl(append([]string{"get", "deployment", t.Name, "-n", namespace, "-o", "json"}, contextArgs...))
+			if err != nil {
+				continue
+			}
+			d, err := watch.ParseDeployment(out)
+			if err != nil {
+				continue
+			}
+			watches = append(watches, recreationWatch{
+				display: "deployment/" + t.Name,
+				kind:    "Deployment",
+				name:    t.Name,
+				oldUID:  d.UID,
+			})
+		}
+	}
+
+	return watches
+}
+
+// awaitRecreation polls until the replacement is ready or the timeout passes
+func (r *Runner) awaitRecreation(w recreationWatch, namespace, context string, timeout time.Duration) bool {
+	contextArgs := kubectlContextArgs(context)
+	var waited time.Duration
+
+	for {
+		if r.recreated(w, namespace, contextArgs) {
+			return true
+		}
+		if waited >= timeout {
+			return false
+		}
+		r.sleep(recreationPollInterval)
+		waited += recreationPollInterval
+	}
+}
+
+// recreated checks once whether the replacement for a watched target is ready
+func (r *Runner) recreated(w recreationWatch, namespace string, contextArgs []string) bool {
+	switch w.kind {
+	case "Pod":
+		out, err := r.queryKubectl(append([]string{"get", "pods", "-n", namespace, "-l", w.selector, "-o", "json"}, contextArgs...))
+		if err != nil {
+			return false
+		}
+		pods, err := watch.ParsePods(out)
+		if err != nil {
+			return false
+		}
+		return watch.CountReady(pods, w.exclude) >= w.readyBefore
+	case "Deployment":
+		out, err := r.queryKubectl(append([]string{"get", "deployment", w.name, "-n", namespace, "-o", "json"}, contextArgs...))
+		if err != nil {
+			return false // not recreated yet
+		}
+		d, err := watch.ParseDeployment(out)
+		if err != nil {
+			return false
+		}
+		return watch.DeploymentRecreated(w.oldUID, d)
+	}
+	return false
+}
+
+// kubectlContextArgs returns the --context flag for queries, if a context was given
+func kubectlContextArgs(context string) []string {
+	if context == "" {
+		return nil
+	}
+	return []string{"--context", context}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"io"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/redact"
+)
+
+// terminalOperations hand the terminal to kubectl or a program it starts when
+// run interactively, so their output cannot be filtered
+var terminalOperations = map[string]bool{
+	"attach": true,
+	"debug":  true,
+	"exec":   true,
+	"run":    true,
+}
+
+// needsTerminal reports whether kubectl must write straight to the terminal:
+// edit opens an editor, and the operations above do with -i or -t
+func needsTerminal(cmd *parser.KubectlCommand) bool {
+	if cmd.Operation == "edit" {
+		return true
+	}
+	if !terminalOperations[cmd.Operation] {
+		return false
+	}
+	for _, flag := range []string{"-i", "-t", "-it", "-ti", "--stdin", "--tty"} {
+		if hasFlag(cmd.Args, flag) {
+			return true
+		}
+	}
+	return false
+}
+
+// mayPrintSecrets reports whether a command's output may hold Secrets: it
+// targets them, or reads manifests that may define them
+func mayPrintSecrets(cmd *parser.KubectlCommand) bool {
+	if len(cmd.FileInputs) > 0 {
+		return true
+	}
+	for _, target := range cmd.Targets {
+		if parser.KindFor(target.Resource) == "Secret" {
+			return true
+		}
+	}
+	return false
+}
+
+// withRedaction returns a copy of the runner whose kubectl output goes through
+// the redactor before it reaches stdout
+func (r *Runner) withRedaction(redactor *redact.Redactor) *Runner {
+	wrapped := *r
+	wrapped.redactor = redactor
+	return wrapped.withFilteredOutput()
+}
+
+// withFilteredOutput returns a copy of the runner that runs kubectl through
+// filterKubectl, so its output can be redacted and captured on the way to
+// the terminal
+func (r *Runner) withFilteredOutput() *Runner {
+	wrapped := *r
+	filter := r.filterKubectl
+	if filter == nil {
+		return &wrapped
+	}
+	redactor, capture, stdout, stderr := r.redactor, r.capture, r.stdout, r.stderr
+	wrapped.executeKubectl = func(args []string) error {
+		outW, errW := stdout, stderr
+		if capture != nil {
+			outW, errW = io.MultiWriter(outW, capture), io.MultiWriter(errW, capture)
+		}
+		if redactor == nil {
+			return filter(args, outW, errW)
+		}
+		// Captured output is redacted too, so the archive holds no more than the screen did
+		w := redactor.Writer(outW)
+		err := filter(args, w, errW)
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}
+	return &wrapped
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/refs"
+)
+
+// configObject is a named ConfigMap or Secret a command changes
+type configObject struct {
+	kind      string
+	name      string
+	namespace string
+}
+
+// commandConfigObjects returns the named ConfigMap/Secret targets of a command
+func commandConfigObjects(cmd *parser.KubectlCommand, namespace string) []configObject {
+	var objects []configObject
+	for _, t := range cmd.Targets {
+		kind := parser.KindFor(t.Resource)
+		if t.Name != "" && (kind == "ConfigMap" || kind == "Secret") {
+			objects = append(objects, configObject{kind: kind, name: t.Name, namespace: namespace})
+		}
+	}
+	return objects
+}
+
+// manifestConfigObjects returns the ConfigMaps/Secrets in manifests
+func manifestConfigObjects(resources []manifest.Resource) []configObject {
+	var objects []configObject
+	for _, res := range resources {
+		if res.Kind == "ConfigMap" || res.Kind == "Secret" {
+			objects = append(objects, configObject{kind: res.Kind, name: res.Name, namespace: res.Namespace})
+		}
+	}
+	return objects
+}
+
+// configReferenceReasons counts the pods that mount or env-reference each
+// object, one reason per referenced object. Namespaces that cannot be listed
+// are skipped.
+func (r *Runner) configReferenceReasons(objects []configObject, kubeContext string) []string {
+	podsByNamespace := make(map[string][]refs.Pod)
+	var reasons []string
+
+	for _, obj := range objects {
+		count := refs.Count(r.namespacePods(podsByNamespace, obj.namespace, kubeContext), obj.kind, obj.name)
+		if count == 0 {
+			continue
+		}
+		reasons = append(reasons, fmt.Sprintf("%s/%s is referenced by %s", strings.ToLower(obj.kind), obj.name, podCount(count)))
+	}
+
+	return reasons
+}
+
+// namespacePods lists the pods in a namespace once per cache. Namespaces that
+// cannot be listed have no pods.
+func (r *Runner) namespacePods(cache map[string][]refs.Pod, namespace, kubeContext string) []refs.Pod {
+	pods, seen := cache[namespace]
+	if !seen {
+		out, err := r.queryKubectl(append([]string{"get", "pods", "-n", namespace, "-o", "json"}, kubectlContextArgs(kubeContext)...))
+		if err == nil {
+			pods, _ = refs.ParsePods(out)
+		}
+		cache[namespace] = pods
+	}
+	return pods
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/backup"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+)
+
+// restoreCommand is the safekubectl subcommand that creates snapshotted objects again
+const restoreCommand = "restore"
+
+// restoreUsage is the usage of the restore subcommand
+const restoreUsage = "usage: safekubectl restore [BACKUP-ID [FILE]] [--context NAME]"
+
+// runRestore handles `safekubectl restore`. Without a backup ID it lists the
+// snapshots taken before deletes; with one it creates the objects in it again,
+// or only FILE, through the usual checks.
+func (r *Runner) runRestore(args []string, cfg *config.Config) error {
+	var id, file, context string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--context" && i+1 < len(args):
+			context = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--context="):
+			context = strings.TrimPrefix(arg, "--context=")
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag %s\n%s", arg, restoreUsage)
+		case id == "":
+			id = arg
+		case file == "":
+			file = arg
+		default:
+			return fmt.Errorf("unexpected argument %q\n%s", arg, restoreUsage)
+		}
+	}
+
+	if id == "" {
+		backups, err := backup.List(cfg.Snapshot.Dir)
+		if err != nil {
+			return err
+		}
+		prompt.DisplayBackupsTo(r.stdout, backups)
+		return nil
+	}
+
+	b, err := backup.Find(cfg.Snapshot.Dir, id)
+	if err != nil {
+		return err
+	}
+	if len(b.Files) == 0 {
+		return fmt.Errorf("backup %s holds no objects", b.ID)
+	}
+	source := b.Dir + string(filepath.Separator)
+	if file != "" {
+		if !strings.HasSuffix(file, ".yaml") {
+			file += ".yaml"
+		}
+		if !slices.Contains(b.Files, file) {
+			return fmt.Errorf("backup %s has no %s: expected one of %s", b.ID, file, strings.Join(b.Files, ", "))
+		}
+		source = filepath.Join(b.Dir, file)
+	}
+
+	createArgs := append([]string{"create", "-f", source}, kubectlContextArgs(context)...)
+	prompt.DisplayRestoreTo(r.stdout, b.ID, createArgs)
+	return r.Run(createArgs)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/audit"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+)
+
+// rolloutTarget is a workload whose rollout is checked after an image change
+type rolloutTarget struct {
+	kind      string // Deployment, StatefulSet or DaemonSet
+	name      string
+	namespace string
+}
+
+func (t rolloutTarget) display() string {
+	return strings.ToLower(t.kind) + "/" + t.name
+}
+
+// setImageRolloutTargets returns the named workloads changed by `set image`
+func setImageRolloutTargets(cmd *parser.KubectlCommand, namespace string) []rolloutTarget {
+	var targets []rolloutTarget
+	for _, t := range cmd.Targets {
+		kind := parser.KindFor(t.Resource)
+		if t.Name == "" || !workloadKinds[kind] {
+			continue
+		}
+		targets = append(targets, rolloutTarget{kind: kind, name: t.Name, namespace: namespace})
+	}
+	return targets
+}
+
+// manifestRolloutTargets returns the workloads in applied manifests whose
+// container images differ from the live object, so it must run before the
+// apply. Workloads that do not exist yet have no rollout to undo, and live
+// objects that cannot be read are skipped.
+func (r *Runner) manifestRolloutTargets(resources []manifest.Resource, kubeContext string) []rolloutTarget {
+	var targets []rolloutTarget
+	for _, res := range resources {
+		if !workloadKinds[res.Kind] || res.Name == "" {
+			continue
+		}
+		out, err := r.queryKubectl(append([]string{"get", strings.ToLower(res.Kind), res.Name, "-n", res.Namespace, "-o", "json"}, kubectlContextArgs(kubeContext)...))
+		if err != nil {
+			continue
+		}
+		live, err := manifest.Images(res.Kind, out)
+		if err != nil {
+			continue
+		}
+		applied, err := manifest.Images(res.Kind, res.Raw)
+		if err != nil || reflect.DeepEqual(live, applied) {
+			continue
+		}
+		targets = append(targets, rolloutTarget{kind: res.Kind, name: res.Name, namespace: res.Namespace})
+	}
+	return targets
+}
+
+// gateRollouts waits for each workload's rollout and, when one fails within
+// the timeout, offers an immediate `rollout undo`. Undo decisions are audited.
+func (r *Runner) gateRollouts(targets []rolloutTarget, gate config.RolloutGateConfig, kubeContext, cluster string, auditLogger *audit.Logger) error {
+	contextArgs := kubectlContextArgs(kubeContext)
+
+	for _, t := range targets {
+		prompt.DisplayRolloutWaitTo(r.stdout, t.display(), gate.Timeout)
+		statusArgs := []string{"rollout", "status", t.display(), "-n", t.namespace, "--timeout", gate.Timeout.String()}
+		_, err := r.queryKubectl(append(statusArgs, contextArgs...))
+		prompt.DisplayRolloutResultTo(r.stdout, t.display(), err == nil)
+		if err == nil {
+			continue
+		}
+
+		undoArgs := append([]string{"rollout", "undo", t.display(), "-n", t.namespace}, contextArgs...)
+		prompt.DisplayRollbackOfferTo(r.stdout, undoArgs)
+		confirmed := prompt.AskConfirmationFrom(r.stdin, r.stdout)
+
+		undo := &checker.CheckResult{
+			Operation: "rollout",
+			Resources: []string{t.display()},
+			Namespace: t.namespace,
+			Cluster:   cluster,
+		}
+		if !confirmed {
+			prompt.DisplayAbortedTo(r.stdout)
+			if err := auditLogger.Log(undo, undoArgs, false, false); err != nil {
+				fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", err)
+			}
+			continue
+		}
+		execution, err := r.execute(undoArgs)
+		if logErr := auditLogger.LogExecuted(undo, undoArgs, true, execution); logErr != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to write audit log: %s\n", logErr)
+		}
+		if err != nil {
+			return fmt.Errorf("rollout undo %s failed: %w", t.display(), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/routes"
+)
+
+// routeListResources are the kubectl resource names used to list existing routes
+var routeListResources = map[string]string{
+	"Ingress":   "ingresses",
+	"HTTPRoute": "httproutes.gateway.networking.k8s.io",
+	"Gateway":   "gateways.gateway.networking.k8s.io",
+}
+
+// criticalHostReason describes a route deletion that takes down critical hosts
+func criticalHostReason(route routes.Route, critical []string) string {
+	hosts := routes.CriticalHosts(route, critical)
+	if len(hosts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s serves critical host %s", strings.ToLower(route.Kind), route.Name, strings.Join(hosts, ", "))
+}
+
+// deleteRouteReasons looks up the routes targeted by a delete and reports the
+// critical hosts they serve. Routes that cannot be looked up are skipped.
+func (r *Runner) deleteRouteReasons(cmd *parser.KubectlCommand, namespace string, critical []string) []string {
+	var reasons []string
+	for _, t := range cmd.Targets {
+		kind := parser.KindFor(t.Resource)
+		if t.Name == "" || !routes.Kinds[kind] {
+			continue
+		}
+		out, err := r.queryKubectl(append([]string{"get", t.Resource, t.Name, "-n", namespace, "-o", "json"}, kubectlContextArgs(cmd.Context)...))
+		if err != nil {
+			continue
+		}
+		route, err := routes.Parse(out)
+		if err != nil {
+			continue
+		}
+		route.Kind = kind
+		if reason := criticalHostReason(route, critical); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	return reasons
+}
+
+// manifestRouteReasons checks the routes in manifests: deletes of critical
+// hosts, and applies whose hosts collide with existing routes of the same kind.
+// It also returns whether a critical host is affected.
+func (r *Runner) manifestRouteReasons(operation string, resources []manifest.Resource, cfg config.RoutesConfig, kubeContext string) ([]string, bool) {
+	var reasons []string
+	critical := false
+	existing := make(map[string][]routes.Route)
+
+	for _, res := range resources {
+		if !routes.Kinds[res.Kind] {
+			continue
+		}
+		route, err := routes.Parse(res.Raw)
+		if err != nil {
+			continue
+		}
+		route.Kind, route.Namespace = res.Kind, res.Namespace
+
+		switch operation {
+		case "delete":
+			if reason := criticalHostReason(route, cfg.CriticalHosts); reason != "" {
+				reasons = append(reasons, reason)
+				critical = true
+			}
+		case "apply", "create", "replace":
+			if !cfg.CheckCollisions {
+				continue
+			}
+			others, listed := existing[res.Kind]
+			if !listed {
+				out, err := r.queryKubectl(append([]string{"get", routeListResources[res.Kind], "-A", "-o", "json"}, kubectlContextArgs(kubeContext)...))
+				if err == nil {
+					others, _ = routes.ParseList(res.Kind, out)
+				}
+				existing[res.Kind] = others
+			}
+			for _, collision := range routes.Collisions(route, others) {
+				reasons = append(reasons, fmt.Sprintf("%s/%s: %s", strings.ToLower(route.Kind), route.Name, collision))
+			}
+		}
+	}
+	return reasons, critical
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+)
+
+// protectedResources returns the resources in protected namespaces, or nil
+// unless the manifests also touch unprotected namespaces or cluster-scoped
+// objects, so skipping them leaves something to run
+func protectedResources(resources []manifest.Resource, cfg *config.Config) []manifest.Resource {
+	var protected []manifest.Resource
+	for _, res := range resources {
+		if res.Namespace != "" && cfg.IsProtectedNamespace(res.Namespace) {
+			protected = append(protected, res)
+		}
+	}
+	if len(protected) == len(resources) {
+		return nil
+	}
+	return protected
+}
+
+// selectResources asks which of the protected resources to skip. It reports
+// the resources to skip, and false if the user aborted.
+func (r *Runner) selectResources(protected []manifest.Resource) ([]manifest.Resource, bool) {
+	switch prompt.AskSelectiveConfirmationFrom(r.stdin, r.stdout, len(protected)) {
+	case prompt.SelectAll:
+		return nil, true
+	case prompt.SelectSkipProtected:
+		return protected, true
+	case prompt.SelectChoose:
+		indexes, ok := prompt.AskResourcesToSkipFrom(r.stdin, r.stdout, protected)
+		if !ok {
+			return nil, false
+		}
+		var skip []manifest.Resource
+		for _, i := range indexes {
+			skip = append(skip, protected[i])
+		}
+		return skip, true
+	}
+	return nil, false
+}
+
+// withoutResources returns the resources that are not in skip
+func withoutResources(resources, skip []manifest.Resource) []manifest.Resource {
+	skipped := make(map[string]bool, len(skip))
+	for _, res := range skip {
+		skipped[resourceKey(res)] = true
+	}
+	var kept []manifest.Resource
+	for _, res := range resources {
+		if !skipped[resourceKey(res)] {
+			kept = append(kept, res)
+		}
+	}
+	return kept
+}
+
+// resourceKey identifies a manifest object by where it comes from and what it is
+func resourceKey(res manifest.Resource) string {
+	return res.Source + "|" + res.Kind + "|" + res.Namespace + "|" + res.Name
+}
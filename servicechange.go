@@ -0,0 +1,118 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/service"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/watch"
+)
+
+// serviceImpactReasons compares a Service's live selector and ports with the
+// updated ones and reports dropped endpoints and ports. Services that cannot be
+// looked up (e.g. not created yet) or updates that cannot be computed are skipped.
+func (r *Runner) serviceImpactReasons(name, namespace, kubeContext string, update func(live service.Service) (service.Service, error)) []string {
+	contextArgs := kubectlContextArgs(kubeContext)
+
+	out, err := r.queryKubectl(append([]string{"get", "service", name, "-n", namespace, "-o", "json"}, contextArgs...))
+	if err != nil {
+		return nil
+	}
+	live, err := service.Parse(out)
+	if err != nil {
+		return nil
+	}
+	updated, err := update(live)
+	if err != nil {
+		return nil
+	}
+
+	out, err = r.queryKubectl(append([]string{"get", "pods", "-n", namespace, "-o", "json"}, contextArgs...))
+	if err != nil {
+		return nil
+	}
+	pods, err := watch.ParsePods(out)
+	if err != nil {
+		return nil
+	}
+	return service.Impact(live, updated, pods)
+}
+
+// patchServiceReasons checks the Services targeted by `kubectl patch`
+func (r *Runner) patchServiceReasons(cmd *parser.KubectlCommand, namespace string) []string {
+	patch, patchType, ok := patchFromArgs(cmd.Args)
+	if !ok {
+		return nil
+	}
+
+	var reasons []string
+	for _, t := range cmd.Targets {
+		if t.Name == "" || parser.KindFor(t.Resource) != "Service" {
+			continue
+		}
+		reasons = append(reasons, r.serviceImpactReasons(t.Name, namespace, cmd.Context, func(live service.Service) (service.Service, error) {
+			return service.ApplyPatch(live, patch, patchType)
+		})...)
+	}
+	return reasons
+}
+
+// manifestServiceReasons checks the Services in applied manifests
+func (r *Runner) manifestServiceReasons(resources []manifest.Resource, kubeContext string) []string {
+	var reasons []string
+	for _, res := range resources {
+		if res.Kind != "Service" {
+			continue
+		}
+		raw := res.Raw
+		reasons = append(reasons, r.serviceImpactReasons(res.Name, res.Namespace, kubeContext, func(live service.Service) (service.Service, error) {
+			return service.Parse(raw)
+		})...)
+	}
+	return reasons
+}
+
+// patchFromArgs returns the patch content and --type (default "strategic")
+// from -p/--patch or --patch-file
+func patchFromArgs(args []string) ([]byte, string, bool) {
+	var patch []byte
+	found := false
+	patchType := "strategic"
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		next := func() string {
+			if i+1 < len(args) {
+				i++
+				return args[i]
+			}
+			return ""
+		}
+		switch {
+		case arg == "--":
+			return patch, patchType, found
+		case arg == "-p" || arg == "--patch":
+			patch, found = []byte(next()), true
+		case strings.HasPrefix(arg, "-p="), strings.HasPrefix(arg, "--patch="):
+			_, value, _ := strings.Cut(arg, "=")
+			patch, found = []byte(value), true
+		case arg == "--patch-file" || strings.HasPrefix(arg, "--patch-file="):
+			path := strings.TrimPrefix(arg, "--patch-file=")
+			if arg == "--patch-file" {
+				path = next()
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, "", false
+			}
+			patch, found = content, true
+		case arg == "--type":
+			patchType = next()
+		case strings.HasPrefix(arg, "--type="):
+			patchType = strings.TrimPrefix(arg, "--type=")
+		}
+	}
+	return patch, patchType, found
+}
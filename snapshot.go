@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/backup"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+)
+
+// commandSnapshotTargets returns the named targets of a delete; type-only
+// targets such as delete pods -l app=x have no single object to save
+func commandSnapshotTargets(cmd *parser.KubectlCommand, namespace string) []backupTarget {
+	var targets []backupTarget
+	for _, t := range cmd.Targets {
+		if t.Name == "" {
+			continue
+		}
+		kind := parser.KindFor(t.Resource)
+		if kind == "" {
+			kind = t.Resource
+		}
+		target := backupTarget{kind: kind, name: t.Name}
+		if !parser.IsClusterScopedKind(kind) {
+			target.namespace = namespace
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// manifestSnapshotTargets returns the resources a delete -f removes. Those
+// without a namespace are in the -n namespace, if given.
+func manifestSnapshotTargets(resources []manifest.Resource, namespace string) []backupTarget {
+	var targets []backupTarget
+	for _, res := range resources {
+		if res.Name == "" {
+			continue
+		}
+		target := backupTarget{kind: res.Kind, name: res.Name, namespace: res.Namespace}
+		if target.namespace == "" && !parser.IsClusterScopedKind(res.Kind) {
+			target.namespace = namespace
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// snapshot saves each target as it is now in a timestamped directory under
+// root and prints how to restore them. It returns the directory, or "" if
+// nothing was saved. Objects that cannot be read are reported and skipped:
+// a missing snapshot never blocks the delete.
+func (r *Runner) snapshot(targets []backupTarget, root, context string) string {
+	dir := backup.Dir(root, time.Now())
+	var paths []string
+	for _, t := range targets {
+		args := []string{"get", t.kind, t.name, "-o", "yaml"}
+		if t.namespace != "" {
+			args = append(args, "-n", t.namespace)
+		}
+		out, err := r.queryKubectl(append(args, kubectlContextArgs(context)...))
+		if err != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to snapshot %s: %s\n", t.display(), err)
+			continue
+		}
+		path, err := backup.Save(dir, t.kind, t.name, t.namespace, out)
+		if err != nil {
+			fmt.Fprintf(r.stderr, "warning: failed to snapshot %s: %s\n", t.display(), err)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 {
+		return ""
+	}
+	prompt.DisplaySnapshotTo(r.stdout, paths, backup.RestoreCommand(dir+string(filepath.Separator), context))
+	return dir
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+)
+
+// writeStdinManifest writes manifest content read from stdin to a temporary
+// file, since kubectl can no longer read it from stdin
+func writeStdinManifest(content []byte) (string, error) {
+	f, err := os.CreateTemp("", "safekubectl-stdin-*.yaml")
+	if err != nil {
+		return "", fmt.Errorf("failed to create stdin manifest: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(content); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write stdin manifest: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// withStdinFile replaces the -f - that reads manifests from stdin with path
+func withStdinFile(args []string, path string) []string {
+	return withFileInput(args, manifest.StdinSource, path)
+}
+
+// withFileInput replaces the -f/--filename value from with to
+func withFileInput(args []string, from, to string) []string {
+	out := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case (arg == "-f" || arg == "--filename") && i+1 < len(args) && args[i+1] == from:
+			out = append(out, arg, to)
+			i++
+		case arg == "-f="+from || arg == "--filename="+from:
+			flag, _, _ := strings.Cut(arg, "=")
+			out = append(out, flag+"="+to)
+		default:
+			out = append(out, arg)
+		}
+	}
+	return out
+}
+
+// withTerminalInput returns a copy of the runner whose prompts read from the
+// terminal, and a func that closes it. Without a terminal, prompts read
+// nothing and so abort.
+func (r *Runner) withTerminalInput() (*Runner, func()) {
+	wrapped := *r
+	wrapped.stdin = strings.NewReader("")
+	if r.openTerminal == nil {
+		return &wrapped, func() {}
+	}
+	tty, err := r.openTerminal()
+	if err != nil {
+		return &wrapped, func() {}
+	}
+	wrapped.stdin = tty
+	return &wrapped, func() { tty.Close() }
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/bundle"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/redact"
+)
+
+// supportBundleCommand is the safekubectl subcommand that packs debugging
+// information for a bug report
+const supportBundleCommand = "support-bundle"
+
+// supportBundleUsage is the usage of the support-bundle subcommand
+const supportBundleUsage = "usage: safekubectl support-bundle [-o FILE] [-- KUBECTL-ARGS...]"
+
+// supportBundleAuditLines is how many of the latest audit entries are included
+const supportBundleAuditLines = 50
+
+// supportBundlePatterns mask credentials commonly passed on the command line,
+// on top of the configured redact patterns
+var supportBundlePatterns = []string{
+	`--from-literal[= ][^=\s]+=(\S+)`,
+	`--(?:token|password|client-secret)[= ](\S+)`,
+	`(?i)authorization: bearer (\S+)`,
+}
+
+// runSupportBundle handles `safekubectl support-bundle`. It writes a tarball
+// with the effective config, the latest audit entries, version and
+// environment details and, given a kubectl command after --, how it is parsed
+// and checked. Credentials are masked, but the user should still review it
+// before sharing.
+func (r *Runner) runSupportBundle(args []string, cfg *config.Config) error {
+	now := time.Now()
+	output := ""
+	var command []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			command = args[i+1:]
+			i = len(args)
+		case (arg == "-o" || arg == "--output") && i+1 < len(args):
+			output = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--output="):
+			output = strings.TrimPrefix(arg, "--output=")
+		default:
+			return fmt.Errorf("unexpected argument %q\n%s", arg, supportBundleUsage)
+		}
+	}
+	name := "safekubectl-support-" + now.UTC().Format("20060102T150405Z")
+	if output == "" {
+		output = name + ".tar.gz"
+	}
+
+	redactor, err := redact.New(append(slices.Clone(supportBundlePatterns), cfg.Redact.Patterns...), true)
+	if err != nil {
+		return err
+	}
+
+	configYAML, err := yaml.Marshal(cfg.Redacted())
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	sources := "# no config files found, built-in defaults\n"
+	if len(cfg.Sources) > 0 {
+		sources = "# sources: " + strings.Join(cfg.Sources, ", ") + "\n"
+	}
+	files := []bundle.File{
+		{Name: "config.yaml", Data: append([]byte(sources), configYAML...)},
+		{Name: "version.txt", Data: r.supportVersion()},
+		{Name: "environment.txt", Data: r.supportEnvironment()},
+	}
+	if cfg.Audit.Sink != "syslog" {
+		if data, err := os.ReadFile(cfg.Audit.Path); err == nil {
+			files = append(files, bundle.File{Name: "audit.log", Data: lastLines(data, supportBundleAuditLines)})
+		}
+	}
+	if len(command) > 0 {
+		data, err := r.supportCommand(command, cfg)
+		if err != nil {
+			return err
+		}
+		files = append(files, bundle.File{Name: "command.json", Data: data})
+	}
+	for i := range files {
+		var buf bytes.Buffer
+		w := redactor.Writer(&buf)
+		w.Write(files[i].Data)
+		w.Close()
+		files[i].Data = buf.Bytes()
+	}
+
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create support bundle: %w", err)
+	}
+	if err := bundle.Write(f, name, files, now); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write support bundle: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write support bundle: %w", err)
+	}
+
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+	prompt.DisplaySupportBundleTo(r.stdout, output, names)
+	return nil
+}
+
+// supportVersion describes the safekubectl build, the platform and kubectl
+func (r *Runner) supportVersion() []byte {
+	version := "unknown"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		version = info.Main.Version
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "safekubectl: %s\n", version)
+	fmt.Fprintf(&b, "go: %s\n", runtime.Version())
+	fmt.Fprintf(&b, "platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	if r.queryKubectl != nil {
+		out, err := r.queryKubectl([]string{"version", "--client"})
+		if err != nil {
+			fmt.Fprintf(&b, "kubectl: %s\n", err)
+		} else {
+			fmt.Fprintf(&b, "kubectl:\n%s", out)
+		}
+	}
+	return []byte(b.String())
+}
+
+// supportEnvironment lists the environment variables that change what
+// safekubectl or kubectl do, and the current context
+func (r *Runner) supportEnvironment() []byte {
+	var lines []string
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		switch {
+		case strings.HasPrefix(name, "SAFEKUBECTL_"), name == "KUBECONFIG", name == "TERM", name == "SHELL", name == "PAGER":
+		case strings.EqualFold(name, "HTTPS_PROXY"), strings.EqualFold(name, "HTTP_PROXY"):
+			if u, err := url.Parse(value); err == nil {
+				value = u.Redacted()
+			}
+		case strings.EqualFold(name, "NO_PROXY"):
+		default:
+			continue
+		}
+		lines = append(lines, name+"="+value)
+	}
+	slices.Sort(lines)
+	if r.getCluster != nil {
+		lines = append(lines, "current context: "+orUnknown(r.getCluster("")))
+	}
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+// supportCommand reports how a kubectl command is parsed and what the checks
+// that need no cluster access make of it
+func (r *Runner) supportCommand(args []string, cfg *config.Config) ([]byte, error) {
+	cmd := parser.Parse(args)
+	cluster := cmd.Context
+	if cluster == "" && r.getCluster != nil {
+		cluster = r.getCluster(cmd.Kubeconfig)
+	}
+	if cmd.Namespace == "" && !cmd.IsNodeScoped() && !cmd.IsClusterScoped() && r.getContextNamespace != nil {
+		cmd.Namespace = r.getContextNamespace(cmd.Kubeconfig, cmd.Context)
+	}
+	report := struct {
+		Args    []string               `json:"args"`
+		Parsed  *parser.KubectlCommand `json:"parsed"`
+		Verdict *checker.CheckResult   `json:"verdict,omitempty"`
+	}{Args: args, Parsed: cmd}
+	if len(cmd.FileInputs) == 0 {
+		report.Verdict = checker.New(cfg).Check(cmd, cluster)
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode command: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// lastLines returns the last n lines of data
+func lastLines(data []byte, n int) []byte {
+	lines := strings.SplitAfter(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return []byte(strings.Join(lines, "") + "\n")
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/ticket"
+)
+
+// changeTicket returns the change ticket a dangerous command on a protected
+// cluster is made under, from --sk-ticket or asked for, after checking it
+// against ticket.pattern and, with ticket.provider, the ticketing system. It
+// returns "" when no ticket is required. A ticketing system that cannot be
+// reached stops the command, unless ticket.failOpen accepts the ticket as given.
+func (r *Runner) changeTicket(cfg config.TicketConfig, protected bool, given string) (string, error) {
+	if !cfg.Required || !protected {
+		return "", nil
+	}
+	id := given
+	if id == "" {
+		id = prompt.AskTicketFrom(r.stdin, r.stdout)
+	}
+	if id == "" {
+		return "", fmt.Errorf("a change ticket is required on protected clusters: enter it when asked, or pass --sk-ticket=ID")
+	}
+	if strings.ContainsFunc(id, func(c rune) bool { return c == ' ' || c == '\t' }) {
+		return "", fmt.Errorf("invalid change ticket %q: it must not contain spaces", id)
+	}
+	if err := ticket.ValidID(id); err != nil {
+		return "", err
+	}
+	if cfg.Pattern != "" && !regexp.MustCompile(cfg.Pattern).MatchString(id) {
+		return "", fmt.Errorf("invalid change ticket %q: expected it to match %s", id, cfg.Pattern)
+	}
+	if cfg.Provider == "" || r.getTicket == nil {
+		return id, nil
+	}
+
+	t, err := r.getTicket(cfg, id)
+	if errors.Is(err, ticket.ErrNotFound) {
+		return "", fmt.Errorf("change ticket %s not found in %s", id, cfg.Provider)
+	}
+	if err != nil && cfg.FailOpen {
+		fmt.Fprintf(r.stderr, "warning: failed to look up change ticket %s, accepting it as given: %s\n", id, err)
+		return id, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up change ticket %s in %s: %w", id, cfg.Provider, err)
+	}
+	if len(cfg.Statuses) > 0 && !slices.ContainsFunc(cfg.Statuses, func(s string) bool { return strings.EqualFold(s, t.Status) }) {
+		return "", fmt.Errorf("change ticket %s is %s: expected %s", id, t.Status, strings.Join(cfg.Statuses, " or "))
+	}
+	prompt.DisplayTicketTo(r.stdout, id, t.Status, t.Summary)
+	return id, nil
+}
+
+// changeReason returns the justification a dangerous command is made for,
+// from --sk-reason or asked for, when audit.requireReason is set, and ""
+// otherwise
+func (r *Runner) changeReason(cfg config.AuditConfig, given string) (string, error) {
+	if !cfg.RequireReason {
+		return "", nil
+	}
+	reason := strings.TrimSpace(given)
+	if reason == "" {
+		reason = prompt.AskReasonFrom(r.stdin, r.stdout)
+	}
+	if reason == "" {
+		return "", fmt.Errorf("a reason is required for dangerous commands: enter it when asked, or pass --sk-reason=TEXT")
+	}
+	return reason, nil
+}
+
+// lookupTicket fetches a change ticket from the configured ticketing system,
+// with basic auth when ticket.userEnv is set and a bearer token otherwise
+func lookupTicket(cfg config.TicketConfig, id string) (ticket.Ticket, error) {
+	token := os.Getenv(cfg.TokenEnv)
+	if token == "" {
+		return ticket.Ticket{}, fmt.Errorf("%s is not set", cfg.TokenEnv)
+	}
+	authorization := "Bearer " + token
+	if cfg.UserEnv != "" {
+		authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(os.Getenv(cfg.UserEnv)+":"+token))
+	}
+	return ticket.Lookup(cfg.Provider, cfg.URL, authorization, id)
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+)
+
+// validateConfigCommand is the safekubectl subcommand that checks the config files
+const validateConfigCommand = "validate-config"
+
+// runValidateConfig handles `safekubectl validate-config`. Loading the config
+// runs the same strict validation as every other command, so a problem found
+// here is exactly what would stop a real kubectl call.
+func (r *Runner) runValidateConfig() error {
+	cfg, err := r.loadConfig()
+	if err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+
+	prompt.DisplayConfigValidTo(r.stdout, cfg.Sources)
+	return nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/manifest"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/watch"
+)
+
+// verifyTarget is one object an operation changes
+type verifyTarget struct {
+	resource  string // as typed, e.g. "po" or "deployment"; the lowercased kind for manifests
+	kind      string
+	name      string
+	namespace string
+}
+
+// commandVerifyTargets returns the named targets of a command
+func commandVerifyTargets(cmd *parser.KubectlCommand, namespace string) []verifyTarget {
+	var targets []verifyTarget
+	for _, t := range cmd.Targets {
+		if t.Name == "" {
+			continue
+		}
+		targets = append(targets, verifyTarget{resource: t.Resource, kind: parser.KindFor(t.Resource), name: t.Name, namespace: namespace})
+	}
+	return targets
+}
+
+// manifestVerifyTargets returns the resources in manifests
+func manifestVerifyTargets(resources []manifest.Resource) []verifyTarget {
+	var targets []verifyTarget
+	for _, res := range resources {
+		targets = append(targets, verifyTarget{resource: strings.ToLower(res.Kind), kind: res.Kind, name: res.Name, namespace: res.Namespace})
+	}
+	return targets
+}
+
+// matchesVerifyResource checks a target against a VerifyCommand resource,
+// given as a kind or any of its resource names
+func matchesVerifyResource(resource string, t verifyTarget) bool {
+	if resource == "" {
+		return true
+	}
+	return parser.SameResource(resource, t.resource)
+}
+
+// verifyCommands expands the follow-up commands configured for an operation.
+// {selector} is looked up before the operation runs, since a deleted object
+// cannot be read afterwards; targets without one skip that command.
+func (r *Runner) verifyCommands(verify []config.VerifyCommand, targets []verifyTarget, kubeContext string) [][]string {
+	var commands [][]string
+	seen := map[string]bool{}
+
+	for _, v := range verify {
+		for _, t := range targets {
+			if !matchesVerifyResource(v.Resource, t) {
+				continue
+			}
+			selector := ""
+			if strings.Contains(v.Command, "{selector}") {
+				if selector = r.targetSelector(t, kubeContext); selector == "" {
+					continue
+				}
+			}
+			replacer := strings.NewReplacer("{name}", t.name, "{namespace}", t.namespace, "{resource}", t.resource, "{selector}", selector)
+			args := append(strings.Fields(replacer.Replace(v.Command)), kubectlContextArgs(kubeContext)...)
+			if key := strings.Join(args, " "); !seen[key] {
+				seen[key] = true
+				commands = append(commands, args)
+			}
+		}
+	}
+	return commands
+}
+
+// targetSelector returns the label selector for a target's pods, or empty if
+// it cannot be looked up
+func (r *Runner) targetSelector(t verifyTarget, kubeContext string) string {
+	if r.queryKubectl == nil {
+		return ""
+	}
+	args := []string{"get", t.resource, t.name, "-o", "json"}
+	if t.namespace != "" {
+		args = append(args, "-n", t.namespace)
+	}
+	out, err := r.queryKubectl(append(args, kubectlContextArgs(kubeContext)...))
+	if err != nil {
+		return ""
+	}
+	selector, err := watch.ParseSelector(out)
+	if err != nil {
+		return ""
+	}
+	return selector
+}
+
+// offerVerification offers to run each follow-up command after an operation,
+// printing its output beneath the operation's. Without ask, the commands are
+// only suggested.
+func (r *Runner) offerVerification(commands [][]string, ask bool) {
+	for _, args := range commands {
+		prompt.DisplayVerifyOfferTo(r.stdout, args)
+		if !ask || !prompt.AskConfirmationFrom(r.stdin, r.stdout) {
+			continue
+		}
+		if err := r.executeKubectl(args); err != nil {
+			fmt.Fprintf(r.stderr, "warning: verification failed: %s\n", err)
+		}
+	}
+}
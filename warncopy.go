@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/parser"
+)
+
+// warningVariant picks the warning wording for an operation on a resource
+// from the most specific matching experiment, weighted at random. The zero
+// variant (the default wording) is returned when no experiment matches.
+func (r *Runner) warningVariant(experiments []config.WarningExperiment, operation, resource string) config.WarningVariant {
+	var match *config.WarningExperiment
+	for i, experiment := range experiments {
+		fields := strings.Fields(experiment.Rule)
+		if len(fields) == 0 || fields[0] != operation || len(experiment.Variants) == 0 {
+			continue
+		}
+		if len(fields) == 2 {
+			if resource != "" && parser.SameResource(fields[1], resource) {
+				return r.pickVariant(experiment.Variants)
+			}
+			continue
+		}
+		if match == nil {
+			match = &experiments[i]
+		}
+	}
+	if match == nil {
+		return config.WarningVariant{}
+	}
+	return r.pickVariant(match.Variants)
+}
+
+// pickVariant picks a variant with probability proportional to its weight
+func (r *Runner) pickVariant(variants []config.WarningVariant) config.WarningVariant {
+	total := 0
+	for _, v := range variants {
+		total += variantWeight(v)
+	}
+	if r.randIntn == nil {
+		return variants[0]
+	}
+	n := r.randIntn(total)
+	for _, v := range variants {
+		if n < variantWeight(v) {
+			return v
+		}
+		n -= variantWeight(v)
+	}
+	return variants[len(variants)-1]
+}
+
+// variantWeight is a variant's weight, counting an unset weight as 1
+func variantWeight(v config.WarningVariant) int {
+	if v.Weight == 0 {
+		return 1
+	}
+	return v.Weight
+}
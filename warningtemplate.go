@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/zufardhiyaulhaq/safekubectl/internal/checker"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/config"
+	"github.com/zufardhiyaulhaq/safekubectl/internal/prompt"
+)
+
+// displayWarning shows the warning from templates.warning if one is set, or
+// the built-in warning in the variant's wording. A template that fails to
+// render falls back to the built-in warning.
+func (r *Runner) displayWarning(template string, result *checker.CheckResult, args []string, variant config.WarningVariant) {
+	if template != "" {
+		err := prompt.DisplayWarningTemplateTo(r.stdout, template, result, args)
+		if err == nil {
+			return
+		}
+		fmt.Fprintf(r.stderr, "warning: %s\n", err)
+	}
+	prompt.DisplayWarningVariantTo(r.stdout, result, args, variant)
+}
+
+// displayResourceWarning shows the warning for a file-based command like
+// displayWarning, from templates.resourceWarning
+func (r *Runner) displayResourceWarning(template string, result *checker.ResourceCheckResult, args []string, variant config.WarningVariant) {
+	if template != "" {
+		err := prompt.DisplayResourceWarningTemplateTo(r.stdout, template, result, args)
+		if err == nil {
+			return
+		}
+		fmt.Fprintf(r.stderr, "warning: %s\n", err)
+	}
+	prompt.DisplayResourceWarningVariantTo(r.stdout, result, args, variant)
+}